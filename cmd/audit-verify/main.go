@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"strings"
+
+	"maintainerd/db"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const defaultDBPath = "/data/maintainers.db"
+
+// audit-verify walks the hash-chained audit log and exits non-zero if any entry was modified, deleted, reordered
+// or written outside the chain. Entries deleted from the end of the log leave a valid shorter chain, so keep the
+// reported head and count and check that later runs still include them.
+func main() {
+	jsonOutput := flag.Bool("json", false, "write the verification report as JSON to stdout")
+	flag.Parse()
+
+	dbDriver := envOr("MD_DB_DRIVER", "sqlite")
+	dbDSN := envOr("MD_DB_DSN", "")
+	dbPath := envOr("MD_DB_PATH", defaultDBPath)
+	if dbDriver == "postgres" && dbDSN == "" {
+		log.Fatal("MD_DB_DSN is required when MD_DB_DRIVER=postgres")
+	}
+	dsn := dbPath
+	if dbDriver == "postgres" {
+		dsn = dbDSN
+	}
+
+	dbConn, err := db.OpenGorm(dbDriver, dsn, &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		log.Fatalf("failed to open DB: %v", err)
+	}
+	store := db.NewSQLStore(dbConn)
+
+	report, err := store.VerifyAuditChain(context.Background())
+	if err != nil {
+		log.Fatalf("audit verification failed: %v", err)
+	}
+
+	if *jsonOutput {
+		if err := json.NewEncoder(os.Stdout).Encode(report); err != nil {
+			log.Fatalf("failed to write report: %v", err)
+		}
+	} else {
+		for _, brk := range report.Breaks {
+			log.Printf("BREAK audit_log id=%d: %s", brk.ID, brk.Reason)
+		}
+		log.Printf("audit chain checked=%d legacy=%d breaks=%d head=%s", report.Checked, report.Legacy, len(report.Breaks), report.Head)
+		log.Printf("note: %s", report.Note)
+	}
+	if !report.OK() {
+		os.Exit(1)
+	}
+}
+
+func envOr(key, fallback string) string {
+	if v := strings.TrimSpace(os.Getenv(key)); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"maintainerd/db"
@@ -30,24 +32,28 @@ func main() {
 	var seed bool
 	var doBackup bool
 	var maxBackups int
+	var sourceKind string
+	var sourcePath string
+	var incremental bool
+	var approve string
+	var reportPath string
+	var actor string
 
 	rootCmd := &cobra.Command{
 		Use:   "bootstrap",
 		Short: "Bootstrap the database schema and optionally seed it",
 		Run: func(cmd *cobra.Command, args []string) {
-			spreadsheetID := viper.GetString(spreadsheetEnvVar)
-			if spreadsheetID == "" {
-				log.Fatalf("ERROR: environment variable %s is not set", spreadsheetEnvVar)
+			var source db.ImportSource
+			if seed || incremental {
+				var err error
+				if source, err = importSource(sourceKind, sourcePath); err != nil {
+					log.Fatalf("ERROR: %v", err)
+				}
 			}
 
 			fossaToken := viper.GetString(apiTokenEnvVar)
-			if fossaToken == "" {
-				log.Fatalf("ERROR: environment variable %s is not set", apiTokenEnvVar)
-			}
-
-			credentialsPath := viper.GetString(googleWorkspaceCredentials)
-			if credentialsPath == "" {
-				log.Fatalf("ERROR: environment variable %s is not set", googleWorkspaceCredentials)
+			if seed && !incremental && fossaToken == "" {
+				log.Printf("WARN: environment variable %s is not set, FOSSA teams will not be synced", apiTokenEnvVar)
 			}
 			if doBackup && dbDriver == "sqlite" {
 				if _, err := os.Stat(dbPath); err == nil {
@@ -79,7 +85,17 @@ func main() {
 				}
 				dsn = dbDSN
 			}
-			_, err := db.BootstrapDB(dbDriver, dsn, spreadsheetID, credentialsPath, fossaToken, seed)
+			if incremental {
+				approved, err := db.ParseReimportCategories(approve)
+				if err != nil {
+					log.Fatalf("ERROR: --approve: %v", err)
+				}
+				if err := reimport(dbDriver, dsn, source, approved, actor, reportPath); err != nil {
+					log.Fatalf("re-import failed: %v", err)
+				}
+				return
+			}
+			_, err := db.BootstrapDB(dbDriver, dsn, source, fossaToken, seed)
 			if err != nil {
 				log.Fatalf("bootstrap failed: %v", err)
 			}
@@ -91,6 +107,12 @@ func main() {
 	rootCmd.Flags().StringVar(&dbDriver, "db-driver", "sqlite", "Database driver (sqlite or postgres)")
 	rootCmd.Flags().StringVar(&dbDSN, "db-dsn", "", "Database DSN (required for postgres)")
 	rootCmd.Flags().BoolVar(&seed, "seed", true, "Whether to load seed data into the database")
+	rootCmd.Flags().StringVar(&sourceKind, "source", "sheets", "Seed data source: sheets, csv (a directory with active.csv and staff.csv) or json")
+	rootCmd.Flags().StringVar(&sourcePath, "source-path", "", "Directory (csv) or file (json) to seed from")
+	rootCmd.Flags().BoolVar(&incremental, "incremental", false, "Compare the source with an existing database and report the differences instead of seeding")
+	rootCmd.Flags().StringVar(&approve, "approve", "", "With --incremental, comma-separated change categories to apply ("+categoryList()+") or all; none are applied by default")
+	rootCmd.Flags().StringVar(&reportPath, "report", "", "With --incremental, file to write the JSON change report to (default: stdout)")
+	rootCmd.Flags().StringVar(&actor, "actor", os.Getenv("USER"), "With --incremental, login recorded in the audit log for applied changes")
 	rootCmd.Flags().BoolVar(&doBackup, "backup", true, "Whether to create a backup of the database if it exists")
 	rootCmd.Flags().IntVar(&maxBackups, "max-backups", defaultMaxBackups, "Maximum number of backups to retain")
 
@@ -100,6 +122,96 @@ func main() {
 		log.Fatalf("command failed: %v", err)
 	}
 }
+
+// importSource returns the seed data source named by kind. The Google Sheets source needs a spreadsheet and
+// credentials from the environment; the csv and json sources read local files.
+func importSource(kind, path string) (db.ImportSource, error) {
+	switch kind {
+	case "sheets":
+		spreadsheetID := viper.GetString(spreadsheetEnvVar)
+		if spreadsheetID == "" {
+			return nil, fmt.Errorf("environment variable %s is not set", spreadsheetEnvVar)
+		}
+		credentialsPath := viper.GetString(googleWorkspaceCredentials)
+		if credentialsPath == "" {
+			return nil, fmt.Errorf("environment variable %s is not set", googleWorkspaceCredentials)
+		}
+		return db.NewSheetsSource(spreadsheetID, credentialsPath), nil
+	case "csv", "json":
+		if path == "" {
+			return nil, fmt.Errorf("--source-path is required with --source=%s", kind)
+		}
+		if _, err := os.Stat(path); err != nil {
+			return nil, err
+		}
+		if kind == "csv" {
+			return db.NewCSVDirSource(path), nil
+		}
+		return db.NewJSONSource(path), nil
+	default:
+		return nil, fmt.Errorf("unknown --source %q: use sheets, csv or json", kind)
+	}
+}
+
+// reimport compares the source's maintainer rows with the database, applies the approved categories of change and
+// writes the change report. With nothing approved it is a dry run.
+func reimport(driver, dsn string, source db.ImportSource, approved []db.ReimportCategory, actor, reportPath string) error {
+	database, err := db.BootstrapDB(driver, dsn, nil, "", false)
+	if err != nil {
+		return err
+	}
+	rows, err := source.MaintainerRows(context.Background())
+	if err != nil {
+		return err
+	}
+	store := db.NewSQLStore(database)
+	var report *db.ReimportReport
+	if len(approved) > 0 {
+		if actor == "" {
+			actor = "bootstrap"
+		}
+		report, err = store.WithActor(db.AuditActor{Login: actor, Role: "cli"}).ApplyReimport(rows, approved)
+	} else {
+		report, err = store.PlanReimport(rows)
+	}
+	if err != nil {
+		return err
+	}
+	for _, warning := range report.Warnings {
+		log.Printf("WARN: %s: %s", warning.Project, warning.Message)
+	}
+	outcome := "planned"
+	if report.Applied {
+		outcome = "applied"
+	}
+	var counts []string
+	for _, category := range db.ReimportCategories {
+		counts = append(counts, fmt.Sprintf("%s=%d", category, report.Counts[category]))
+	}
+	log.Printf("re-import %s: %s warnings=%d", outcome, strings.Join(counts, " "), len(report.Warnings))
+
+	out := os.Stdout
+	if reportPath != "" {
+		f, err := os.Create(reportPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+func categoryList() string {
+	names := make([]string, len(db.ReimportCategories))
+	for i, category := range db.ReimportCategories {
+		names[i] = string(category)
+	}
+	return strings.Join(names, ", ")
+}
+
 func copyFile(src, dst string) error {
 	sourceFileStat, err := os.Stat(src)
 	if err != nil {
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"strings"
+
+	"maintainerd/db"
+
+	"gorm.io/gorm"
+)
+
+const defaultDBPath = "/data/maintainers.db"
+
+func main() {
+	failOnConflict := flag.Bool("fail-on-conflict", false, "exit non-zero when conflicts are found")
+	flag.Parse()
+
+	dbDriver := envOr("MD_DB_DRIVER", "sqlite")
+	dbDSN := envOr("MD_DB_DSN", "")
+	dbPath := envOr("MD_DB_PATH", defaultDBPath)
+	if dbDriver == "postgres" && dbDSN == "" {
+		log.Fatal("MD_DB_DSN is required when MD_DB_DRIVER=postgres")
+	}
+	dsn := dbPath
+	if dbDriver == "postgres" {
+		dsn = dbDSN
+	}
+
+	dbConn, err := db.OpenGorm(dbDriver, dsn, &gorm.Config{})
+	if err != nil {
+		log.Fatalf("failed to open DB: %v", err)
+	}
+	store := db.NewSQLStore(dbConn)
+
+	conflicts, err := store.FindCompanyDomainConflicts()
+	if err != nil {
+		log.Fatalf("company domain check failed: %v", err)
+	}
+	for _, conflict := range conflicts {
+		log.Printf("maintainer %d (%s <%s>): recorded company %q (%d), email domain belongs to %q (%d)",
+			conflict.MaintainerID, conflict.MaintainerName, conflict.Email,
+			conflict.RecordedCompany, conflict.RecordedCompanyID,
+			conflict.DomainCompany, conflict.DomainCompanyID)
+	}
+	log.Printf("company domain check complete: conflicts=%d", len(conflicts))
+
+	if conflicts == nil {
+		conflicts = []db.CompanyDomainConflict{}
+	}
+	if err := json.NewEncoder(os.Stdout).Encode(conflicts); err != nil {
+		log.Fatalf("failed to write report: %v", err)
+	}
+	if *failOnConflict && len(conflicts) > 0 {
+		os.Exit(1)
+	}
+}
+
+func envOr(key, fallback string) string {
+	if v := strings.TrimSpace(os.Getenv(key)); v != "" {
+		return v
+	}
+	return fallback
+}
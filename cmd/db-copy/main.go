@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"maintainerd/db"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func main() {
+	from := flag.String("from", "", "source database as driver:dsn, e.g. sqlite:/data/maintainers.db")
+	to := flag.String("to", "", "destination database as driver:dsn, e.g. postgres:postgres://user@host/maintainerd")
+	batchSize := flag.Int("batch-size", 500, "rows read and written per transaction")
+	resume := flag.Bool("resume", false, "continue an interrupted copy instead of requiring an empty destination")
+	compare := flag.Bool("compare", false, "only compare row counts and checksums; write nothing")
+	flag.Parse()
+	if *from == "" || *to == "" {
+		log.Fatal("-from and -to are required")
+	}
+
+	src, err := openSpec(*from)
+	if err != nil {
+		log.Fatalf("failed to open source: %v", err)
+	}
+	dst, err := openSpec(*to)
+	if err != nil {
+		log.Fatalf("failed to open destination: %v", err)
+	}
+
+	report, err := db.CopyDatabase(src, dst, db.CopyOptions{
+		BatchSize:   *batchSize,
+		Resume:      *resume,
+		CompareOnly: *compare,
+		Progress: func(table string, copied, total int64) {
+			log.Printf("%s: %d/%d rows", table, copied, total)
+		},
+	})
+	for _, table := range report {
+		if !table.Match {
+			log.Printf("%s differs: source rows=%d checksum=%s, destination rows=%d checksum=%s",
+				table.Table, table.SourceRows, table.SourceChecksum, table.DestinationRows, table.DestinationChecksum)
+		}
+	}
+	if report != nil {
+		if err := json.NewEncoder(os.Stdout).Encode(report); err != nil {
+			log.Fatalf("failed to write report: %v", err)
+		}
+	}
+	switch {
+	case errors.Is(err, db.ErrDestinationNotEmpty):
+		log.Fatalf("%v; pass -resume to continue an interrupted copy", err)
+	case err != nil:
+		log.Fatalf("copy failed: %v", err)
+	case *compare:
+		log.Printf("databases match (%d tables)", len(report))
+	default:
+		log.Printf("copy complete and verified (%d tables)", len(report))
+	}
+}
+
+// openSpec opens a database named as driver:dsn.
+func openSpec(spec string) (*gorm.DB, error) {
+	driver, dsn, ok := strings.Cut(spec, ":")
+	if !ok || dsn == "" {
+		return nil, fmt.Errorf("%q: want driver:dsn", spec)
+	}
+	return db.OpenGorm(driver, dsn, &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"maintainerd/db"
+	"maintainerd/dotproject"
+	"maintainerd/export"
+
+	"gorm.io/gorm"
+)
+
+const defaultDBPath = "/data/maintainers.db"
+
+func main() {
+	format := flag.String("format", "json", "json, csv or dotproject")
+	table := flag.String("table", "", "table to write for csv to stdout: "+strings.Join(export.Tables, ", "))
+	out := flag.String("out", "-", "output file, or for csv and dotproject a directory to write one file per table or project (- for stdout)")
+	includeEmails := flag.Bool("include-emails", false, "include maintainer email addresses")
+	flag.Parse()
+
+	dbDriver := envOr("MD_DB_DRIVER", "sqlite")
+	dbDSN := envOr("MD_DB_DSN", "")
+	dbPath := envOr("MD_DB_PATH", defaultDBPath)
+	if dbDriver == "postgres" && dbDSN == "" {
+		log.Fatal("MD_DB_DSN is required when MD_DB_DRIVER=postgres")
+	}
+	dsn := dbPath
+	if dbDriver == "postgres" {
+		dsn = dbDSN
+	}
+
+	dbConn, err := db.OpenGorm(dbDriver, dsn, &gorm.Config{})
+	if err != nil {
+		log.Fatalf("failed to open DB: %v", err)
+	}
+	snapshot, err := export.Load(db.NewSQLStore(dbConn), export.Options{RedactEmails: !*includeEmails})
+	if err != nil {
+		log.Fatalf("export failed: %v", err)
+	}
+
+	switch *format {
+	case "json":
+		err = writeFile(*out, snapshot.WriteJSON)
+	case "csv":
+		if *out == "-" {
+			if *table == "" {
+				log.Fatal("-table is required when writing csv to stdout")
+			}
+			err = snapshot.WriteCSV(os.Stdout, *table)
+			break
+		}
+		for _, name := range export.Tables {
+			if err = writeFile(filepath.Join(*out, name+".csv"), func(w io.Writer) error { return snapshot.WriteCSV(w, name) }); err != nil {
+				break
+			}
+		}
+	case "dotproject":
+		projects := snapshot.DotProjects()
+		if *out == "-" {
+			err = export.WriteDotProjects(os.Stdout, projects)
+			break
+		}
+		for _, project := range projects {
+			path := filepath.Join(*out, project.Slug, ".project.yaml")
+			if err = writeFile(path, func(w io.Writer) error {
+				body, err := dotproject.Render(project)
+				if err != nil {
+					return err
+				}
+				_, err = w.Write(body)
+				return err
+			}); err != nil {
+				break
+			}
+		}
+	default:
+		log.Fatalf("unknown format %q: use json, csv or dotproject", *format)
+	}
+	if err != nil {
+		log.Fatalf("export failed: %v", err)
+	}
+	log.Printf("export complete: format=%s projects=%d maintainers=%d companies=%d memberships=%d serviceTeams=%d emailsRedacted=%t",
+		*format, len(snapshot.Projects), len(snapshot.Maintainers), len(snapshot.Companies),
+		len(snapshot.Memberships), len(snapshot.ServiceTeams), snapshot.EmailsRedacted)
+}
+
+// writeFile calls write with path opened for writing, creating parent directories, or with stdout for "-".
+func writeFile(path string, write func(w io.Writer) error) error {
+	if path == "-" {
+		return write(os.Stdout)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func envOr(key, fallback string) string {
+	if v := strings.TrimSpace(os.Getenv(key)); v != "" {
+		return v
+	}
+	return fallback
+}
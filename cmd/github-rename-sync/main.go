@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"maintainerd/db"
+
+	"github.com/google/go-github/v55/github"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+)
+
+const defaultDBPath = "/data/maintainers.db"
+
+// githubResolver resolves GitHub user IDs to their current login using the REST API.
+type githubResolver struct {
+	client *github.Client
+}
+
+func (r githubResolver) LoginByID(ctx context.Context, id int64) (string, error) {
+	user, resp, err := r.client.Users.GetByID(ctx, id)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return "", db.ErrGitHubUserNotFound
+		}
+		var rateErr *github.RateLimitError
+		if errors.As(err, &rateErr) {
+			wait := time.Until(rateErr.Rate.Reset.Time)
+			log.Printf("rate limited, waiting %s", wait.Truncate(time.Second))
+			select {
+			case <-ctx.Done():
+				return "", ctx.Err()
+			case <-time.After(wait):
+			}
+			return r.LoginByID(ctx, id)
+		}
+		return "", err
+	}
+	return user.GetLogin(), nil
+}
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "report renames without updating records")
+	flag.Parse()
+
+	ctx := context.Background()
+
+	dbDriver := envOr("MD_DB_DRIVER", "sqlite")
+	dbDSN := envOr("MD_DB_DSN", "")
+	dbPath := envOr("MD_DB_PATH", defaultDBPath)
+	if dbDriver == "postgres" && dbDSN == "" {
+		log.Fatal("MD_DB_DSN is required when MD_DB_DRIVER=postgres")
+	}
+	dsn := dbPath
+	if dbDriver == "postgres" {
+		dsn = dbDSN
+	}
+
+	githubToken := strings.TrimSpace(os.Getenv("GITHUB_API_TOKEN"))
+	if githubToken == "" {
+		log.Fatal("GITHUB_API_TOKEN is required")
+	}
+
+	dbConn, err := db.OpenGorm(dbDriver, dsn, &gorm.Config{})
+	if err != nil {
+		log.Fatalf("failed to open DB: %v", err)
+	}
+	store := db.NewSQLStore(dbConn)
+
+	resolver := githubResolver{client: github.NewClient(oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{
+		AccessToken: githubToken,
+	})))}
+
+	report, err := store.DetectGitHubRenames(ctx, resolver, *dryRun)
+	if err != nil {
+		log.Fatalf("rename detection failed: %v", err)
+	}
+	for _, rename := range report.Renamed {
+		log.Printf("%s %d: @%s -> @%s (github id %d)", rename.Kind, rename.RecordID, rename.From, rename.To, rename.GitHubID)
+	}
+	for _, missing := range report.Missing {
+		log.Printf("%s %d: github id %d (@%s) no longer exists", missing.Kind, missing.RecordID, missing.GitHubID, missing.From)
+	}
+	for _, failure := range report.Failures {
+		log.Printf("error: %s", failure)
+	}
+	log.Printf("rename detection complete: checked=%d renamed=%d missing=%d failures=%d dryRun=%t",
+		report.Checked, len(report.Renamed), len(report.Missing), len(report.Failures), *dryRun)
+
+	if err := json.NewEncoder(os.Stdout).Encode(report); err != nil {
+		log.Fatalf("failed to write report: %v", err)
+	}
+	if len(report.Failures) > 0 {
+		os.Exit(1)
+	}
+}
+
+func envOr(key, fallback string) string {
+	if v := strings.TrimSpace(os.Getenv(key)); v != "" {
+		return v
+	}
+	return fallback
+}
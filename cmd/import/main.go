@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"maintainerd/db"
+
+	"gorm.io/gorm"
+)
+
+const defaultDBPath = "/data/maintainers.db"
+
+func main() {
+	file := flag.String("file", "", "CSV or YAML file of project, name, email, github and company rows (- for stdin)")
+	format := flag.String("format", "", "csv or yaml (default: from the file extension)")
+	apply := flag.Bool("apply", false, "apply the import; without it only the dry-run plan is printed")
+	actor := flag.String("actor", envOr("USER", "import"), "login recorded in the audit log for applied changes")
+	flag.Parse()
+	if *file == "" {
+		log.Fatal("-file is required")
+	}
+
+	dbDriver := envOr("MD_DB_DRIVER", "sqlite")
+	dbDSN := envOr("MD_DB_DSN", "")
+	dbPath := envOr("MD_DB_PATH", defaultDBPath)
+	if dbDriver == "postgres" && dbDSN == "" {
+		log.Fatal("MD_DB_DSN is required when MD_DB_DRIVER=postgres")
+	}
+	dsn := dbPath
+	if dbDriver == "postgres" {
+		dsn = dbDSN
+	}
+
+	var input io.Reader = os.Stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			log.Fatalf("failed to open %s: %v", *file, err)
+		}
+		defer f.Close()
+		input = f
+	}
+	if *format == "" {
+		*format = strings.TrimPrefix(filepath.Ext(*file), ".")
+	}
+	rows, err := db.ParseImportRows(*format, input)
+	if err != nil {
+		log.Fatalf("failed to read import: %v", err)
+	}
+
+	dbConn, err := db.OpenGorm(dbDriver, dsn, &gorm.Config{})
+	if err != nil {
+		log.Fatalf("failed to open DB: %v", err)
+	}
+	store := db.NewSQLStore(dbConn)
+
+	var plan *db.ImportPlan
+	if *apply {
+		plan, err = store.WithActor(db.AuditActor{Login: *actor, Role: "cli"}).ApplyImport(rows)
+	} else {
+		plan, err = store.PlanImport(rows)
+	}
+	if err != nil && !errors.Is(err, db.ErrImportConflicts) {
+		log.Fatalf("import failed: %v", err)
+	}
+	for _, row := range plan.Rows {
+		if row.Action == db.ImportConflict {
+			log.Printf("line %d: %s", row.Line, row.Reason)
+		}
+	}
+	outcome := "planned"
+	if plan.Applied {
+		outcome = "applied"
+	}
+	log.Printf("import %s: rows=%d creates=%d updates=%d unchanged=%d conflicts=%d",
+		outcome, len(plan.Rows), plan.Creates, plan.Updates, plan.Unchanged, plan.Conflicts)
+
+	if err := json.NewEncoder(os.Stdout).Encode(plan); err != nil {
+		log.Fatalf("failed to write plan: %v", err)
+	}
+	// Conflicts fail a dry run too, so scripts can check a file before applying it.
+	if plan.Conflicts > 0 {
+		os.Exit(1)
+	}
+}
+
+func envOr(key, fallback string) string {
+	if v := strings.TrimSpace(os.Getenv(key)); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -2,23 +2,42 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
 	"log"
 	"os"
 	"strings"
 	"time"
 
 	"maintainerd/db"
-	"maintainerd/model"
 
 	"gorm.io/gorm"
 )
 
 const defaultDBPath = "/data/maintainers.db"
 
+const usage = `usage: migrate [up [-to N] | down [-to N] | status]
+
+  up      apply pending migrations, all of them or up to version N (default)
+  down    roll back the latest migration, or every migration after version N
+  status  list migrations and whether they are applied, as JSON
+`
+
 func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
+	command := "up"
+	args := os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+	flags := flag.NewFlagSet("migrate "+command, flag.ExitOnError)
+	flags.Usage = func() { fmt.Fprint(flags.Output(), usage) }
+	to := flags.Int("to", -1, "target migration version")
+	_ = flags.Parse(args)
+
 	dbDriver := envOr("MD_DB_DRIVER", "sqlite")
 	dbDSN := envOr("MD_DB_DSN", "")
 	dbPath := envOr("MD_DB_PATH", defaultDBPath)
@@ -36,73 +55,92 @@ func main() {
 	}
 	store := db.NewSQLStore(dbConn)
 
-	if err := migrate(ctx, store); err != nil {
-		log.Fatalf("migrate failed: %v", err)
+	switch command {
+	case "up":
+		if err := migrateUp(ctx, store, max(*to, 0)); err != nil {
+			log.Fatalf("migrate failed: %v", err)
+		}
+		log.Println("migration complete")
+	case "down":
+		if err := migrateDown(store, *to); err != nil {
+			log.Fatalf("migrate down failed: %v", err)
+		}
+	case "status":
+		statuses, err := db.MigrationStatuses(store.DB())
+		if err != nil {
+			log.Fatalf("migrate status failed: %v", err)
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(statuses); err != nil {
+			log.Fatalf("failed to write status: %v", err)
+		}
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
 	}
-
-	log.Println("migration complete")
 }
 
-func migrate(_ context.Context, store *db.SQLStore) error {
-	if err := store.DB().AutoMigrate(
-		&model.AuditLog{},
-		&model.Collaborator{},
-		&model.Company{},
-		&model.Foundation{},
-		&model.FoundationOfficer{},
-		&model.StaffMember{},
-		&model.Maintainer{},
-		&model.MaintainerProject{},
-		&model.Project{},
-		&model.Service{},
-		&model.ServiceTeam{},
-		&model.ServiceUser{},
-		&model.MaintainerRefCache{},
-	); err != nil {
+// migrateUp applies pending migrations, then backfills data that older builds left incomplete.
+func migrateUp(_ context.Context, store *db.SQLStore, target int) error {
+	applied, err := db.MigrateUp(store.DB(), target)
+	for _, migration := range applied {
+		log.Printf("applied migration %d %s", migration.Version, migration.Name)
+	}
+	if err != nil {
 		return err
 	}
 
-	if store.DB().Name() != "postgres" {
-		return nil
+	backfilled, err := store.BackfillMembershipHistory()
+	if err != nil {
+		return fmt.Errorf("backfill membership history: %w", err)
+	}
+	if backfilled > 0 {
+		log.Printf("backfilled status and history for %d memberships", backfilled)
 	}
 
-	extensions := []string{
-		"CREATE EXTENSION IF NOT EXISTS pg_trgm",
-		"CREATE EXTENSION IF NOT EXISTS unaccent",
-		`CREATE OR REPLACE FUNCTION unaccent_immutable(text)
-		 RETURNS text
-		 LANGUAGE sql
-		 IMMUTABLE
-		 PARALLEL SAFE
-		 AS $$ SELECT unaccent($1); $$`,
+	aliases, err := store.BackfillCompanyAliases()
+	if err != nil {
+		return fmt.Errorf("backfill company aliases: %w", err)
 	}
-	for _, stmt := range extensions {
-		if err := store.DB().Exec(stmt).Error; err != nil {
-			return err
-		}
+	if aliases > 0 {
+		log.Printf("backfilled %d company aliases from earlier merges", aliases)
 	}
 
-	indexes := []string{
-		"CREATE INDEX IF NOT EXISTS idx_projects_name_trgm ON projects USING gin (lower(name) gin_trgm_ops)",
-		"CREATE INDEX IF NOT EXISTS idx_projects_ref_trgm ON projects USING gin (lower(maintainer_ref) gin_trgm_ops)",
-		"CREATE INDEX IF NOT EXISTS idx_projects_dotref_trgm ON projects USING gin (lower(dot_project_yaml_ref) gin_trgm_ops)",
-		"CREATE INDEX IF NOT EXISTS idx_projects_github_org_trgm ON projects USING gin (lower(git_hub_org) gin_trgm_ops)",
-		"CREATE INDEX IF NOT EXISTS idx_maintainers_name_trgm ON maintainers USING gin (lower(name) gin_trgm_ops)",
-		"CREATE INDEX IF NOT EXISTS idx_maintainers_email_trgm ON maintainers USING gin (lower(email) gin_trgm_ops)",
-		"CREATE INDEX IF NOT EXISTS idx_maintainers_github_trgm ON maintainers USING gin (lower(git_hub_account) gin_trgm_ops)",
-		"CREATE INDEX IF NOT EXISTS idx_companies_name_trgm ON companies USING gin (lower(name) gin_trgm_ops)",
-		"ALTER TABLE maintainers ADD COLUMN IF NOT EXISTS search_tsv tsvector GENERATED ALWAYS AS (to_tsvector('simple', unaccent_immutable(coalesce(name, '') || ' ' || coalesce(email, '') || ' ' || coalesce(git_hub_account, '')))) STORED",
-		"ALTER TABLE projects ADD COLUMN IF NOT EXISTS search_tsv tsvector GENERATED ALWAYS AS (to_tsvector('simple', unaccent_immutable(coalesce(name, '') || ' ' || coalesce(maintainer_ref, '') || ' ' || coalesce(dot_project_yaml_ref, '') || ' ' || coalesce(git_hub_org, '')))) STORED",
-		"CREATE INDEX IF NOT EXISTS idx_maintainers_search_tsv ON maintainers USING gin (search_tsv)",
-		"CREATE INDEX IF NOT EXISTS idx_projects_search_tsv ON projects USING gin (search_tsv)",
-	}
-	for _, stmt := range indexes {
-		if err := store.DB().Exec(stmt).Error; err != nil {
+	links, err := store.BackfillCollaboratorProjects()
+	if err != nil {
+		return fmt.Errorf("backfill collaborator projects: %w", err)
+	}
+	if links > 0 {
+		log.Printf("backfilled %d collaborator projects from service teams", links)
+	}
+	return nil
+}
+
+// migrateDown rolls back to target, or only the latest applied migration when target is negative. Rolling back
+// migration 1 drops every table, so it has to be asked for with -to 0.
+func migrateDown(store *db.SQLStore, target int) error {
+	if target < 0 {
+		statuses, err := db.MigrationStatuses(store.DB())
+		if err != nil {
 			return err
 		}
+		target = -1
+		for i := len(statuses) - 1; i >= 0 && target < 0; i-- {
+			if statuses[i].Applied {
+				target = statuses[i].Version - 1
+			}
+		}
+		if target <= 0 {
+			log.Println("nothing to roll back; pass -to 0 to drop the baseline schema")
+			return nil
+		}
 	}
-
-	return nil
+	rolledBack, err := db.MigrateDown(store.DB(), target)
+	for _, migration := range rolledBack {
+		log.Printf("rolled back migration %d %s", migration.Version, migration.Name)
+	}
+	return err
 }
 
 func envOr(key, fallback string) string {
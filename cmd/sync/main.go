@@ -4,16 +4,21 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"maps"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	apis "maintainerd/apis/maintainers/v1alpha1"
 	"maintainerd/db"
+	"maintainerd/model"
 
 	"gorm.io/gorm"
 
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -105,8 +110,10 @@ func syncAll(ctx context.Context, store *db.SQLStore, c client.Client, ns string
 	return nil
 }
 
+// syncStaff mirrors staff members into StaffMember resources. Deactivated members' resources are deleted, which
+// removes them from the workspace staff bindings.
 func syncStaff(ctx context.Context, store *db.SQLStore, c client.Client, ns string) error {
-	staffMembers, err := store.ListStaffMembers()
+	staffMembers, err := store.ListAllStaffMembers()
 	if err != nil {
 		return err
 	}
@@ -122,6 +129,18 @@ func syncStaff(ctx context.Context, store *db.SQLStore, c client.Client, ns stri
 		obj := &apis.StaffMember{}
 		key := client.ObjectKey{Name: name, Namespace: ns}
 		err := c.Get(ctx, key, obj)
+		if staff.DeactivatedAt != nil {
+			if err == nil {
+				if err := c.Delete(ctx, obj); err != nil && !errors.IsNotFound(err) {
+					return fmt.Errorf("delete staffmember %s: %w", name, err)
+				}
+				continue
+			}
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
 		var registeredAt *metav1.Time
 		if staff.RegisteredAt != nil {
 			t := metav1.NewTime(*staff.RegisteredAt)
@@ -160,20 +179,35 @@ func syncStaff(ctx context.Context, store *db.SQLStore, c client.Client, ns stri
 	return nil
 }
 
+// companyIDLabel carries the database ID of the company a Company resource mirrors. Company resources are named
+// after the company, so the label is how resources left behind by renamed or deleted companies are found.
+const companyIDLabel = "maintainer-d.cncf.io/company-id"
+
+// syncCompanies mirrors companies into Company resources. Labelled resources that no longer match a company, because
+// it was renamed, merged or deleted, are deleted.
 func syncCompanies(ctx context.Context, store *db.SQLStore, c client.Client, ns string) error {
 	companies, err := store.ListCompanies()
 	if err != nil {
 		return err
 	}
+	current := make(map[string]bool, len(companies))
 	for _, comp := range companies {
 		obj := &apis.Company{}
 		name := sanitizeName(comp.Name)
+		current[name] = true
+		id := strconv.FormatUint(uint64(comp.ID), 10)
 		key := client.ObjectKey{Name: name, Namespace: ns}
+		spec := apis.CompanySpec{
+			DisplayName: comp.Name,
+			Website:     comp.Website,
+			Notes:       comp.Notes,
+			Tags:        comp.Tags,
+		}
 		err := c.Get(ctx, key, obj)
 		if errors.IsNotFound(err) {
 			obj = &apis.Company{
-				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns},
-				Spec:       apis.CompanySpec{DisplayName: comp.Name},
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns, Labels: map[string]string{companyIDLabel: id}},
+				Spec:       spec,
 			}
 			if err := c.Create(ctx, obj); err != nil {
 				return fmt.Errorf("create company %s: %w", name, err)
@@ -183,16 +217,62 @@ func syncCompanies(ctx context.Context, store *db.SQLStore, c client.Client, ns
 		if err != nil {
 			return err
 		}
-		if obj.Spec.DisplayName != comp.Name {
-			obj.Spec.DisplayName = comp.Name
+		if relabelled := setLabel(obj, companyIDLabel, id); relabelled || !companySpecEqual(obj.Spec, spec) {
+			obj.Spec = spec
 			if err := c.Update(ctx, obj); err != nil {
 				return fmt.Errorf("update company %s: %w", name, err)
 			}
 		}
 	}
+	if err := pruneUnsynced(ctx, c, ns, &apis.CompanyList{}, companyIDLabel, current); err != nil {
+		return fmt.Errorf("prune companies: %w", err)
+	}
+	return nil
+}
+
+// setLabel sets the label key on obj to value and reports whether that changed it.
+func setLabel(obj metav1.Object, key, value string) bool {
+	labels := obj.GetLabels()
+	if current, ok := labels[key]; ok && current == value {
+		return false
+	}
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[key] = value
+	obj.SetLabels(labels)
+	return true
+}
+
+// pruneUnsynced deletes the resources of list's kind that carry label but are not named in keep: resources an
+// earlier sync created for records that were since renamed, merged or removed.
+func pruneUnsynced(ctx context.Context, c client.Client, ns string, list client.ObjectList, label string, keep map[string]bool) error {
+	if err := c.List(ctx, list, client.InNamespace(ns), client.HasLabels{label}); err != nil {
+		return err
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		obj, ok := item.(client.Object)
+		if !ok || keep[obj.GetName()] {
+			continue
+		}
+		if err := c.Delete(ctx, obj); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("delete %s: %w", obj.GetName(), err)
+		}
+	}
 	return nil
 }
 
+func companySpecEqual(a, b apis.CompanySpec) bool {
+	return a.DisplayName == b.DisplayName &&
+		a.Website == b.Website &&
+		a.Notes == b.Notes &&
+		maps.Equal(a.Tags, b.Tags)
+}
+
 func syncMaintainers(ctx context.Context, store *db.SQLStore, c client.Client, ns string) error {
 	mByEmail, err := store.GetMaintainerMapByEmail()
 	if err != nil {
@@ -249,17 +329,22 @@ func syncMaintainers(ctx context.Context, store *db.SQLStore, c client.Client, n
 	return nil
 }
 
+// syncProjects mirrors projects into Project resources named after the project. Labelled resources that no longer
+// match a project, such as the one for a project's name before a rename, are deleted.
 func syncProjects(ctx context.Context, store *db.SQLStore, c client.Client, ns string) error {
-	projectsByName, err := store.GetProjectMapByName()
+	projects, err := store.ListProjectsWithMaintainers()
 	if err != nil {
 		return err
 	}
-	parentNameByID := make(map[uint]string, len(projectsByName))
-	for _, p := range projectsByName {
+	parentNameByID := make(map[uint]string, len(projects))
+	for _, p := range projects {
 		parentNameByID[p.ID] = p.Name
 	}
-	for _, p := range projectsByName {
+	current := make(map[string]bool, len(projects))
+	for _, p := range projects {
 		name := sanitizeName(p.Name)
+		current[name] = true
+		id := strconv.FormatUint(uint64(p.ID), 10)
 		obj := &apis.Project{}
 		key := client.ObjectKey{Name: name, Namespace: ns}
 		spec := apis.ProjectSpec{
@@ -287,7 +372,7 @@ func syncProjects(ctx context.Context, store *db.SQLStore, c client.Client, ns s
 		err := c.Get(ctx, key, obj)
 		if errors.IsNotFound(err) {
 			obj = &apis.Project{
-				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns},
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns, Labels: map[string]string{projectIDLabel: id}},
 				Spec:       spec,
 			}
 			if err := c.Create(ctx, obj); err != nil {
@@ -298,55 +383,118 @@ func syncProjects(ctx context.Context, store *db.SQLStore, c client.Client, ns s
 		if err != nil {
 			return err
 		}
-		if !projectSpecEqual(obj.Spec, spec) {
+		if relabelled := setLabel(obj, projectIDLabel, id); relabelled || !projectSpecEqual(obj.Spec, spec) {
 			obj.Spec = spec
 			if err := c.Update(ctx, obj); err != nil {
 				return fmt.Errorf("update project %s: %w", name, err)
 			}
 		}
 	}
+	if err := pruneUnsynced(ctx, c, ns, &apis.ProjectList{}, projectIDLabel, current); err != nil {
+		return fmt.Errorf("prune projects: %w", err)
+	}
 	return nil
 }
 
+// Labels carrying the database IDs of the maintainer and project a resource mirrors. Resources are named after
+// project names and maintainer emails, so the labels are how resources left behind by renames, removals and merges
+// are found. ProjectMembership resources carry both; Project resources carry projectIDLabel.
+const (
+	maintainerIDLabel = "maintainer-d.cncf.io/maintainer-id"
+	projectIDLabel    = "maintainer-d.cncf.io/project-id"
+)
+
+// syncMemberships mirrors memberships into ProjectMembership resources. Labelled resources that no longer match a
+// membership are deleted, which takes away the workspace access they granted.
 func syncMemberships(ctx context.Context, store *db.SQLStore, c client.Client, ns string) error {
-	projectsByName, err := store.GetProjectMapByName()
+	memberships, err := store.ListMemberships()
 	if err != nil {
 		return err
 	}
-	for _, p := range projectsByName {
-		for _, m := range p.Maintainers {
-			name := sanitizeName(fmt.Sprintf("%s-%s", p.Name, m.Email))
-			obj := &apis.ProjectMembership{}
-			key := client.ObjectKey{Name: name, Namespace: ns}
-			spec := apis.ProjectMembershipSpec{
-				ProjectRef:    apis.ResourceReference{Name: sanitizeName(p.Name)},
-				MaintainerRef: apis.ResourceReference{Name: sanitizeName(m.Email)},
-			}
-			err := c.Get(ctx, key, obj)
-			if errors.IsNotFound(err) {
-				obj = &apis.ProjectMembership{
-					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns},
-					Spec:       spec,
-				}
-				if err := c.Create(ctx, obj); err != nil {
-					return fmt.Errorf("create membership %s: %w", name, err)
-				}
-				continue
+	current := make(map[string]bool, len(memberships))
+	for _, membership := range memberships {
+		p, m := membership.Project, membership.Maintainer
+		if p.ID == 0 || m.ID == 0 {
+			continue // the project or maintainer is deleted, so the resource is pruned below
+		}
+		name := sanitizeName(fmt.Sprintf("%s-%s", p.Name, m.Email))
+		current[name] = true
+		labels := map[string]string{
+			maintainerIDLabel: strconv.FormatUint(uint64(m.ID), 10),
+			projectIDLabel:    strconv.FormatUint(uint64(p.ID), 10),
+		}
+		obj := &apis.ProjectMembership{}
+		key := client.ObjectKey{Name: name, Namespace: ns}
+		spec := membershipSpec(membership)
+		err := c.Get(ctx, key, obj)
+		if errors.IsNotFound(err) {
+			obj = &apis.ProjectMembership{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: ns, Labels: labels},
+				Spec:       spec,
 			}
-			if err != nil {
-				return err
+			if err := c.Create(ctx, obj); err != nil {
+				return fmt.Errorf("create membership %s: %w", name, err)
 			}
-			if obj.Spec.ProjectRef.Name != spec.ProjectRef.Name || obj.Spec.MaintainerRef.Name != spec.MaintainerRef.Name {
-				obj.Spec = spec
-				if err := c.Update(ctx, obj); err != nil {
-					return fmt.Errorf("update membership %s: %w", name, err)
-				}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		relabelled := setLabel(obj, maintainerIDLabel, labels[maintainerIDLabel])
+		relabelled = setLabel(obj, projectIDLabel, labels[projectIDLabel]) || relabelled
+		if relabelled || !membershipSpecEqual(obj.Spec, spec) {
+			spec.Notes = obj.Spec.Notes
+			obj.Spec = spec
+			if err := c.Update(ctx, obj); err != nil {
+				return fmt.Errorf("update membership %s: %w", name, err)
 			}
 		}
 	}
+	if err := pruneUnsynced(ctx, c, ns, &apis.ProjectMembershipList{}, maintainerIDLabel, current); err != nil {
+		return fmt.Errorf("prune memberships: %w", err)
+	}
 	return nil
 }
 
+// membershipSpec maps a membership to its CRD spec. Roles carries the membership role, plus the membership status
+// when it is not Active, so an Emeritus lead is ["lead", "emeritus"].
+func membershipSpec(membership model.MaintainerProject) apis.ProjectMembershipSpec {
+	role := membership.Role
+	if role == "" {
+		role = model.MaintainerRole
+	}
+	roles := []string{string(role)}
+	if membership.Status != "" && membership.Status != model.ActiveMaintainer {
+		roles = append(roles, strings.ToLower(string(membership.Status)))
+	}
+	// CRD timestamps have second precision; truncate so unchanged memberships compare equal.
+	joinedAt := metav1.NewTime(membership.JoinedAt.UTC().Truncate(time.Second))
+	return apis.ProjectMembershipSpec{
+		ProjectRef:    apis.ResourceReference{Name: sanitizeName(membership.Project.Name)},
+		MaintainerRef: apis.ResourceReference{Name: sanitizeName(membership.Maintainer.Email)},
+		Roles:         roles,
+		JoinedAt:      &joinedAt,
+	}
+}
+
+func membershipSpecEqual(a, b apis.ProjectMembershipSpec) bool {
+	if a.ProjectRef.Name != b.ProjectRef.Name || a.MaintainerRef.Name != b.MaintainerRef.Name {
+		return false
+	}
+	if (a.JoinedAt == nil) != (b.JoinedAt == nil) || (a.JoinedAt != nil && !a.JoinedAt.Equal(b.JoinedAt)) {
+		return false
+	}
+	if len(a.Roles) != len(b.Roles) {
+		return false
+	}
+	for i := range a.Roles {
+		if a.Roles[i] != b.Roles[i] {
+			return false
+		}
+	}
+	return true
+}
+
 func sanitizeName(s string) string {
 	if s == "" {
 		return "unnamed"
@@ -6,6 +6,7 @@ import (
 	"log"
 	"time"
 
+	mdb "maintainerd/db"
 	"maintainerd/model"
 
 	"gorm.io/driver/sqlite"
@@ -24,23 +25,8 @@ func main() {
 		log.Fatalf("seed: failed to open db: %v", err)
 	}
 
-	if err := db.AutoMigrate(
-		&model.AuditLog{},
-		&model.Company{},
-		&model.Foundation{},
-		&model.Project{},
-		&model.Maintainer{},
-		&model.StaffMember{},
-		&model.FoundationOfficer{},
-		&model.Collaborator{},
-		&model.MaintainerProject{},
-		&model.MaintainerRefCache{},
-		&model.Service{},
-		&model.ServiceTeam{},
-		&model.ServiceUser{},
-		&model.ServiceUserTeams{},
-	); err != nil {
-		log.Fatalf("seed: auto-migrate failed: %v", err)
+	if _, err := mdb.MigrateUp(db, 0); err != nil {
+		log.Fatalf("seed: schema migration failed: %v", err)
 	}
 
 	company := model.Company{Name: "Example Labs"}
@@ -59,6 +45,7 @@ func main() {
 		GitHubEmail:   "staff.tester@github.test",
 		FoundationID:  &foundation.ID,
 		RegisteredAt:  timePtr(time.Now()),
+		Admin:         true,
 	}
 	if err := db.FirstOrCreate(&staff, model.StaffMember{GitHubAccount: staff.GitHubAccount}).Error; err != nil {
 		log.Fatalf("seed: staff insert failed: %v", err)
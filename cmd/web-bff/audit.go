@@ -0,0 +1,373 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"maintainerd/db"
+	"maintainerd/export"
+	"maintainerd/model"
+)
+
+const auditExportBatchSize = 500
+
+// auditedStore returns the store to write through on behalf of sess, so every change is recorded with the
+// session user as the actor.
+func (s *server) auditedStore(sess *session) *db.AuditedStore {
+	actor := db.AuditActor{Login: sess.Login, Role: sess.Role}
+	switch sess.Role {
+	case roleStaff:
+		var staff model.StaffMember
+		if err := s.store.DB().
+			Where("LOWER(git_hub_account) = ?", strings.ToLower(sess.Login)).
+			First(&staff).Error; err == nil {
+			actor.StaffID = &staff.ID
+			actor.Name = staff.Name
+		}
+	case roleMaintainer:
+		if maintainer, err := s.getSessionMaintainer(sess); err == nil {
+			actor.MaintainerID = &maintainer.ID
+			actor.Name = strings.TrimSpace(maintainer.Name)
+		}
+	}
+	return s.store.WithActor(actor)
+}
+
+type auditLogResponse struct {
+	ID             uint      `json:"id"`
+	Action         string    `json:"action"`
+	Message        string    `json:"message"`
+	Metadata       string    `json:"metadata,omitempty"`
+	CreatedAt      time.Time `json:"createdAt"`
+	ProjectID      *uint     `json:"projectId,omitempty"`
+	ProjectName    string    `json:"projectName,omitempty"`
+	MaintainerID   *uint     `json:"maintainerId,omitempty"`
+	MaintainerName string    `json:"maintainerName,omitempty"`
+	ServiceID      *uint     `json:"serviceId,omitempty"`
+	ServiceName    string    `json:"serviceName,omitempty"`
+	StaffID        *uint     `json:"staffId,omitempty"`
+	StaffName      string    `json:"staffName,omitempty"`
+	StaffLogin     string    `json:"staffLogin,omitempty"`
+	RevertOfID     *uint     `json:"revertOfId,omitempty"`
+}
+
+type auditListResponse struct {
+	Total int64              `json:"total"`
+	Logs  []auditLogResponse `json:"logs"`
+}
+
+var auditCSVHeader = []string{
+	"id", "created_at", "action", "message",
+	"project_id", "project_name",
+	"maintainer_id", "maintainer_name",
+	"service_id", "service_name",
+	"staff_id", "staff_name", "staff_login",
+	"metadata",
+}
+
+// handleAudit lists audit entries, newest first. Supported filters (all optional, combinable):
+// projectId, maintainerId, staffId, serviceId (comma-separated IDs), action (comma-separated),
+// from/to (RFC 3339 or YYYY-MM-DD; to is exclusive, a bare date includes that whole day) and q (message search).
+func (s *server) handleAudit(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	session := sessionFromContext(r.Context())
+	if session == nil || session.Role != roleStaff {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	filter, err := parseAuditFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	limit := parseIntParam(r, "limit", 20, 1, 200)
+	offset := parseIntParam(r, "offset", 0, 0, 10_000_000)
+
+	logs, total, err := s.store.ListAuditLogs(filter, limit, offset)
+	if err != nil {
+		s.logger.Printf("web-bff: handleAudit list error: %v", err)
+		http.Error(w, "failed to load audit logs", http.StatusInternalServerError)
+		return
+	}
+	names, err := s.store.ResolveAuditNames(logs)
+	if err != nil {
+		s.logger.Printf("web-bff: handleAudit resolve names error: %v", err)
+	}
+
+	response := auditListResponse{
+		Total: total,
+		Logs:  make([]auditLogResponse, 0, len(logs)),
+	}
+	for _, logEntry := range logs {
+		response.Logs = append(response.Logs, toAuditLogResponse(logEntry, names))
+	}
+
+	w.Header().Set(headerContentType, contentTypeJSON)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		s.logger.Printf("web-bff: handleAudit encode error: %v", err)
+	}
+}
+
+// handleAuditEntry serves /api/audit/{id}/revert, which undoes the change recorded by an audit entry.
+func (s *server) handleAuditEntry(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if !strings.HasSuffix(r.URL.Path, "/revert") {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id, err := parseIDParam(strings.TrimSuffix(r.URL.Path, "/revert"), "/api/audit/")
+	if err != nil {
+		http.Error(w, "invalid audit id", http.StatusBadRequest)
+		return
+	}
+	session := sessionFromContext(r.Context())
+	if session == nil || session.Role != roleStaff {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	reverts, err := s.auditedStore(session).RevertAuditEntry(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrAuditEntryNotFound):
+			http.Error(w, "audit entry not found", http.StatusNotFound)
+		case errors.Is(err, db.ErrRevertConflict), errors.Is(err, db.ErrAlreadyReverted):
+			http.Error(w, err.Error(), http.StatusConflict)
+		case errors.Is(err, db.ErrRevertUnsupported):
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		default:
+			s.logger.Printf("web-bff: revert audit entry failed id=%d err=%v", id, err)
+			http.Error(w, "failed to revert change", http.StatusInternalServerError)
+		}
+		return
+	}
+	s.invalidateOnboardingCache()
+
+	names, err := s.store.ResolveAuditNames(reverts)
+	if err != nil {
+		s.logger.Printf("web-bff: revert audit entry resolve names error: %v", err)
+	}
+	response := make([]auditLogResponse, 0, len(reverts))
+	for _, entry := range reverts {
+		response = append(response, toAuditLogResponse(entry, names))
+	}
+	w.Header().Set(headerContentType, contentTypeJSON)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		s.logger.Printf("web-bff: handleAuditEntry encode error: %v", err)
+	}
+}
+
+// handleAuditExport streams every audit entry matching the handleAudit filters, oldest first, as CSV
+// (format=csv, the default) or JSON lines (format=jsonl).
+func (s *server) handleAuditExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	session := sessionFromContext(r.Context())
+	if session == nil || session.Role != roleStaff {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	filter, err := parseAuditFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	format := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("format")))
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "jsonl" {
+		http.Error(w, "format must be csv or jsonl", http.StatusBadRequest)
+		return
+	}
+
+	filename := fmt.Sprintf("audit-%s.%s", time.Now().UTC().Format("20060102T150405Z"), format)
+	if format == "csv" {
+		w.Header().Set(headerContentType, "text/csv; charset=utf-8")
+	} else {
+		w.Header().Set(headerContentType, "application/x-ndjson")
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	s.logger.Printf("web-bff: audit export user=%s format=%s filter=%+v", session.Login, format, filter)
+
+	flusher, _ := w.(http.Flusher)
+	csvWriter := csv.NewWriter(w)
+	jsonEncoder := json.NewEncoder(w)
+	if format == "csv" {
+		if err := csvWriter.Write(auditCSVHeader); err != nil {
+			s.logger.Printf("web-bff: audit export write error: %v", err)
+			return
+		}
+	}
+	exported := 0
+	err = s.store.StreamAuditLogs(r.Context(), filter, auditExportBatchSize, func(batch []model.AuditLog) error {
+		names, err := s.store.ResolveAuditNames(batch)
+		if err != nil {
+			return err
+		}
+		for _, entry := range batch {
+			item := toAuditLogResponse(entry, names)
+			if format == "csv" {
+				if err := csvWriter.Write(auditCSVRecord(item)); err != nil {
+					return err
+				}
+			} else if err := jsonEncoder.Encode(item); err != nil {
+				return err
+			}
+		}
+		exported += len(batch)
+		if format == "csv" {
+			csvWriter.Flush()
+			if err := csvWriter.Error(); err != nil {
+				return err
+			}
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		// Headers are already sent; the truncated body is the only signal left for the client.
+		s.logger.Printf("web-bff: audit export failed after %d rows: %v", exported, err)
+		return
+	}
+	s.logger.Printf("web-bff: audit export complete user=%s rows=%d", session.Login, exported)
+}
+
+func toAuditLogResponse(entry model.AuditLog, names db.AuditNames) auditLogResponse {
+	item := auditLogResponse{
+		ID:           entry.ID,
+		Action:       entry.Action,
+		Message:      entry.Message,
+		Metadata:     entry.Metadata,
+		CreatedAt:    entry.CreatedAt,
+		ProjectID:    entry.ProjectID,
+		MaintainerID: entry.MaintainerID,
+		ServiceID:    entry.ServiceID,
+		StaffID:      entry.StaffID,
+		RevertOfID:   entry.RevertOfID,
+	}
+	if entry.ProjectID != nil {
+		item.ProjectName = names.Projects[*entry.ProjectID]
+	}
+	if entry.MaintainerID != nil {
+		item.MaintainerName = names.Maintainers[*entry.MaintainerID]
+	}
+	if entry.ServiceID != nil {
+		item.ServiceName = names.Services[*entry.ServiceID]
+	}
+	if entry.Staff != nil {
+		item.StaffName = entry.Staff.Name
+		item.StaffLogin = entry.Staff.GitHubAccount
+	}
+	return item
+}
+
+func auditCSVRecord(item auditLogResponse) []string {
+	optionalID := func(id *uint) string {
+		if id == nil {
+			return ""
+		}
+		return strconv.FormatUint(uint64(*id), 10)
+	}
+	return []string{
+		strconv.FormatUint(uint64(item.ID), 10),
+		item.CreatedAt.UTC().Format(time.RFC3339),
+		export.CSVCell(item.Action),
+		export.CSVCell(item.Message),
+		optionalID(item.ProjectID), export.CSVCell(item.ProjectName),
+		optionalID(item.MaintainerID), export.CSVCell(item.MaintainerName),
+		optionalID(item.ServiceID), export.CSVCell(item.ServiceName),
+		optionalID(item.StaffID), export.CSVCell(item.StaffName), export.CSVCell(item.StaffLogin),
+		export.CSVCell(item.Metadata),
+	}
+}
+
+func parseAuditFilter(r *http.Request) (db.AuditFilter, error) {
+	var filter db.AuditFilter
+	var err error
+	if filter.ProjectIDs, err = parseIDListParam(r, "projectId"); err != nil {
+		return filter, err
+	}
+	if filter.MaintainerIDs, err = parseIDListParam(r, "maintainerId"); err != nil {
+		return filter, err
+	}
+	if filter.StaffIDs, err = parseIDListParam(r, "staffId"); err != nil {
+		return filter, err
+	}
+	if filter.ServiceIDs, err = parseIDListParam(r, "serviceId"); err != nil {
+		return filter, err
+	}
+	filter.Actions = parseCSVParam(r, "action")
+	if filter.From, err = parseAuditTime(r.URL.Query().Get("from"), false); err != nil {
+		return filter, fmt.Errorf("invalid from: %w", err)
+	}
+	if filter.To, err = parseAuditTime(r.URL.Query().Get("to"), true); err != nil {
+		return filter, fmt.Errorf("invalid to: %w", err)
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return filter, fmt.Errorf("from must be before to")
+	}
+	filter.Query = strings.TrimSpace(r.URL.Query().Get("q"))
+	return filter, nil
+}
+
+func parseIDListParam(r *http.Request, key string) ([]uint, error) {
+	var ids []uint
+	for _, raw := range parseCSVParam(r, key) {
+		value, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil || value == 0 {
+			return nil, fmt.Errorf("invalid %s %q", key, raw)
+		}
+		ids = append(ids, uint(value))
+	}
+	return ids, nil
+}
+
+// parseAuditTime accepts RFC 3339 timestamps or YYYY-MM-DD dates (UTC). For an exclusive upper bound a bare date
+// is moved to the start of the following day so the named day is included.
+func parseAuditTime(raw string, upper bool) (*time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	if parsed, err := time.Parse(time.RFC3339, raw); err == nil {
+		return &parsed, nil
+	}
+	parsed, err := time.Parse("2006-01-02", raw)
+	if err != nil {
+		return nil, fmt.Errorf("expected RFC 3339 or YYYY-MM-DD, got %q", raw)
+	}
+	if upper {
+		parsed = parsed.AddDate(0, 0, 1)
+	}
+	return &parsed, nil
+}
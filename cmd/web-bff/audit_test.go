@@ -0,0 +1,69 @@
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAuditFilter(t *testing.T) {
+	req := httptest.NewRequest("GET", "/api/audit?projectId=3,4&maintainerId=7&staffId=1&serviceId=2&action=MAINTAINER_UPDATE,project_create&from=2025-01-01&to=2025-03-31&q=kubernetes+lead", nil)
+	filter, err := parseAuditFilter(req)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{3, 4}, filter.ProjectIDs)
+	assert.Equal(t, []uint{7}, filter.MaintainerIDs)
+	assert.Equal(t, []uint{1}, filter.StaffIDs)
+	assert.Equal(t, []uint{2}, filter.ServiceIDs)
+	assert.Equal(t, []string{"MAINTAINER_UPDATE", "project_create"}, filter.Actions)
+	require.NotNil(t, filter.From)
+	require.NotNil(t, filter.To)
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), *filter.From)
+	assert.Equal(t, time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), *filter.To, "a bare to-date includes the whole day")
+	assert.Equal(t, "kubernetes lead", filter.Query)
+
+	req = httptest.NewRequest("GET", "/api/audit?from=2025-02-01T10:00:00Z", nil)
+	filter, err = parseAuditFilter(req)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 2, 1, 10, 0, 0, 0, time.UTC), *filter.From)
+	assert.Nil(t, filter.To)
+
+	for _, bad := range []string{
+		"/api/audit?projectId=abc",
+		"/api/audit?maintainerId=0",
+		"/api/audit?from=yesterday",
+		"/api/audit?from=2025-03-01&to=2025-02-01",
+	} {
+		_, err := parseAuditFilter(httptest.NewRequest("GET", bad, nil))
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestAuditCSVRecord(t *testing.T) {
+	projectID := uint(5)
+	record := auditCSVRecord(auditLogResponse{
+		ID:          9,
+		Action:      "PROJECT_MATURITY_UPDATE",
+		Message:     "Maturity changed",
+		CreatedAt:   time.Date(2025, 5, 6, 7, 8, 9, 0, time.UTC),
+		ProjectID:   &projectID,
+		ProjectName: "Kubernetes",
+		StaffName:   "Sam",
+		StaffLogin:  "sam",
+		Metadata:    `{"changes":{}}`,
+	})
+	require.Len(t, record, len(auditCSVHeader))
+	assert.Equal(t, []string{
+		"9", "2025-05-06T07:08:09Z", "PROJECT_MATURITY_UPDATE", "Maturity changed",
+		"5", "Kubernetes", "", "", "", "", "", "Sam", "sam", `{"changes":{}}`,
+	}, record)
+
+	record = auditCSVRecord(auditLogResponse{Message: "=HYPERLINK(\"https://evil.example\")", MaintainerName: "@bob", StaffName: "-1+2", StaffLogin: "\tsam"})
+	assert.Equal(t, `'=HYPERLINK("https://evil.example")`, record[3])
+	assert.Equal(t, "'@bob", record[7])
+	assert.Equal(t, "'-1+2", record[11])
+	assert.Equal(t, "'\tsam", record[12])
+	assert.Equal(t, "", record[2], "empty cells stay empty")
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"maintainerd/db"
+	"maintainerd/model"
+)
+
+type collaboratorProject struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+type collaboratorResponse struct {
+	ID            uint                  `json:"id"`
+	Name          string                `json:"name"`
+	Email         string                `json:"email,omitempty"`
+	GitHubAccount string                `json:"githubAccount,omitempty"`
+	Projects      []collaboratorProject `json:"projects"`
+}
+
+// collaboratorRequest is the body of POST /api/collaborators and PATCH /api/collaborators/{id}. On PATCH only
+// fields present are changed, and projectIds replaces the collaborator's projects.
+type collaboratorRequest struct {
+	Name          *string `json:"name"`
+	Email         *string `json:"email"`
+	GitHubAccount *string `json:"githubAccount"`
+	ProjectIDs    *[]uint `json:"projectIds"`
+}
+
+type promoteCollaboratorRequest struct {
+	ProjectIDs []uint `json:"projectIds"`
+}
+
+func newCollaboratorResponse(collaborator model.Collaborator) collaboratorResponse {
+	resp := collaboratorResponse{
+		ID:       collaborator.ID,
+		Name:     collaborator.Name,
+		Projects: make([]collaboratorProject, 0, len(collaborator.Projects)),
+	}
+	if collaborator.Email != "EMAIL_MISSING" {
+		resp.Email = collaborator.Email
+	}
+	if collaborator.GitHubAccount != nil && *collaborator.GitHubAccount != "GITHUB_MISSING" {
+		resp.GitHubAccount = *collaborator.GitHubAccount
+	}
+	for _, project := range collaborator.Projects {
+		resp.Projects = append(resp.Projects, collaboratorProject{ID: project.ID, Name: project.Name})
+	}
+	return resp
+}
+
+// handleCollaborators serves GET /api/collaborators, optionally filtered by projectId, and POST, which adds a
+// collaborator. Staff only.
+func (s *server) handleCollaborators(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	session := sessionFromContext(r.Context())
+	if session == nil || session.Role != roleStaff {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		var projectID uint
+		if raw := strings.TrimSpace(r.URL.Query().Get("projectId")); raw != "" {
+			value, err := strconv.ParseUint(raw, 10, 64)
+			if err != nil || value == 0 {
+				http.Error(w, "invalid projectId", http.StatusBadRequest)
+				return
+			}
+			projectID = uint(value)
+		}
+		collaborators, err := s.store.ListCollaborators(projectID)
+		if err != nil {
+			s.logger.Printf("web-bff: handleCollaborators list error: %v", err)
+			http.Error(w, "failed to load collaborators", http.StatusInternalServerError)
+			return
+		}
+		resp := make([]collaboratorResponse, 0, len(collaborators))
+		for _, collaborator := range collaborators {
+			resp = append(resp, newCollaboratorResponse(collaborator))
+		}
+		w.Header().Set(headerContentType, contentTypeJSON)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			s.logger.Printf("web-bff: handleCollaborators encode error: %v", err)
+		}
+	case http.MethodPost:
+		var req collaboratorRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Name == nil {
+			http.Error(w, "name is required", http.StatusBadRequest)
+			return
+		}
+		input := db.CollaboratorInput{Name: *req.Name}
+		if req.Email != nil {
+			input.Email = *req.Email
+		}
+		if req.GitHubAccount != nil {
+			input.GitHubAccount = *req.GitHubAccount
+		}
+		if req.ProjectIDs != nil {
+			input.ProjectIDs = *req.ProjectIDs
+		}
+		collaborator, err := s.auditedStore(session).CreateCollaborator(input)
+		if err != nil {
+			s.writeCollaboratorError(w, "create", err)
+			return
+		}
+		w.Header().Set(headerContentType, contentTypeJSON)
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(newCollaboratorResponse(*collaborator)); err != nil {
+			s.logger.Printf("web-bff: handleCollaborators encode error: %v", err)
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleCollaborator serves GET and PATCH on /api/collaborators/{id} and POST /api/collaborators/{id}/promote,
+// which makes the collaborator a maintainer of the given projects (their own projects by default) and moves
+// their service team links to the maintainer. Staff only.
+func (s *server) handleCollaborator(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	idPart, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/collaborators/"), "/")
+	id, err := parseIDParam(idPart, "")
+	if err != nil {
+		http.Error(w, "invalid collaborator id", http.StatusBadRequest)
+		return
+	}
+	session := sessionFromContext(r.Context())
+	if session == nil || session.Role != roleStaff {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	if action == "promote" {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		var req promoteCollaboratorRequest
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "invalid request", http.StatusBadRequest)
+				return
+			}
+		}
+		promotion, err := s.auditedStore(session).PromoteCollaborator(id, req.ProjectIDs)
+		if err != nil {
+			s.writeCollaboratorError(w, "promote", err)
+			return
+		}
+		w.Header().Set(headerContentType, contentTypeJSON)
+		if err := json.NewEncoder(w).Encode(promotion); err != nil {
+			s.logger.Printf("web-bff: handleCollaborator encode error: %v", err)
+		}
+		return
+	}
+	if action != "" {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	var collaborator *model.Collaborator
+	switch r.Method {
+	case http.MethodGet:
+		collaborator, err = s.store.GetCollaborator(id)
+	case http.MethodPatch:
+		var req collaboratorRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+		collaborator, err = s.auditedStore(session).UpdateCollaborator(id, db.CollaboratorUpdate{
+			Name:          req.Name,
+			Email:         req.Email,
+			GitHubAccount: req.GitHubAccount,
+			ProjectIDs:    req.ProjectIDs,
+		})
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err != nil {
+		s.writeCollaboratorError(w, strings.ToLower(r.Method), err)
+		return
+	}
+	w.Header().Set(headerContentType, contentTypeJSON)
+	if err := json.NewEncoder(w).Encode(newCollaboratorResponse(*collaborator)); err != nil {
+		s.logger.Printf("web-bff: handleCollaborator encode error: %v", err)
+	}
+}
+
+func (s *server) writeCollaboratorError(w http.ResponseWriter, op string, err error) {
+	switch {
+	case errors.Is(err, db.ErrCollaboratorNotFound):
+		http.Error(w, "collaborator not found", http.StatusNotFound)
+	case errors.Is(err, db.ErrProjectNotFound):
+		http.Error(w, "project not found", http.StatusBadRequest)
+	case errors.Is(err, db.ErrCollaboratorExists), errors.Is(err, db.ErrAlreadyMaintainer):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, db.ErrCollaboratorIdentity), errors.Is(err, db.ErrNoProjects):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		s.logger.Printf("web-bff: collaborator %s error: %v", op, err)
+		http.Error(w, "failed to update collaborator", http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"maintainerd/db"
+	"maintainerd/model"
+)
+
+type companyDomainRequest struct {
+	Domain string `json:"domain"`
+}
+
+type companyDomainsResponse struct {
+	CompanyID uint     `json:"companyId"`
+	Domains   []string `json:"domains"`
+}
+
+// parseCompanyDomainsPath splits /api/companies/{id}/domains[/{domain}] into the company ID and optional domain.
+func parseCompanyDomainsPath(path string) (uint, string, error) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(path, "/api/companies/"), "/"), "/")
+	if len(parts) < 2 || len(parts) > 3 || parts[1] != "domains" {
+		return 0, "", fmt.Errorf("invalid company domains path")
+	}
+	id, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil || id == 0 {
+		return 0, "", fmt.Errorf("invalid company id")
+	}
+	domain := ""
+	if len(parts) == 3 {
+		domain = parts[2]
+	}
+	return uint(id), domain, nil
+}
+
+// handleCompanyDomains serves /api/companies/{id}/domains: GET lists the company's email domains, POST adds one and
+// DELETE /api/companies/{id}/domains/{domain} removes one.
+func (s *server) handleCompanyDomains(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	companyID, domain, err := parseCompanyDomainsPath(r.URL.Path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	session := sessionFromContext(r.Context())
+	if session == nil || session.Role != roleStaff {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	if err := s.store.DB().Select("id").First(&model.Company{}, companyID).Error; err != nil {
+		http.Error(w, "company not found", http.StatusNotFound)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		var req companyDomainRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+		if _, err := s.auditedStore(session).AddCompanyDomain(companyID, req.Domain); err != nil {
+			switch {
+			case errors.Is(err, db.ErrInvalidDomain), errors.Is(err, db.ErrFreemailDomain):
+				http.Error(w, err.Error(), http.StatusBadRequest)
+			case errors.Is(err, db.ErrDomainClaimed):
+				http.Error(w, err.Error(), http.StatusConflict)
+			default:
+				s.logger.Printf("web-bff: add company domain failed company=%d domain=%q err=%v", companyID, req.Domain, err)
+				http.Error(w, "failed to add domain", http.StatusInternalServerError)
+			}
+			return
+		}
+	case http.MethodDelete:
+		if domain == "" {
+			http.Error(w, "domain is required", http.StatusBadRequest)
+			return
+		}
+		removed, err := s.auditedStore(session).RemoveCompanyDomain(companyID, domain)
+		if err != nil {
+			if errors.Is(err, db.ErrInvalidDomain) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			s.logger.Printf("web-bff: remove company domain failed company=%d domain=%q err=%v", companyID, domain, err)
+			http.Error(w, "failed to remove domain", http.StatusInternalServerError)
+			return
+		}
+		if !removed {
+			http.Error(w, "domain not found", http.StatusNotFound)
+			return
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	domains, err := s.companyDomains(companyID)
+	if err != nil {
+		s.logger.Printf("web-bff: list company domains failed company=%d err=%v", companyID, err)
+		http.Error(w, "failed to load domains", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set(headerContentType, contentTypeJSON)
+	if err := json.NewEncoder(w).Encode(companyDomainsResponse{CompanyID: companyID, Domains: domains}); err != nil {
+		s.logger.Printf("web-bff: handleCompanyDomains encode error: %v", err)
+	}
+}
+
+// handleCompanyDomainConflicts serves GET /api/companies/domain-conflicts: maintainers whose email domain belongs to
+// a company other than the one recorded for them.
+func (s *server) handleCompanyDomainConflicts(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	session := sessionFromContext(r.Context())
+	if session == nil || session.Role != roleStaff {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	conflicts, err := s.store.FindCompanyDomainConflicts()
+	if err != nil {
+		s.logger.Printf("web-bff: company domain conflicts failed: %v", err)
+		http.Error(w, "failed to check company domains", http.StatusInternalServerError)
+		return
+	}
+	if conflicts == nil {
+		conflicts = []db.CompanyDomainConflict{}
+	}
+	w.Header().Set(headerContentType, contentTypeJSON)
+	if err := json.NewEncoder(w).Encode(conflicts); err != nil {
+		s.logger.Printf("web-bff: handleCompanyDomainConflicts encode error: %v", err)
+	}
+}
+
+func (s *server) companyDomains(companyID uint) ([]string, error) {
+	rows, err := s.store.ListCompanyDomains(companyID)
+	if err != nil {
+		return nil, err
+	}
+	domains := make([]string, 0, len(rows))
+	for _, row := range rows {
+		domains = append(domains, row.Domain)
+	}
+	return domains, nil
+}
+
+// suggestedCompany returns the company owning the maintainer's email domain when it differs from the recorded one.
+func (s *server) suggestedCompany(maintainer *model.Maintainer) *companyResponse {
+	company, err := s.store.InferCompanyFromEmail(maintainer.Email)
+	if err != nil {
+		s.logger.Printf("web-bff: company inference failed maintainer=%d err=%v", maintainer.ID, err)
+		return nil
+	}
+	if company == nil || (maintainer.CompanyID != nil && *maintainer.CompanyID == company.ID) {
+		return nil
+	}
+	return &companyResponse{ID: company.ID, Name: company.Name}
+}
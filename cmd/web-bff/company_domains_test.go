@@ -0,0 +1,25 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCompanyDomainsPath(t *testing.T) {
+	id, domain, err := parseCompanyDomainsPath("/api/companies/7/domains")
+	require.NoError(t, err)
+	assert.Equal(t, uint(7), id)
+	assert.Empty(t, domain)
+
+	id, domain, err = parseCompanyDomainsPath("/api/companies/7/domains/example.com")
+	require.NoError(t, err)
+	assert.Equal(t, uint(7), id)
+	assert.Equal(t, "example.com", domain)
+
+	for _, path := range []string{"/api/companies/7", "/api/companies/x/domains", "/api/companies/7/aliases"} {
+		_, _, err := parseCompanyDomainsPath(path)
+		assert.Error(t, err, path)
+	}
+}
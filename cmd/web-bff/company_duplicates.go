@@ -0,0 +1,38 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"maintainerd/db"
+)
+
+// handleCompanyMergeSuggestions serves GET /api/companies?duplicates=fuzzy: pairs of companies with similar names,
+// best matches first. threshold (0-1] sets the minimum similarity and limit caps the number of pairs returned.
+func (s *server) handleCompanyMergeSuggestions(w http.ResponseWriter, r *http.Request) {
+	threshold := db.DefaultCompanySimilarityThreshold
+	if raw := r.URL.Query().Get("threshold"); raw != "" {
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil || value <= 0 || value > 1 {
+			http.Error(w, "threshold must be between 0 and 1", http.StatusBadRequest)
+			return
+		}
+		threshold = value
+	}
+	limit := parseIntParam(r, "limit", 100, 1, 1000)
+
+	suggestions, err := s.store.FindCompanyMergeSuggestions(threshold)
+	if err != nil {
+		s.logger.Printf("web-bff: company merge suggestions failed: %v", err)
+		http.Error(w, "failed to find duplicate companies", http.StatusInternalServerError)
+		return
+	}
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	w.Header().Set(headerContentType, contentTypeJSON)
+	if err := json.NewEncoder(w).Encode(suggestions); err != nil {
+		s.logger.Printf("web-bff: handleCompanyMergeSuggestions encode error: %v", err)
+	}
+}
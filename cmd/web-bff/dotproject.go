@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"maintainerd/db"
+	"maintainerd/dotproject"
+
+	"github.com/google/go-github/v55/github"
+	"golang.org/x/oauth2"
+)
+
+// dotProjectBranchPrefix names the branches .project.yaml pull requests are opened from, one per project.
+const dotProjectBranchPrefix = "maintainer-d/"
+
+// dotProjectPRRequest is the body of POST /api/projects/{id}/dotproject/pr. File is needed only when the
+// project has no DotProjectYamlRef.
+type dotProjectPRRequest struct {
+	File string `json:"file"`
+}
+
+type dotProjectPullRequest struct {
+	Number  int    `json:"number"`
+	URL     string `json:"url"`
+	Created bool   `json:"created"`
+}
+
+type dotProjectResponse struct {
+	ProjectID   uint                   `json:"projectId"`
+	File        string                 `json:"file,omitempty"`
+	Base        string                 `json:"base,omitempty"`
+	Branch      string                 `json:"branch,omitempty"`
+	Exists      bool                   `json:"exists"`
+	Changed     bool                   `json:"changed"`
+	Content     string                 `json:"content"`
+	Diff        string                 `json:"diff,omitempty"`
+	Summary     []string               `json:"summary,omitempty"`
+	PullRequest *dotProjectPullRequest `json:"pullRequest,omitempty"`
+}
+
+// isDotProjectPath reports whether path is one of the .project.yaml endpoints under /api/projects/{id}.
+func isDotProjectPath(path string) bool {
+	path = strings.TrimRight(path, "/")
+	return strings.HasSuffix(path, "/dotproject") || strings.HasSuffix(path, "/dotproject/pr")
+}
+
+// handleDotProject serves GET /api/projects/{id}/dotproject, the .project.yaml generated from the project's records
+// and its diff against the file at DotProjectYamlRef (or the file query parameter), and POST
+// /api/projects/{id}/dotproject/pr, which commits the generated file to a branch and opens or updates a pull
+// request for it (staff only). Generated files never include email addresses, since they land in public
+// repositories.
+func (s *server) handleDotProject(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	idPart, action, _ := strings.Cut(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/projects/"), "/"), "/")
+	id, err := parseIDParam(idPart, "")
+	if err != nil {
+		http.Error(w, "invalid project id", http.StatusBadRequest)
+		return
+	}
+	openPR := strings.TrimRight(action, "/") == "dotproject/pr"
+	if (openPR && r.Method != http.MethodPost) || (!openPR && r.Method != http.MethodGet) {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	session := sessionFromContext(r.Context())
+	if session == nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if session.Role == roleMaintainer && !openPR {
+		if _, err := s.getSessionMaintainer(session); err != nil {
+			s.logger.Printf("web-bff: maintainer access denied project=%d user=%s role=%s reason=%v", id, session.Login, session.Role, err)
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+	} else if session.Role != roleStaff {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	file := r.URL.Query().Get("file")
+	if openPR {
+		var req dotProjectPRRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+		file = req.File
+	}
+
+	src, err := dotproject.LoadSource(s.store, id, true)
+	if err != nil {
+		if errors.Is(err, db.ErrProjectNotFound) {
+			http.Error(w, "project not found", http.StatusNotFound)
+			return
+		}
+		s.logger.Printf("web-bff: dotproject load error project=%d err=%v", id, err)
+		http.Error(w, "failed to load project", http.StatusInternalServerError)
+		return
+	}
+	project := dotproject.Build(src)
+	if strings.TrimSpace(file) == "" {
+		file = src.Project.DotProjectYamlRef
+	}
+
+	var proposal *dotproject.Proposal
+	if strings.TrimSpace(file) == "" {
+		if openPR {
+			http.Error(w, "project has no dotProjectYamlRef; pass file with the target URL", http.StatusBadRequest)
+			return
+		}
+		// Without a target file there is nothing to compare against, so return the generated content alone.
+		content, err := dotproject.Render(project)
+		if err != nil {
+			s.logger.Printf("web-bff: dotproject render error project=%d err=%v", id, err)
+			http.Error(w, "failed to render project", http.StatusInternalServerError)
+			return
+		}
+		proposal = &dotproject.Proposal{Content: content, Changed: true}
+	} else {
+		ref, err := dotproject.ParseFileRef(file)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		client := s.dotProjectClient(r.Context())
+		if client == nil {
+			s.logger.Printf("web-bff: dotproject error: github api token not configured")
+			http.Error(w, "github api token not configured", http.StatusInternalServerError)
+			return
+		}
+		if openPR {
+			proposal, err = dotproject.Propose(r.Context(), client, ref, project, dotProjectBranchPrefix+project.Slug)
+		} else {
+			proposal, err = dotproject.Preview(r.Context(), client, ref, project)
+		}
+		if err != nil {
+			s.logger.Printf("web-bff: dotproject error project=%d file=%s pr=%t user=%s err=%v", id, ref, openPR, session.Login, err)
+			if errors.Is(err, dotproject.ErrNotFound) {
+				http.Error(w, "repository not found on GitHub", http.StatusNotFound)
+				return
+			}
+			http.Error(w, "failed to reach GitHub", http.StatusBadGateway)
+			return
+		}
+	}
+
+	response := dotProjectResponse{
+		ProjectID: id,
+		Base:      proposal.Base,
+		Branch:    proposal.Branch,
+		Exists:    proposal.Exists,
+		Changed:   proposal.Changed,
+		Content:   string(proposal.Content),
+		Diff:      proposal.Diff,
+		Summary:   proposal.Summary,
+	}
+	if proposal.File.Repo != "" {
+		response.File = proposal.File.String()
+	}
+	if proposal.PullRequest != nil {
+		response.PullRequest = &dotProjectPullRequest{
+			Number:  proposal.PullRequest.Number,
+			URL:     proposal.PullRequest.URL,
+			Created: proposal.PullRequestCreated,
+		}
+		s.logger.Printf("web-bff: dotproject pull request project=%d user=%s pr=%s created=%t", id, session.Login, proposal.PullRequest.URL, proposal.PullRequestCreated)
+	}
+	w.Header().Set(headerContentType, contentTypeJSON)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		s.logger.Printf("web-bff: dotproject encode error: %v", err)
+	}
+}
+
+// dotProjectClient returns the GitHub client .project.yaml pull requests go through, or nil when no token is
+// configured.
+func (s *server) dotProjectClient(ctx context.Context) dotproject.GitHubClient {
+	if s.newDotProjectClient != nil {
+		return s.newDotProjectClient(ctx)
+	}
+	if s.githubToken == "" {
+		return nil
+	}
+	return dotproject.NewGitHubClient(github.NewClient(oauth2.NewClient(ctx, oauth2.StaticTokenSource(&oauth2.Token{
+		AccessToken: s.githubToken,
+	}))))
+}
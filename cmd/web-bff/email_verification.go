@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+
+	"maintainerd/db"
+	"maintainerd/emailverify"
+	"maintainerd/model"
+
+	"gorm.io/gorm"
+)
+
+// verifyEmailPage asks the maintainer to confirm the verification. The emailed link only shows this page, so mail
+// scanners and prefetchers that follow links cannot verify an address on their own; the form posts the token back.
+var verifyEmailPage = template.Must(template.New("verify-email").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="robots" content="noindex">
+<title>Verify your email address</title>
+</head>
+<body>
+<p>Confirm that {{.Email}} is your email address for maintainer-d.</p>
+<form method="post">
+<input type="hidden" name="token" value="{{.Token}}">
+<button type="submit">Verify email address</button>
+</form>
+</body>
+</html>
+`))
+
+// handleVerifyEmail consumes the signed link emailed to a maintainer. GET shows a confirmation page and POST marks
+// the address as verified. It is reachable without a session because the token itself proves control of the mailbox.
+func (s *server) handleVerifyEmail(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.emailVerifier == nil {
+		http.Error(w, "email verification is not configured", http.StatusServiceUnavailable)
+		return
+	}
+	token := r.URL.Query().Get("token")
+	if r.Method == http.MethodPost {
+		token = r.PostFormValue("token")
+	}
+	claims, err := s.emailVerifier.Signer.Parse(token, time.Now())
+	if err != nil {
+		s.logger.Printf("web-bff: verify email rejected token err=%v ip=%s", err, clientIP(r))
+		if errors.Is(err, emailverify.ErrExpiredToken) {
+			http.Error(w, "verification link expired", http.StatusGone)
+			return
+		}
+		http.Error(w, "invalid verification link", http.StatusBadRequest)
+		return
+	}
+	if r.Method == http.MethodGet {
+		// The token is in the URL, so keep it out of caches and Referer headers.
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Referrer-Policy", "no-referrer")
+		w.Header().Set(headerContentType, "text/html; charset=utf-8")
+		if err := verifyEmailPage.Execute(w, map[string]string{"Email": claims.Email, "Token": token}); err != nil {
+			s.logger.Printf("web-bff: verify email page render error: %v", err)
+		}
+		return
+	}
+	maintainerID := claims.MaintainerID
+	// The token proves the maintainer followed the link, so the change is attributed to them.
+	store := s.store.WithActor(db.AuditActor{Role: roleMaintainer, MaintainerID: &maintainerID})
+	if err := store.MarkMaintainerEmailVerified(maintainerID, claims.Email, time.Now()); err != nil {
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			http.Error(w, "maintainer not found", http.StatusNotFound)
+		case errors.Is(err, db.ErrEmailMismatch):
+			http.Error(w, "email address has changed since this link was sent", http.StatusConflict)
+		default:
+			s.logger.Printf("web-bff: verify email failed maintainer=%d err=%v", claims.MaintainerID, err)
+			http.Error(w, "failed to verify email", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("%s/maintainers/%d?emailVerified=1", s.webBaseURL, maintainerID), http.StatusSeeOther)
+}
+
+// handleMaintainerEmailVerification emails a fresh verification link to a maintainer. Staff may trigger it for
+// anyone; maintainers only for themselves.
+func (s *server) handleMaintainerEmailVerification(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	id, err := parseIDParam(strings.TrimSuffix(r.URL.Path, "/verify-email"), "/api/maintainers/")
+	if err != nil {
+		http.Error(w, "invalid maintainer id", http.StatusBadRequest)
+		return
+	}
+	session := sessionFromContext(r.Context())
+	if session == nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	switch session.Role {
+	case roleStaff:
+	case roleMaintainer:
+		requester, err := s.getSessionMaintainer(session)
+		if err != nil || requester.ID != id {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+	default:
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	if s.emailVerifier == nil {
+		http.Error(w, "email verification is not configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	var maintainer model.Maintainer
+	if err := s.store.DB().First(&maintainer, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			http.Error(w, "maintainer not found", http.StatusNotFound)
+			return
+		}
+		s.logger.Printf("web-bff: verification email lookup failed id=%d err=%v", id, err)
+		http.Error(w, "failed to send verification email", http.StatusInternalServerError)
+		return
+	}
+	if normalizeValue(strings.TrimSpace(maintainer.Email), "EMAIL_MISSING") == "" {
+		http.Error(w, "maintainer has no email address", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set(headerContentType, contentTypeJSON)
+	if maintainer.EmailVerifiedAt != nil {
+		if err := json.NewEncoder(w).Encode(map[string]string{"status": "already_verified"}); err != nil {
+			s.logger.Printf("web-bff: handleMaintainerEmailVerification encode error: %v", err)
+		}
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
+	defer cancel()
+	if err := s.emailVerifier.Send(ctx, maintainer); err != nil {
+		s.logger.Printf("web-bff: verification email send failed id=%d err=%v", id, err)
+		http.Error(w, "failed to send verification email", http.StatusBadGateway)
+		return
+	}
+	if err := s.store.MarkEmailVerificationSent(id, time.Now()); err != nil {
+		s.logger.Printf("web-bff: verification email sent time not recorded id=%d err=%v", id, err)
+	}
+
+	metadata, err := json.Marshal(map[string]any{
+		"actor": map[string]string{
+			"login": session.Login,
+			"role":  session.Role,
+		},
+	})
+	if err != nil {
+		s.logger.Printf("web-bff: verification email audit metadata encode error: %v", err)
+	}
+	event := model.AuditLog{
+		MaintainerID: &id,
+		StaffID:      lookupStaffID(s.store, session.Login),
+		Action:       "MAINTAINER_EMAIL_VERIFICATION_SENT",
+		Message:      fmt.Sprintf("Email verification link sent by %s", session.Login),
+		Metadata:     string(metadata),
+	}
+	if err := s.store.AppendAuditLog(&event); err != nil {
+		s.logger.Printf("web-bff: verification email audit log failed: %v", err)
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(map[string]string{"status": "sent"}); err != nil {
+		s.logger.Printf("web-bff: handleMaintainerEmailVerification encode error: %v", err)
+	}
+}
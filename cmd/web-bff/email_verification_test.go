@@ -0,0 +1,81 @@
+package main
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"maintainerd/db"
+	"maintainerd/emailverify"
+	"maintainerd/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyEmailRequiresConfirmation(t *testing.T) {
+	dbConn := setupPostgresTestDB(t)
+	store := db.NewSQLStore(dbConn)
+
+	alice := model.Maintainer{
+		Name:             "Alice Example",
+		Email:            "alice@example.org",
+		GitHubAccount:    "alice-example",
+		MaintainerStatus: model.ActiveMaintainer,
+	}
+	require.NoError(t, dbConn.Create(&alice).Error)
+
+	signer, err := emailverify.NewSigner([]byte("0123456789abcdef0123456789abcdef"), time.Hour)
+	require.NoError(t, err)
+	token, err := signer.Issue(alice.ID, alice.Email, time.Now())
+	require.NoError(t, err)
+
+	s := &server{
+		store:         store,
+		logger:        log.New(io.Discard, "", 0),
+		webBaseURL:    "https://web.example.org",
+		emailVerifier: &emailverify.Verifier{Signer: signer, BaseURL: "https://bff.example.org"},
+	}
+
+	t.Run("GET shows the confirmation page without verifying", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, emailverify.VerifyPath+"?token="+url.QueryEscape(token), nil)
+		rec := httptest.NewRecorder()
+		s.handleVerifyEmail(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `<form method="post">`)
+		assert.Contains(t, rec.Body.String(), "alice@example.org")
+		assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+
+		var stored model.Maintainer
+		require.NoError(t, dbConn.First(&stored, alice.ID).Error)
+		assert.Nil(t, stored.EmailVerifiedAt)
+	})
+
+	t.Run("GET rejects an invalid token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, emailverify.VerifyPath+"?token=bogus", nil)
+		rec := httptest.NewRecorder()
+		s.handleVerifyEmail(rec, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("POST verifies and redirects", func(t *testing.T) {
+		form := url.Values{"token": {token}}
+		req := httptest.NewRequest(http.MethodPost, emailverify.VerifyPath, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		s.handleVerifyEmail(rec, req)
+
+		require.Equal(t, http.StatusSeeOther, rec.Code)
+		assert.Contains(t, rec.Header().Get("Location"), "emailVerified=1")
+
+		var stored model.Maintainer
+		require.NoError(t, dbConn.First(&stored, alice.ID).Error)
+		assert.NotNil(t, stored.EmailVerifiedAt)
+	})
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"maintainerd/dotproject"
+	"maintainerd/export"
+)
+
+// handleExport serves GET /api/export, a snapshot of projects, maintainers, companies, memberships and service
+// teams. format is json (default), csv with a table parameter naming one table, or dotproject for .project.yaml
+// documents, optionally limited to one project by id or name with the project parameter. Staff see email
+// addresses; maintainers get them redacted.
+func (s *server) handleExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	session := sessionFromContext(r.Context())
+	if session == nil || (session.Role != roleStaff && session.Role != roleMaintainer) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	format := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("format")))
+	if format == "" {
+		format = "json"
+	}
+	table := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("table")))
+	switch format {
+	case "json", "dotproject":
+	case "csv":
+		if !validExportTable(table) {
+			http.Error(w, "table must be one of "+strings.Join(export.Tables, ", "), http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, "format must be json, csv or dotproject", http.StatusBadRequest)
+		return
+	}
+
+	redact := session.Role != roleStaff
+	snapshot, err := export.Load(s.store, export.Options{RedactEmails: redact})
+	if err != nil {
+		s.logger.Printf("web-bff: export failed user=%s format=%s err=%v", session.Login, format, err)
+		http.Error(w, "failed to export", http.StatusInternalServerError)
+		return
+	}
+	s.logger.Printf("web-bff: export user=%s role=%s format=%s table=%s redacted=%t", session.Login, session.Role, format, table, redact)
+
+	stamp := snapshot.GeneratedAt.Format("20060102T150405Z")
+	switch format {
+	case "json":
+		w.Header().Set(headerContentType, contentTypeJSON)
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "maintainerd-"+stamp+".json"))
+		err = snapshot.WriteJSON(w)
+	case "csv":
+		w.Header().Set(headerContentType, "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", table+"-"+stamp+".csv"))
+		err = snapshot.WriteCSV(w, table)
+	case "dotproject":
+		projects, ok := filterDotProjects(snapshot, r.URL.Query().Get("project"))
+		if !ok {
+			http.Error(w, "project not found", http.StatusNotFound)
+			return
+		}
+		w.Header().Set(headerContentType, "application/yaml")
+		err = export.WriteDotProjects(w, projects)
+	}
+	if err != nil {
+		s.logger.Printf("web-bff: export write error user=%s format=%s err=%v", session.Login, format, err)
+	}
+}
+
+func validExportTable(table string) bool {
+	for _, name := range export.Tables {
+		if name == table {
+			return true
+		}
+	}
+	return false
+}
+
+// filterDotProjects returns the snapshot's .project.yaml documents, or only the one for the project named by id,
+// name or slug in filter. It reports false when filter matches no project.
+func filterDotProjects(snapshot *export.Snapshot, filter string) ([]dotproject.Project, bool) {
+	projects := snapshot.DotProjects()
+	filter = strings.TrimSpace(filter)
+	if filter == "" {
+		return projects, true
+	}
+	slug := dotproject.Slug(filter)
+	if id, err := strconv.ParseUint(filter, 10, 64); err == nil {
+		for _, project := range snapshot.Projects {
+			if uint64(project.ID) == id {
+				slug = project.Slug
+			}
+		}
+	}
+	for _, project := range projects {
+		if project.Slug == slug {
+			return []dotproject.Project{project}, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"maintainerd/db"
+)
+
+type foundationRequest struct {
+	Name string `json:"name"`
+}
+
+type foundationResponse struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+// handleFoundations serves GET /api/foundations, listing foundations with their staff counts, and POST, which
+// creates one. Staff only.
+func (s *server) handleFoundations(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	session := sessionFromContext(r.Context())
+	if session == nil || session.Role != roleStaff {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		foundations, err := s.store.ListFoundations()
+		if err != nil {
+			s.logger.Printf("web-bff: handleFoundations list error: %v", err)
+			http.Error(w, "failed to load foundations", http.StatusInternalServerError)
+			return
+		}
+		if foundations == nil {
+			foundations = []db.FoundationSummary{}
+		}
+		w.Header().Set(headerContentType, contentTypeJSON)
+		if err := json.NewEncoder(w).Encode(foundations); err != nil {
+			s.logger.Printf("web-bff: handleFoundations encode error: %v", err)
+		}
+	case http.MethodPost:
+		var req foundationRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || strings.TrimSpace(req.Name) == "" {
+			http.Error(w, "name is required", http.StatusBadRequest)
+			return
+		}
+		foundation, err := s.auditedStore(session).CreateFoundation(req.Name)
+		if err != nil {
+			if errors.Is(err, db.ErrFoundationExists) {
+				http.Error(w, "foundation already exists", http.StatusConflict)
+				return
+			}
+			s.logger.Printf("web-bff: handleFoundations create error: %v", err)
+			http.Error(w, "failed to create foundation", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set(headerContentType, contentTypeJSON)
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(foundationResponse{ID: foundation.ID, Name: foundation.Name}); err != nil {
+			s.logger.Printf("web-bff: handleFoundations encode error: %v", err)
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleFoundation serves GET, PATCH (rename) and DELETE on /api/foundations/{id}. A foundation that staff members
+// still belong to cannot be deleted.
+func (s *server) handleFoundation(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	id, err := parseIDParam(r.URL.Path, "/api/foundations/")
+	if err != nil {
+		http.Error(w, "invalid foundation id", http.StatusBadRequest)
+		return
+	}
+	session := sessionFromContext(r.Context())
+	if session == nil || session.Role != roleStaff {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		foundation, err := s.store.GetFoundation(id)
+		if err != nil {
+			if errors.Is(err, db.ErrFoundationNotFound) {
+				http.Error(w, "foundation not found", http.StatusNotFound)
+				return
+			}
+			s.logger.Printf("web-bff: handleFoundation get error: %v", err)
+			http.Error(w, "failed to load foundation", http.StatusInternalServerError)
+			return
+		}
+		s.writeFoundation(w, foundation.ID, foundation.Name)
+	case http.MethodPatch:
+		var req foundationRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || strings.TrimSpace(req.Name) == "" {
+			http.Error(w, "name is required", http.StatusBadRequest)
+			return
+		}
+		foundation, err := s.auditedStore(session).RenameFoundation(id, req.Name)
+		if err != nil {
+			switch {
+			case errors.Is(err, db.ErrFoundationNotFound):
+				http.Error(w, "foundation not found", http.StatusNotFound)
+			case errors.Is(err, db.ErrFoundationExists):
+				http.Error(w, "foundation already exists", http.StatusConflict)
+			default:
+				s.logger.Printf("web-bff: handleFoundation rename error: %v", err)
+				http.Error(w, "failed to update foundation", http.StatusInternalServerError)
+			}
+			return
+		}
+		s.writeFoundation(w, foundation.ID, foundation.Name)
+	case http.MethodDelete:
+		if err := s.auditedStore(session).DeleteFoundation(id); err != nil {
+			switch {
+			case errors.Is(err, db.ErrFoundationNotFound):
+				http.Error(w, "foundation not found", http.StatusNotFound)
+			case errors.Is(err, db.ErrFoundationInUse):
+				http.Error(w, err.Error(), http.StatusConflict)
+			default:
+				s.logger.Printf("web-bff: handleFoundation delete error: %v", err)
+				http.Error(w, "failed to delete foundation", http.StatusInternalServerError)
+			}
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *server) writeFoundation(w http.ResponseWriter, id uint, name string) {
+	w.Header().Set(headerContentType, contentTypeJSON)
+	if err := json.NewEncoder(w).Encode(foundationResponse{ID: id, Name: name}); err != nil {
+		s.logger.Printf("web-bff: handleFoundation encode error: %v", err)
+	}
+}
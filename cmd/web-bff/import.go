@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"maintainerd/db"
+)
+
+// maxImportBytes caps the size of a bulk import upload.
+const maxImportBytes = 5 << 20
+
+// handleImport serves POST /api/import. The body is CSV or YAML rows of project, name, email, github and company,
+// chosen by the format query parameter or the Content-Type. Without apply=true it returns the dry-run plan; with it
+// the rows are applied in one transaction, or not at all if any conflict (409 with the plan). Staff only.
+func (s *server) handleImport(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	session := sessionFromContext(r.Context())
+	if session == nil || session.Role != roleStaff {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	format := importFormat(r)
+	rows, err := db.ParseImportRows(format, http.MaxBytesReader(w, r.Body, maxImportBytes))
+	if err != nil {
+		http.Error(w, "invalid import: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	apply := r.URL.Query().Get("apply") == "true"
+
+	var plan *db.ImportPlan
+	status := http.StatusOK
+	if apply {
+		plan, err = s.auditedStore(session).ApplyImport(rows)
+	} else {
+		plan, err = s.store.PlanImport(rows)
+	}
+	switch {
+	case errors.Is(err, db.ErrImportConflicts):
+		status = http.StatusConflict
+	case err != nil:
+		s.logger.Printf("web-bff: import failed user=%s format=%s rows=%d apply=%t err=%v", session.Login, format, len(rows), apply, err)
+		http.Error(w, "failed to import", http.StatusInternalServerError)
+		return
+	}
+	s.logger.Printf("web-bff: import user=%s format=%s rows=%d apply=%t applied=%t creates=%d updates=%d conflicts=%d",
+		session.Login, format, len(rows), apply, plan.Applied, plan.Creates, plan.Updates, plan.Conflicts)
+
+	w.Header().Set(headerContentType, contentTypeJSON)
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(plan); err != nil {
+		s.logger.Printf("web-bff: handleImport encode error: %v", err)
+	}
+}
+
+// importFormat returns the format query parameter, or one derived from the Content-Type, defaulting to csv.
+func importFormat(r *http.Request) string {
+	if format := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("format"))); format != "" {
+		return format
+	}
+	contentType := strings.ToLower(r.Header.Get(headerContentType))
+	if strings.Contains(contentType, "yaml") || strings.Contains(contentType, "json") {
+		return "yaml"
+	}
+	return "csv"
+}
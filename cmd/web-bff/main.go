@@ -21,8 +21,11 @@ import (
 	"time"
 
 	"maintainerd/db"
+	"maintainerd/dotproject"
+	"maintainerd/emailverify"
 	"maintainerd/model"
 	"maintainerd/onboarding"
+	"maintainerd/plugins/fossa"
 	"maintainerd/refparse"
 
 	"github.com/google/go-github/v55/github"
@@ -90,11 +93,20 @@ type server struct {
 	fetchIssueTitle func(ctx context.Context, owner, repo string, number int) (string, error)
 	onboardingCache *onboardingIssueCache
 	fetchIssues     func(ctx context.Context) ([]onboardingIssueSummary, error)
+	// newDotProjectClient overrides the GitHub client .project.yaml pull requests are opened with.
+	newDotProjectClient func(ctx context.Context) dotproject.GitHubClient
+	emailVerifier       *emailverify.Verifier
+	// removeFromServiceTeam revokes a maintainer's access to a remote service team. Nil when no FOSSA token is set.
+	removeFromServiceTeam func(teamID int, email string) error
+	// staffAdmins are lower-cased GitHub logins treated as staff admins whatever their stored flag, so a new
+	// deployment can appoint its first admin.
+	staffAdmins map[string]bool
 }
 
 type session struct {
 	ID        string
 	Login     string
+	GitHubID  int64
 	Role      string
 	CreatedAt time.Time
 	ExpiresAt time.Time
@@ -146,6 +158,7 @@ func main() {
 	sessionTTL := parseDuration(envOr("SESSION_TTL", ""), defaultSessionTTL)
 	cookieSecure := envOr("SESSION_COOKIE_SECURE", "") == "true"
 	testMode := envOr("BFF_TEST_MODE", "") == "true"
+	staffAdmins := parseLoginSet(os.Getenv("BFF_STAFF_ADMINS"))
 
 	clientID := os.Getenv("GITHUB_OAUTH_CLIENT_ID")
 	clientSecret := os.Getenv("GITHUB_OAUTH_CLIENT_SECRET")
@@ -220,6 +233,13 @@ func main() {
 	if err != nil {
 		logger.Fatalf("web-bff: failed to open database: %v", err)
 	}
+	emailVerifier, err := emailverify.NewVerifierFromEnv()
+	if err != nil {
+		logger.Fatalf("web-bff: invalid email verification config: %v", err)
+	}
+	if emailVerifier == nil {
+		logger.Printf("web-bff: EMAIL_VERIFICATION_SECRET not set; email verification disabled")
+	}
 
 	s := &server{
 		oauthConfig: &oauth2.Config{
@@ -245,6 +265,13 @@ func main() {
 		onboardingCache: &onboardingIssueCache{
 			expires: time.Time{},
 		},
+		emailVerifier: emailVerifier,
+		staffAdmins:   staffAdmins,
+	}
+	if token := strings.TrimSpace(os.Getenv("FOSSA_API_TOKEN")); token != "" {
+		s.removeFromServiceTeam = fossa.NewClient(token).RemoveUserFromTeamByEmail
+	} else {
+		logger.Printf("web-bff: FOSSA_API_TOKEN not set; service team removals will only be recorded locally")
 	}
 	s.fetchIssueTitle = s.fetchIssueTitleFromGitHub
 	s.fetchIssues = s.fetchOnboardingIssuesFromGitHub
@@ -271,18 +298,36 @@ func main() {
 	mux.HandleFunc("/auth/callback", s.handleCallback)
 	mux.Handle("/auth/test-login", s.withCORS(http.HandlerFunc(s.handleTestLogin)))
 	mux.Handle("/auth/logout", s.withCORS(http.HandlerFunc(s.handleLogout)))
+	mux.HandleFunc(emailverify.VerifyPath, s.handleVerifyEmail)
 	mux.Handle("/api/me", s.withCORS(s.requireSession(http.HandlerFunc(s.handleMe))))
 	mux.Handle("/api/projects", s.withCORS(s.requireSession(http.HandlerFunc(s.handleProjects))))
 	mux.Handle("/api/projects/recent", s.withCORS(s.requireSession(http.HandlerFunc(s.handleRecentProjects))))
 	mux.Handle("/api/projects/", s.withCORS(s.requireSession(http.HandlerFunc(s.handleProject))))
 	mux.Handle("/api/search", s.withCORS(s.requireSession(http.HandlerFunc(s.handleSearch))))
 	mux.Handle("/api/maintainers/status", s.withCORS(s.requireSession(http.HandlerFunc(s.handleMaintainerStatusUpdate))))
+	mux.Handle("/api/maintainers/duplicates", s.withCORS(s.requireSession(http.HandlerFunc(s.handleMaintainerDuplicates))))
+	mux.Handle("/api/maintainers/merge", s.withCORS(s.requireSession(http.HandlerFunc(s.handleMaintainerMerge))))
 	mux.Handle("/api/maintainers/from-ref", s.withCORS(s.requireSession(http.HandlerFunc(s.handleMaintainerFromRef))))
 	mux.Handle("/api/maintainers/", s.withCORS(s.requireSession(http.HandlerFunc(s.handleMaintainer))))
 	mux.Handle("/api/audit", s.withCORS(s.requireSession(http.HandlerFunc(s.handleAudit))))
+	mux.Handle("/api/audit/export", s.withCORS(s.requireSession(http.HandlerFunc(s.handleAuditExport))))
+	mux.Handle("/api/audit/", s.withCORS(s.requireSession(http.HandlerFunc(s.handleAuditEntry))))
+	mux.Handle("/api/companies/domain-conflicts", s.withCORS(s.requireSession(http.HandlerFunc(s.handleCompanyDomainConflicts))))
 	mux.Handle("/api/companies/merge", s.withCORS(s.requireSession(http.HandlerFunc(s.handleCompanyMerge))))
 	mux.Handle("/api/companies", s.withCORS(s.requireSession(http.HandlerFunc(s.handleCompanies))))
 	mux.Handle("/api/companies/", s.withCORS(s.requireSession(http.HandlerFunc(s.handleCompany))))
+	mux.Handle("/api/foundations", s.withCORS(s.requireSession(http.HandlerFunc(s.handleFoundations))))
+	mux.Handle("/api/foundations/", s.withCORS(s.requireSession(http.HandlerFunc(s.handleFoundation))))
+	mux.Handle("/api/collaborators", s.withCORS(s.requireSession(http.HandlerFunc(s.handleCollaborators))))
+	mux.Handle("/api/collaborators/", s.withCORS(s.requireSession(http.HandlerFunc(s.handleCollaborator))))
+	mux.Handle("/api/officers", s.withCORS(s.requireSession(http.HandlerFunc(s.handleOfficers))))
+	mux.Handle("/api/officers/", s.withCORS(s.requireSession(http.HandlerFunc(s.handleOfficer))))
+	mux.Handle("/api/services", s.withCORS(s.requireSession(http.HandlerFunc(s.handleServices))))
+	mux.Handle("/api/services/", s.withCORS(s.requireSession(http.HandlerFunc(s.handleService))))
+	mux.Handle("/api/staff", s.withCORS(s.requireSession(http.HandlerFunc(s.handleStaff))))
+	mux.Handle("/api/staff/", s.withCORS(s.requireSession(http.HandlerFunc(s.handleStaffMember))))
+	mux.Handle("/api/export", s.withCORS(s.requireSession(http.HandlerFunc(s.handleExport))))
+	mux.Handle("/api/import", s.withCORS(s.requireSession(http.HandlerFunc(s.handleImport))))
 	mux.Handle("/api/onboarding/resolve", s.withCORS(s.requireSession(http.HandlerFunc(s.handleResolveOnboarding))))
 	mux.Handle("/api/onboarding/issues", s.withCORS(s.requireSession(http.HandlerFunc(s.handleOnboardingIssues))))
 	mux.Handle("/api/", s.withCORS(s.requireSession(http.HandlerFunc(s.handleAPINotImplemented))))
@@ -374,19 +419,20 @@ func (s *server) handleCallback(w http.ResponseWriter, r *http.Request) {
 	}
 
 	login := strings.ToLower(ghUser.GetLogin())
-	role, authorized := s.authorizeLogin(login)
+	githubID := ghUser.GetID()
+	role, authorized := s.authorizeGitHubUser(login, githubID)
 	attemptRole := role
 	if !authorized {
 		attemptRole = "unauthorized"
 	}
-	s.logger.Printf("web-bff: login attempt user=%s role=%s ip=%s", login, attemptRole, clientIP(r))
+	s.logger.Printf("web-bff: login attempt user=%s githubID=%d role=%s ip=%s", login, githubID, attemptRole, clientIP(r))
 	if !authorized {
 		s.logger.Printf("web-bff: unauthorized login attempt from github user %q ip=%s", login, clientIP(r))
 		http.Error(w, "unauthorized", http.StatusForbidden)
 		return
 	}
 
-	if err := s.createSession(login, role, w); err != nil {
+	if err := s.createSession(login, githubID, role, w); err != nil {
 		http.Error(w, "failed to establish session", http.StatusInternalServerError)
 		return
 	}
@@ -423,7 +469,7 @@ func (s *server) handleTestLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := s.createSession(login, role, w); err != nil {
+	if err := s.createSession(login, 0, role, w); err != nil {
 		http.Error(w, "failed to create session", http.StatusInternalServerError)
 		return
 	}
@@ -460,7 +506,7 @@ func (s *server) handleLogout(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func (s *server) createSession(login, role string, w http.ResponseWriter) error {
+func (s *server) createSession(login string, githubID int64, role string, w http.ResponseWriter) error {
 	sessionID, err := randomToken(48)
 	if err != nil {
 		return err
@@ -470,6 +516,7 @@ func (s *server) createSession(login, role string, w http.ResponseWriter) error
 	s.sessions.Set(session{
 		ID:        sessionID,
 		Login:     login,
+		GitHubID:  githubID,
 		Role:      role,
 		CreatedAt: now,
 		ExpiresAt: now.Add(s.sessionTTL),
@@ -503,10 +550,13 @@ func (s *server) handleMe(w http.ResponseWriter, r *http.Request) {
 		"role":  session.Role,
 	}
 	if session.Role == roleMaintainer {
-		if maintainer, err := s.getMaintainerByLogin(session.Login); err == nil {
+		if maintainer, err := s.getSessionMaintainer(session); err == nil {
 			response["maintainerId"] = maintainer.ID
 		}
 	}
+	if session.Role == roleStaff {
+		response["admin"] = s.isStaffAdmin(session)
+	}
 	w.Header().Set(headerContentType, contentTypeJSON)
 	if err := json.NewEncoder(w).Encode(response); err != nil {
 		s.logger.Printf("web-bff: handleMe encode error: %v", err)
@@ -514,10 +564,15 @@ func (s *server) handleMe(w http.ResponseWriter, r *http.Request) {
 }
 
 type projectSummary struct {
-	ID          uint                `json:"id"`
-	Name        string              `json:"name"`
-	Maturity    string              `json:"maturity"`
-	Maintainers []maintainerSummary `json:"maintainers"`
+	ID              uint                `json:"id"`
+	Name            string              `json:"name"`
+	Maturity        string              `json:"maturity"`
+	ParentProjectID *uint               `json:"parentProjectId,omitempty"`
+	Maintainers     []maintainerSummary `json:"maintainers"`
+	// Subprojects is the sub-project tree when listing with subprojects=nest.
+	Subprojects []projectSummary `json:"subprojects,omitempty"`
+	// SubprojectCount is the number of sub-projects folded into the project when listing with subprojects=collapse.
+	SubprojectCount int `json:"subprojectCount,omitempty"`
 }
 
 type projectsResponse struct {
@@ -581,12 +636,16 @@ type maintainerSummary struct {
 }
 
 type projectMaintainerDetail struct {
-	ID              uint   `json:"id"`
-	Name            string `json:"name"`
-	GitHub          string `json:"github"`
-	InMaintainerRef bool   `json:"inMaintainerRef"`
-	Status          string `json:"status"`
-	Company         string `json:"company,omitempty"`
+	ID              uint       `json:"id"`
+	Name            string     `json:"name"`
+	GitHub          string     `json:"github"`
+	InMaintainerRef bool       `json:"inMaintainerRef"`
+	Status          string     `json:"status"`
+	Company         string     `json:"company,omitempty"`
+	ProjectStatus   string     `json:"projectStatus,omitempty"`
+	Role            string     `json:"role,omitempty"`
+	JoinedAt        *time.Time `json:"joinedAt,omitempty"`
+	LeftAt          *time.Time `json:"leftAt,omitempty"`
 }
 
 type serviceSummary struct {
@@ -635,7 +694,7 @@ func (s *server) handleProjects(w http.ResponseWriter, r *http.Request) {
 	}
 	var maintainerID uint
 	if session.Role == roleMaintainer {
-		maintainer, err := s.getMaintainerByLogin(session.Login)
+		maintainer, err := s.getSessionMaintainer(session)
 		if err != nil {
 			s.logger.Printf("web-bff: access denied projects user=%s role=%s reason=maintainer_lookup_failed err=%v", session.Login, session.Role, err)
 			http.Error(w, "forbidden", http.StatusForbidden)
@@ -666,11 +725,16 @@ func (s *server) handleProjects(w http.ResponseWriter, r *http.Request) {
 	}
 
 	maturityFilters := parseCSVParam(r, "maturity")
+	subprojects := parseSubprojectsParam(r)
 
 	base := s.store.DB().Model(&model.Project{})
 	if len(maturityFilters) > 0 {
 		base = base.Where("projects.maturity IN ?", maturityFilters)
 	}
+	if subprojects != subprojectsFlat {
+		// Nested and collapsed listings page through top-level projects only; filters match those projects.
+		base = base.Where("projects.parent_project_id IS NULL")
+	}
 	if namePrefix != "" {
 		base = base.Where("LOWER(projects.name) LIKE ?", strings.ToLower(namePrefix)+"%")
 	}
@@ -738,15 +802,14 @@ func (s *server) handleProjects(w http.ResponseWriter, r *http.Request) {
 			if !ok {
 				continue
 			}
-			maintainers := summarizeMaintainers(project.Maintainers)
-			projects = append(projects, projectSummary{
-				ID:          project.ID,
-				Name:        project.Name,
-				Maturity:    string(project.Maturity),
-				Maintainers: maintainers,
-			})
+			projects = append(projects, summarizeProject(project))
 		}
 	}
+	if err := s.groupSubprojects(projects, subprojects); err != nil {
+		s.logger.Printf("web-bff: handleProjects load subprojects error: %v", err)
+		http.Error(w, "failed to load projects", http.StatusInternalServerError)
+		return
+	}
 
 	w.Header().Set(headerContentType, contentTypeJSON)
 	if namePrefix != "" {
@@ -983,6 +1046,22 @@ func (s *server) handleRecentProjects(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *server) handleProject(w http.ResponseWriter, r *http.Request) {
+	if isDotProjectPath(r.URL.Path) {
+		s.handleDotProject(w, r)
+		return
+	}
+	if isProjectHierarchyPath(r.URL.Path) {
+		s.handleProjectHierarchy(w, r)
+		return
+	}
+	if strings.Contains(r.URL.Path, "/maintainers/") {
+		s.handleProjectMembership(w, r)
+		return
+	}
+	if strings.Contains(r.URL.Path, "/lifecycle") {
+		s.handleProjectLifecycle(w, r)
+		return
+	}
 	if r.Method == http.MethodPatch {
 		if strings.HasSuffix(r.URL.Path, "/maturity") {
 			s.handleProjectMaturityUpdate(w, r)
@@ -1017,7 +1096,7 @@ func (s *server) handleProject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if session.Role == roleMaintainer {
-		if _, err := s.getMaintainerByLogin(session.Login); err != nil {
+		if _, err := s.getSessionMaintainer(session); err != nil {
 			s.logger.Printf("web-bff: maintainer access denied project=%d user=%s role=%s reason=%v", id, session.Login, session.Role, err)
 			http.Error(w, "forbidden", http.StatusForbidden)
 			return
@@ -1058,6 +1137,11 @@ func (s *server) handleProject(w http.ResponseWriter, r *http.Request) {
 	}
 
 	maintainers := summarizeMaintainerDetails(project.Maintainers, refMatches)
+	if memberships, err := s.store.GetProjectMemberships(id); err != nil {
+		s.logger.Printf("web-bff: failed to load memberships project=%d err=%v", id, err)
+	} else {
+		applyMemberships(maintainers, memberships)
+	}
 	services := make([]serviceSummary, 0, len(project.Services))
 	for _, service := range project.Services {
 		services = append(services, serviceSummary{
@@ -1257,37 +1341,11 @@ func (s *server) handleProjectCreate(w http.ResponseWriter, r *http.Request) {
 		DotProjectYamlRef:   dotProjectRef,
 		OnboardingIssue:     &onboardingIssue,
 	}
-	if err := s.store.DB().Create(&project).Error; err != nil {
+	if err := s.auditedStore(session).CreateProject(&project); err != nil {
 		s.logger.Printf("web-bff: create project error: %v", err)
 		http.Error(w, "failed to create project", http.StatusInternalServerError)
 		return
 	}
-	staffID := lookupStaffID(s.store, session.Login)
-	actorName := session.Login
-	if staffID != nil {
-		var staff model.StaffMember
-		if err := s.store.DB().First(&staff, *staffID).Error; err == nil && staff.Name != "" {
-			actorName = staff.Name
-		}
-	}
-	changes := map[string]map[string]string{
-		"projectName":     {"to": projectName},
-		"githubOrg":       {"to": githubOrg},
-		"maturity":        {"to": string(maturity)},
-		"onboardingIssue": {"to": onboardingIssue},
-	}
-	if metadataJSON, err := json.Marshal(changes); err == nil {
-		event := model.AuditLog{
-			StaffID:   staffID,
-			Action:    "PROJECT_CREATE",
-			Message:   fmt.Sprintf("Project created by %s", actorName),
-			Metadata:  string(metadataJSON),
-			ProjectID: &project.ID,
-		}
-		if err := s.store.DB().Create(&event).Error; err != nil {
-			s.logger.Printf("web-bff: create project audit log failed: %v", err)
-		}
-	}
 	s.invalidateOnboardingCache()
 	w.Header().Set(headerContentType, contentTypeJSON)
 	if err := json.NewEncoder(w).Encode(projectCreateResponse{
@@ -1312,15 +1370,16 @@ func (s *server) invalidateOnboardingCache() {
 	s.onboardingCache.mu.Unlock()
 }
 
+// ensureProjectNameAvailable returns db.ErrProjectExists if name is a project's current or former name.
 func ensureProjectNameAvailable(store *db.SQLStore, name string) error {
-	var project model.Project
-	if err := store.DB().Where("name = ?", name).First(&project).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil
-		}
+	project, err := store.ResolveProject(name)
+	if err != nil {
 		return err
 	}
-	return db.ErrProjectExists
+	if project != nil {
+		return db.ErrProjectExists
+	}
+	return nil
 }
 
 func lookupStaffID(store *db.SQLStore, login string) *uint {
@@ -1367,17 +1426,7 @@ func (s *server) handleProjectMaintainerRefUpdate(w http.ResponseWriter, r *http
 		http.Error(w, "maintainerRef must be a URL", http.StatusBadRequest)
 		return
 	}
-	beforeProject, beforeErr := s.store.GetProjectByID(id)
-	if beforeErr != nil {
-		if errors.Is(beforeErr, db.ErrProjectNotFound) {
-			http.Error(w, "project not found", http.StatusNotFound)
-			return
-		}
-		s.logger.Printf("web-bff: load project before maintainerRef update failed id=%d err=%v", id, beforeErr)
-		http.Error(w, "failed to update project", http.StatusInternalServerError)
-		return
-	}
-	if err := s.store.UpdateProjectLegacyMaintainerRef(id, ref); err != nil {
+	if err := s.auditedStore(session).UpdateProjectLegacyMaintainerRef(id, ref); err != nil {
 		if errors.Is(err, db.ErrProjectNotFound) {
 			http.Error(w, "project not found", http.StatusNotFound)
 			return
@@ -1386,47 +1435,6 @@ func (s *server) handleProjectMaintainerRefUpdate(w http.ResponseWriter, r *http
 		http.Error(w, "failed to update project", http.StatusInternalServerError)
 		return
 	}
-	var staffID *uint
-	staffName := ""
-	if session.Login != "" {
-		var staff model.StaffMember
-		if err := s.store.DB().
-			Where("LOWER(git_hub_account) = ?", strings.ToLower(session.Login)).
-			First(&staff).Error; err == nil {
-			staffID = &staff.ID
-			staffName = staff.Name
-		}
-	}
-	if staffName == "" {
-		staffName = session.Login
-	}
-	changes := map[string]map[string]string{
-		"maintainerRef": {
-			"from": strings.TrimSpace(beforeProject.LegacyMaintainerRef),
-			"to":   ref,
-		},
-	}
-	metadata := map[string]any{
-		"actor": map[string]string{
-			"login": session.Login,
-			"role":  session.Role,
-		},
-		"changes": changes,
-	}
-	if metadataJSON, err := json.Marshal(metadata); err != nil {
-		s.logger.Printf("web-bff: update maintainerRef audit metadata encode error: %v", err)
-	} else {
-		event := model.AuditLog{
-			ProjectID: &id,
-			StaffID:   staffID,
-			Action:    "PROJECT_MAINTAINER_REF_UPDATE",
-			Message:   fmt.Sprintf("Project maintainer ref updated by %s", staffName),
-			Metadata:  string(metadataJSON),
-		}
-		if err := s.store.DB().Create(&event).Error; err != nil {
-			s.logger.Printf("web-bff: update maintainerRef audit log failed: %v", err)
-		}
-	}
 	w.Header().Set(headerContentType, contentTypeJSON)
 	if err := json.NewEncoder(w).Encode(map[string]string{"status": "ok"}); err != nil {
 		s.logger.Printf("web-bff: handleProject update encode error: %v", err)
@@ -1459,17 +1467,7 @@ func (s *server) handleProjectMaturityUpdate(w http.ResponseWriter, r *http.Requ
 		http.Error(w, "invalid maturity", http.StatusBadRequest)
 		return
 	}
-	project, err := s.store.GetProjectByID(id)
-	if err != nil {
-		if errors.Is(err, db.ErrProjectNotFound) {
-			http.Error(w, "project not found", http.StatusNotFound)
-			return
-		}
-		s.logger.Printf("web-bff: load project before maturity update failed id=%d err=%v", id, err)
-		http.Error(w, "failed to update project", http.StatusInternalServerError)
-		return
-	}
-	if err := s.store.UpdateProjectMaturity(id, next); err != nil {
+	if err := s.auditedStore(session).UpdateProjectMaturity(id, next); err != nil {
 		if errors.Is(err, db.ErrProjectNotFound) {
 			http.Error(w, "project not found", http.StatusNotFound)
 			return
@@ -1479,47 +1477,6 @@ func (s *server) handleProjectMaturityUpdate(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	var staffID *uint
-	staffName := ""
-	if session.Login != "" {
-		var staff model.StaffMember
-		if err := s.store.DB().
-			Where("LOWER(git_hub_account) = ?", strings.ToLower(session.Login)).
-			First(&staff).Error; err == nil {
-			staffID = &staff.ID
-			staffName = staff.Name
-		}
-	}
-	if staffName == "" {
-		staffName = session.Login
-	}
-	metadata := map[string]any{
-		"actor": map[string]string{
-			"login": session.Login,
-			"role":  session.Role,
-		},
-		"changes": map[string]map[string]string{
-			"maturity": {
-				"from": string(project.Maturity),
-				"to":   string(next),
-			},
-		},
-	}
-	if metadataJSON, err := json.Marshal(metadata); err != nil {
-		s.logger.Printf("web-bff: update maturity audit metadata encode error: %v", err)
-	} else {
-		event := model.AuditLog{
-			ProjectID: &id,
-			StaffID:   staffID,
-			Action:    "PROJECT_MATURITY_UPDATE",
-			Message:   fmt.Sprintf("Project maturity updated by %s", staffName),
-			Metadata:  string(metadataJSON),
-		}
-		if err := s.store.DB().Create(&event).Error; err != nil {
-			s.logger.Printf("web-bff: update maturity audit log failed: %v", err)
-		}
-	}
-
 	w.Header().Set(headerContentType, contentTypeJSON)
 	if err := json.NewEncoder(w).Encode(map[string]string{"status": "ok"}); err != nil {
 		s.logger.Printf("web-bff: handleProject update encode error: %v", err)
@@ -1527,43 +1484,31 @@ func (s *server) handleProjectMaturityUpdate(w http.ResponseWriter, r *http.Requ
 }
 
 type maintainerDetailResponse struct {
-	ID          uint                        `json:"id"`
-	Name        string                      `json:"name"`
-	Email       string                      `json:"email"`
-	GitHub      string                      `json:"github"`
-	GitHubEmail string                      `json:"githubEmail"`
-	Status      string                      `json:"status"`
-	CompanyID   *uint                       `json:"companyId,omitempty"`
-	Company     string                      `json:"company,omitempty"`
-	Projects    []maintainerProjectResponse `json:"projects"`
-	CreatedAt   time.Time                   `json:"createdAt"`
-	UpdatedAt   time.Time                   `json:"updatedAt"`
-	DeletedAt   *time.Time                  `json:"deletedAt,omitempty"`
-	UpdatedBy   string                      `json:"updatedBy,omitempty"`
+	ID              uint                        `json:"id"`
+	Name            string                      `json:"name"`
+	Email           string                      `json:"email"`
+	EmailVerifiedAt *time.Time                  `json:"emailVerifiedAt,omitempty"`
+	GitHub          string                      `json:"github"`
+	GitHubEmail     string                      `json:"githubEmail"`
+	Status          string                      `json:"status"`
+	CompanyID       *uint                       `json:"companyId,omitempty"`
+	Company         string                      `json:"company,omitempty"`
+	Projects        []maintainerProjectResponse `json:"projects"`
+	CreatedAt       time.Time                   `json:"createdAt"`
+	UpdatedAt       time.Time                   `json:"updatedAt"`
+	DeletedAt       *time.Time                  `json:"deletedAt,omitempty"`
+	UpdatedBy       string                      `json:"updatedBy,omitempty"`
+	// SuggestedCompany is the company owning the email domain, when it is not the recorded company.
+	SuggestedCompany *companyResponse `json:"suggestedCompany,omitempty"`
 }
 
 type maintainerProjectResponse struct {
-	ID   uint   `json:"id"`
-	Name string `json:"name"`
-}
-
-type auditLogResponse struct {
-	ID           uint      `json:"id"`
-	Action       string    `json:"action"`
-	Message      string    `json:"message"`
-	Metadata     string    `json:"metadata,omitempty"`
-	CreatedAt    time.Time `json:"createdAt"`
-	ProjectID    *uint     `json:"projectId,omitempty"`
-	MaintainerID *uint     `json:"maintainerId,omitempty"`
-	ServiceID    *uint     `json:"serviceId,omitempty"`
-	StaffID      *uint     `json:"staffId,omitempty"`
-	StaffName    string    `json:"staffName,omitempty"`
-	StaffLogin   string    `json:"staffLogin,omitempty"`
-}
-
-type auditListResponse struct {
-	Total int64              `json:"total"`
-	Logs  []auditLogResponse `json:"logs"`
+	ID       uint       `json:"id"`
+	Name     string     `json:"name"`
+	Status   string     `json:"status,omitempty"`
+	Role     string     `json:"role,omitempty"`
+	JoinedAt *time.Time `json:"joinedAt,omitempty"`
+	LeftAt   *time.Time `json:"leftAt,omitempty"`
 }
 
 func (s *server) handleMaintainer(w http.ResponseWriter, r *http.Request) {
@@ -1571,6 +1516,10 @@ func (s *server) handleMaintainer(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusNoContent)
 		return
 	}
+	if strings.HasSuffix(strings.TrimRight(r.URL.Path, "/"), "/verify-email") {
+		s.handleMaintainerEmailVerification(w, r)
+		return
+	}
 	id, err := parseIDParam(r.URL.Path, "/api/maintainers/")
 	if err != nil {
 		http.Error(w, "invalid maintainer id", http.StatusBadRequest)
@@ -1586,7 +1535,7 @@ func (s *server) handleMaintainer(w http.ResponseWriter, r *http.Request) {
 	s.logger.Printf("web-bff: maintainer lookup id=%d path=%s user=%s role=%s", id, r.URL.Path, login, role)
 	var requester *model.Maintainer
 	if session.Role == roleMaintainer {
-		maintainer, err := s.getMaintainerByLogin(session.Login)
+		maintainer, err := s.getSessionMaintainer(session)
 		if err != nil {
 			s.logger.Printf("web-bff: maintainer access denied target=%d user=%s role=%s reason=%v", id, session.Login, session.Role, err)
 			http.Error(w, "forbidden", http.StatusForbidden)
@@ -1611,24 +1560,19 @@ func (s *server) handleMaintainer(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		projects := make([]maintainerProjectResponse, 0, len(maintainer.Projects))
-		for _, project := range maintainer.Projects {
-			projects = append(projects, maintainerProjectResponse{
-				ID:   project.ID,
-				Name: project.Name,
-			})
-		}
+		projects := s.maintainerProjects(&maintainer)
 
 		response := maintainerDetailResponse{
-			ID:          maintainer.ID,
-			Name:        maintainer.Name,
-			Email:       normalizeValue(maintainer.Email, "EMAIL_MISSING"),
-			GitHub:      normalizeValue(maintainer.GitHubAccount, "GITHUB_MISSING"),
-			GitHubEmail: normalizeValue(maintainer.GitHubEmail, "GITHUB_MISSING"),
-			Status:      string(maintainer.MaintainerStatus),
-			Projects:    projects,
-			CreatedAt:   maintainer.CreatedAt,
-			UpdatedAt:   maintainer.UpdatedAt,
+			ID:              maintainer.ID,
+			Name:            maintainer.Name,
+			Email:           normalizeValue(maintainer.Email, "EMAIL_MISSING"),
+			GitHub:          normalizeValue(maintainer.GitHubAccount, "GITHUB_MISSING"),
+			GitHubEmail:     normalizeValue(maintainer.GitHubEmail, "GITHUB_MISSING"),
+			Status:          string(maintainer.MaintainerStatus),
+			Projects:        projects,
+			CreatedAt:       maintainer.CreatedAt,
+			UpdatedAt:       maintainer.UpdatedAt,
+			EmailVerifiedAt: maintainer.EmailVerifiedAt,
 		}
 		if maintainer.DeletedAt.Valid {
 			deleted := maintainer.DeletedAt.Time
@@ -1640,6 +1584,7 @@ func (s *server) handleMaintainer(w http.ResponseWriter, r *http.Request) {
 		if maintainer.Company.Name != "" {
 			response.Company = maintainer.Company.Name
 		}
+		response.SuggestedCompany = s.suggestedCompany(&maintainer)
 
 		var audit model.AuditLog
 		if err := s.store.DB().
@@ -1667,7 +1612,7 @@ func (s *server) handleMaintainer(w http.ResponseWriter, r *http.Request) {
 	case http.MethodPatch, http.MethodPut:
 		maintainerEditSelf := false
 		if session.Role == roleMaintainer {
-			requester, err := s.getMaintainerByLogin(session.Login)
+			requester, err := s.getSessionMaintainer(session)
 			if err != nil || requester.ID != id {
 				http.Error(w, "forbidden", http.StatusForbidden)
 				return
@@ -1702,7 +1647,8 @@ func (s *server) handleMaintainer(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "invalid status", http.StatusBadRequest)
 			return
 		}
-		updated, err := s.store.UpdateMaintainerDetails(id, req.Name, req.Email, req.GitHub, status, req.CompanyID)
+		store := s.auditedStore(session)
+		updated, err := store.UpdateMaintainerDetails(id, req.Name, req.Email, req.GitHub, status, req.CompanyID)
 		if err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				http.Error(w, "maintainer not found", http.StatusNotFound)
@@ -1712,125 +1658,19 @@ func (s *server) handleMaintainer(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "failed to update maintainer", http.StatusInternalServerError)
 			return
 		}
-
-		var staffID *uint
-		staffName := ""
-		if session.Login != "" {
-			var staff model.StaffMember
-			if err := s.store.DB().
-				Where("LOWER(git_hub_account) = ?", strings.ToLower(session.Login)).
-				First(&staff).Error; err == nil {
-				staffID = &staff.ID
-				staffName = staff.Name
-			}
-		}
-		if staffName == "" {
-			staffName = session.Login
-		}
-
-		changes := make(map[string]map[string]string)
-		beforeName := strings.TrimSpace(before.Name)
-		afterName := strings.TrimSpace(updated.Name)
-		if beforeName == "" {
-			beforeName = "NAME_MISSING"
-		}
-		if afterName == "" {
-			afterName = "NAME_MISSING"
-		}
-		if beforeName != afterName {
-			changes["name"] = map[string]string{"from": beforeName, "to": afterName}
-		}
-		beforeEmail := normalizeValue(before.Email, "EMAIL_MISSING")
-		afterEmail := normalizeValue(updated.Email, "EMAIL_MISSING")
-		if beforeEmail != afterEmail {
-			changes["email"] = map[string]string{"from": beforeEmail, "to": afterEmail}
-		}
-		beforeGitHub := normalizeValue(before.GitHubAccount, "GITHUB_MISSING")
-		afterGitHub := normalizeValue(updated.GitHubAccount, "GITHUB_MISSING")
-		if beforeGitHub != afterGitHub {
-			changes["github"] = map[string]string{"from": beforeGitHub, "to": afterGitHub}
-		}
-		if before.MaintainerStatus != updated.MaintainerStatus {
-			changes["status"] = map[string]string{
-				"from": string(before.MaintainerStatus),
-				"to":   string(updated.MaintainerStatus),
-			}
-		}
-		beforeCompany := ""
-		if before.CompanyID != nil {
-			beforeCompany = fmt.Sprintf("%d", *before.CompanyID)
-		}
-		afterCompany := ""
-		if updated.CompanyID != nil {
-			afterCompany = fmt.Sprintf("%d", *updated.CompanyID)
-		}
-		if beforeCompany != afterCompany {
-			beforeCompanyName := ""
-			if before.CompanyID != nil {
-				var company model.Company
-				if err := s.store.DB().First(&company, *before.CompanyID).Error; err == nil {
-					beforeCompanyName = strings.TrimSpace(company.Name)
-				}
-			}
-			afterCompanyName := strings.TrimSpace(updated.Company.Name)
-			if beforeCompanyName == "" {
-				beforeCompanyName = "COMPANY_MISSING"
-			}
-			if afterCompanyName == "" {
-				afterCompanyName = "COMPANY_MISSING"
-			}
-			changes["company"] = map[string]string{"from": beforeCompanyName, "to": afterCompanyName}
-		}
-
-		metadata := map[string]any{
-			"actor": map[string]string{
-				"login": session.Login,
-				"role":  session.Role,
-			},
-			"changes": changes,
-		}
-		fieldNames := make([]string, 0, len(changes))
-		for field := range changes {
-			fieldNames = append(fieldNames, field)
-		}
-		sort.Strings(fieldNames)
-		message := fmt.Sprintf("Maintainer updated by %s", staffName)
-		if len(fieldNames) > 0 {
-			message = fmt.Sprintf("Maintainer [%s] updated by %s", strings.Join(fieldNames, ", "), staffName)
-		}
-		if metadataJSON, err := json.Marshal(metadata); err != nil {
-			s.logger.Printf("web-bff: update maintainer audit metadata encode error: %v", err)
-		} else {
-			event := model.AuditLog{
-				MaintainerID: &id,
-				StaffID:      staffID,
-				Action:       "MAINTAINER_UPDATE",
-				Message:      message,
-				Metadata:     string(metadataJSON),
-			}
-			if err := s.store.DB().Create(&event).Error; err != nil {
-				s.logger.Printf("web-bff: update maintainer audit log failed: %v", err)
-			}
-		}
-
-		projects := make([]maintainerProjectResponse, 0, len(updated.Projects))
-		for _, project := range updated.Projects {
-			projects = append(projects, maintainerProjectResponse{
-				ID:   project.ID,
-				Name: project.Name,
-			})
-		}
+		projects := s.maintainerProjects(updated)
 
 		response := maintainerDetailResponse{
-			ID:          updated.ID,
-			Name:        updated.Name,
-			Email:       normalizeValue(updated.Email, "EMAIL_MISSING"),
-			GitHub:      normalizeValue(updated.GitHubAccount, "GITHUB_MISSING"),
-			GitHubEmail: normalizeValue(updated.GitHubEmail, "GITHUB_MISSING"),
-			Status:      string(updated.MaintainerStatus),
-			Projects:    projects,
-			CreatedAt:   updated.CreatedAt,
-			UpdatedAt:   updated.UpdatedAt,
+			ID:              updated.ID,
+			Name:            updated.Name,
+			Email:           normalizeValue(updated.Email, "EMAIL_MISSING"),
+			GitHub:          normalizeValue(updated.GitHubAccount, "GITHUB_MISSING"),
+			GitHubEmail:     normalizeValue(updated.GitHubEmail, "GITHUB_MISSING"),
+			Status:          string(updated.MaintainerStatus),
+			Projects:        projects,
+			CreatedAt:       updated.CreatedAt,
+			UpdatedAt:       updated.UpdatedAt,
+			EmailVerifiedAt: updated.EmailVerifiedAt,
 		}
 		if updated.DeletedAt.Valid {
 			deleted := updated.DeletedAt.Time
@@ -1842,9 +1682,8 @@ func (s *server) handleMaintainer(w http.ResponseWriter, r *http.Request) {
 		if updated.Company.Name != "" {
 			response.Company = updated.Company.Name
 		}
-		if staffName != "" {
-			response.UpdatedBy = staffName
-		}
+		response.SuggestedCompany = s.suggestedCompany(updated)
+		response.UpdatedBy = store.Actor().DisplayName()
 
 		w.Header().Set(headerContentType, contentTypeJSON)
 		if err := json.NewEncoder(w).Encode(response); err != nil {
@@ -1857,73 +1696,6 @@ func (s *server) handleMaintainer(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func (s *server) handleAudit(w http.ResponseWriter, r *http.Request) {
-	if r.Method == http.MethodOptions {
-		w.WriteHeader(http.StatusNoContent)
-		return
-	}
-	if r.Method != http.MethodGet {
-		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
-	session := sessionFromContext(r.Context())
-	if session == nil || session.Role != roleStaff {
-		http.Error(w, "forbidden", http.StatusForbidden)
-		return
-	}
-
-	limit := parseIntParam(r, "limit", 20, 1, 200)
-	offset := parseIntParam(r, "offset", 0, 0, 10_000_000)
-
-	base := s.store.DB().Model(&model.AuditLog{})
-	var total int64
-	if err := base.Count(&total).Error; err != nil {
-		s.logger.Printf("web-bff: handleAudit count error: %v", err)
-		http.Error(w, "failed to load audit logs", http.StatusInternalServerError)
-		return
-	}
-
-	var logs []model.AuditLog
-	if err := base.
-		Preload("Staff").
-		Order("created_at desc").
-		Limit(limit).
-		Offset(offset).
-		Find(&logs).Error; err != nil {
-		s.logger.Printf("web-bff: handleAudit list error: %v", err)
-		http.Error(w, "failed to load audit logs", http.StatusInternalServerError)
-		return
-	}
-
-	response := auditListResponse{
-		Total: total,
-		Logs:  make([]auditLogResponse, 0, len(logs)),
-	}
-	for _, logEntry := range logs {
-		item := auditLogResponse{
-			ID:           logEntry.ID,
-			Action:       logEntry.Action,
-			Message:      logEntry.Message,
-			Metadata:     logEntry.Metadata,
-			CreatedAt:    logEntry.CreatedAt,
-			ProjectID:    logEntry.ProjectID,
-			MaintainerID: logEntry.MaintainerID,
-			ServiceID:    logEntry.ServiceID,
-			StaffID:      logEntry.StaffID,
-		}
-		if logEntry.Staff != nil {
-			item.StaffName = logEntry.Staff.Name
-			item.StaffLogin = logEntry.Staff.GitHubAccount
-		}
-		response.Logs = append(response.Logs, item)
-	}
-
-	w.Header().Set(headerContentType, contentTypeJSON)
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		s.logger.Printf("web-bff: handleAudit encode error: %v", err)
-	}
-}
-
 type maintainerUpdateRequest struct {
 	Name      string `json:"name"`
 	Email     string `json:"email"`
@@ -1967,7 +1739,7 @@ func (s *server) handleMaintainerStatusUpdate(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	if err := s.store.UpdateMaintainersStatus(req.IDs, status); err != nil {
+	if err := s.auditedStore(session).UpdateMaintainersStatus(req.IDs, status); err != nil {
 		s.logger.Printf("web-bff: maintainer status update failed ids=%v status=%s err=%v", req.IDs, status, err)
 		http.Error(w, "failed to update maintainers", http.StatusInternalServerError)
 		return
@@ -2019,29 +1791,7 @@ func (s *server) handleMaintainerFromRef(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	var before *model.Maintainer
-	if req.GitHubHandle != "" {
-		var existing model.Maintainer
-		err := s.store.DB().Where("LOWER(git_hub_account) = ?", strings.ToLower(req.GitHubHandle)).First(&existing).Error
-		if err == nil {
-			before = &existing
-		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
-			http.Error(w, "failed to load maintainer", http.StatusInternalServerError)
-			return
-		}
-	}
-	if before == nil && req.Email != "" {
-		var existing model.Maintainer
-		err := s.store.DB().Where("LOWER(email) = ?", strings.ToLower(req.Email)).First(&existing).Error
-		if err == nil {
-			before = &existing
-		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
-			http.Error(w, "failed to load maintainer", http.StatusInternalServerError)
-			return
-		}
-	}
-
-	maintainer, err := s.store.UpsertMaintainer(req.ProjectID, req.Name, req.Email, req.GitHubHandle, req.Company)
+	maintainer, err := s.auditedStore(session).UpsertMaintainer(req.ProjectID, req.Name, req.Email, req.GitHubHandle, req.Company)
 	if err != nil {
 		if errors.Is(err, db.ErrProjectNotFound) {
 			http.Error(w, "project not found", http.StatusNotFound)
@@ -2051,117 +1801,6 @@ func (s *server) handleMaintainerFromRef(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	staffID := lookupStaffID(s.store, session.Login)
-	staffName := session.Login
-	if staffID != nil {
-		var staff model.StaffMember
-		if err := s.store.DB().First(&staff, *staffID).Error; err == nil && staff.Name != "" {
-			staffName = staff.Name
-		}
-	}
-
-	changes := map[string]map[string]string{}
-	if before == nil {
-		if req.Name != "" {
-			changes["name"] = map[string]string{"to": req.Name}
-		}
-		if req.Email != "" {
-			changes["email"] = map[string]string{"to": req.Email}
-		}
-		if req.GitHubHandle != "" {
-			changes["github"] = map[string]string{"to": req.GitHubHandle}
-		}
-		if req.Company != "" {
-			changes["company"] = map[string]string{"to": req.Company}
-		}
-	} else {
-		beforeName := strings.TrimSpace(before.Name)
-		afterName := strings.TrimSpace(maintainer.Name)
-		if beforeName == "" {
-			beforeName = "NAME_MISSING"
-		}
-		if afterName == "" {
-			afterName = "NAME_MISSING"
-		}
-		if beforeName != afterName {
-			changes["name"] = map[string]string{"from": beforeName, "to": afterName}
-		}
-		beforeEmail := normalizeValue(before.Email, "EMAIL_MISSING")
-		afterEmail := normalizeValue(maintainer.Email, "EMAIL_MISSING")
-		if beforeEmail != afterEmail {
-			changes["email"] = map[string]string{"from": beforeEmail, "to": afterEmail}
-		}
-		beforeGitHub := normalizeValue(before.GitHubAccount, "GITHUB_MISSING")
-		afterGitHub := normalizeValue(maintainer.GitHubAccount, "GITHUB_MISSING")
-		if beforeGitHub != afterGitHub {
-			changes["github"] = map[string]string{"from": beforeGitHub, "to": afterGitHub}
-		}
-		beforeCompany := ""
-		if before.CompanyID != nil {
-			var company model.Company
-			if err := s.store.DB().First(&company, *before.CompanyID).Error; err == nil {
-				beforeCompany = strings.TrimSpace(company.Name)
-			}
-		}
-		afterCompany := strings.TrimSpace(maintainer.Company.Name)
-		if afterCompany == "" && maintainer.CompanyID != nil {
-			var company model.Company
-			if err := s.store.DB().First(&company, *maintainer.CompanyID).Error; err == nil {
-				afterCompany = strings.TrimSpace(company.Name)
-			}
-		}
-		if afterCompany == "" && strings.TrimSpace(req.Company) != "" {
-			afterCompany = strings.TrimSpace(req.Company)
-		}
-		if beforeCompany == "" {
-			beforeCompany = "COMPANY_MISSING"
-		}
-		if afterCompany == "" {
-			afterCompany = "COMPANY_MISSING"
-		}
-		if beforeCompany != afterCompany {
-			changes["company"] = map[string]string{"from": beforeCompany, "to": afterCompany}
-		}
-	}
-
-	if len(changes) > 0 {
-		metadata := map[string]any{
-			"actor": map[string]string{
-				"login": session.Login,
-				"role":  session.Role,
-			},
-			"changes": changes,
-		}
-		fieldNames := make([]string, 0, len(changes))
-		for field := range changes {
-			fieldNames = append(fieldNames, field)
-		}
-		sort.Strings(fieldNames)
-		message := fmt.Sprintf("Maintainer updated by %s", staffName)
-		action := "MAINTAINER_UPDATE"
-		if before == nil {
-			message = fmt.Sprintf("Maintainer created by %s", staffName)
-			action = "MAINTAINER_CREATE"
-		} else if len(fieldNames) > 0 {
-			message = fmt.Sprintf("Maintainer [%s] updated by %s", strings.Join(fieldNames, ", "), staffName)
-		}
-		if metadataJSON, err := json.Marshal(metadata); err != nil {
-			s.logger.Printf("web-bff: add maintainer audit metadata encode error: %v", err)
-		} else {
-			event := model.AuditLog{
-				ProjectID:    &req.ProjectID,
-				MaintainerID: &maintainer.ID,
-				StaffID:      staffID,
-				Action:       action,
-				Message:      message,
-				Metadata:     string(metadataJSON),
-			}
-			if err := s.store.DB().Create(&event).Error; err != nil {
-				s.logger.Printf("web-bff: add maintainer audit log failed: %v", err)
-			}
-		}
-	}
-
 	response := addMaintainerResponse{
 		ID:     maintainer.ID,
 		Name:   maintainer.Name,
@@ -2186,6 +1825,16 @@ type createCompanyRequest struct {
 	Name string `json:"name"`
 }
 
+// updateCompanyRequest is the body of PATCH /api/companies/{id}. Only fields present are changed; Aliases and Tags
+// replace the whole list or map.
+type updateCompanyRequest struct {
+	Name    *string            `json:"name"`
+	Website *string            `json:"website"`
+	Notes   *string            `json:"notes"`
+	Tags    *map[string]string `json:"tags"`
+	Aliases *[]string          `json:"aliases"`
+}
+
 type mergeCompanyRequest struct {
 	FromID uint `json:"fromId"`
 	ToID   uint `json:"toId"`
@@ -2194,6 +1843,7 @@ type mergeCompanyRequest struct {
 type companyDetailResponse struct {
 	ID              uint   `json:"id"`
 	Name            string `json:"name"`
+	Website         string `json:"website,omitempty"`
 	MaintainerCount int64  `json:"maintainerCount"`
 }
 
@@ -2246,6 +1896,11 @@ type companyMaintainerResponse struct {
 type companyMaintainersResponse struct {
 	ID          uint                        `json:"id"`
 	Name        string                      `json:"name"`
+	Website     string                      `json:"website,omitempty"`
+	Notes       string                      `json:"notes,omitempty"`
+	Tags        map[string]string           `json:"tags,omitempty"`
+	Domains     []string                    `json:"domains"`
+	Aliases     []string                    `json:"aliases"`
 	Maintainers []companyMaintainerResponse `json:"maintainers"`
 }
 
@@ -2263,6 +1918,10 @@ func (s *server) handleCompanies(w http.ResponseWriter, r *http.Request) {
 
 	switch r.Method {
 	case http.MethodGet:
+		if strings.EqualFold(strings.TrimSpace(r.URL.Query().Get("duplicates")), "fuzzy") {
+			s.handleCompanyMergeSuggestions(w, r)
+			return
+		}
 		companies, err := s.store.ListCompanies()
 		if err != nil {
 			http.Error(w, "failed to load companies", http.StatusInternalServerError)
@@ -2298,6 +1957,7 @@ func (s *server) handleCompanies(w http.ResponseWriter, r *http.Request) {
 			resp = append(resp, companyDetailResponse{
 				ID:              company.ID,
 				Name:            company.Name,
+				Website:         company.Website,
 				MaintainerCount: countMap[company.ID],
 			})
 		}
@@ -2325,7 +1985,7 @@ func (s *server) handleCompanies(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "invalid request", http.StatusBadRequest)
 			return
 		}
-		company, err := s.store.CreateCompany(req.Name)
+		company, err := s.auditedStore(session).CreateCompany(req.Name)
 		if err != nil {
 			if errors.Is(err, db.ErrCompanyExists) {
 				http.Error(w, "company already exists", http.StatusConflict)
@@ -2334,52 +1994,6 @@ func (s *server) handleCompanies(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "failed to create company", http.StatusBadRequest)
 			return
 		}
-		var staffID *uint
-		var maintainerID *uint
-		actorName := session.Login
-		if session.Role == roleStaff && session.Login != "" {
-			var staff model.StaffMember
-			if err := s.store.DB().
-				Where("LOWER(git_hub_account) = ?", strings.ToLower(session.Login)).
-				First(&staff).Error; err == nil {
-				staffID = &staff.ID
-				if staff.Name != "" {
-					actorName = staff.Name
-				}
-			}
-		}
-		if session.Role == roleMaintainer && session.Login != "" {
-			if maintainer, err := s.getMaintainerByLogin(session.Login); err == nil {
-				maintainerID = &maintainer.ID
-				if strings.TrimSpace(maintainer.Name) != "" {
-					actorName = maintainer.Name
-				}
-			}
-		}
-		metadata := map[string]any{
-			"actor": map[string]string{
-				"login": session.Login,
-				"role":  session.Role,
-			},
-			"company": map[string]any{
-				"id":   company.ID,
-				"name": company.Name,
-			},
-		}
-		if metadataJSON, err := json.Marshal(metadata); err != nil {
-			s.logger.Printf("web-bff: create company audit metadata encode error: %v", err)
-		} else {
-			event := model.AuditLog{
-				StaffID:      staffID,
-				MaintainerID: maintainerID,
-				Action:       "COMPANY_CREATE",
-				Message:      fmt.Sprintf("Company created by %s", actorName),
-				Metadata:     string(metadataJSON),
-			}
-			if err := s.store.DB().Create(&event).Error; err != nil {
-				s.logger.Printf("web-bff: create company audit log failed: %v", err)
-			}
-		}
 		w.Header().Set(headerContentType, contentTypeJSON)
 		if err := json.NewEncoder(w).Encode(companyResponse{ID: company.ID, Name: company.Name}); err != nil {
 			s.logger.Printf("web-bff: handleCompanies encode error: %v", err)
@@ -2394,7 +2008,11 @@ func (s *server) handleCompany(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusNoContent)
 		return
 	}
-	if r.Method != http.MethodGet {
+	if strings.Contains(r.URL.Path, "/domains") {
+		s.handleCompanyDomains(w, r)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodPatch && r.Method != http.MethodDelete {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
@@ -2415,6 +2033,60 @@ func (s *server) handleCompany(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if r.Method == http.MethodDelete {
+		if err := s.auditedStore(session).DeleteCompany(id); err != nil {
+			if errors.Is(err, db.ErrCompanyInUse) {
+				http.Error(w, err.Error()+"; merge it into another company instead", http.StatusConflict)
+				return
+			}
+			s.logger.Printf("web-bff: handleCompany delete error: %v", err)
+			http.Error(w, "failed to delete company", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if r.Method == http.MethodPatch {
+		var req updateCompanyRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+		if req.Name != nil || req.Website != nil || req.Notes != nil || req.Tags != nil {
+			updated, err := s.auditedStore(session).UpdateCompany(id, db.CompanyUpdate{
+				Name:    req.Name,
+				Website: req.Website,
+				Notes:   req.Notes,
+				Tags:    req.Tags,
+			})
+			if err != nil {
+				switch {
+				case errors.Is(err, db.ErrCompanyExists):
+					http.Error(w, "company already exists", http.StatusConflict)
+				case errors.Is(err, db.ErrInvalidWebsite), errors.Is(err, db.ErrInvalidTag):
+					http.Error(w, err.Error(), http.StatusBadRequest)
+				default:
+					s.logger.Printf("web-bff: handleCompany update error: %v", err)
+					http.Error(w, "failed to update company", http.StatusBadRequest)
+				}
+				return
+			}
+			company = *updated
+		}
+		if req.Aliases != nil {
+			if _, err := s.auditedStore(session).SetCompanyAliases(id, *req.Aliases); err != nil {
+				if errors.Is(err, db.ErrAliasClaimed) {
+					http.Error(w, err.Error(), http.StatusConflict)
+					return
+				}
+				s.logger.Printf("web-bff: handleCompany aliases update error: %v", err)
+				http.Error(w, "failed to update company", http.StatusInternalServerError)
+				return
+			}
+		}
+	}
+
 	var maintainers []model.Maintainer
 	if err := s.store.DB().
 		Preload("Projects").
@@ -2444,10 +2116,32 @@ func (s *server) handleCompany(w http.ResponseWriter, r *http.Request) {
 		})
 	}
 
+	domains, err := s.companyDomains(company.ID)
+	if err != nil {
+		s.logger.Printf("web-bff: handleCompany domains error: %v", err)
+		http.Error(w, "failed to load domains", http.StatusInternalServerError)
+		return
+	}
+	aliases, err := s.store.ListCompanyAliases(company.ID)
+	if err != nil {
+		s.logger.Printf("web-bff: handleCompany aliases error: %v", err)
+		http.Error(w, "failed to load aliases", http.StatusInternalServerError)
+		return
+	}
+	aliasNames := make([]string, 0, len(aliases))
+	for _, alias := range aliases {
+		aliasNames = append(aliasNames, alias.Name)
+	}
+
 	w.Header().Set(headerContentType, contentTypeJSON)
 	if err := json.NewEncoder(w).Encode(companyMaintainersResponse{
 		ID:          company.ID,
 		Name:        company.Name,
+		Website:     company.Website,
+		Notes:       company.Notes,
+		Tags:        company.Tags,
+		Domains:     domains,
+		Aliases:     aliasNames,
 		Maintainers: maintainerResults,
 	}); err != nil {
 		s.logger.Printf("web-bff: handleCompany encode error: %v", err)
@@ -2825,7 +2519,7 @@ func (s *server) handleCompanyMerge(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "invalid ids", http.StatusBadRequest)
 		return
 	}
-	if err := s.store.MergeCompanies(req.FromID, req.ToID); err != nil {
+	if err := s.auditedStore(session).MergeCompanies(req.FromID, req.ToID); err != nil {
 		s.logger.Printf("web-bff: merge companies error: %v", err)
 		http.Error(w, "failed to merge companies", http.StatusBadRequest)
 		return
@@ -3164,7 +2858,7 @@ func (s *server) withCORS(next http.Handler) http.Handler {
 			w.Header().Set("Vary", "Origin")
 		}
 		if r.Method == http.MethodOptions {
-			w.Header().Set("Access-Control-Allow-Methods", "GET,POST,PATCH,OPTIONS")
+			w.Header().Set("Access-Control-Allow-Methods", "GET,POST,PATCH,DELETE,OPTIONS")
 			w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
 			w.WriteHeader(http.StatusNoContent)
 			return
@@ -3243,6 +2937,39 @@ func (s *server) authorizeLogin(login string) (string, bool) {
 	return "", false
 }
 
+// authorizeGitHubUser authorizes an OAuth-authenticated GitHub user. Matching is done on the immutable GitHub user
+// ID once a record has been linked, and on the handle only for records that have never logged in; see
+// db.SQLStore.LinkGitHubIdentity.
+func (s *server) authorizeGitHubUser(login string, githubID int64) (string, bool) {
+	if login == "" || githubID == 0 {
+		return "", false
+	}
+	identity, err := s.store.LinkGitHubIdentity(login, githubID)
+	if err != nil {
+		s.logger.Printf("web-bff: failed to link github identity user=%s githubID=%d: %v", login, githubID, err)
+		return "", false
+	}
+	if identity.Staff != nil {
+		return roleStaff, true
+	}
+	if identity.Maintainer != nil {
+		return roleMaintainer, true
+	}
+	return "", false
+}
+
+// getSessionMaintainer returns the maintainer record for the logged-in user, by GitHub user ID when the session
+// came from an OAuth login and by handle otherwise (test-mode logins).
+func (s *server) getSessionMaintainer(sess *session) (*model.Maintainer, error) {
+	if sess == nil {
+		return nil, fmt.Errorf("missing session")
+	}
+	if sess.GitHubID != 0 {
+		return s.store.GetMaintainerByGitHubID(sess.GitHubID)
+	}
+	return s.getMaintainerByLogin(sess.Login)
+}
+
 func (s *server) getMaintainerByLogin(login string) (*model.Maintainer, error) {
 	if strings.TrimSpace(login) == "" {
 		return nil, fmt.Errorf("missing login")
@@ -3315,6 +3042,20 @@ func (s *sessionStore) Get(id string) (session, bool) {
 	return sess, true
 }
 
+// DeleteByLogin ends every session of login with role and returns how many were ended.
+func (s *sessionStore) DeleteByLogin(login, role string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deleted := 0
+	for id, sess := range s.sessions {
+		if sess.Role == role && strings.EqualFold(sess.Login, login) {
+			delete(s.sessions, id)
+			deleted++
+		}
+	}
+	return deleted
+}
+
 func (s *sessionStore) Delete(id string) (session, bool) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -3384,6 +3125,17 @@ func envOr(key, fallback string) string {
 	return fallback
 }
 
+// parseLoginSet splits a comma-separated list of GitHub logins into a lower-cased set.
+func parseLoginSet(raw string) map[string]bool {
+	logins := make(map[string]bool)
+	for _, login := range strings.Split(raw, ",") {
+		if login = strings.ToLower(strings.TrimSpace(login)); login != "" {
+			logins[login] = true
+		}
+	}
+	return logins
+}
+
 func parseDuration(raw string, fallback time.Duration) time.Duration {
 	if raw == "" {
 		return fallback
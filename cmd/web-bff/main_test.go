@@ -82,7 +82,13 @@ func setupPostgresTestDB(t *testing.T) *gorm.DB {
 		&model.StaffMember{},
 		&model.FoundationOfficer{},
 		&model.Collaborator{},
+		&model.CollaboratorProject{},
+		&model.ProjectAlias{},
+		&model.ProjectMaturityEvent{},
 		&model.MaintainerProject{},
+		&model.MembershipHistory{},
+		&model.CompanyDomain{},
+		&model.CompanyAlias{},
 		&model.Service{},
 		&model.ServiceTeam{},
 		&model.ServiceUser{},
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+type mergeMaintainersRequest struct {
+	FromID uint `json:"fromId"`
+	ToID   uint `json:"toId"`
+}
+
+// handleMaintainerDuplicates serves GET /api/maintainers/duplicates: pairs of maintainer records that look like the
+// same person, strongest matches first.
+func (s *server) handleMaintainerDuplicates(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	session := sessionFromContext(r.Context())
+	if session == nil || session.Role != roleStaff {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	suggestions, err := s.store.FindMaintainerDuplicates()
+	if err != nil {
+		s.logger.Printf("web-bff: maintainer duplicates failed: %v", err)
+		http.Error(w, "failed to find duplicate maintainers", http.StatusInternalServerError)
+		return
+	}
+	if limit := parseIntParam(r, "limit", 100, 1, 1000); len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	w.Header().Set(headerContentType, contentTypeJSON)
+	if err := json.NewEncoder(w).Encode(suggestions); err != nil {
+		s.logger.Printf("web-bff: handleMaintainerDuplicates encode error: %v", err)
+	}
+}
+
+// handleMaintainerMerge serves POST /api/maintainers/merge, folding maintainer fromId into toId. The merge is audited
+// and can be undone through the audit revert endpoint.
+func (s *server) handleMaintainerMerge(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	session := sessionFromContext(r.Context())
+	if session == nil || session.Role != roleStaff {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	var req mergeMaintainersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+	if req.FromID == 0 || req.ToID == 0 || req.FromID == req.ToID {
+		http.Error(w, "invalid ids", http.StatusBadRequest)
+		return
+	}
+	merge, err := s.auditedStore(session).MergeMaintainers(req.FromID, req.ToID)
+	if err != nil {
+		s.logger.Printf("web-bff: merge maintainers error from=%d to=%d: %v", req.FromID, req.ToID, err)
+		http.Error(w, "failed to merge maintainers", http.StatusBadRequest)
+		return
+	}
+	w.Header().Set(headerContentType, contentTypeJSON)
+	if err := json.NewEncoder(w).Encode(merge); err != nil {
+		s.logger.Printf("web-bff: handleMaintainerMerge encode error: %v", err)
+	}
+}
@@ -0,0 +1,357 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"maintainerd/db"
+	"maintainerd/model"
+)
+
+type membershipHistoryResponse struct {
+	Event      string    `json:"event"`
+	FromStatus string    `json:"fromStatus,omitempty"`
+	ToStatus   string    `json:"toStatus,omitempty"`
+	FromRole   string    `json:"fromRole,omitempty"`
+	ToRole     string    `json:"toRole,omitempty"`
+	Actor      string    `json:"actor,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type membershipResponse struct {
+	ProjectID    uint                        `json:"projectId"`
+	MaintainerID uint                        `json:"maintainerId"`
+	Status       string                      `json:"status"`
+	Role         string                      `json:"role"`
+	JoinedAt     time.Time                   `json:"joinedAt"`
+	LeftAt       *time.Time                  `json:"leftAt,omitempty"`
+	History      []membershipHistoryResponse `json:"history"`
+}
+
+type membershipUpdateRequest struct {
+	Status string `json:"status"`
+	Role   string `json:"role"`
+}
+
+type membershipRemoveRequest struct {
+	MaintainerIDs       []uint `json:"maintainerIds"`
+	CascadeServiceTeams bool   `json:"cascadeServiceTeams"`
+}
+
+type serviceTeamRemovalResponse struct {
+	ID      uint   `json:"id"`
+	Name    string `json:"name"`
+	Revoked bool   `json:"revoked"`
+	Error   string `json:"error,omitempty"`
+}
+
+type membershipRemovalResponse struct {
+	ProjectID    uint                         `json:"projectId"`
+	MaintainerID uint                         `json:"maintainerId"`
+	ServiceTeams []serviceTeamRemovalResponse `json:"serviceTeams"`
+}
+
+// parseMembershipPath extracts the project and maintainer IDs from /api/projects/{id}/maintainers/{mid}.
+func parseMembershipPath(path string) (uint, uint, error) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(path, "/api/projects/"), "/"), "/")
+	if len(parts) != 3 || parts[1] != "maintainers" {
+		return 0, 0, fmt.Errorf("invalid membership path")
+	}
+	projectID, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil || projectID == 0 {
+		return 0, 0, fmt.Errorf("invalid project id")
+	}
+	maintainerID, err := strconv.ParseUint(parts[2], 10, 64)
+	if err != nil || maintainerID == 0 {
+		return 0, 0, fmt.Errorf("invalid maintainer id")
+	}
+	return uint(projectID), uint(maintainerID), nil
+}
+
+// handleProjectMembership serves GET, PATCH and DELETE /api/projects/{id}/maintainers/{mid}: a maintainer's status
+// and role on one project, with its history, and removal from the project.
+func (s *server) handleProjectMembership(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if strings.HasSuffix(strings.TrimRight(r.URL.Path, "/"), "/maintainers/remove") {
+		s.handleProjectMembershipRemove(w, r)
+		return
+	}
+	if r.Method == http.MethodDelete {
+		s.handleProjectMembershipDelete(w, r)
+		return
+	}
+	projectID, maintainerID, err := parseMembershipPath(r.URL.Path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	session := sessionFromContext(r.Context())
+	if session == nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var membership *model.MaintainerProject
+	switch r.Method {
+	case http.MethodGet:
+		if session.Role != roleStaff && session.Role != roleMaintainer {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		membership, err = s.store.GetMembership(maintainerID, projectID)
+	case http.MethodPatch:
+		if session.Role != roleStaff {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		var req membershipUpdateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+		status := model.MaintainerStatus(strings.TrimSpace(req.Status))
+		if status != "" && !status.IsValid() {
+			http.Error(w, "invalid status", http.StatusBadRequest)
+			return
+		}
+		role := model.MembershipRole(strings.ToLower(strings.TrimSpace(req.Role)))
+		if role != "" && !role.IsValid() {
+			http.Error(w, "invalid role", http.StatusBadRequest)
+			return
+		}
+		membership, err = s.auditedStore(session).UpdateMembership(maintainerID, projectID, status, role)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err != nil {
+		if errors.Is(err, db.ErrMembershipNotFound) {
+			http.Error(w, "membership not found", http.StatusNotFound)
+			return
+		}
+		s.logger.Printf("web-bff: membership %s failed project=%d maintainer=%d err=%v", r.Method, projectID, maintainerID, err)
+		http.Error(w, "failed to load membership", http.StatusInternalServerError)
+		return
+	}
+
+	history, err := s.store.GetMembershipHistory(maintainerID, projectID)
+	if err != nil {
+		s.logger.Printf("web-bff: membership history failed project=%d maintainer=%d err=%v", projectID, maintainerID, err)
+	}
+	response := membershipResponse{
+		ProjectID:    membership.ProjectID,
+		MaintainerID: membership.MaintainerID,
+		Status:       string(membership.Status),
+		Role:         string(membership.Role),
+		JoinedAt:     membership.JoinedAt,
+		LeftAt:       membership.LeftAt,
+		History:      make([]membershipHistoryResponse, 0, len(history)),
+	}
+	for _, entry := range history {
+		response.History = append(response.History, membershipHistoryResponse{
+			Event:      entry.Event,
+			FromStatus: entry.FromStatus,
+			ToStatus:   entry.ToStatus,
+			FromRole:   entry.FromRole,
+			ToRole:     entry.ToRole,
+			Actor:      entry.ActorLogin,
+			CreatedAt:  entry.CreatedAt,
+		})
+	}
+	w.Header().Set(headerContentType, contentTypeJSON)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		s.logger.Printf("web-bff: handleProjectMembership encode error: %v", err)
+	}
+}
+
+// handleProjectMembershipDelete serves DELETE /api/projects/{id}/maintainers/{mid}. The maintainer record and their
+// other projects are kept. With ?cascadeServiceTeams=true their access to the project's service teams is revoked too.
+func (s *server) handleProjectMembershipDelete(w http.ResponseWriter, r *http.Request) {
+	projectID, maintainerID, err := parseMembershipPath(r.URL.Path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	session := sessionFromContext(r.Context())
+	if session == nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if session.Role != roleStaff {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	cascade := r.URL.Query().Get("cascadeServiceTeams") == "true"
+	removals, ok := s.removeMemberships(w, session, projectID, []uint{maintainerID}, cascade)
+	if !ok {
+		return
+	}
+	w.Header().Set(headerContentType, contentTypeJSON)
+	if err := json.NewEncoder(w).Encode(removals[0]); err != nil {
+		s.logger.Printf("web-bff: handleProjectMembershipDelete encode error: %v", err)
+	}
+}
+
+// handleProjectMembershipRemove serves POST /api/projects/{id}/maintainers/remove, which removes several maintainers
+// from the project at once.
+func (s *server) handleProjectMembershipRemove(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	projectID, err := parseIDParam(strings.TrimSuffix(strings.TrimRight(r.URL.Path, "/"), "/maintainers/remove"), "/api/projects/")
+	if err != nil {
+		http.Error(w, "invalid project id", http.StatusBadRequest)
+		return
+	}
+	session := sessionFromContext(r.Context())
+	if session == nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if session.Role != roleStaff {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	var req membershipRemoveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+	if len(req.MaintainerIDs) == 0 {
+		http.Error(w, "no maintainer ids provided", http.StatusBadRequest)
+		return
+	}
+	removals, ok := s.removeMemberships(w, session, projectID, uniqueIDs(req.MaintainerIDs), req.CascadeServiceTeams)
+	if !ok {
+		return
+	}
+	w.Header().Set(headerContentType, contentTypeJSON)
+	if err := json.NewEncoder(w).Encode(map[string]any{"removed": removals}); err != nil {
+		s.logger.Printf("web-bff: handleProjectMembershipRemove encode error: %v", err)
+	}
+}
+
+// removeMemberships removes the memberships through the audited store, then revokes any service team access the
+// removal cascaded to. It writes the error response and returns false if the removal failed.
+func (s *server) removeMemberships(w http.ResponseWriter, session *session, projectID uint, maintainerIDs []uint, cascade bool) ([]membershipRemovalResponse, bool) {
+	removals, err := s.auditedStore(session).RemoveMaintainersFromProject(projectID, maintainerIDs, cascade)
+	if err != nil {
+		if errors.Is(err, db.ErrMembershipNotFound) {
+			http.Error(w, "membership not found", http.StatusNotFound)
+			return nil, false
+		}
+		s.logger.Printf("web-bff: membership removal failed project=%d maintainers=%v user=%s err=%v", projectID, maintainerIDs, session.Login, err)
+		http.Error(w, "failed to remove maintainers", http.StatusInternalServerError)
+		return nil, false
+	}
+	responses := make([]membershipRemovalResponse, 0, len(removals))
+	for _, removal := range removals {
+		responses = append(responses, membershipRemovalResponse{
+			ProjectID:    removal.Membership.ProjectID,
+			MaintainerID: removal.Membership.MaintainerID,
+			ServiceTeams: s.revokeServiceTeams(session, removal),
+		})
+	}
+	return responses, true
+}
+
+// revokeServiceTeams removes the maintainer from the remote service teams their membership removal unlinked. Every
+// service team is currently a FOSSA team. Failures are reported per team rather than failing the removal, which has
+// already been committed. Each revocation is audited with the FOSSA identity of the acting foundation officer.
+func (s *server) revokeServiceTeams(session *session, removal db.MembershipRemoval) []serviceTeamRemovalResponse {
+	teams := make([]serviceTeamRemovalResponse, 0, len(removal.ServiceTeams))
+	for _, link := range removal.ServiceTeams {
+		team := serviceTeamRemovalResponse{ID: link.ServiceTeamID, Name: fmt.Sprintf("team %d", link.ServiceTeam.ServiceTeamID)}
+		if link.ServiceTeam.ServiceTeamName != nil {
+			team.Name = *link.ServiceTeam.ServiceTeamName
+		}
+		if s.removeFromServiceTeam == nil {
+			team.Error = "service credentials not configured"
+		} else if err := s.removeFromServiceTeam(link.ServiceTeam.ServiceTeamID, removal.Membership.Maintainer.Email); err != nil {
+			s.logger.Printf("web-bff: service team removal failed team=%d maintainer=%d err=%v", link.ServiceTeam.ServiceTeamID, removal.Membership.MaintainerID, err)
+			team.Error = err.Error()
+		} else {
+			team.Revoked = true
+			projectID, maintainerID := removal.Membership.ProjectID, removal.Membership.MaintainerID
+			if err := s.auditedStore(session).LogServiceAction("FOSSA", model.AuditLog{
+				ProjectID:    &projectID,
+				MaintainerID: &maintainerID,
+				Action:       "FOSSA_REMOVE_MEMBER",
+				Message:      fmt.Sprintf("Removed @%s from FOSSA team %s", removal.Membership.Maintainer.GitHubAccount, team.Name),
+			}); err != nil {
+				s.logger.Printf("web-bff: failed to audit service team removal team=%d maintainer=%d err=%v", link.ServiceTeam.ServiceTeamID, maintainerID, err)
+			}
+		}
+		teams = append(teams, team)
+	}
+	return teams
+}
+
+// uniqueIDs drops repeated IDs, keeping the first occurrence of each.
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	unique := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// applyMemberships fills in each maintainer's status and role on the project.
+func applyMemberships(maintainers []projectMaintainerDetail, memberships []model.MaintainerProject) {
+	byMaintainer := make(map[uint]model.MaintainerProject, len(memberships))
+	for _, membership := range memberships {
+		byMaintainer[membership.MaintainerID] = membership
+	}
+	for i := range maintainers {
+		membership, ok := byMaintainer[maintainers[i].ID]
+		if !ok {
+			continue
+		}
+		joinedAt := membership.JoinedAt
+		maintainers[i].ProjectStatus = string(membership.Status)
+		maintainers[i].Role = string(membership.Role)
+		maintainers[i].JoinedAt = &joinedAt
+		maintainers[i].LeftAt = membership.LeftAt
+	}
+}
+
+// maintainerProjects lists the maintainer's projects with their status and role on each.
+func (s *server) maintainerProjects(maintainer *model.Maintainer) []maintainerProjectResponse {
+	memberships, err := s.store.GetMaintainerMemberships(maintainer.ID)
+	if err != nil {
+		s.logger.Printf("web-bff: failed to load memberships maintainer=%d err=%v", maintainer.ID, err)
+	}
+	byProject := make(map[uint]model.MaintainerProject, len(memberships))
+	for _, membership := range memberships {
+		byProject[membership.ProjectID] = membership
+	}
+	projects := make([]maintainerProjectResponse, 0, len(maintainer.Projects))
+	for _, project := range maintainer.Projects {
+		item := maintainerProjectResponse{
+			ID:   project.ID,
+			Name: project.Name,
+		}
+		if membership, ok := byProject[project.ID]; ok {
+			joinedAt := membership.JoinedAt
+			item.Status = string(membership.Status)
+			item.Role = string(membership.Role)
+			item.JoinedAt = &joinedAt
+			item.LeftAt = membership.LeftAt
+		}
+		projects = append(projects, item)
+	}
+	return projects
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"maintainerd/db"
+	"maintainerd/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMembershipPath(t *testing.T) {
+	projectID, maintainerID, err := parseMembershipPath("/api/projects/12/maintainers/34")
+	require.NoError(t, err)
+	assert.Equal(t, uint(12), projectID)
+	assert.Equal(t, uint(34), maintainerID)
+
+	for _, path := range []string{
+		"/api/projects/12/maintainers/",
+		"/api/projects/12/maintainers/remove",
+		"/api/projects/x/maintainers/34",
+		"/api/projects/12/owners/34",
+	} {
+		_, _, err := parseMembershipPath(path)
+		assert.Error(t, err, path)
+	}
+}
+
+func TestUniqueIDs(t *testing.T) {
+	assert.Equal(t, []uint{3, 1, 2}, uniqueIDs([]uint{3, 1, 3, 2, 1}))
+}
+
+func TestHandleProjectMembershipDelete(t *testing.T) {
+	dbConn := setupPostgresTestDB(t)
+	store := db.NewSQLStore(dbConn)
+	now := time.Now()
+
+	staff := model.StaffMember{Name: "Staff Tester", GitHubAccount: "staff-tester"}
+	require.NoError(t, dbConn.Create(&staff).Error)
+	project := model.Project{Name: "Cedar", Maturity: model.Sandbox}
+	require.NoError(t, dbConn.Create(&project).Error)
+	other := model.Project{Name: "Birch", Maturity: model.Sandbox}
+	require.NoError(t, dbConn.Create(&other).Error)
+	maintainer := model.Maintainer{
+		Name:             "Sam Quill",
+		Email:            "sam.quill@example.invalid",
+		GitHubAccount:    "samquill",
+		MaintainerStatus: model.ActiveMaintainer,
+		Projects:         []model.Project{project, other},
+	}
+	require.NoError(t, dbConn.Create(&maintainer).Error)
+	teamName := "cedar-team"
+	team := model.ServiceTeam{ProjectID: project.ID, ServiceID: 1, ServiceTeamID: 55, ServiceTeamName: &teamName}
+	require.NoError(t, dbConn.Create(&team).Error)
+	require.NoError(t, dbConn.Create(&model.ServiceUserTeams{ServiceID: 1, ServiceTeamID: team.ID, MaintainerID: &maintainer.ID}).Error)
+
+	var revoked []string
+	s := &server{
+		store:      store,
+		sessions:   newSessionStore(log.New(io.Discard, "", 0)),
+		cookieName: defaultSessionCookieName,
+		logger:     log.New(io.Discard, "", 0),
+		removeFromServiceTeam: func(teamID int, email string) error {
+			revoked = append(revoked, fmt.Sprintf("%d:%s", teamID, email))
+			return nil
+		},
+	}
+	s.sessions.Set(session{
+		ID:        "staff-session",
+		Login:     staff.GitHubAccount,
+		Role:      roleStaff,
+		CreatedAt: now,
+		ExpiresAt: now.Add(time.Hour),
+	})
+
+	path := fmt.Sprintf("/api/projects/%d/maintainers/%d?cascadeServiceTeams=true", project.ID, maintainer.ID)
+	req := httptest.NewRequest(http.MethodDelete, path, nil)
+	req.AddCookie(&http.Cookie{Name: s.cookieName, Value: "staff-session"})
+	rec := httptest.NewRecorder()
+	s.requireSession(http.HandlerFunc(s.handleProject)).ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var response membershipRemovalResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Len(t, response.ServiceTeams, 1)
+	assert.Equal(t, "cedar-team", response.ServiceTeams[0].Name)
+	assert.True(t, response.ServiceTeams[0].Revoked)
+	assert.Equal(t, []string{"55:sam.quill@example.invalid"}, revoked)
+
+	remaining, err := store.GetMaintainerMemberships(maintainer.ID)
+	require.NoError(t, err)
+	require.Len(t, remaining, 1)
+	assert.Equal(t, other.ID, remaining[0].ProjectID)
+
+	var audit model.AuditLog
+	require.NoError(t, dbConn.Where("action = ?", "MEMBERSHIP_REMOVE").First(&audit).Error)
+	assert.Equal(t, maintainer.ID, *audit.MaintainerID)
+
+	body := fmt.Sprintf(`{"maintainerIds":[%d]}`, maintainer.ID)
+	req = httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/projects/%d/maintainers/remove", project.ID), strings.NewReader(body))
+	req.AddCookie(&http.Cookie{Name: s.cookieName, Value: "staff-session"})
+	rec = httptest.NewRecorder()
+	s.requireSession(http.HandlerFunc(s.handleProject)).ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotFound, rec.Code, "already removed")
+}
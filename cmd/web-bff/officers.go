@@ -0,0 +1,371 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"maintainerd/db"
+	"maintainerd/model"
+)
+
+type officerServiceResponse struct {
+	ID           uint   `json:"id"`
+	ServiceID    uint   `json:"serviceId"`
+	RemoteUserID int    `json:"remoteUserId,omitempty"`
+	Email        string `json:"email,omitempty"`
+	Ref          string `json:"ref,omitempty"`
+	GitHubName   string `json:"githubName,omitempty"`
+}
+
+type officerResponse struct {
+	ID            uint                     `json:"id"`
+	Name          string                   `json:"name"`
+	Email         string                   `json:"email,omitempty"`
+	GitHubAccount string                   `json:"githubAccount,omitempty"`
+	CompanyID     *uint                    `json:"companyId,omitempty"`
+	RegisteredAt  *time.Time               `json:"registeredAt,omitempty"`
+	Services      []officerServiceResponse `json:"services"`
+}
+
+// officerRequest is the body of POST /api/officers and PATCH /api/officers/{id}. On PATCH only fields present are
+// changed, and a companyId of 0 clears the company.
+type officerRequest struct {
+	Name          *string `json:"name"`
+	Email         *string `json:"email"`
+	GitHubAccount *string `json:"githubAccount"`
+	CompanyID     *uint   `json:"companyId"`
+}
+
+// officerServiceRequest is the body of POST /api/officers/{id}/services.
+type officerServiceRequest struct {
+	ServiceID    uint   `json:"serviceId"`
+	RemoteUserID int    `json:"remoteUserId"`
+	Email        string `json:"email"`
+	Ref          string `json:"ref"`
+	GitHubName   string `json:"githubName"`
+}
+
+type serviceResponse struct {
+	ID              uint   `json:"id"`
+	Name            string `json:"name"`
+	Description     string `json:"description,omitempty"`
+	ActingOfficerID *uint  `json:"actingOfficerId,omitempty"`
+}
+
+type actingOfficerRequest struct {
+	OfficerID uint `json:"officerId"`
+}
+
+func newOfficerServiceResponse(user model.ServiceUser) officerServiceResponse {
+	resp := officerServiceResponse{
+		ID:           user.ID,
+		ServiceID:    user.ServiceID,
+		RemoteUserID: user.ServiceUserID,
+		Ref:          user.ServiceRef,
+	}
+	if user.ServiceEmail != "EMAIL_MISSING" {
+		resp.Email = user.ServiceEmail
+	}
+	if user.ServiceGitHubName != nil {
+		resp.GitHubName = *user.ServiceGitHubName
+	}
+	return resp
+}
+
+func newOfficerResponse(officer model.FoundationOfficer) officerResponse {
+	resp := officerResponse{
+		ID:           officer.ID,
+		Name:         officer.Name,
+		CompanyID:    officer.CompanyID,
+		RegisteredAt: officer.RegisteredAt,
+		Services:     make([]officerServiceResponse, 0, len(officer.Services)),
+	}
+	if officer.Email != "EMAIL_MISSING" {
+		resp.Email = officer.Email
+	}
+	if officer.GitHubAccount != "GITHUB_MISSING" {
+		resp.GitHubAccount = officer.GitHubAccount
+	}
+	for _, user := range officer.Services {
+		resp.Services = append(resp.Services, newOfficerServiceResponse(user))
+	}
+	return resp
+}
+
+// handleOfficers serves GET /api/officers, listing foundation officers with their service identities, and POST,
+// which registers one. Any staff member can list; only admins can register officers.
+func (s *server) handleOfficers(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	session := sessionFromContext(r.Context())
+	if session == nil || session.Role != roleStaff {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		officers, err := s.store.ListFoundationOfficers()
+		if err != nil {
+			s.logger.Printf("web-bff: handleOfficers list error: %v", err)
+			http.Error(w, "failed to load officers", http.StatusInternalServerError)
+			return
+		}
+		resp := make([]officerResponse, 0, len(officers))
+		for _, officer := range officers {
+			resp = append(resp, newOfficerResponse(officer))
+		}
+		w.Header().Set(headerContentType, contentTypeJSON)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			s.logger.Printf("web-bff: handleOfficers encode error: %v", err)
+		}
+	case http.MethodPost:
+		if !s.isStaffAdmin(session) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		var req officerRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Name == nil {
+			http.Error(w, "name is required", http.StatusBadRequest)
+			return
+		}
+		input := db.FoundationOfficerInput{Name: *req.Name, CompanyID: req.CompanyID}
+		if req.Email != nil {
+			input.Email = *req.Email
+		}
+		if req.GitHubAccount != nil {
+			input.GitHubAccount = *req.GitHubAccount
+		}
+		officer, err := s.auditedStore(session).CreateFoundationOfficer(input)
+		if err != nil {
+			s.writeOfficerError(w, "create", err)
+			return
+		}
+		w.Header().Set(headerContentType, contentTypeJSON)
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(newOfficerResponse(*officer)); err != nil {
+			s.logger.Printf("web-bff: handleOfficers encode error: %v", err)
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleOfficer serves GET, PATCH and DELETE on /api/officers/{id}, POST /api/officers/{id}/services, which links
+// the officer to their account on a service, and DELETE /api/officers/{id}/services/{serviceUserId}. Any staff
+// member can read; only admins can make changes.
+func (s *server) handleOfficer(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	idPart, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/officers/"), "/")
+	id, err := parseIDParam(idPart, "")
+	if err != nil {
+		http.Error(w, "invalid officer id", http.StatusBadRequest)
+		return
+	}
+	session := sessionFromContext(r.Context())
+	if session == nil || session.Role != roleStaff {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	if r.Method != http.MethodGet && !s.isStaffAdmin(session) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	if action == "services" {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		var req officerServiceRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ServiceID == 0 {
+			http.Error(w, "serviceId is required", http.StatusBadRequest)
+			return
+		}
+		user, err := s.auditedStore(session).LinkOfficerService(id, db.OfficerServiceInput{
+			ServiceID:  req.ServiceID,
+			RemoteID:   req.RemoteUserID,
+			Email:      req.Email,
+			Ref:        req.Ref,
+			GitHubName: req.GitHubName,
+		})
+		if err != nil {
+			s.writeOfficerError(w, "link", err)
+			return
+		}
+		w.Header().Set(headerContentType, contentTypeJSON)
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(newOfficerServiceResponse(*user)); err != nil {
+			s.logger.Printf("web-bff: handleOfficer encode error: %v", err)
+		}
+		return
+	}
+	if userPart, ok := strings.CutPrefix(action, "services/"); ok {
+		if r.Method != http.MethodDelete {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		serviceUserID, err := parseIDParam(userPart, "")
+		if err != nil {
+			http.Error(w, "invalid service user id", http.StatusBadRequest)
+			return
+		}
+		if err := s.auditedStore(session).UnlinkOfficerService(id, serviceUserID); err != nil {
+			s.writeOfficerError(w, "unlink", err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if action != "" {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	var officer *model.FoundationOfficer
+	switch r.Method {
+	case http.MethodGet:
+		officer, err = s.store.GetFoundationOfficer(id)
+	case http.MethodPatch:
+		var req officerRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+		officer, err = s.auditedStore(session).UpdateFoundationOfficer(id, db.FoundationOfficerUpdate{
+			Name:          req.Name,
+			Email:         req.Email,
+			GitHubAccount: req.GitHubAccount,
+			CompanyID:     req.CompanyID,
+		})
+	case http.MethodDelete:
+		if err := s.auditedStore(session).DeleteFoundationOfficer(id); err != nil {
+			s.writeOfficerError(w, "delete", err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err != nil {
+		s.writeOfficerError(w, strings.ToLower(r.Method), err)
+		return
+	}
+	w.Header().Set(headerContentType, contentTypeJSON)
+	if err := json.NewEncoder(w).Encode(newOfficerResponse(*officer)); err != nil {
+		s.logger.Printf("web-bff: handleOfficer encode error: %v", err)
+	}
+}
+
+// handleServices serves GET /api/services, listing the external services with their acting officers. Staff only.
+func (s *server) handleServices(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	session := sessionFromContext(r.Context())
+	if session == nil || session.Role != roleStaff {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	services, err := s.store.ListServices()
+	if err != nil {
+		s.logger.Printf("web-bff: handleServices list error: %v", err)
+		http.Error(w, "failed to load services", http.StatusInternalServerError)
+		return
+	}
+	resp := make([]serviceResponse, 0, len(services))
+	for _, service := range services {
+		resp = append(resp, newServiceResponse(service))
+	}
+	w.Header().Set(headerContentType, contentTypeJSON)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		s.logger.Printf("web-bff: handleServices encode error: %v", err)
+	}
+}
+
+// handleService serves PATCH /api/services/{id}/acting-officer, which chooses the foundation officer whose identity
+// on the service performs automated actions such as FOSSA onboarding. An officerId of 0 clears the choice. Admins
+// only.
+func (s *server) handleService(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	idPart, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/services/"), "/")
+	id, err := parseIDParam(idPart, "")
+	if err != nil {
+		http.Error(w, "invalid service id", http.StatusBadRequest)
+		return
+	}
+	session := sessionFromContext(r.Context())
+	if !s.isStaffAdmin(session) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	if action != "acting-officer" {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	if r.Method != http.MethodPatch {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req actingOfficerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request", http.StatusBadRequest)
+		return
+	}
+	service, err := s.auditedStore(session).SetActingOfficer(id, req.OfficerID)
+	if err != nil {
+		s.writeOfficerError(w, "acting officer", err)
+		return
+	}
+	w.Header().Set(headerContentType, contentTypeJSON)
+	if err := json.NewEncoder(w).Encode(newServiceResponse(*service)); err != nil {
+		s.logger.Printf("web-bff: handleService encode error: %v", err)
+	}
+}
+
+func newServiceResponse(service model.Service) serviceResponse {
+	return serviceResponse{
+		ID:              service.ID,
+		Name:            service.Name,
+		Description:     service.Description,
+		ActingOfficerID: service.ActingOfficerID,
+	}
+}
+
+func (s *server) writeOfficerError(w http.ResponseWriter, op string, err error) {
+	switch {
+	case errors.Is(err, db.ErrOfficerNotFound):
+		http.Error(w, "officer not found", http.StatusNotFound)
+	case errors.Is(err, db.ErrServiceNotFound):
+		http.Error(w, "service not found", http.StatusNotFound)
+	case errors.Is(err, db.ErrServiceUserNotFound):
+		http.Error(w, "service user not linked to officer", http.StatusNotFound)
+	case errors.Is(err, db.ErrCompanyNotFound):
+		http.Error(w, "company not found", http.StatusBadRequest)
+	case errors.Is(err, db.ErrOfficerExists), errors.Is(err, db.ErrOfficerActing):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, db.ErrServiceIdentity), errors.Is(err, db.ErrOfficerNotLinked):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		s.logger.Printf("web-bff: officer %s error: %v", op, err)
+		http.Error(w, "failed to update officer", http.StatusBadRequest)
+	}
+}
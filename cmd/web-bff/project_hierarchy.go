@@ -0,0 +1,241 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"maintainerd/db"
+	"maintainerd/model"
+)
+
+const (
+	subprojectsFlat     = "flat"
+	subprojectsNest     = "nest"
+	subprojectsCollapse = "collapse"
+)
+
+type projectChildrenResponse struct {
+	ProjectID uint             `json:"projectId"`
+	Children  []projectSummary `json:"children"`
+}
+
+type familyMaintainerResponse struct {
+	ID         uint   `json:"id"`
+	Name       string `json:"name"`
+	GitHub     string `json:"github"`
+	Status     string `json:"status"`
+	Company    string `json:"company,omitempty"`
+	ProjectIDs []uint `json:"projectIds"`
+}
+
+type projectFamilyResponse struct {
+	ProjectID   uint                       `json:"projectId"`
+	ProjectIDs  []uint                     `json:"projectIds"`
+	Maintainers []familyMaintainerResponse `json:"maintainers"`
+}
+
+// projectParentRequest is the body of PATCH /api/projects/{id}/parent. A null parentProjectId makes the project
+// top-level.
+type projectParentRequest struct {
+	ParentProjectID *uint `json:"parentProjectId"`
+}
+
+// isProjectHierarchyPath reports whether path is one of the sub-project endpoints under /api/projects/{id}.
+func isProjectHierarchyPath(path string) bool {
+	path = strings.TrimRight(path, "/")
+	return strings.HasSuffix(path, "/children") || strings.HasSuffix(path, "/family") || strings.HasSuffix(path, "/parent")
+}
+
+// handleProjectHierarchy serves GET /api/projects/{id}/children, the project's direct sub-projects; GET
+// /api/projects/{id}/family, the rolled-up maintainers of the project and all of its sub-projects; and PATCH
+// /api/projects/{id}/parent, which moves the project under another (staff only).
+func (s *server) handleProjectHierarchy(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	idPart, action, _ := strings.Cut(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/projects/"), "/"), "/")
+	id, err := parseIDParam(idPart, "")
+	if err != nil {
+		http.Error(w, "invalid project id", http.StatusBadRequest)
+		return
+	}
+	session := sessionFromContext(r.Context())
+	if session == nil {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if session.Role == roleMaintainer && r.Method == http.MethodGet {
+		if _, err := s.getSessionMaintainer(session); err != nil {
+			s.logger.Printf("web-bff: maintainer access denied project=%d user=%s role=%s reason=%v", id, session.Login, session.Role, err)
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+	} else if session.Role != roleStaff {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	var resp any
+	switch {
+	case action == "children" && r.Method == http.MethodGet:
+		if _, err := s.store.GetProjectByID(id); err != nil {
+			s.writeProjectHierarchyError(w, id, err)
+			return
+		}
+		children, err := s.store.ListChildProjects(id)
+		if err != nil {
+			s.writeProjectHierarchyError(w, id, err)
+			return
+		}
+		summaries := make([]projectSummary, 0, len(children))
+		for _, child := range children {
+			summaries = append(summaries, summarizeProject(child))
+		}
+		resp = projectChildrenResponse{ProjectID: id, Children: summaries}
+	case action == "family" && r.Method == http.MethodGet:
+		projectIDs, maintainers, err := s.store.ListProjectFamilyMaintainers(id)
+		if err != nil {
+			s.writeProjectHierarchyError(w, id, err)
+			return
+		}
+		family := projectFamilyResponse{
+			ProjectID:   id,
+			ProjectIDs:  projectIDs,
+			Maintainers: make([]familyMaintainerResponse, 0, len(maintainers)),
+		}
+		for _, entry := range maintainers {
+			family.Maintainers = append(family.Maintainers, familyMaintainerResponse{
+				ID:         entry.Maintainer.ID,
+				Name:       entry.Maintainer.Name,
+				GitHub:     entry.Maintainer.GitHubAccount,
+				Status:     string(entry.Maintainer.MaintainerStatus),
+				Company:    entry.Maintainer.Company.Name,
+				ProjectIDs: entry.ProjectIDs,
+			})
+		}
+		resp = family
+	case action == "parent" && r.Method == http.MethodPatch:
+		var req projectParentRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+		if err := s.auditedStore(session).SetProjectParent(id, req.ParentProjectID); err != nil {
+			s.writeProjectHierarchyError(w, id, err)
+			return
+		}
+		resp = map[string]any{"status": "ok", "parentProjectId": req.ParentProjectID}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set(headerContentType, contentTypeJSON)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		s.logger.Printf("web-bff: handleProjectHierarchy encode error: %v", err)
+	}
+}
+
+func (s *server) writeProjectHierarchyError(w http.ResponseWriter, projectID uint, err error) {
+	switch {
+	case errors.Is(err, db.ErrProjectNotFound):
+		http.Error(w, "project not found", http.StatusNotFound)
+	case errors.Is(err, db.ErrParentProjectNotFound):
+		http.Error(w, "parent project not found", http.StatusBadRequest)
+	case errors.Is(err, db.ErrProjectCycle):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		s.logger.Printf("web-bff: project hierarchy failed id=%d err=%v", projectID, err)
+		http.Error(w, "failed to load project hierarchy", http.StatusInternalServerError)
+	}
+}
+
+func summarizeProject(project model.Project) projectSummary {
+	return projectSummary{
+		ID:              project.ID,
+		Name:            project.Name,
+		Maturity:        string(project.Maturity),
+		ParentProjectID: project.ParentProjectID,
+		Maintainers:     summarizeMaintainers(project.Maintainers),
+	}
+}
+
+// groupSubprojects attaches each top-level project's sub-projects according to mode: nest builds the sub-project
+// tree under each project, and collapse folds sub-project maintainers into the top-level project and counts the
+// sub-projects instead.
+func (s *server) groupSubprojects(projects []projectSummary, mode string) error {
+	if len(projects) == 0 || mode == subprojectsFlat {
+		return nil
+	}
+	rootIDs := make([]uint, 0, len(projects))
+	for _, project := range projects {
+		rootIDs = append(rootIDs, project.ID)
+	}
+	descendants, err := s.store.ListProjectDescendants(rootIDs)
+	if err != nil {
+		return err
+	}
+	children := make(map[uint][]model.Project)
+	for _, project := range descendants {
+		children[*project.ParentProjectID] = append(children[*project.ParentProjectID], project)
+	}
+
+	for i := range projects {
+		if mode == subprojectsNest {
+			projects[i].Subprojects = nestSubprojects(projects[i].ID, children)
+			continue
+		}
+		var maintainers []model.Maintainer
+		count := 0
+		pending := append([]model.Project(nil), children[projects[i].ID]...)
+		for len(pending) > 0 {
+			next := pending[0]
+			pending = append(pending[1:], children[next.ID]...)
+			maintainers = append(maintainers, next.Maintainers...)
+			count++
+		}
+		if count == 0 {
+			continue
+		}
+		rolled := summarizeMaintainers(maintainers)
+		seen := make(map[uint]struct{}, len(projects[i].Maintainers))
+		for _, maintainer := range projects[i].Maintainers {
+			seen[maintainer.ID] = struct{}{}
+		}
+		for _, maintainer := range rolled {
+			if _, ok := seen[maintainer.ID]; ok {
+				continue
+			}
+			seen[maintainer.ID] = struct{}{}
+			projects[i].Maintainers = append(projects[i].Maintainers, maintainer)
+		}
+		projects[i].SubprojectCount = count
+	}
+	return nil
+}
+
+func nestSubprojects(parentID uint, children map[uint][]model.Project) []projectSummary {
+	if len(children[parentID]) == 0 {
+		return nil
+	}
+	nested := make([]projectSummary, 0, len(children[parentID]))
+	for _, child := range children[parentID] {
+		summary := summarizeProject(child)
+		summary.Subprojects = nestSubprojects(child.ID, children)
+		nested = append(nested, summary)
+	}
+	return nested
+}
+
+// parseSubprojectsParam reads the subprojects query parameter; anything other than nest or collapse is flat.
+func parseSubprojectsParam(r *http.Request) string {
+	switch mode := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("subprojects"))); mode {
+	case subprojectsNest, subprojectsCollapse:
+		return mode
+	default:
+		return subprojectsFlat
+	}
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"maintainerd/db"
+	"maintainerd/model"
+)
+
+type maturityEventResponse struct {
+	ID           uint      `json:"id"`
+	From         string    `json:"from,omitempty"`
+	To           string    `json:"to"`
+	EffectiveAt  time.Time `json:"effectiveAt"`
+	VoteIssueURL string    `json:"voteIssueUrl,omitempty"`
+	ActorLogin   string    `json:"actorLogin,omitempty"`
+}
+
+type projectLifecycleResponse struct {
+	ProjectID      uint                    `json:"projectId"`
+	Name           string                  `json:"name"`
+	Maturity       string                  `json:"maturity"`
+	Aliases        []string                `json:"aliases"`
+	MaturityEvents []maturityEventResponse `json:"maturityEvents"`
+	// Transitions lists the maturity levels the project can move to next.
+	Transitions []string `json:"transitions"`
+}
+
+// maturityTransitionRequest is the body of POST /api/projects/{id}/lifecycle/maturity. effectiveAt is an RFC 3339
+// time or a YYYY-MM-DD date and defaults to now.
+type maturityTransitionRequest struct {
+	Maturity     string `json:"maturity"`
+	EffectiveAt  string `json:"effectiveAt"`
+	VoteIssueURL string `json:"voteIssueUrl"`
+}
+
+type projectRenameRequest struct {
+	Name string `json:"name"`
+}
+
+// handleProjectLifecycle serves GET /api/projects/{id}/lifecycle, the project's maturity history and former
+// names; POST /api/projects/{id}/lifecycle/maturity, which moves the project to its next maturity level (archiving
+// also archives its memberships and flags its service teams for cleanup); and POST
+// /api/projects/{id}/lifecycle/rename, which renames the project and keeps the old name as an alias. Staff only.
+func (s *server) handleProjectLifecycle(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	idPart, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/projects/"), "/lifecycle")
+	id, err := parseIDParam(idPart, "")
+	if err != nil {
+		http.Error(w, "invalid project id", http.StatusBadRequest)
+		return
+	}
+	session := sessionFromContext(r.Context())
+	if session == nil || session.Role != roleStaff {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+	case action == "/maturity" && r.Method == http.MethodPost:
+		var req maturityTransitionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+		to, ok := parseMaturity(req.Maturity)
+		if !ok {
+			http.Error(w, "invalid maturity", http.StatusBadRequest)
+			return
+		}
+		effectiveAt, err := parseEffectiveAt(req.EffectiveAt)
+		if err != nil {
+			http.Error(w, "effectiveAt must be an RFC 3339 time or YYYY-MM-DD date", http.StatusBadRequest)
+			return
+		}
+		voteURL := strings.TrimSpace(req.VoteIssueURL)
+		if voteURL != "" && !strings.HasPrefix(voteURL, "https://") {
+			http.Error(w, "voteIssueUrl must be an https URL", http.StatusBadRequest)
+			return
+		}
+		if _, err := s.auditedStore(session).TransitionProjectMaturity(id, db.MaturityTransition{
+			To:           to,
+			EffectiveAt:  effectiveAt,
+			VoteIssueURL: voteURL,
+		}); err != nil {
+			s.writeProjectLifecycleError(w, id, err)
+			return
+		}
+	case action == "/rename" && r.Method == http.MethodPost:
+		var req projectRenameRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || strings.TrimSpace(req.Name) == "" {
+			http.Error(w, "name is required", http.StatusBadRequest)
+			return
+		}
+		if _, err := s.auditedStore(session).RenameProject(id, req.Name); err != nil {
+			s.writeProjectLifecycleError(w, id, err)
+			return
+		}
+	case action == "" || action == "/maturity" || action == "/rename":
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	default:
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+
+	resp, err := s.projectLifecycle(id)
+	if err != nil {
+		s.writeProjectLifecycleError(w, id, err)
+		return
+	}
+	w.Header().Set(headerContentType, contentTypeJSON)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		s.logger.Printf("web-bff: handleProjectLifecycle encode error: %v", err)
+	}
+}
+
+func (s *server) projectLifecycle(projectID uint) (*projectLifecycleResponse, error) {
+	project, err := s.store.GetProjectByID(projectID)
+	if err != nil {
+		return nil, err
+	}
+	aliases, err := s.store.ListProjectAliases(projectID)
+	if err != nil {
+		return nil, err
+	}
+	events, err := s.store.ListProjectMaturityEvents(projectID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &projectLifecycleResponse{
+		ProjectID:      project.ID,
+		Name:           project.Name,
+		Maturity:       string(project.Maturity),
+		Aliases:        make([]string, 0, len(aliases)),
+		MaturityEvents: make([]maturityEventResponse, 0, len(events)),
+		Transitions:    []string{},
+	}
+	for _, alias := range aliases {
+		resp.Aliases = append(resp.Aliases, alias.Name)
+	}
+	for _, event := range events {
+		item := maturityEventResponse{
+			ID:          event.ID,
+			To:          string(event.ToMaturity),
+			EffectiveAt: event.EffectiveAt,
+			ActorLogin:  event.ActorLogin,
+		}
+		if event.FromMaturity != nil {
+			item.From = string(*event.FromMaturity)
+		}
+		if event.VoteIssueURL != nil {
+			item.VoteIssueURL = *event.VoteIssueURL
+		}
+		resp.MaturityEvents = append(resp.MaturityEvents, item)
+	}
+	for _, next := range []model.Maturity{model.Sandbox, model.Incubating, model.Graduated, model.Archived} {
+		if next != project.Maturity && db.CanTransitionMaturity(project.Maturity, next) {
+			resp.Transitions = append(resp.Transitions, string(next))
+		}
+	}
+	return resp, nil
+}
+
+func (s *server) writeProjectLifecycleError(w http.ResponseWriter, projectID uint, err error) {
+	switch {
+	case errors.Is(err, db.ErrProjectNotFound):
+		http.Error(w, "project not found", http.StatusNotFound)
+	case errors.Is(err, db.ErrInvalidMaturityTransition):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, db.ErrProjectExists):
+		http.Error(w, "a project with that name already exists", http.StatusConflict)
+	default:
+		s.logger.Printf("web-bff: project lifecycle failed id=%d err=%v", projectID, err)
+		http.Error(w, "failed to update project", http.StatusInternalServerError)
+	}
+}
+
+// parseEffectiveAt parses an RFC 3339 time or a YYYY-MM-DD date. An empty value is the zero time.
+func parseEffectiveAt(raw string) (time.Time, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	if parsed, err := time.Parse(time.RFC3339, raw); err == nil {
+		return parsed, nil
+	}
+	return time.Parse("2006-01-02", raw)
+}
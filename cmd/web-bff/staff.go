@@ -0,0 +1,216 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"maintainerd/db"
+	"maintainerd/model"
+)
+
+type staffMemberResponse struct {
+	ID             uint       `json:"id"`
+	Name           string     `json:"name"`
+	Email          string     `json:"email,omitempty"`
+	GitHubAccount  string     `json:"githubAccount"`
+	FoundationID   *uint      `json:"foundationId,omitempty"`
+	FoundationName string     `json:"foundationName,omitempty"`
+	Admin          bool       `json:"admin"`
+	Active         bool       `json:"active"`
+	DeactivatedAt  *time.Time `json:"deactivatedAt,omitempty"`
+}
+
+// staffMemberRequest is the body of POST /api/staff and PATCH /api/staff/{id}. On PATCH only fields present are
+// changed, and a foundationId of 0 removes the member from their foundation.
+type staffMemberRequest struct {
+	Name          *string `json:"name"`
+	Email         *string `json:"email"`
+	GitHubAccount *string `json:"githubAccount"`
+	FoundationID  *uint   `json:"foundationId"`
+	Admin         *bool   `json:"admin"`
+}
+
+func newStaffMemberResponse(staff model.StaffMember) staffMemberResponse {
+	resp := staffMemberResponse{
+		ID:            staff.ID,
+		Name:          staff.Name,
+		GitHubAccount: staff.GitHubAccount,
+		FoundationID:  staff.FoundationID,
+		Admin:         staff.Admin,
+		Active:        staff.DeactivatedAt == nil,
+		DeactivatedAt: staff.DeactivatedAt,
+	}
+	if staff.Email != "EMAIL_MISSING" {
+		resp.Email = staff.Email
+	}
+	if staff.FoundationID != nil {
+		resp.FoundationName = staff.Foundation.Name
+	}
+	return resp
+}
+
+// isStaffAdmin reports whether the session belongs to a staff admin, either by the stored flag or by
+// BFF_STAFF_ADMINS.
+func (s *server) isStaffAdmin(sess *session) bool {
+	if sess == nil || sess.Role != roleStaff {
+		return false
+	}
+	if s.staffAdmins[strings.ToLower(sess.Login)] {
+		return true
+	}
+	admin, err := s.store.IsStaffAdmin(sess.Login, sess.GitHubID)
+	if err != nil {
+		s.logger.Printf("web-bff: staff admin check failed user=%s: %v", sess.Login, err)
+		return false
+	}
+	return admin
+}
+
+// handleStaff serves GET /api/staff, which lists every staff member including deactivated ones, and POST, which
+// adds one. Any staff member can list; only admins can add.
+func (s *server) handleStaff(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	session := sessionFromContext(r.Context())
+	if session == nil || session.Role != roleStaff {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		staff, err := s.store.ListAllStaffMembers()
+		if err != nil {
+			s.logger.Printf("web-bff: handleStaff list error: %v", err)
+			http.Error(w, "failed to load staff", http.StatusInternalServerError)
+			return
+		}
+		resp := make([]staffMemberResponse, 0, len(staff))
+		for _, member := range staff {
+			resp = append(resp, newStaffMemberResponse(member))
+		}
+		w.Header().Set(headerContentType, contentTypeJSON)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			s.logger.Printf("web-bff: handleStaff encode error: %v", err)
+		}
+	case http.MethodPost:
+		if !s.isStaffAdmin(session) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		var req staffMemberRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Name == nil || req.GitHubAccount == nil {
+			http.Error(w, "name and githubAccount are required", http.StatusBadRequest)
+			return
+		}
+		input := db.StaffMemberInput{
+			Name:          *req.Name,
+			GitHubAccount: *req.GitHubAccount,
+			FoundationID:  req.FoundationID,
+		}
+		if req.Email != nil {
+			input.Email = *req.Email
+		}
+		if req.Admin != nil {
+			input.Admin = *req.Admin
+		}
+		staff, err := s.auditedStore(session).CreateStaffMember(input)
+		if err != nil {
+			s.writeStaffError(w, "create", err)
+			return
+		}
+		w.Header().Set(headerContentType, contentTypeJSON)
+		w.WriteHeader(http.StatusCreated)
+		if err := json.NewEncoder(w).Encode(newStaffMemberResponse(*staff)); err != nil {
+			s.logger.Printf("web-bff: handleStaff encode error: %v", err)
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleStaffMember serves /api/staff/{id}: GET, PATCH to change details, foundation or admin permission, DELETE to
+// deactivate and POST /api/staff/{id}/reactivate. Changes are admin only; deactivation also ends the member's
+// open staff sessions.
+func (s *server) handleStaffMember(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	path := strings.TrimPrefix(r.URL.Path, "/api/staff/")
+	idPart, action, _ := strings.Cut(path, "/")
+	id, err := parseIDParam(idPart, "")
+	if err != nil {
+		http.Error(w, "invalid staff id", http.StatusBadRequest)
+		return
+	}
+	session := sessionFromContext(r.Context())
+	if session == nil || session.Role != roleStaff {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+	if r.Method != http.MethodGet && !s.isStaffAdmin(session) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	var staff *model.StaffMember
+	switch {
+	case action == "reactivate" && r.Method == http.MethodPost:
+		staff, err = s.auditedStore(session).ReactivateStaffMember(id)
+	case action != "":
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	case r.Method == http.MethodGet:
+		staff, err = s.store.GetStaffMember(id)
+	case r.Method == http.MethodPatch:
+		var req staffMemberRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+		staff, err = s.auditedStore(session).UpdateStaffMember(id, db.StaffMemberUpdate{
+			Name:          req.Name,
+			Email:         req.Email,
+			GitHubAccount: req.GitHubAccount,
+			FoundationID:  req.FoundationID,
+			Admin:         req.Admin,
+		})
+	case r.Method == http.MethodDelete:
+		if staff, err = s.auditedStore(session).DeactivateStaffMember(id); err == nil {
+			if ended := s.sessions.DeleteByLogin(staff.GitHubAccount, roleStaff); ended > 0 {
+				s.logger.Printf("web-bff: ended %d sessions of deactivated staff member user=%s", ended, staff.GitHubAccount)
+			}
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err != nil {
+		s.writeStaffError(w, strings.ToLower(r.Method), err)
+		return
+	}
+	w.Header().Set(headerContentType, contentTypeJSON)
+	if err := json.NewEncoder(w).Encode(newStaffMemberResponse(*staff)); err != nil {
+		s.logger.Printf("web-bff: handleStaffMember encode error: %v", err)
+	}
+}
+
+func (s *server) writeStaffError(w http.ResponseWriter, op string, err error) {
+	switch {
+	case errors.Is(err, db.ErrStaffNotFound):
+		http.Error(w, "staff member not found", http.StatusNotFound)
+	case errors.Is(err, db.ErrFoundationNotFound):
+		http.Error(w, "foundation not found", http.StatusBadRequest)
+	case errors.Is(err, db.ErrStaffExists), errors.Is(err, db.ErrLastAdmin):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		s.logger.Printf("web-bff: staff %s error: %v", op, err)
+		http.Error(w, "failed to update staff member", http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionStoreDeleteByLogin(t *testing.T) {
+	store := newSessionStore(nil)
+	expires := time.Now().Add(time.Hour)
+	store.Set(session{ID: "a", Login: "Dana", Role: roleStaff, ExpiresAt: expires})
+	store.Set(session{ID: "b", Login: "dana", Role: roleStaff, ExpiresAt: expires})
+	store.Set(session{ID: "c", Login: "dana", Role: roleMaintainer, ExpiresAt: expires})
+	store.Set(session{ID: "d", Login: "lee", Role: roleStaff, ExpiresAt: expires})
+
+	assert.Equal(t, 2, store.DeleteByLogin("DANA", roleStaff))
+	_, ok := store.Get("c")
+	assert.True(t, ok, "maintainer sessions of the same login are kept")
+	_, ok = store.Get("d")
+	assert.True(t, ok)
+}
+
+func TestParseLoginSet(t *testing.T) {
+	assert.Equal(t, map[string]bool{"sam": true, "dana": true}, parseLoginSet(" Sam, ,dana "))
+	assert.Empty(t, parseLoginSet(""))
+}
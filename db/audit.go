@@ -0,0 +1,192 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"maintainerd/model"
+
+	"gorm.io/gorm"
+)
+
+// AuditFilter narrows audit log queries. Empty fields are ignored; multiple IDs or actions within a field are ORed.
+type AuditFilter struct {
+	ProjectIDs    []uint
+	MaintainerIDs []uint
+	StaffIDs      []uint
+	ServiceIDs    []uint
+	Actions       []string
+	// From is inclusive and To is exclusive.
+	From *time.Time
+	To   *time.Time
+	// Query is matched against Message: full-text on postgres, every whitespace-separated term as a substring
+	// elsewhere.
+	Query string
+}
+
+// AuditNames holds display names for the IDs referenced by a set of audit log entries.
+type AuditNames struct {
+	Projects    map[uint]string
+	Maintainers map[uint]string
+	Services    map[uint]string
+}
+
+// auditQuery builds the query for the entries matching filter.
+func (s *SQLStore) auditQuery(filter AuditFilter) (*gorm.DB, error) {
+	query := s.db.Model(&model.AuditLog{})
+	if len(filter.ProjectIDs) > 0 {
+		query = query.Where("project_id IN ?", filter.ProjectIDs)
+	}
+	if len(filter.MaintainerIDs) > 0 {
+		// Entries keep the IDs of maintainers merged away, so include them with the maintainer they were merged into.
+		ids, err := mergedMaintainerIDs(s.db, filter.MaintainerIDs)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where("maintainer_id IN ?", ids)
+	}
+	if len(filter.StaffIDs) > 0 {
+		query = query.Where("staff_id IN ?", filter.StaffIDs)
+	}
+	if len(filter.ServiceIDs) > 0 {
+		query = query.Where("service_id IN ?", filter.ServiceIDs)
+	}
+	if len(filter.Actions) > 0 {
+		actions := make([]string, 0, len(filter.Actions))
+		for _, action := range filter.Actions {
+			actions = append(actions, strings.ToUpper(strings.TrimSpace(action)))
+		}
+		query = query.Where("action IN ?", actions)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+	if text := strings.TrimSpace(filter.Query); text != "" {
+		if s.db.Name() == "postgres" {
+			query = query.Where("to_tsvector('simple', coalesce(message, '')) @@ websearch_to_tsquery('simple', ?)", text)
+		} else {
+			for _, term := range strings.Fields(strings.ToLower(text)) {
+				query = query.Where("LOWER(message) LIKE ? ESCAPE '\\'", "%"+escapeLike(term)+"%")
+			}
+		}
+	}
+	return query, nil
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+// ListAuditLogs returns one page of matching audit entries, newest first, with the total match count.
+func (s *SQLStore) ListAuditLogs(filter AuditFilter, limit, offset int) ([]model.AuditLog, int64, error) {
+	query, err := s.auditQuery(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var logs []model.AuditLog
+	if err := query.Session(&gorm.Session{}).
+		Preload("Staff").
+		Order("created_at desc").
+		Order("id desc").
+		Limit(limit).
+		Offset(offset).
+		Find(&logs).Error; err != nil {
+		return nil, 0, err
+	}
+	return logs, total, nil
+}
+
+// StreamAuditLogs calls fn with successive batches of matching audit entries in ID order, so exports never hold the
+// whole log in memory. Iteration stops at the first error from fn or when ctx is cancelled.
+func (s *SQLStore) StreamAuditLogs(ctx context.Context, filter AuditFilter, batchSize int, fn func([]model.AuditLog) error) error {
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+	query, err := s.auditQuery(filter)
+	if err != nil {
+		return err
+	}
+	var lastID uint
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var batch []model.AuditLog
+		if err := query.Session(&gorm.Session{}).
+			WithContext(ctx).
+			Preload("Staff").
+			Where("id > ?", lastID).
+			Order("id asc").
+			Limit(batchSize).
+			Find(&batch).Error; err != nil {
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := fn(batch); err != nil {
+			return err
+		}
+		lastID = batch[len(batch)-1].ID
+		if len(batch) < batchSize {
+			return nil
+		}
+	}
+}
+
+// ResolveAuditNames looks up project, maintainer and service names referenced by logs, including soft-deleted rows
+// so historical entries stay readable.
+func (s *SQLStore) ResolveAuditNames(logs []model.AuditLog) (AuditNames, error) {
+	names := AuditNames{
+		Projects:    map[uint]string{},
+		Maintainers: map[uint]string{},
+		Services:    map[uint]string{},
+	}
+	var projectIDs, maintainerIDs, serviceIDs []uint
+	for _, entry := range logs {
+		if entry.ProjectID != nil {
+			projectIDs = append(projectIDs, *entry.ProjectID)
+		}
+		if entry.MaintainerID != nil {
+			maintainerIDs = append(maintainerIDs, *entry.MaintainerID)
+		}
+		if entry.ServiceID != nil {
+			serviceIDs = append(serviceIDs, *entry.ServiceID)
+		}
+	}
+	type idName struct {
+		ID   uint
+		Name string
+	}
+	load := func(table string, ids []uint, into map[uint]string) error {
+		if len(ids) == 0 {
+			return nil
+		}
+		var rows []idName
+		if err := s.db.Table(table).Select("id, name").Where("id IN ?", ids).Scan(&rows).Error; err != nil {
+			return err
+		}
+		for _, row := range rows {
+			into[row.ID] = row.Name
+		}
+		return nil
+	}
+	if err := load("projects", projectIDs, names.Projects); err != nil {
+		return names, err
+	}
+	if err := load("maintainers", maintainerIDs, names.Maintainers); err != nil {
+		return names, err
+	}
+	if err := load("services", serviceIDs, names.Services); err != nil {
+		return names, err
+	}
+	return names, nil
+}
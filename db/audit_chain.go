@@ -0,0 +1,237 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"maintainerd/model"
+
+	"gorm.io/gorm"
+)
+
+// auditChainLockID is the postgres advisory lock key serialising audit chain appends across processes.
+const auditChainLockID = 7_316_001
+
+const auditChainAppendAttempts = 3
+
+var errAuditAppendOnly = errors.New("audit entries are append-only")
+
+// auditHashInput is the canonical, ordered form of an audit entry that gets hashed. Field order and names are part
+// of the chain format; changing them invalidates every existing hash. Fields added later must be omitempty so older
+// entries keep their hash.
+type auditHashInput struct {
+	PrevHash     string  `json:"prevHash"`
+	CreatedAt    string  `json:"createdAt"`
+	Action       string  `json:"action"`
+	Message      string  `json:"message"`
+	Metadata     string  `json:"metadata"`
+	ProjectID    *uint   `json:"projectId"`
+	MaintainerID *uint   `json:"maintainerId"`
+	ServiceID    *uint   `json:"serviceId"`
+	StaffID      *uint   `json:"staffId"`
+	RevertOfID   *uint   `json:"revertOfId,omitempty"`
+	BatchID      *string `json:"batchId,omitempty"`
+}
+
+// AuditEntryHash returns the hex SHA-256 of entry's contents chained to entry.PrevHash.
+func AuditEntryHash(entry model.AuditLog) string {
+	prev := ""
+	if entry.PrevHash != nil {
+		prev = *entry.PrevHash
+	}
+	payload, _ := json.Marshal(auditHashInput{
+		PrevHash:     prev,
+		CreatedAt:    entry.CreatedAt.UTC().Format(time.RFC3339Nano),
+		Action:       entry.Action,
+		Message:      entry.Message,
+		Metadata:     entry.Metadata,
+		ProjectID:    entry.ProjectID,
+		MaintainerID: entry.MaintainerID,
+		ServiceID:    entry.ServiceID,
+		StaffID:      entry.StaffID,
+		RevertOfID:   entry.RevertOfID,
+		BatchID:      entry.BatchID,
+	})
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+// auditEntryHashMatches reports whether entry's contents match its stored hash. Entries chained before the
+// batch_id column existed were hashed without it and had it backfilled from their metadata afterwards, so they also
+// match when hashed without a BatchID that agrees with the batch in their (hashed) metadata.
+func auditEntryHashMatches(entry model.AuditLog) bool {
+	if AuditEntryHash(entry) == entry.Hash {
+		return true
+	}
+	if entry.BatchID == nil {
+		return false
+	}
+	metadata, err := ParseAuditMetadata(entry.Metadata)
+	if err != nil || metadata.Batch == nil || metadata.Batch.ID != *entry.BatchID {
+		return false
+	}
+	entry.BatchID = nil
+	return AuditEntryHash(entry) == entry.Hash
+}
+
+// AppendAuditLog is the single writer for audit entries: it links event to the current chain head and inserts it.
+// It retries when another writer appended first.
+func (s *SQLStore) AppendAuditLog(event *model.AuditLog) error {
+	if event.ID != 0 {
+		return errAuditAppendOnly
+	}
+	var err error
+	for attempt := 0; attempt < auditChainAppendAttempts; attempt++ {
+		err = s.db.Transaction(func(tx *gorm.DB) error {
+			return AppendAuditLogTx(tx, event)
+		})
+		if err == nil {
+			return nil
+		}
+		event.ID = 0
+	}
+	return err
+}
+
+// AppendAuditLogTx appends event to the audit chain inside an existing transaction, so an audit entry can commit
+// atomically with the change it records.
+func AppendAuditLogTx(tx *gorm.DB, event *model.AuditLog) error {
+	if event.ID != 0 {
+		return errAuditAppendOnly
+	}
+	if tx.Name() == "postgres" {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", auditChainLockID).Error; err != nil {
+			return err
+		}
+	}
+	head, err := auditChainHead(tx)
+	if err != nil {
+		return err
+	}
+	if event.Message == "" {
+		event.Message = event.Action
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	// Postgres keeps microseconds; truncate so the stored value hashes the same as the one we hash now.
+	event.CreatedAt = event.CreatedAt.UTC().Truncate(time.Microsecond)
+	event.UpdatedAt = event.CreatedAt
+	if metadata, err := ParseAuditMetadata(event.Metadata); err == nil && metadata.Batch != nil {
+		event.BatchID = &metadata.Batch.ID
+	}
+	event.PrevHash = &head
+	event.Hash = AuditEntryHash(*event)
+	return tx.Create(event).Error
+}
+
+// auditChainHead returns the hash of the newest chained entry, or "" if nothing has been chained yet.
+func auditChainHead(tx *gorm.DB) (string, error) {
+	var head model.AuditLog
+	err := tx.Unscoped().
+		Select("id", "hash").
+		Where("hash <> ''").
+		Order("id desc").
+		First(&head).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return head.Hash, nil
+}
+
+// AuditChainBreak describes one entry that fails verification.
+type AuditChainBreak struct {
+	ID     uint   `json:"id"`
+	Reason string `json:"reason"`
+}
+
+// auditChainTailNote is reported with every verification: the chain proves nothing about entries that come after
+// the head, so deleting the newest entries leaves a shorter chain that still verifies.
+const auditChainTailNote = "entries deleted from the end of the log cannot be detected; " +
+	"keep head and checked from each run outside the database and confirm the next run still contains that head"
+
+// AuditChainReport is the result of VerifyAuditChain.
+type AuditChainReport struct {
+	Checked int               `json:"checked"`
+	Legacy  int               `json:"legacy"`
+	Head    string            `json:"head"`
+	Breaks  []AuditChainBreak `json:"breaks"`
+	Note    string            `json:"note"`
+}
+
+// OK reports whether the chain verified without breaks.
+func (r *AuditChainReport) OK() bool {
+	return len(r.Breaks) == 0
+}
+
+// VerifyAuditChain walks every audit entry in ID order, including soft-deleted ones, and reports entries that were
+// modified, deleted, reordered or written outside the chain. Entries older than the first chained entry are counted
+// as legacy and not verified. Truncation of the newest entries is not detectable from the log alone; see
+// AuditChainReport.Note.
+func (s *SQLStore) VerifyAuditChain(ctx context.Context) (*AuditChainReport, error) {
+	report := &AuditChainReport{Note: auditChainTailNote}
+	var lastID uint
+	chained := false
+	prevHash := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var batch []model.AuditLog
+		if err := s.db.WithContext(ctx).
+			Unscoped().
+			Where("id > ?", lastID).
+			Order("id asc").
+			Limit(1000).
+			Find(&batch).Error; err != nil {
+			return nil, err
+		}
+		if len(batch) == 0 {
+			break
+		}
+		for _, entry := range batch {
+			report.Checked++
+			if entry.Hash == "" {
+				if chained {
+					report.Breaks = append(report.Breaks, AuditChainBreak{ID: entry.ID, Reason: "entry written outside the hash chain"})
+				} else {
+					report.Legacy++
+				}
+				continue
+			}
+			entryPrev := ""
+			if entry.PrevHash != nil {
+				entryPrev = *entry.PrevHash
+			}
+			if entryPrev != prevHash {
+				reason := "previous hash does not match the preceding entry (entry deleted or reordered)"
+				if !chained {
+					reason = "first chained entry does not start a new chain (earlier entries deleted)"
+				}
+				report.Breaks = append(report.Breaks, AuditChainBreak{ID: entry.ID, Reason: reason})
+			}
+			if !auditEntryHashMatches(entry) {
+				report.Breaks = append(report.Breaks, AuditChainBreak{ID: entry.ID, Reason: "content does not match its hash (entry modified)"})
+			}
+			if entry.DeletedAt.Valid {
+				report.Breaks = append(report.Breaks, AuditChainBreak{
+					ID:     entry.ID,
+					Reason: fmt.Sprintf("entry soft-deleted at %s", entry.DeletedAt.Time.UTC().Format(time.RFC3339)),
+				})
+			}
+			chained = true
+			prevHash = entry.Hash
+		}
+		lastID = batch[len(batch)-1].ID
+	}
+	report.Head = prevHash
+	return report, nil
+}
@@ -0,0 +1,187 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"maintainerd/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func setupAuditChainStore(t *testing.T) (*gorm.DB, *SQLStore) {
+	t.Helper()
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&model.Foundation{}, &model.StaffMember{}, &model.AuditLog{}))
+	return db, NewSQLStore(db)
+}
+
+func appendEntries(t *testing.T, store *SQLStore, actions ...string) []model.AuditLog {
+	t.Helper()
+	var out []model.AuditLog
+	for i, action := range actions {
+		projectID := uint(i + 1)
+		event := model.AuditLog{ProjectID: &projectID, Action: action, Metadata: `{"n":1}`}
+		require.NoError(t, store.AppendAuditLog(&event))
+		out = append(out, event)
+	}
+	return out
+}
+
+func TestAppendAuditLogChainsEntries(t *testing.T) {
+	_, store := setupAuditChainStore(t)
+	entries := appendEntries(t, store, "A", "B", "C")
+
+	require.NotNil(t, entries[0].PrevHash)
+	assert.Equal(t, "", *entries[0].PrevHash)
+	assert.Equal(t, entries[0].Hash, *entries[1].PrevHash)
+	assert.Equal(t, entries[1].Hash, *entries[2].PrevHash)
+	assert.Len(t, entries[2].Hash, 64)
+	assert.Equal(t, "C", entries[2].Message, "message defaults to action")
+
+	report, err := store.VerifyAuditChain(context.Background())
+	require.NoError(t, err)
+	assert.True(t, report.OK(), "breaks: %+v", report.Breaks)
+	assert.Equal(t, 3, report.Checked)
+	assert.Equal(t, entries[2].Hash, report.Head)
+
+	reused := entries[0]
+	assert.Error(t, store.AppendAuditLog(&reused), "existing entries cannot be re-appended")
+}
+
+func TestLogAuditEventUsesChain(t *testing.T) {
+	_, store := setupAuditChainStore(t)
+	appendEntries(t, store, "A")
+	require.NoError(t, store.LogAuditEvent(zap.NewNop().Sugar(), model.AuditLog{Action: "FOSSA_ADD_MEMBER"}))
+
+	report, err := store.VerifyAuditChain(context.Background())
+	require.NoError(t, err)
+	assert.True(t, report.OK())
+	assert.Equal(t, 2, report.Checked)
+}
+
+func TestVerifyAuditChainDetectsTampering(t *testing.T) {
+	t.Run("legacy entries before the chain are tolerated", func(t *testing.T) {
+		db, store := setupAuditChainStore(t)
+		require.NoError(t, db.Create(&model.AuditLog{Action: "LEGACY"}).Error)
+		appendEntries(t, store, "A", "B")
+
+		report, err := store.VerifyAuditChain(context.Background())
+		require.NoError(t, err)
+		assert.True(t, report.OK())
+		assert.Equal(t, 1, report.Legacy)
+	})
+
+	t.Run("modified entry", func(t *testing.T) {
+		db, store := setupAuditChainStore(t)
+		entries := appendEntries(t, store, "A", "B", "C")
+		require.NoError(t, db.Model(&model.AuditLog{}).Where("id = ?", entries[1].ID).Update("message", "edited").Error)
+
+		report, err := store.VerifyAuditChain(context.Background())
+		require.NoError(t, err)
+		require.Len(t, report.Breaks, 1)
+		assert.Equal(t, entries[1].ID, report.Breaks[0].ID)
+		assert.Contains(t, report.Breaks[0].Reason, "modified")
+	})
+
+	t.Run("modified batch ID", func(t *testing.T) {
+		db, store := setupAuditChainStore(t)
+		appendEntries(t, store, "A")
+		event := model.AuditLog{Action: "BATCHED", Metadata: `{"batch":{"id":"b1","size":1}}`}
+		require.NoError(t, store.AppendAuditLog(&event))
+		require.NotNil(t, event.BatchID)
+
+		report, err := store.VerifyAuditChain(context.Background())
+		require.NoError(t, err)
+		require.True(t, report.OK())
+
+		require.NoError(t, db.Model(&model.AuditLog{}).Where("id = ?", event.ID).Update("batch_id", "b2").Error)
+		report, err = store.VerifyAuditChain(context.Background())
+		require.NoError(t, err)
+		require.Len(t, report.Breaks, 1)
+		assert.Equal(t, event.ID, report.Breaks[0].ID)
+		assert.Contains(t, report.Breaks[0].Reason, "modified")
+	})
+
+	t.Run("batch ID backfilled after hashing", func(t *testing.T) {
+		db, store := setupAuditChainStore(t)
+		event := model.AuditLog{Action: "BATCHED", Metadata: `{"batch":{"id":"b1","size":1}}`}
+		require.NoError(t, store.AppendAuditLog(&event))
+		unhashed := event
+		unhashed.BatchID = nil
+		require.NoError(t, db.Model(&model.AuditLog{}).Where("id = ?", event.ID).Update("hash", AuditEntryHash(unhashed)).Error)
+
+		report, err := store.VerifyAuditChain(context.Background())
+		require.NoError(t, err)
+		assert.True(t, report.OK(), "entries chained before the batch_id column keep verifying")
+	})
+
+	t.Run("truncated tail is not detected", func(t *testing.T) {
+		db, store := setupAuditChainStore(t)
+		entries := appendEntries(t, store, "A", "B", "C")
+		require.NoError(t, db.Unscoped().Delete(&model.AuditLog{}, entries[2].ID).Error)
+
+		report, err := store.VerifyAuditChain(context.Background())
+		require.NoError(t, err)
+		assert.True(t, report.OK())
+		assert.Equal(t, entries[1].Hash, report.Head)
+		assert.Contains(t, report.Note, "deleted from the end of the log cannot be detected")
+	})
+
+	t.Run("hard-deleted entry", func(t *testing.T) {
+		db, store := setupAuditChainStore(t)
+		entries := appendEntries(t, store, "A", "B", "C")
+		require.NoError(t, db.Unscoped().Delete(&model.AuditLog{}, entries[1].ID).Error)
+
+		report, err := store.VerifyAuditChain(context.Background())
+		require.NoError(t, err)
+		require.Len(t, report.Breaks, 1)
+		assert.Equal(t, entries[2].ID, report.Breaks[0].ID)
+		assert.Contains(t, report.Breaks[0].Reason, "deleted")
+	})
+
+	t.Run("deleted chain start", func(t *testing.T) {
+		db, store := setupAuditChainStore(t)
+		entries := appendEntries(t, store, "A", "B")
+		require.NoError(t, db.Unscoped().Delete(&model.AuditLog{}, entries[0].ID).Error)
+
+		report, err := store.VerifyAuditChain(context.Background())
+		require.NoError(t, err)
+		require.Len(t, report.Breaks, 1)
+		assert.Contains(t, report.Breaks[0].Reason, "earlier entries deleted")
+	})
+
+	t.Run("soft-deleted entry", func(t *testing.T) {
+		db, store := setupAuditChainStore(t)
+		entries := appendEntries(t, store, "A", "B")
+		require.NoError(t, db.Delete(&model.AuditLog{}, entries[0].ID).Error)
+
+		report, err := store.VerifyAuditChain(context.Background())
+		require.NoError(t, err)
+		require.Len(t, report.Breaks, 1)
+		assert.Equal(t, entries[0].ID, report.Breaks[0].ID)
+		assert.Contains(t, report.Breaks[0].Reason, "soft-deleted")
+	})
+
+	t.Run("entry written around the writer", func(t *testing.T) {
+		db, store := setupAuditChainStore(t)
+		appendEntries(t, store, "A")
+		rogue := model.AuditLog{Action: "ROGUE"}
+		require.NoError(t, db.Create(&rogue).Error)
+
+		report, err := store.VerifyAuditChain(context.Background())
+		require.NoError(t, err)
+		require.Len(t, report.Breaks, 1)
+		assert.Equal(t, rogue.ID, report.Breaks[0].ID)
+	})
+
+	t.Run("forked chain is rejected at write time", func(t *testing.T) {
+		db, store := setupAuditChainStore(t)
+		entries := appendEntries(t, store, "A")
+		fork := model.AuditLog{Action: "FORK", PrevHash: entries[0].PrevHash, Hash: "x"}
+		assert.Error(t, db.Create(&fork).Error)
+	})
+}
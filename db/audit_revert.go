@@ -0,0 +1,1077 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"maintainerd/model"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAuditEntryNotFound = errors.New("audit entry not found")
+	ErrRevertUnsupported  = errors.New("audit entry cannot be reverted")
+	ErrRevertConflict     = errors.New("entity has changed since the audited change")
+	ErrAlreadyReverted    = errors.New("audit entry has already been reverted")
+)
+
+// projectRevertColumns maps the audited project fields to their columns.
+var projectRevertColumns = map[string]string{
+	"projectName":     "name",
+	"maturity":        "maturity",
+	"githubOrg":       "git_hub_org",
+	"maintainerRef":   "maintainer_ref",
+	"dotProjectRef":   "dot_project_yaml_ref",
+	"onboardingIssue": "onboarding_issue",
+	"parentProjectId": "parent_project_id",
+	"mailingList":     "mailing_list",
+}
+
+// maintainerRevertColumns maps the audited maintainer fields to their columns. "company" is display-only and
+// "projectIds" is reverted through the join table.
+var maintainerRevertColumns = map[string]string{
+	"name":            "name",
+	"email":           "email",
+	"github":          "git_hub_account",
+	"githubEmail":     "git_hub_email",
+	"status":          "maintainer_status",
+	"companyId":       "company_id",
+	"emailVerifiedAt": "email_verified_at",
+}
+
+// staffRevertColumns maps the audited staff member fields to their columns.
+var staffRevertColumns = map[string]string{
+	"name":          "name",
+	"email":         "email",
+	"github":        "git_hub_account",
+	"foundationId":  "foundation_id",
+	"admin":         "admin",
+	"deactivatedAt": "deactivated_at",
+}
+
+// RevertAuditEntry undoes the change recorded by audit entry id by writing back the "from" side of its diff, and
+// records an AUDIT_REVERT entry for each entry undone. Reverting one entry of a bulk operation reverts the whole
+// batch. It returns ErrRevertConflict if any field no longer holds the value the entry recorded.
+func (a *AuditedStore) RevertAuditEntry(id uint) ([]model.AuditLog, error) {
+	var reverts []model.AuditLog
+	err := a.write(func(tx *gorm.DB, store *SQLStore) ([]model.AuditLog, error) {
+		var entry model.AuditLog
+		if err := tx.First(&entry, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrAuditEntryNotFound
+			}
+			return nil, err
+		}
+		metadata, err := ParseAuditMetadata(entry.Metadata)
+		if err != nil || (len(metadata.Changes) == 0 && metadata.Merge == nil && metadata.MaintainerMerge == nil) {
+			return nil, fmt.Errorf("%w: %s has no recorded diff", ErrRevertUnsupported, entry.Action)
+		}
+		targets := []model.AuditLog{entry}
+		if metadata.Batch != nil {
+			targets = nil
+			if err := tx.Where("batch_id = ? AND action = ?", metadata.Batch.ID, entry.Action).
+				Order("id").
+				Find(&targets).Error; err != nil {
+				return nil, err
+			}
+		}
+
+		events := make([]model.AuditLog, 0, len(targets))
+		for _, target := range targets {
+			var reverted int64
+			if err := tx.Model(&model.AuditLog{}).Where("revert_of_id = ?", target.ID).Count(&reverted).Error; err != nil {
+				return nil, err
+			}
+			if reverted > 0 {
+				return nil, fmt.Errorf("%w: entry %d", ErrAlreadyReverted, target.ID)
+			}
+			targetMetadata, err := ParseAuditMetadata(target.Metadata)
+			if err != nil {
+				return nil, err
+			}
+			if err := revertAuditEntryTx(tx, store, target, targetMetadata); err != nil {
+				return nil, err
+			}
+			inverse := make(map[string]AuditChange, len(targetMetadata.Changes))
+			for field, change := range targetMetadata.Changes {
+				inverse[field] = AuditChange{From: change.To, To: change.From}
+			}
+			event, err := a.event("AUDIT_REVERT",
+				fmt.Sprintf("Reverted %s #%d by %s", target.Action, target.ID, a.actor.DisplayName()),
+				AuditMetadata{Changes: inverse})
+			if err != nil {
+				return nil, err
+			}
+			targetID := target.ID
+			event.RevertOfID = &targetID
+			event.ProjectID = target.ProjectID
+			event.MaintainerID = target.MaintainerID
+			event.ServiceID = target.ServiceID
+			events = append(events, event)
+		}
+		reverts = events
+		return events, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reverts, nil
+}
+
+func revertAuditEntryTx(tx *gorm.DB, store *SQLStore, entry model.AuditLog, metadata *AuditMetadata) error {
+	switch entry.Action {
+	case "PROJECT_MATURITY_UPDATE", "PROJECT_MAINTAINER_REF_UPDATE", "PROJECT_MAILING_LIST_UPDATE":
+		if entry.ProjectID == nil {
+			return fmt.Errorf("%w: entry has no project", ErrRevertUnsupported)
+		}
+		return revertProjectFields(tx, *entry.ProjectID, metadata.Changes)
+	case "PROJECT_PARENT_UPDATE":
+		if entry.ProjectID == nil {
+			return fmt.Errorf("%w: entry has no project", ErrRevertUnsupported)
+		}
+		if err := revertProjectFields(tx, *entry.ProjectID, metadata.Changes); err != nil {
+			return err
+		}
+		return revertProjectParent(tx, *entry.ProjectID)
+	case "PROJECT_MATURITY_TRANSITION", "PROJECT_RENAME":
+		if entry.ProjectID == nil || metadata.Lifecycle == nil {
+			return fmt.Errorf("%w: entry has no project lifecycle", ErrRevertUnsupported)
+		}
+		if err := revertProjectFields(tx, *entry.ProjectID, metadata.Changes); err != nil {
+			return err
+		}
+		return revertProjectLifecycle(tx, store, *metadata.Lifecycle)
+	case "MAINTAINER_UPDATE", "MAINTAINER_STATUS_UPDATE", "MAINTAINER_EMAIL_VERIFIED":
+		if entry.MaintainerID == nil {
+			return fmt.Errorf("%w: entry has no maintainer", ErrRevertUnsupported)
+		}
+		return revertMaintainerFields(tx, *entry.MaintainerID, metadata.Changes)
+	case "MEMBERSHIP_UPDATE":
+		if entry.MaintainerID == nil || entry.ProjectID == nil {
+			return fmt.Errorf("%w: entry has no membership", ErrRevertUnsupported)
+		}
+		return revertMembershipFields(tx, store, *entry.MaintainerID, *entry.ProjectID, metadata.Changes)
+	case "MEMBERSHIP_REMOVE":
+		if entry.MaintainerID == nil || entry.ProjectID == nil {
+			return fmt.Errorf("%w: entry has no membership", ErrRevertUnsupported)
+		}
+		return revertMembershipRemove(tx, store, *entry.MaintainerID, *entry.ProjectID, metadata.Changes)
+	case "MAINTAINER_CREATE":
+		if entry.MaintainerID == nil {
+			return fmt.Errorf("%w: entry has no maintainer", ErrRevertUnsupported)
+		}
+		return revertMaintainerCreate(tx, *entry.MaintainerID, metadata.Changes)
+	case "COMPANY_CREATE":
+		return revertCompanyCreate(tx, metadata.Changes)
+	case "COMPANY_DOMAINS_UPDATE":
+		if metadata.CompanyID == nil {
+			return fmt.Errorf("%w: entry has no company", ErrRevertUnsupported)
+		}
+		return revertCompanyDomains(tx, *metadata.CompanyID, metadata.Changes["domains"])
+	case "COMPANY_ALIASES_UPDATE":
+		if metadata.CompanyID == nil {
+			return fmt.Errorf("%w: entry has no company", ErrRevertUnsupported)
+		}
+		return revertCompanyAliases(tx, store, *metadata.CompanyID, metadata.Changes["aliases"])
+	case "COMPANY_MERGE":
+		if metadata.Merge == nil {
+			return fmt.Errorf("%w: merge did not record the maintainers it moved", ErrRevertUnsupported)
+		}
+		return revertCompanyMerge(tx, *metadata.Merge)
+	case "COMPANY_UPDATE":
+		if metadata.CompanyID == nil {
+			return fmt.Errorf("%w: entry has no company", ErrRevertUnsupported)
+		}
+		return revertCompanyUpdate(tx, *metadata.CompanyID, metadata.Changes)
+	case "COMPANY_DELETE":
+		if metadata.CompanyID == nil {
+			return fmt.Errorf("%w: entry has no company", ErrRevertUnsupported)
+		}
+		return revertCompanyDelete(tx, *metadata.CompanyID, metadata.Changes)
+	case "FOUNDATION_CREATE", "FOUNDATION_UPDATE", "FOUNDATION_DELETE":
+		if metadata.FoundationID == nil {
+			return fmt.Errorf("%w: entry has no foundation", ErrRevertUnsupported)
+		}
+		return revertFoundation(tx, *metadata.FoundationID, metadata.Changes["name"])
+	case "STAFF_CREATE":
+		if metadata.StaffMemberID == nil {
+			return fmt.Errorf("%w: entry has no staff member", ErrRevertUnsupported)
+		}
+		return revertStaffCreate(tx, *metadata.StaffMemberID, metadata.Changes)
+	case "STAFF_UPDATE", "STAFF_DEACTIVATE", "STAFF_REACTIVATE":
+		if metadata.StaffMemberID == nil {
+			return fmt.Errorf("%w: entry has no staff member", ErrRevertUnsupported)
+		}
+		return revertStaffFields(tx, *metadata.StaffMemberID, metadata.Changes)
+	case "COLLABORATOR_CREATE", "COLLABORATOR_UPDATE":
+		if metadata.CollaboratorID == nil {
+			return fmt.Errorf("%w: entry has no collaborator", ErrRevertUnsupported)
+		}
+		return revertCollaborator(tx, *metadata.CollaboratorID, metadata.Changes)
+	case "MAINTAINER_MERGE":
+		if metadata.MaintainerMerge == nil {
+			return fmt.Errorf("%w: merge did not record what it moved", ErrRevertUnsupported)
+		}
+		return revertMaintainerMerge(tx, *metadata.MaintainerMerge, metadata.Changes)
+	case "OFFICER_CREATE", "OFFICER_UPDATE", "OFFICER_DELETE", "OFFICER_SERVICE_LINK", "OFFICER_SERVICE_UNLINK":
+		if metadata.OfficerID == nil {
+			return fmt.Errorf("%w: entry has no officer", ErrRevertUnsupported)
+		}
+		return revertOfficer(tx, *metadata.OfficerID, metadata.Changes)
+	case "SERVICE_ACTING_OFFICER_UPDATE":
+		if entry.ServiceID == nil {
+			return fmt.Errorf("%w: entry has no service", ErrRevertUnsupported)
+		}
+		return revertActingOfficer(tx, *entry.ServiceID, metadata.Changes["actingOfficerId"])
+	default:
+		return fmt.Errorf("%w: %s", ErrRevertUnsupported, entry.Action)
+	}
+}
+
+// checkRevertable returns ErrRevertConflict unless every recorded field still holds its "to" value.
+func checkRevertable(current map[string]string, changes map[string]AuditChange, displayOnly ...string) error {
+	for field, change := range changes {
+		if containsString(displayOnly, field) {
+			continue
+		}
+		if current[field] != change.To {
+			return fmt.Errorf("%w: %s is now %q", ErrRevertConflict, field, current[field])
+		}
+	}
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// revertUpdates converts the "from" side of changes into column updates.
+func revertUpdates(changes map[string]AuditChange, columns map[string]string, skip ...string) (map[string]any, error) {
+	updates := make(map[string]any, len(changes))
+	for field, change := range changes {
+		if containsString(skip, field) {
+			continue
+		}
+		column, ok := columns[field]
+		if !ok {
+			return nil, fmt.Errorf("%w: field %s", ErrRevertUnsupported, field)
+		}
+		value, err := revertValue(field, change.From)
+		if err != nil {
+			return nil, err
+		}
+		updates[column] = value
+	}
+	return updates, nil
+}
+
+func revertValue(field, value string) (any, error) {
+	switch field {
+	case "companyId", "parentProjectId", "foundationId", "actingOfficerId":
+		if value == "" {
+			return nil, nil
+		}
+		id, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %w", field, value, err)
+		}
+		return uint(id), nil
+	case "emailVerifiedAt", "leftAt", "joinedAt", "deactivatedAt":
+		if value == "" {
+			return nil, nil
+		}
+		return time.Parse(time.RFC3339Nano, value)
+	case "onboardingIssue", "mailingList":
+		if value == "" {
+			return nil, nil
+		}
+		return value, nil
+	case "admin":
+		return value == "true", nil
+	case "status":
+		return model.MaintainerStatus(value), nil
+	case "maturity":
+		return model.Maturity(value), nil
+	default:
+		return value, nil
+	}
+}
+
+func revertProjectFields(tx *gorm.DB, projectID uint, changes map[string]AuditChange) error {
+	var project model.Project
+	if err := tx.First(&project, projectID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: project %d no longer exists", ErrRevertConflict, projectID)
+		}
+		return err
+	}
+	if err := checkRevertable(projectAuditFields(project), changes); err != nil {
+		return err
+	}
+	updates, err := revertUpdates(changes, projectRevertColumns)
+	if err != nil {
+		return err
+	}
+	return tx.Model(&model.Project{}).Where("id = ?", projectID).Updates(updates).Error
+}
+
+// revertProjectParent checks that restoring a project's previous parent does not nest it under its own sub-projects.
+func revertProjectParent(tx *gorm.DB, projectID uint) error {
+	var project model.Project
+	if err := tx.Select("id", "parent_project_id").First(&project, projectID).Error; err != nil {
+		return err
+	}
+	if err := checkProjectParent(tx, projectID, project.ParentProjectID); err != nil {
+		return fmt.Errorf("%w: %v", ErrRevertConflict, err)
+	}
+	return nil
+}
+
+func loadRevertMaintainer(tx *gorm.DB, maintainerID uint, changes map[string]AuditChange) error {
+	if err := tx.Select("id").First(&model.Maintainer{}, maintainerID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: maintainer %d no longer exists", ErrRevertConflict, maintainerID)
+		}
+		return err
+	}
+	current, err := maintainerAuditFields(tx, maintainerID)
+	if err != nil {
+		return err
+	}
+	return checkRevertable(current, changes, "company")
+}
+
+func revertMaintainerFields(tx *gorm.DB, maintainerID uint, changes map[string]AuditChange) error {
+	if err := loadRevertMaintainer(tx, maintainerID, changes); err != nil {
+		return err
+	}
+	updates, err := revertUpdates(changes, maintainerRevertColumns, "company", "projectIds")
+	if err != nil {
+		return err
+	}
+	if len(updates) > 0 {
+		if err := tx.Model(&model.Maintainer{}).Where("id = ?", maintainerID).Updates(updates).Error; err != nil {
+			return err
+		}
+	}
+	if change, ok := changes["projectIds"]; ok {
+		return setMaintainerProjects(tx, maintainerID, splitAuditIDs(change.To), splitAuditIDs(change.From))
+	}
+	return nil
+}
+
+// revertMembershipFields restores a membership's status and role through UpdateMembership, so the membership
+// history records the revert, then restores LeftAt exactly.
+func revertMembershipFields(tx *gorm.DB, store *SQLStore, maintainerID, projectID uint, changes map[string]AuditChange) error {
+	current, err := membershipAuditFields(tx, maintainerID, projectID)
+	if err != nil {
+		if errors.Is(err, ErrMembershipNotFound) {
+			return fmt.Errorf("%w: membership no longer exists", ErrRevertConflict)
+		}
+		return err
+	}
+	if err := checkRevertable(current, changes); err != nil {
+		return err
+	}
+	var status model.MaintainerStatus
+	if change, ok := changes["status"]; ok {
+		status = model.MaintainerStatus(change.From)
+	}
+	var role model.MembershipRole
+	if change, ok := changes["role"]; ok {
+		role = model.MembershipRole(change.From)
+	}
+	if _, err := store.UpdateMembership(maintainerID, projectID, status, role); err != nil {
+		return err
+	}
+	if change, ok := changes["leftAt"]; ok {
+		leftAt, err := revertValue("leftAt", change.From)
+		if err != nil {
+			return err
+		}
+		return tx.Model(&model.MaintainerProject{}).
+			Where("maintainer_id = ? AND project_id = ?", maintainerID, projectID).
+			Update("left_at", leftAt).Error
+	}
+	return nil
+}
+
+// revertMembershipRemove restores a removed membership with its recorded status, role and dates. Removals that
+// also revoked service team access are not reverted, since that access was removed on the remote service.
+func revertMembershipRemove(tx *gorm.DB, store *SQLStore, maintainerID, projectID uint, changes map[string]AuditChange) error {
+	if changes["serviceTeams"].From != "" {
+		return fmt.Errorf("%w: service team access was revoked with the membership", ErrRevertUnsupported)
+	}
+	if err := tx.Select("id").First(&model.Maintainer{}, maintainerID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: maintainer %d no longer exists", ErrRevertConflict, maintainerID)
+		}
+		return err
+	}
+	if _, err := NewSQLStore(tx).GetMembership(maintainerID, projectID); err == nil {
+		return fmt.Errorf("%w: maintainer has rejoined the project", ErrRevertConflict)
+	} else if !errors.Is(err, ErrMembershipNotFound) {
+		return err
+	}
+	membership := model.MaintainerProject{
+		MaintainerID: maintainerID,
+		ProjectID:    projectID,
+		Status:       model.MaintainerStatus(changes["status"].From),
+		Role:         model.MembershipRole(changes["role"].From),
+	}
+	if value, err := revertValue("joinedAt", changes["joinedAt"].From); err != nil {
+		return err
+	} else if joinedAt, ok := value.(time.Time); ok {
+		membership.JoinedAt = joinedAt
+	}
+	if value, err := revertValue("leftAt", changes["leftAt"].From); err != nil {
+		return err
+	} else if leftAt, ok := value.(time.Time); ok {
+		membership.LeftAt = &leftAt
+	}
+	if err := tx.Create(&membership).Error; err != nil {
+		return err
+	}
+	return store.recordMembershipHistory(tx, model.MembershipHistory{
+		MaintainerID: maintainerID,
+		ProjectID:    projectID,
+		Event:        "JOIN",
+		ToStatus:     string(membership.Status),
+		ToRole:       string(membership.Role),
+		CreatedAt:    time.Now(),
+	})
+}
+
+func revertMaintainerCreate(tx *gorm.DB, maintainerID uint, changes map[string]AuditChange) error {
+	if err := loadRevertMaintainer(tx, maintainerID, changes); err != nil {
+		return err
+	}
+	if err := tx.Where("maintainer_id = ?", maintainerID).Delete(&model.MaintainerProject{}).Error; err != nil {
+		return err
+	}
+	return tx.Delete(&model.Maintainer{}, maintainerID).Error
+}
+
+// setMaintainerProjects moves a maintainer's project links from the from set to the to set.
+func setMaintainerProjects(tx *gorm.DB, maintainerID uint, from, to []uint) error {
+	for _, projectID := range from {
+		if !containsUint(to, projectID) {
+			if err := tx.Where("maintainer_id = ? AND project_id = ?", maintainerID, projectID).
+				Delete(&model.MaintainerProject{}).Error; err != nil {
+				return err
+			}
+		}
+	}
+	for _, projectID := range to {
+		if !containsUint(from, projectID) {
+			if err := tx.Create(&model.MaintainerProject{MaintainerID: maintainerID, ProjectID: projectID}).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func containsUint(values []uint, value uint) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func splitAuditIDs(value string) []uint {
+	var ids []uint
+	for _, part := range strings.Split(value, ",") {
+		if id, err := strconv.ParseUint(strings.TrimSpace(part), 10, 64); err == nil {
+			ids = append(ids, uint(id))
+		}
+	}
+	return ids
+}
+
+func revertCompanyCreate(tx *gorm.DB, changes map[string]AuditChange) error {
+	id, err := strconv.ParseUint(changes["companyId"].To, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: entry does not record the company id", ErrRevertUnsupported)
+	}
+	var company model.Company
+	if err := tx.First(&company, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: company %d no longer exists", ErrRevertConflict, id)
+		}
+		return err
+	}
+	if company.Name != changes["name"].To {
+		return fmt.Errorf("%w: company is now named %q", ErrRevertConflict, company.Name)
+	}
+	var members int64
+	if err := tx.Model(&model.Maintainer{}).Where("company_id = ?", id).Count(&members).Error; err != nil {
+		return err
+	}
+	if members > 0 {
+		return fmt.Errorf("%w: %d maintainers now belong to the company", ErrRevertConflict, members)
+	}
+	return tx.Delete(&company).Error
+}
+
+// revertCompanyDomains puts back the company's previous domain list.
+func revertCompanyDomains(tx *gorm.DB, companyID uint, change AuditChange) error {
+	current, err := companyDomainsAuditField(tx, companyID)
+	if err != nil {
+		return err
+	}
+	if current != change.To {
+		return fmt.Errorf("%w: domains are now %q", ErrRevertConflict, current)
+	}
+	from := splitAuditDomains(change.From)
+	to := splitAuditDomains(change.To)
+	for _, domain := range to {
+		if !containsString(from, domain) {
+			if err := tx.Where("company_id = ? AND domain = ?", companyID, domain).Delete(&model.CompanyDomain{}).Error; err != nil {
+				return err
+			}
+		}
+	}
+	for _, domain := range from {
+		if containsString(to, domain) {
+			continue
+		}
+		var owner model.CompanyDomain
+		err := tx.Where("domain = ?", domain).First(&owner).Error
+		if err == nil {
+			return fmt.Errorf("%w: %s now belongs to company %d", ErrRevertConflict, domain, owner.CompanyID)
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if err := tx.Create(&model.CompanyDomain{CompanyID: companyID, Domain: domain}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// revertCompanyAliases puts back the company's previous aliases.
+func revertCompanyAliases(tx *gorm.DB, store *SQLStore, companyID uint, change AuditChange) error {
+	current, err := companyAliasesAuditField(tx, companyID)
+	if err != nil {
+		return err
+	}
+	if current != change.To {
+		return fmt.Errorf("%w: aliases are now %q", ErrRevertConflict, current)
+	}
+	if _, err := store.SetCompanyAliases(companyID, splitAuditAliases(change.From)); err != nil {
+		if errors.Is(err, ErrAliasClaimed) {
+			return fmt.Errorf("%w: %v", ErrRevertConflict, err)
+		}
+		return err
+	}
+	return nil
+}
+
+func splitAuditDomains(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
+// revertCompanyMerge restores the merged-away company and moves back the maintainers the merge moved.
+func revertCompanyMerge(tx *gorm.DB, merge AuditCompanyMerge) error {
+	var source model.Company
+	if err := tx.Unscoped().First(&source, merge.FromCompanyID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: company %d no longer exists", ErrRevertConflict, merge.FromCompanyID)
+		}
+		return err
+	}
+	if !source.DeletedAt.Valid {
+		return fmt.Errorf("%w: company %d has already been restored", ErrRevertConflict, merge.FromCompanyID)
+	}
+	if len(merge.MaintainerIDs) > 0 {
+		var stillMoved int64
+		if err := tx.Model(&model.Maintainer{}).
+			Where("id IN ? AND company_id = ?", merge.MaintainerIDs, merge.ToCompanyID).
+			Count(&stillMoved).Error; err != nil {
+			return err
+		}
+		if int(stillMoved) != len(merge.MaintainerIDs) {
+			return fmt.Errorf("%w: %d of %d moved maintainers have changed company since", ErrRevertConflict, len(merge.MaintainerIDs)-int(stillMoved), len(merge.MaintainerIDs))
+		}
+	}
+	if err := tx.Unscoped().Model(&source).Update("deleted_at", nil).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("company_id = ? AND normalized_name = ?", merge.ToCompanyID, NormalizeCompanyName(source.Name)).
+		Delete(&model.CompanyAlias{}).Error; err != nil {
+		return err
+	}
+	if len(merge.Aliases) > 0 {
+		if err := tx.Model(&model.CompanyAlias{}).
+			Where("normalized_name IN ? AND company_id = ?", merge.Aliases, merge.ToCompanyID).
+			Update("company_id", merge.FromCompanyID).Error; err != nil {
+			return err
+		}
+	}
+	if len(merge.Domains) > 0 {
+		if err := tx.Model(&model.CompanyDomain{}).
+			Where("domain IN ? AND company_id = ?", merge.Domains, merge.ToCompanyID).
+			Update("company_id", merge.FromCompanyID).Error; err != nil {
+			return err
+		}
+	}
+	if len(merge.MaintainerIDs) == 0 {
+		return nil
+	}
+	return tx.Model(&model.Maintainer{}).
+		Where("id IN ?", merge.MaintainerIDs).
+		Update("company_id", merge.FromCompanyID).Error
+}
+
+// revertMaintainerMerge restores the merged-away maintainer and moves its memberships, history and service team
+// links back. Ref caches cleared by the merge are left to be refetched.
+func revertMaintainerMerge(tx *gorm.DB, merge MaintainerMerge, changes map[string]AuditChange) error {
+	var source model.Maintainer
+	if err := tx.Unscoped().First(&source, merge.FromMaintainerID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: maintainer %d no longer exists", ErrRevertConflict, merge.FromMaintainerID)
+		}
+		return err
+	}
+	if !source.DeletedAt.Valid || source.MergedIntoID == nil || *source.MergedIntoID != merge.ToMaintainerID {
+		return fmt.Errorf("%w: maintainer %d has already been restored", ErrRevertConflict, merge.FromMaintainerID)
+	}
+	if len(merge.MovedProjectIDs) > 0 {
+		var stillMoved int64
+		if err := tx.Model(&model.MaintainerProject{}).
+			Where("maintainer_id = ? AND project_id IN ?", merge.ToMaintainerID, merge.MovedProjectIDs).
+			Count(&stillMoved).Error; err != nil {
+			return err
+		}
+		if int(stillMoved) != len(merge.MovedProjectIDs) {
+			return fmt.Errorf("%w: %d of %d moved memberships have changed since", ErrRevertConflict, len(merge.MovedProjectIDs)-int(stillMoved), len(merge.MovedProjectIDs))
+		}
+	}
+	if err := revertMaintainerFields(tx, merge.ToMaintainerID, changes); err != nil {
+		return err
+	}
+
+	if merge.GitHubID != nil {
+		if err := tx.Model(&model.Maintainer{}).Where("id = ?", merge.ToMaintainerID).Update("git_hub_id", nil).Error; err != nil {
+			return err
+		}
+	}
+	restore := map[string]any{"deleted_at": nil, "merged_into_id": nil}
+	if merge.GitHubID != nil {
+		restore["git_hub_id"] = *merge.GitHubID
+	}
+	if err := tx.Unscoped().Model(&model.Maintainer{}).Where("id = ?", merge.FromMaintainerID).Updates(restore).Error; err != nil {
+		return err
+	}
+
+	if len(merge.MovedProjectIDs) > 0 {
+		if err := tx.Model(&model.MaintainerProject{}).
+			Where("maintainer_id = ? AND project_id IN ?", merge.ToMaintainerID, merge.MovedProjectIDs).
+			Update("maintainer_id", merge.FromMaintainerID).Error; err != nil {
+			return err
+		}
+	}
+	for _, dropped := range merge.DroppedMemberships {
+		if err := tx.Create(&model.MaintainerProject{
+			MaintainerID: merge.FromMaintainerID,
+			ProjectID:    dropped.ProjectID,
+			JoinedAt:     dropped.JoinedAt,
+			Status:       model.MaintainerStatus(dropped.Status),
+			Role:         model.MembershipRole(dropped.Role),
+			LeftAt:       dropped.LeftAt,
+		}).Error; err != nil {
+			return err
+		}
+	}
+	if len(merge.HistoryIDs) > 0 {
+		if err := tx.Model(&model.MembershipHistory{}).
+			Where("id IN ?", merge.HistoryIDs).
+			Update("maintainer_id", merge.FromMaintainerID).Error; err != nil {
+			return err
+		}
+	}
+	if len(merge.ServiceUserTeamIDs) > 0 {
+		if err := tx.Model(&model.ServiceUserTeams{}).
+			Where("id IN ?", merge.ServiceUserTeamIDs).
+			Update("maintainer_id", merge.FromMaintainerID).Error; err != nil {
+			return err
+		}
+	}
+	if len(merge.DroppedServiceUserTeamIDs) > 0 {
+		if err := tx.Unscoped().Model(&model.ServiceUserTeams{}).
+			Where("id IN ?", merge.DroppedServiceUserTeamIDs).
+			Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// revertCompanyUpdate writes back a company's previous name, website, notes and tags.
+func revertCompanyUpdate(tx *gorm.DB, companyID uint, changes map[string]AuditChange) error {
+	var company model.Company
+	if err := tx.First(&company, companyID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: company %d no longer exists", ErrRevertConflict, companyID)
+		}
+		return err
+	}
+	if err := checkRevertable(companyAuditFields(company), changes); err != nil {
+		return err
+	}
+	for field, change := range changes {
+		switch field {
+		case "name":
+			owner, err := resolveCompany(tx, change.From)
+			if err != nil {
+				return err
+			}
+			if owner != nil && owner.ID != companyID {
+				return fmt.Errorf("%w: name %q now belongs to company %s", ErrRevertConflict, change.From, owner.Name)
+			}
+			company.Name = change.From
+		case "website":
+			company.Website = change.From
+		case "notes":
+			company.Notes = change.From
+		case "tags":
+			tags, err := parseAuditTags(change.From)
+			if err != nil {
+				return err
+			}
+			company.Tags = tags
+		default:
+			return fmt.Errorf("%w: field %s", ErrRevertUnsupported, field)
+		}
+	}
+	return tx.Model(&company).Select("name", "website", "notes", "tags").Updates(&company).Error
+}
+
+// revertCompanyDelete recreates a deleted company with its ID, fields, domains and aliases.
+func revertCompanyDelete(tx *gorm.DB, companyID uint, changes map[string]AuditChange) error {
+	var existing int64
+	if err := tx.Unscoped().Model(&model.Company{}).Where("id = ?", companyID).Count(&existing).Error; err != nil {
+		return err
+	}
+	if existing > 0 {
+		return fmt.Errorf("%w: company %d already exists", ErrRevertConflict, companyID)
+	}
+	name := changes["name"].From
+	owner, err := resolveCompany(tx, name)
+	if err != nil {
+		return err
+	}
+	if owner != nil {
+		return fmt.Errorf("%w: name %q now belongs to company %s", ErrRevertConflict, name, owner.Name)
+	}
+	tags, err := parseAuditTags(changes["tags"].From)
+	if err != nil {
+		return err
+	}
+	company := model.Company{
+		Model:   gorm.Model{ID: companyID},
+		Name:    name,
+		Website: changes["website"].From,
+		Notes:   changes["notes"].From,
+		Tags:    tags,
+	}
+	if err := tx.Create(&company).Error; err != nil {
+		return err
+	}
+	if err := revertCompanyDomains(tx, companyID, AuditChange{From: changes["domains"].From}); err != nil {
+		return err
+	}
+	for _, alias := range splitAuditAliases(changes["aliases"].From) {
+		normalized := NormalizeCompanyName(alias)
+		owner, err := resolveCompany(tx, normalized)
+		if err != nil {
+			return err
+		}
+		if owner != nil {
+			return fmt.Errorf("%w: alias %q now belongs to company %s", ErrRevertConflict, alias, owner.Name)
+		}
+		if err := tx.Create(&model.CompanyAlias{CompanyID: companyID, Name: alias, NormalizedName: normalized}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// revertFoundation undoes a foundation create, rename or delete recorded as a change to its name.
+func revertFoundation(tx *gorm.DB, foundationID uint, change AuditChange) error {
+	var foundation model.Foundation
+	err := tx.First(&foundation, foundationID).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	exists := err == nil
+	current := ""
+	if exists {
+		current = foundation.Name
+	}
+	if current != change.To {
+		return fmt.Errorf("%w: foundation %d is now %q", ErrRevertConflict, foundationID, current)
+	}
+	if change.From != "" {
+		taken, err := foundationNameTaken(tx, change.From, foundationID)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return fmt.Errorf("%w: foundation name %q is taken", ErrRevertConflict, change.From)
+		}
+	}
+	switch {
+	case !exists:
+		return tx.Create(&model.Foundation{Model: gorm.Model{ID: foundationID}, Name: change.From}).Error
+	case change.From == "":
+		var staff int64
+		if err := tx.Model(&model.StaffMember{}).Where("foundation_id = ?", foundationID).Count(&staff).Error; err != nil {
+			return err
+		}
+		if staff > 0 {
+			return fmt.Errorf("%w: %d staff members now belong to the foundation", ErrRevertConflict, staff)
+		}
+		return tx.Unscoped().Delete(&foundation).Error
+	default:
+		return tx.Model(&foundation).Update("name", change.From).Error
+	}
+}
+
+// loadRevertStaff checks that the staff member's audited fields still hold the values changes recorded.
+func loadRevertStaff(tx *gorm.DB, staffID uint, changes map[string]AuditChange) (*model.StaffMember, error) {
+	var staff model.StaffMember
+	if err := tx.First(&staff, staffID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: staff member %d no longer exists", ErrRevertConflict, staffID)
+		}
+		return nil, err
+	}
+	if err := checkRevertable(staffAuditFields(staff), changes); err != nil {
+		return nil, err
+	}
+	return &staff, nil
+}
+
+func revertStaffFields(tx *gorm.DB, staffID uint, changes map[string]AuditChange) error {
+	staff, err := loadRevertStaff(tx, staffID, changes)
+	if err != nil {
+		return err
+	}
+	updates, err := revertUpdates(changes, staffRevertColumns)
+	if err != nil {
+		return err
+	}
+	if admin, ok := updates["admin"]; ok && admin == false && staff.Admin && staff.DeactivatedAt == nil {
+		if err := checkOtherAdmins(tx, staffID); err != nil {
+			return fmt.Errorf("%w: %w", ErrRevertConflict, err)
+		}
+	}
+	if deactivatedAt, ok := updates["deactivated_at"]; ok && deactivatedAt != nil && staff.Admin {
+		if err := checkOtherAdmins(tx, staffID); err != nil {
+			return fmt.Errorf("%w: %w", ErrRevertConflict, err)
+		}
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+	return tx.Model(staff).Updates(updates).Error
+}
+
+// revertStaffCreate removes a staff member added by mistake. A member who has already made audited changes must be
+// deactivated instead, so those entries keep naming them.
+func revertStaffCreate(tx *gorm.DB, staffID uint, changes map[string]AuditChange) error {
+	staff, err := loadRevertStaff(tx, staffID, changes)
+	if err != nil {
+		return err
+	}
+	var written int64
+	if err := tx.Model(&model.AuditLog{}).Where("staff_id = ?", staffID).Count(&written).Error; err != nil {
+		return err
+	}
+	if written > 0 {
+		return fmt.Errorf("%w: staff member %d has made changes since; deactivate them instead", ErrRevertConflict, staffID)
+	}
+	if staff.Admin {
+		if err := checkOtherAdmins(tx, staffID); err != nil {
+			return fmt.Errorf("%w: %w", ErrRevertConflict, err)
+		}
+	}
+	return tx.Unscoped().Delete(staff).Error
+}
+
+// revertCollaborator restores a collaborator's fields and projects, or removes a collaborator whose creation is
+// reverted, as long as no service team links have been attached to them since.
+func revertCollaborator(tx *gorm.DB, collaboratorID uint, changes map[string]AuditChange) error {
+	current, err := collaboratorAuditFields(tx, collaboratorID)
+	if err != nil {
+		if errors.Is(err, ErrCollaboratorNotFound) {
+			return fmt.Errorf("%w: collaborator %d no longer exists", ErrRevertConflict, collaboratorID)
+		}
+		return err
+	}
+	if err := checkRevertable(current, changes); err != nil {
+		return err
+	}
+	if changes["name"].From == "" && changes["name"].To != "" {
+		var links int64
+		if err := tx.Model(&model.ServiceUserTeams{}).Where("collaborator_id = ?", collaboratorID).Count(&links).Error; err != nil {
+			return err
+		}
+		if links > 0 {
+			return fmt.Errorf("%w: collaborator %d now has service team links", ErrRevertConflict, collaboratorID)
+		}
+		if err := tx.Where("collaborator_id = ?", collaboratorID).Delete(&model.CollaboratorProject{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&model.Collaborator{}, collaboratorID).Error
+	}
+	updates := make(map[string]any)
+	if change, ok := changes["name"]; ok {
+		updates["name"] = change.From
+	}
+	if change, ok := changes["email"]; ok {
+		updates["email"] = normalizeOrSentinel(change.From, "EMAIL_MISSING")
+	}
+	if change, ok := changes["github"]; ok {
+		updates["git_hub_account"] = normalizeOrSentinel(change.From, "GITHUB_MISSING")
+	}
+	if len(updates) > 0 {
+		if err := tx.Model(&model.Collaborator{}).Where("id = ?", collaboratorID).Updates(updates).Error; err != nil {
+			return err
+		}
+	}
+	if change, ok := changes["projectIds"]; ok {
+		return setCollaboratorProjects(tx, collaboratorID, splitAuditIDs(change.From))
+	}
+	return nil
+}
+
+// revertOfficer restores an officer's fields and service identities, removes an officer whose registration is
+// reverted, or restores a removed officer. An officer cannot lose the identity they act on a service with.
+func revertOfficer(tx *gorm.DB, officerID uint, changes map[string]AuditChange) error {
+	current, err := officerAuditFields(tx, officerID)
+	if err != nil {
+		if errors.Is(err, ErrOfficerNotFound) {
+			return fmt.Errorf("%w: officer %d no longer exists", ErrRevertConflict, officerID)
+		}
+		return err
+	}
+	if err := checkRevertable(current, changes); err != nil {
+		return err
+	}
+	officer := model.FoundationOfficer{}
+	officer.ID = officerID
+	name := changes["name"]
+	switch {
+	case name.From == "" && name.To != "":
+		var acting int64
+		if err := tx.Model(&model.Service{}).Where("acting_officer_id = ?", officerID).Count(&acting).Error; err != nil {
+			return err
+		}
+		if acting > 0 {
+			return fmt.Errorf("%w: %w", ErrRevertConflict, ErrOfficerActing)
+		}
+		if err := tx.Model(&officer).Association("Services").Clear(); err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&officer).Error
+	case name.From != "" && name.To == "":
+		if err := checkOfficerUnique(tx, changes["github"].From, changes["email"].From, officerID); err != nil {
+			return fmt.Errorf("%w: %w", ErrRevertConflict, err)
+		}
+		return tx.Unscoped().Model(&officer).Update("deleted_at", nil).Error
+	}
+	updates := make(map[string]any)
+	if change, ok := changes["name"]; ok {
+		updates["name"] = change.From
+	}
+	if change, ok := changes["email"]; ok {
+		updates["email"] = normalizeOrSentinel(change.From, "EMAIL_MISSING")
+	}
+	if change, ok := changes["github"]; ok {
+		updates["git_hub_account"] = normalizeOrSentinel(change.From, "GITHUB_MISSING")
+	}
+	if change, ok := changes["companyId"]; ok {
+		if updates["company_id"], err = revertValue("companyId", change.From); err != nil {
+			return err
+		}
+	}
+	if len(updates) > 0 {
+		if err := tx.Model(&officer).Updates(updates).Error; err != nil {
+			return err
+		}
+	}
+	change, ok := changes["serviceUserIds"]
+	if !ok {
+		return nil
+	}
+	var users []model.ServiceUser
+	if ids := splitAuditIDs(change.From); len(ids) > 0 {
+		if err := tx.Find(&users, ids).Error; err != nil {
+			return err
+		}
+		if len(users) != len(ids) {
+			return fmt.Errorf("%w: a linked service user no longer exists", ErrRevertConflict)
+		}
+	}
+	if err := tx.Model(&officer).Association("Services").Replace(users); err != nil {
+		return err
+	}
+	var acting []model.Service
+	if err := tx.Where("acting_officer_id = ?", officerID).Find(&acting).Error; err != nil {
+		return err
+	}
+	for _, service := range acting {
+		if _, err := officerServiceUser(tx, officerID, service.ID); err != nil {
+			return fmt.Errorf("%w: officer %d acts for %s", ErrRevertConflict, officerID, service.Name)
+		}
+	}
+	return nil
+}
+
+// revertActingOfficer restores a service's previous acting officer, who must still have an identity on it.
+func revertActingOfficer(tx *gorm.DB, serviceID uint, change AuditChange) error {
+	var service model.Service
+	if err := tx.First(&service, serviceID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: service %d no longer exists", ErrRevertConflict, serviceID)
+		}
+		return err
+	}
+	if current := formatAuditID(service.ActingOfficerID); current != change.To {
+		return fmt.Errorf("%w: actingOfficerId is now %q", ErrRevertConflict, current)
+	}
+	previous, err := revertValue("actingOfficerId", change.From)
+	if err != nil {
+		return err
+	}
+	if id, ok := previous.(uint); ok {
+		if err := tx.Select("id").First(&model.FoundationOfficer{}, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: officer %d no longer exists", ErrRevertConflict, id)
+			}
+			return err
+		}
+		if _, err := officerServiceUser(tx, id, serviceID); err != nil {
+			return fmt.Errorf("%w: %w", ErrRevertConflict, err)
+		}
+	}
+	return tx.Model(&service).Update("acting_officer_id", previous).Error
+}
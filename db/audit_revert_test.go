@@ -0,0 +1,158 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"maintainerd/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRevertAuditEntryProjectMaturity(t *testing.T) {
+	db, store, _ := setupAuditedStore(t)
+	_, project, _, _, _, _ := seedTestData(t, db)
+
+	require.NoError(t, store.UpdateProjectMaturity(project.ID, model.Sandbox))
+	entry := auditEntries(t, db, "PROJECT_MATURITY_UPDATE")[0]
+
+	reverts, err := store.RevertAuditEntry(entry.ID)
+	require.NoError(t, err)
+	require.Len(t, reverts, 1)
+	assert.Equal(t, entry.ID, *reverts[0].RevertOfID)
+	assert.Equal(t, project.ID, *reverts[0].ProjectID)
+	assert.Equal(t, "Reverted PROJECT_MATURITY_UPDATE #"+formatAuditID(&entry.ID)+" by Sam Staff", reverts[0].Message)
+	assert.Equal(t, AuditChange{From: "Sandbox", To: "Graduated"}, auditMetadata(t, reverts[0]).Changes["maturity"])
+
+	var reloaded model.Project
+	require.NoError(t, db.First(&reloaded, project.ID).Error)
+	assert.Equal(t, model.Graduated, reloaded.Maturity)
+
+	_, err = store.RevertAuditEntry(entry.ID)
+	assert.ErrorIs(t, err, ErrAlreadyReverted)
+	_, err = store.RevertAuditEntry(reverts[0].ID)
+	assert.ErrorIs(t, err, ErrRevertUnsupported)
+	_, err = store.RevertAuditEntry(9999)
+	assert.ErrorIs(t, err, ErrAuditEntryNotFound)
+
+	report, err := store.VerifyAuditChain(context.Background())
+	require.NoError(t, err)
+	assert.True(t, report.OK())
+}
+
+func TestRevertAuditEntryConflict(t *testing.T) {
+	db, store, _ := setupAuditedStore(t)
+	_, project, _, _, _, _ := seedTestData(t, db)
+
+	require.NoError(t, store.UpdateProjectMaturity(project.ID, model.Incubating))
+	require.NoError(t, store.UpdateProjectMaturity(project.ID, model.Sandbox))
+	first := auditEntries(t, db, "PROJECT_MATURITY_UPDATE")[0]
+
+	_, err := store.RevertAuditEntry(first.ID)
+	assert.ErrorIs(t, err, ErrRevertConflict)
+
+	var reloaded model.Project
+	require.NoError(t, db.First(&reloaded, project.ID).Error)
+	assert.Equal(t, model.Sandbox, reloaded.Maturity)
+	assert.Empty(t, auditEntries(t, db, "AUDIT_REVERT"))
+}
+
+func TestRevertAuditEntryBulkStatus(t *testing.T) {
+	db, store, _ := setupAuditedStore(t)
+	_, _, _, alice, bob, _ := seedTestData(t, db)
+
+	require.NoError(t, store.UpdateMaintainersStatus([]uint{alice.ID, bob.ID}, model.RetiredMaintainer))
+	require.NoError(t, store.UpdateMaintainerStatus(alice.ID, model.EmeritusMaintainer))
+	entries := auditEntries(t, db, "MAINTAINER_STATUS_UPDATE")
+	require.Len(t, entries, 3)
+
+	_, err := store.RevertAuditEntry(entries[1].ID)
+	assert.ErrorIs(t, err, ErrRevertConflict, "alice changed again after the bulk update")
+
+	require.NoError(t, store.UpdateMaintainerStatus(alice.ID, model.RetiredMaintainer))
+	reverts, err := store.RevertAuditEntry(entries[1].ID)
+	require.NoError(t, err)
+	require.Len(t, reverts, 2, "the whole batch is reverted")
+
+	for _, id := range []uint{alice.ID, bob.ID} {
+		var m model.Maintainer
+		require.NoError(t, db.First(&m, id).Error)
+		assert.Equal(t, model.ActiveMaintainer, m.MaintainerStatus)
+	}
+}
+
+func TestRevertAuditEntryMaintainerChanges(t *testing.T) {
+	db, store, _ := setupAuditedStore(t)
+	company, project1, project2, alice, _, _ := seedTestData(t, db)
+
+	_, err := store.UpdateMaintainerDetails(alice.ID, "Alice D", "alice@new.example", "alice", model.ActiveMaintainer, nil)
+	require.NoError(t, err)
+	_, err = store.UpsertMaintainer(project2.ID, "", "", "alice", "")
+	require.NoError(t, err)
+	updates := auditEntries(t, db, "MAINTAINER_UPDATE")
+	require.Len(t, updates, 2)
+
+	_, err = store.RevertAuditEntry(updates[1].ID)
+	require.NoError(t, err)
+	_, err = store.RevertAuditEntry(updates[0].ID)
+	require.NoError(t, err)
+
+	var reloaded model.Maintainer
+	require.NoError(t, db.Preload("Projects").First(&reloaded, alice.ID).Error)
+	assert.Equal(t, "Alice Developer", reloaded.Name)
+	assert.Equal(t, "alice@example.com", reloaded.Email)
+	require.NotNil(t, reloaded.CompanyID)
+	assert.Equal(t, company.ID, *reloaded.CompanyID)
+	require.Len(t, reloaded.Projects, 1)
+	assert.Equal(t, project1.ID, reloaded.Projects[0].ID)
+
+	created, err := store.UpsertMaintainer(project1.ID, "Dana", "dana@example.com", "dana", "")
+	require.NoError(t, err)
+	_, err = store.RevertAuditEntry(auditEntries(t, db, "MAINTAINER_CREATE")[0].ID)
+	require.NoError(t, err)
+	assert.Error(t, db.First(&model.Maintainer{}, created.ID).Error)
+	var links int64
+	require.NoError(t, db.Model(&model.MaintainerProject{}).Where("maintainer_id = ?", created.ID).Count(&links).Error)
+	assert.Zero(t, links)
+}
+
+func TestRevertAuditEntryCompanyMerge(t *testing.T) {
+	db, store, _ := setupAuditedStore(t)
+	company, _, _, alice, bob, charlie := seedTestData(t, db)
+	target := model.Company{Name: "Acme"}
+	require.NoError(t, db.Create(&target).Error)
+	existing := model.Maintainer{Name: "Eve", GitHubAccount: "eve", MaintainerStatus: model.ActiveMaintainer, CompanyID: &target.ID}
+	require.NoError(t, db.Create(&existing).Error)
+
+	require.NoError(t, store.MergeCompanies(company.ID, target.ID))
+	merge := auditEntries(t, db, "COMPANY_MERGE")[0]
+
+	_, err := store.RevertAuditEntry(merge.ID)
+	require.NoError(t, err)
+
+	var restored model.Company
+	require.NoError(t, db.First(&restored, company.ID).Error)
+	for _, id := range []uint{alice.ID, bob.ID, charlie.ID} {
+		var m model.Maintainer
+		require.NoError(t, db.First(&m, id).Error)
+		assert.Equal(t, company.ID, *m.CompanyID)
+	}
+	var eve model.Maintainer
+	require.NoError(t, db.First(&eve, existing.ID).Error)
+	assert.Equal(t, target.ID, *eve.CompanyID, "maintainers already at the target stay put")
+}
+
+func TestRevertAuditEntryUnsupported(t *testing.T) {
+	db, store, _ := setupAuditedStore(t)
+
+	created := model.Project{Name: "envoy", Maturity: model.Sandbox}
+	require.NoError(t, store.CreateProject(&created))
+	_, err := store.RevertAuditEntry(auditEntries(t, db, "PROJECT_CREATE")[0].ID)
+	assert.ErrorIs(t, err, ErrRevertUnsupported)
+
+	legacy := model.AuditLog{Action: "COMPANY_MERGE", Metadata: `{"actor":{"login":"sam"}}`}
+	require.NoError(t, store.AppendAuditLog(&legacy))
+	_, err = store.RevertAuditEntry(legacy.ID)
+	assert.ErrorIs(t, err, ErrRevertUnsupported)
+}
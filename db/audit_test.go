@@ -0,0 +1,161 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"maintainerd/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func seedAuditLogs(t *testing.T, db *gorm.DB) (model.Project, model.Maintainer, []model.AuditLog) {
+	t.Helper()
+	require.NoError(t, db.AutoMigrate(&model.Foundation{}, &model.StaffMember{}, &model.AuditLog{}))
+	_, kubernetes, prometheus, alice, _, _ := seedTestData(t, db)
+
+	staff := model.StaffMember{Name: "Sam Staff", GitHubAccount: "sam"}
+	require.NoError(t, db.Create(&staff).Error)
+	fossa := model.Service{Name: "FOSSA"}
+	require.NoError(t, db.Create(&fossa).Error)
+
+	base := time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC)
+	logs := []model.AuditLog{
+		{ProjectID: &kubernetes.ID, MaintainerID: &alice.ID, StaffID: &staff.ID, Action: "PROJECT_MAINTAINER_ADD", Message: "Alice added to kubernetes"},
+		{ProjectID: &kubernetes.ID, Action: "PROJECT_MATURITY_UPDATE", Message: "Maturity changed to Graduated"},
+		{ProjectID: &prometheus.ID, MaintainerID: &alice.ID, ServiceID: &fossa.ID, Action: "FOSSA_ADD_MEMBER", Message: "Added @alice to FOSSA team prometheus"},
+		{MaintainerID: &alice.ID, StaffID: &staff.ID, Action: "MAINTAINER_UPDATE", Message: "Maintainer [email] updated by Sam 100%"},
+	}
+	for i := range logs {
+		logs[i].CreatedAt = base.AddDate(0, i, 0)
+		require.NoError(t, db.Create(&logs[i]).Error)
+	}
+	return kubernetes, alice, logs
+}
+
+func TestListAuditLogsFilters(t *testing.T) {
+	db := setupTestDB(t)
+	store := NewSQLStore(db)
+	kubernetes, alice, logs := seedAuditLogs(t, db)
+
+	actions := func(entries []model.AuditLog) []string {
+		var out []string
+		for _, entry := range entries {
+			out = append(out, entry.Action)
+		}
+		return out
+	}
+
+	t.Run("no filter returns newest first", func(t *testing.T) {
+		result, total, err := store.ListAuditLogs(AuditFilter{}, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), total)
+		assert.Equal(t, []string{"MAINTAINER_UPDATE", "FOSSA_ADD_MEMBER", "PROJECT_MATURITY_UPDATE", "PROJECT_MAINTAINER_ADD"}, actions(result))
+		require.NotNil(t, result[0].Staff)
+		assert.Equal(t, "sam", result[0].Staff.GitHubAccount)
+	})
+
+	t.Run("project and action", func(t *testing.T) {
+		result, total, err := store.ListAuditLogs(AuditFilter{
+			ProjectIDs: []uint{kubernetes.ID},
+			Actions:    []string{"project_maintainer_add", "MAINTAINER_UPDATE"},
+		}, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, []string{"PROJECT_MAINTAINER_ADD"}, actions(result))
+	})
+
+	t.Run("maintainer, staff and service", func(t *testing.T) {
+		result, _, err := store.ListAuditLogs(AuditFilter{MaintainerIDs: []uint{alice.ID}, StaffIDs: []uint{*logs[0].StaffID}}, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"MAINTAINER_UPDATE", "PROJECT_MAINTAINER_ADD"}, actions(result))
+
+		result, _, err = store.ListAuditLogs(AuditFilter{ServiceIDs: []uint{*logs[2].ServiceID}}, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"FOSSA_ADD_MEMBER"}, actions(result))
+	})
+
+	t.Run("date range is half open", func(t *testing.T) {
+		from := logs[1].CreatedAt
+		to := logs[3].CreatedAt
+		result, _, err := store.ListAuditLogs(AuditFilter{From: &from, To: &to}, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"FOSSA_ADD_MEMBER", "PROJECT_MATURITY_UPDATE"}, actions(result))
+	})
+
+	t.Run("message search matches every term", func(t *testing.T) {
+		result, _, err := store.ListAuditLogs(AuditFilter{Query: "alice FOSSA"}, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"FOSSA_ADD_MEMBER"}, actions(result))
+
+		result, _, err = store.ListAuditLogs(AuditFilter{Query: "100%"}, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"MAINTAINER_UPDATE"}, actions(result))
+
+		result, _, err = store.ListAuditLogs(AuditFilter{Query: "_"}, 10, 0)
+		require.NoError(t, err)
+		assert.Empty(t, result, "LIKE wildcards in the query are literal")
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		result, total, err := store.ListAuditLogs(AuditFilter{}, 2, 2)
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), total)
+		assert.Equal(t, []string{"PROJECT_MATURITY_UPDATE", "PROJECT_MAINTAINER_ADD"}, actions(result))
+	})
+
+	t.Run("failed merge lookup is returned", func(t *testing.T) {
+		require.NoError(t, db.Migrator().DropTable(&model.Maintainer{}))
+		_, _, err := store.ListAuditLogs(AuditFilter{MaintainerIDs: []uint{alice.ID}}, 10, 0)
+		assert.Error(t, err, "the filter is not silently narrowed to the listed maintainers")
+		err = store.StreamAuditLogs(context.Background(), AuditFilter{MaintainerIDs: []uint{alice.ID}}, 10, func([]model.AuditLog) error { return nil })
+		assert.Error(t, err)
+	})
+}
+
+func TestStreamAuditLogs(t *testing.T) {
+	db := setupTestDB(t)
+	store := NewSQLStore(db)
+	kubernetes, _, _ := seedAuditLogs(t, db)
+
+	var batches [][]uint
+	err := store.StreamAuditLogs(context.Background(), AuditFilter{}, 3, func(batch []model.AuditLog) error {
+		var ids []uint
+		for _, entry := range batch {
+			ids = append(ids, entry.ID)
+		}
+		batches = append(batches, ids)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, [][]uint{{1, 2, 3}, {4}}, batches)
+
+	var count int
+	err = store.StreamAuditLogs(context.Background(), AuditFilter{ProjectIDs: []uint{kubernetes.ID}}, 1, func(batch []model.AuditLog) error {
+		count += len(batch)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, store.StreamAuditLogs(ctx, AuditFilter{}, 2, func([]model.AuditLog) error { return nil }), context.Canceled)
+}
+
+func TestResolveAuditNames(t *testing.T) {
+	db := setupTestDB(t)
+	store := NewSQLStore(db)
+	kubernetes, alice, logs := seedAuditLogs(t, db)
+
+	require.NoError(t, db.Delete(&alice).Error)
+
+	names, err := store.ResolveAuditNames(logs)
+	require.NoError(t, err)
+	assert.Equal(t, "kubernetes", names.Projects[kubernetes.ID])
+	assert.Equal(t, "Alice Developer", names.Maintainers[alice.ID], "soft-deleted maintainers still resolve")
+	assert.Equal(t, "FOSSA", names.Services[*logs[2].ServiceID])
+}
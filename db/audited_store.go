@@ -0,0 +1,1324 @@
+package db
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"maintainerd/model"
+
+	"gorm.io/gorm"
+)
+
+// AuditActor identifies who made a change recorded in the audit log.
+type AuditActor struct {
+	Login string
+	Role  string
+	Name  string
+	// StaffID is set when the actor is a staff member.
+	StaffID *uint
+	// MaintainerID is set when the actor is a maintainer acting on their own behalf.
+	MaintainerID *uint
+}
+
+// DisplayName returns the actor's name for audit messages.
+func (a AuditActor) DisplayName() string {
+	if name := strings.TrimSpace(a.Name); name != "" {
+		return name
+	}
+	if a.Login != "" {
+		return a.Login
+	}
+	return "system"
+}
+
+// AuditChange is the before and after value of one field. An empty From means the field was set for the first
+// time; an empty To means it was cleared.
+type AuditChange struct {
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+}
+
+// AuditMetadata is the JSON stored in AuditLog.Metadata for changes written through an AuditedStore.
+type AuditMetadata struct {
+	Actor   auditMetadataActor     `json:"actor"`
+	Changes map[string]AuditChange `json:"changes"`
+	// Batch groups the entries written by a single bulk operation.
+	Batch *AuditBatch `json:"batch,omitempty"`
+	// Merge records what a company merge did, so it can be undone.
+	Merge *AuditCompanyMerge `json:"merge,omitempty"`
+	// MaintainerMerge records what a maintainer merge moved, so it can be undone.
+	MaintainerMerge *MaintainerMerge `json:"maintainerMerge,omitempty"`
+	// CompanyID identifies the company for changes to a company's own settings, such as its domains.
+	CompanyID *uint `json:"companyId,omitempty"`
+	// FoundationID identifies the foundation for changes to a foundation.
+	FoundationID *uint `json:"foundationId,omitempty"`
+	// StaffMemberID identifies the staff member for changes to a staff member; AuditLog.StaffID is the actor.
+	StaffMemberID *uint `json:"staffMemberId,omitempty"`
+	// CollaboratorID identifies the collaborator for changes to a collaborator.
+	CollaboratorID *uint `json:"collaboratorId,omitempty"`
+	// Promotion records what promoting a collaborator to maintainer did.
+	Promotion *CollaboratorPromotion `json:"promotion,omitempty"`
+	// OfficerID identifies the foundation officer for changes to an officer or their service identities.
+	OfficerID *uint `json:"officerId,omitempty"`
+	// Officer records the officer identity an action on a remote service, such as FOSSA, was performed as.
+	Officer *OfficerIdentity `json:"officer,omitempty"`
+	// Lifecycle records what a project maturity transition or rename did, so it can be undone.
+	Lifecycle *ProjectLifecycle `json:"lifecycle,omitempty"`
+}
+
+type auditMetadataActor struct {
+	Login string `json:"login"`
+	Role  string `json:"role"`
+}
+
+// AuditBatch identifies the entries written by one bulk operation.
+type AuditBatch struct {
+	ID   string `json:"id"`
+	Size int    `json:"size"`
+}
+
+// AuditCompanyMerge records a MergeCompanies call and the maintainers, domains and aliases it moved.
+type AuditCompanyMerge struct {
+	FromCompanyID uint     `json:"fromCompanyId"`
+	ToCompanyID   uint     `json:"toCompanyId"`
+	MaintainerIDs []uint   `json:"maintainerIds"`
+	Domains       []string `json:"domains,omitempty"`
+	// Aliases are the normalized names of the source company's own aliases, which moved to the target.
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+// ParseAuditMetadata decodes the metadata of an audit entry written through an AuditedStore.
+func ParseAuditMetadata(raw string) (*AuditMetadata, error) {
+	var metadata AuditMetadata
+	if err := json.Unmarshal([]byte(raw), &metadata); err != nil {
+		return nil, err
+	}
+	return &metadata, nil
+}
+
+// AuditedStore wraps SQLStore so that each write records the actor and a field-level before/after diff in the
+// audit log, in the same transaction as the change. Writes that change nothing are not recorded.
+type AuditedStore struct {
+	*SQLStore
+	actor AuditActor
+}
+
+// WithActor returns a store whose writes are audited as made by actor.
+func (s *SQLStore) WithActor(actor AuditActor) *AuditedStore {
+	return &AuditedStore{SQLStore: s, actor: actor}
+}
+
+// Actor returns the actor writes are attributed to.
+func (a *AuditedStore) Actor() AuditActor {
+	return a.actor
+}
+
+// write runs fn in a transaction and appends the audit entries it returns before committing.
+func (a *AuditedStore) write(fn func(tx *gorm.DB, store *SQLStore) ([]model.AuditLog, error)) error {
+	return a.db.Transaction(func(tx *gorm.DB) error {
+		events, err := fn(tx, &SQLStore{db: tx, actorLogin: a.actor.Login})
+		if err != nil {
+			return err
+		}
+		for i := range events {
+			if err := AppendAuditLogTx(tx, &events[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// event builds an audit entry attributed to the actor.
+func (a *AuditedStore) event(action, message string, metadata AuditMetadata) (model.AuditLog, error) {
+	metadata.Actor = auditMetadataActor{Login: a.actor.Login, Role: a.actor.Role}
+	raw, err := json.Marshal(metadata)
+	if err != nil {
+		return model.AuditLog{}, err
+	}
+	return model.AuditLog{
+		StaffID:      a.actor.StaffID,
+		MaintainerID: a.actor.MaintainerID,
+		Action:       action,
+		Message:      message,
+		Metadata:     string(raw),
+	}, nil
+}
+
+// DiffAuditFields returns the fields whose values differ between before and after.
+func DiffAuditFields(before, after map[string]string) map[string]AuditChange {
+	changes := make(map[string]AuditChange)
+	for field, to := range after {
+		if from := before[field]; from != to {
+			changes[field] = AuditChange{From: from, To: to}
+		}
+	}
+	for field, from := range before {
+		if _, ok := after[field]; !ok && from != "" {
+			changes[field] = AuditChange{From: from}
+		}
+	}
+	return changes
+}
+
+func changedFieldNames(changes map[string]AuditChange) []string {
+	names := make([]string, 0, len(changes))
+	for field := range changes {
+		names = append(names, field)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func formatAuditID(id *uint) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.FormatUint(uint64(*id), 10)
+}
+
+func formatAuditTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// projectAuditFields is the audited view of a project.
+func projectAuditFields(project model.Project) map[string]string {
+	onboardingIssue := ""
+	if project.OnboardingIssue != nil {
+		onboardingIssue = *project.OnboardingIssue
+	}
+	return map[string]string{
+		"projectName":     project.Name,
+		"maturity":        string(project.Maturity),
+		"githubOrg":       project.GitHubOrg,
+		"maintainerRef":   strings.TrimSpace(project.LegacyMaintainerRef),
+		"dotProjectRef":   project.DotProjectYamlRef,
+		"onboardingIssue": onboardingIssue,
+		"parentProjectId": formatAuditID(project.ParentProjectID),
+	}
+}
+
+// maintainerAuditFields loads the audited view of a maintainer, including soft-deleted ones.
+func maintainerAuditFields(tx *gorm.DB, maintainerID uint) (map[string]string, error) {
+	var maintainer model.Maintainer
+	if err := tx.Unscoped().First(&maintainer, maintainerID).Error; err != nil {
+		return nil, err
+	}
+	companyName := ""
+	if maintainer.CompanyID != nil {
+		var company model.Company
+		if err := tx.Unscoped().Select("name").First(&company, *maintainer.CompanyID).Error; err == nil {
+			companyName = company.Name
+		}
+	}
+	var projectIDs []uint
+	if err := tx.Table("maintainer_projects").
+		Where("maintainer_id = ?", maintainerID).
+		Order("project_id").
+		Pluck("project_id", &projectIDs).Error; err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(projectIDs))
+	for _, id := range projectIDs {
+		ids = append(ids, strconv.FormatUint(uint64(id), 10))
+	}
+	return map[string]string{
+		"name":            strings.TrimSpace(maintainer.Name),
+		"email":           maintainer.Email,
+		"github":          maintainer.GitHubAccount,
+		"githubEmail":     maintainer.GitHubEmail,
+		"status":          string(maintainer.MaintainerStatus),
+		"companyId":       formatAuditID(maintainer.CompanyID),
+		"company":         companyName,
+		"emailVerifiedAt": formatAuditTime(maintainer.EmailVerifiedAt),
+		"projectIds":      strings.Join(ids, ","),
+	}, nil
+}
+
+// maintainerEvent builds an audit entry for a change to maintainerID, or returns nil if nothing changed.
+func (a *AuditedStore) maintainerEvent(action string, maintainerID uint, projectID *uint, before, after map[string]string, metadata AuditMetadata, message func(fields []string) string) (*model.AuditLog, error) {
+	changes := DiffAuditFields(before, after)
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	metadata.Changes = changes
+	event, err := a.event(action, message(changedFieldNames(changes)), metadata)
+	if err != nil {
+		return nil, err
+	}
+	event.MaintainerID = &maintainerID
+	event.ProjectID = projectID
+	return &event, nil
+}
+
+// CreateProject inserts project and records a PROJECT_CREATE entry.
+func (a *AuditedStore) CreateProject(project *model.Project) error {
+	return a.write(func(tx *gorm.DB, store *SQLStore) ([]model.AuditLog, error) {
+		if err := store.CreateProject(project); err != nil {
+			return nil, err
+		}
+		event, err := a.event("PROJECT_CREATE",
+			fmt.Sprintf("Project created by %s", a.actor.DisplayName()),
+			AuditMetadata{Changes: DiffAuditFields(nil, projectAuditFields(*project))})
+		if err != nil {
+			return nil, err
+		}
+		event.ProjectID = &project.ID
+		return []model.AuditLog{event}, nil
+	})
+}
+
+// UpdateProjectMaturity updates the maturity and records a PROJECT_MATURITY_UPDATE entry.
+func (a *AuditedStore) UpdateProjectMaturity(projectID uint, maturity model.Maturity) error {
+	return a.updateProject(projectID, "PROJECT_MATURITY_UPDATE", "Project maturity updated by %s", func(store *SQLStore) error {
+		return store.UpdateProjectMaturity(projectID, maturity)
+	})
+}
+
+// UpdateProjectLegacyMaintainerRef updates the maintainer ref and records a PROJECT_MAINTAINER_REF_UPDATE entry.
+func (a *AuditedStore) UpdateProjectLegacyMaintainerRef(projectID uint, ref string) error {
+	return a.updateProject(projectID, "PROJECT_MAINTAINER_REF_UPDATE", "Project maintainer ref updated by %s", func(store *SQLStore) error {
+		return store.UpdateProjectLegacyMaintainerRef(projectID, ref)
+	})
+}
+
+// SetProjectParent nests a project under another, or makes it top-level when parentID is nil, and records a
+// PROJECT_PARENT_UPDATE entry.
+func (a *AuditedStore) SetProjectParent(projectID uint, parentID *uint) error {
+	return a.updateProject(projectID, "PROJECT_PARENT_UPDATE", "Project parent updated by %s", func(store *SQLStore) error {
+		return store.SetProjectParent(projectID, parentID)
+	})
+}
+
+// TransitionProjectMaturity moves a project to a new maturity level and records a PROJECT_MATURITY_TRANSITION
+// entry, including the memberships and service teams an archival changed.
+func (a *AuditedStore) TransitionProjectMaturity(projectID uint, transition MaturityTransition) (*ProjectLifecycle, error) {
+	var lifecycle *ProjectLifecycle
+	err := a.updateProjectLifecycle(projectID, "PROJECT_MATURITY_TRANSITION", "Project maturity moved by %s", func(store *SQLStore) (*ProjectLifecycle, error) {
+		var err error
+		lifecycle, err = store.TransitionProjectMaturity(projectID, transition)
+		return lifecycle, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lifecycle, nil
+}
+
+// RenameProject renames a project, keeping the old name as an alias, and records a PROJECT_RENAME entry.
+func (a *AuditedStore) RenameProject(projectID uint, name string) (*ProjectLifecycle, error) {
+	var lifecycle *ProjectLifecycle
+	err := a.updateProjectLifecycle(projectID, "PROJECT_RENAME", "Project renamed by %s", func(store *SQLStore) (*ProjectLifecycle, error) {
+		var err error
+		lifecycle, err = store.RenameProject(projectID, name)
+		return lifecycle, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lifecycle, nil
+}
+
+func (a *AuditedStore) updateProjectLifecycle(projectID uint, action, message string, apply func(store *SQLStore) (*ProjectLifecycle, error)) error {
+	return a.updateProjectWith(projectID, action, message, func(store *SQLStore, metadata *AuditMetadata) error {
+		lifecycle, err := apply(store)
+		metadata.Lifecycle = lifecycle
+		return err
+	})
+}
+
+func (a *AuditedStore) updateProject(projectID uint, action, message string, update func(store *SQLStore) error) error {
+	return a.updateProjectWith(projectID, action, message, func(store *SQLStore, _ *AuditMetadata) error {
+		return update(store)
+	})
+}
+
+// updateProjectWith applies update to a project and records action with the project's field-level diff and any
+// metadata update adds.
+func (a *AuditedStore) updateProjectWith(projectID uint, action, message string, update func(store *SQLStore, metadata *AuditMetadata) error) error {
+	return a.write(func(tx *gorm.DB, store *SQLStore) ([]model.AuditLog, error) {
+		var before model.Project
+		if err := tx.First(&before, projectID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrProjectNotFound
+			}
+			return nil, err
+		}
+		var metadata AuditMetadata
+		if err := update(store, &metadata); err != nil {
+			return nil, err
+		}
+		var after model.Project
+		if err := tx.First(&after, projectID).Error; err != nil {
+			return nil, err
+		}
+		metadata.Changes = DiffAuditFields(projectAuditFields(before), projectAuditFields(after))
+		if len(metadata.Changes) == 0 {
+			return nil, nil
+		}
+		event, err := a.event(action, fmt.Sprintf(message, a.actor.DisplayName()), metadata)
+		if err != nil {
+			return nil, err
+		}
+		event.ProjectID = &projectID
+		return []model.AuditLog{event}, nil
+	})
+}
+
+// UpdateMaintainerStatus updates one maintainer's status and records a MAINTAINER_STATUS_UPDATE entry.
+func (a *AuditedStore) UpdateMaintainerStatus(maintainerID uint, status model.MaintainerStatus) error {
+	return a.UpdateMaintainersStatus([]uint{maintainerID}, status)
+}
+
+// UpdateMaintainersStatus updates each maintainer's status and records one MAINTAINER_STATUS_UPDATE entry per
+// maintainer that changed. Entries from a bulk update share a batch ID.
+func (a *AuditedStore) UpdateMaintainersStatus(ids []uint, status model.MaintainerStatus) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if !status.IsValid() {
+		return fmt.Errorf("invalid maintainer status %q", status)
+	}
+	var batch *AuditBatch
+	if len(ids) > 1 {
+		batch = &AuditBatch{ID: newAuditBatchID(), Size: len(ids)}
+	}
+	return a.write(func(tx *gorm.DB, store *SQLStore) ([]model.AuditLog, error) {
+		var events []model.AuditLog
+		for _, id := range ids {
+			before, err := maintainerAuditFields(tx, id)
+			if err != nil {
+				return nil, err
+			}
+			if err := store.UpdateMaintainerStatus(id, status); err != nil {
+				return nil, err
+			}
+			after, err := maintainerAuditFields(tx, id)
+			if err != nil {
+				return nil, err
+			}
+			event, err := a.maintainerEvent("MAINTAINER_STATUS_UPDATE", id, nil, before, after, AuditMetadata{Batch: batch}, func([]string) string {
+				return fmt.Sprintf("Maintainer status updated by %s", a.actor.DisplayName())
+			})
+			if err != nil {
+				return nil, err
+			}
+			if event != nil {
+				events = append(events, *event)
+			}
+		}
+		return events, nil
+	})
+}
+
+func newAuditBatchID() string {
+	buf := make([]byte, 8)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// UpdateMaintainerDetails updates a maintainer's editable fields and records a MAINTAINER_UPDATE entry.
+func (a *AuditedStore) UpdateMaintainerDetails(maintainerID uint, name, email, github string, status model.MaintainerStatus, companyID *uint) (*model.Maintainer, error) {
+	var updated *model.Maintainer
+	err := a.write(func(tx *gorm.DB, store *SQLStore) ([]model.AuditLog, error) {
+		before, err := maintainerAuditFields(tx, maintainerID)
+		if err != nil {
+			return nil, err
+		}
+		updated, err = store.UpdateMaintainerDetails(maintainerID, name, email, github, status, companyID)
+		if err != nil {
+			return nil, err
+		}
+		after, err := maintainerAuditFields(tx, maintainerID)
+		if err != nil {
+			return nil, err
+		}
+		event, err := a.maintainerEvent("MAINTAINER_UPDATE", maintainerID, nil, before, after, AuditMetadata{}, a.maintainerUpdateMessage)
+		if err != nil || event == nil {
+			return nil, err
+		}
+		return []model.AuditLog{*event}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (a *AuditedStore) maintainerUpdateMessage(fields []string) string {
+	return fmt.Sprintf("Maintainer [%s] updated by %s", strings.Join(fields, ", "), a.actor.DisplayName())
+}
+
+// UpsertMaintainer creates or updates a maintainer on a project and records a MAINTAINER_CREATE or
+// MAINTAINER_UPDATE entry.
+func (a *AuditedStore) UpsertMaintainer(projectID uint, name, email, githubHandle, company string) (*model.Maintainer, error) {
+	var maintainer *model.Maintainer
+	err := a.write(func(tx *gorm.DB, store *SQLStore) ([]model.AuditLog, error) {
+		var event *model.AuditLog
+		var err error
+		maintainer, event, err = a.upsertMaintainer(tx, store, projectID, name, email, githubHandle, company)
+		if err != nil || event == nil {
+			return nil, err
+		}
+		return []model.AuditLog{*event}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return maintainer, nil
+}
+
+// ApplyImport applies a bulk import in one transaction, recording a MAINTAINER_CREATE or MAINTAINER_UPDATE entry
+// per row that changes something. The entries share a batch, so reverting one reverts its kind for the whole import.
+// Nothing is applied if any row conflicts; the plan is returned with ErrImportConflicts.
+func (a *AuditedStore) ApplyImport(rows []ImportRow) (*ImportPlan, error) {
+	var plan *ImportPlan
+	err := a.write(func(tx *gorm.DB, store *SQLStore) ([]model.AuditLog, error) {
+		var err error
+		if plan, err = planImport(tx, rows); err != nil {
+			return nil, err
+		}
+		if plan.Conflicts > 0 {
+			return nil, ErrImportConflicts
+		}
+		var events []model.AuditLog
+		err = plan.apply(func(row ImportPlanRow) (*model.Maintainer, error) {
+			maintainer, event, err := a.upsertMaintainer(tx, store, row.ProjectID, row.Name, row.Email, row.GitHub, row.Company)
+			if event != nil {
+				events = append(events, *event)
+			}
+			return maintainer, err
+		})
+		if err != nil {
+			return nil, err
+		}
+		return withAuditBatch(events)
+	})
+	if err != nil && plan == nil {
+		return nil, err
+	}
+	return plan, err
+}
+
+func (a *AuditedStore) upsertMaintainer(tx *gorm.DB, store *SQLStore, projectID uint, name, email, githubHandle, company string) (*model.Maintainer, *model.AuditLog, error) {
+	existing, err := findUpsertMaintainer(tx, email, githubHandle)
+	if err != nil {
+		return nil, nil, err
+	}
+	var before map[string]string
+	action := "MAINTAINER_CREATE"
+	message := func([]string) string { return fmt.Sprintf("Maintainer created by %s", a.actor.DisplayName()) }
+	if existing != nil {
+		if before, err = maintainerAuditFields(tx, existing.ID); err != nil {
+			return nil, nil, err
+		}
+		action = "MAINTAINER_UPDATE"
+		message = a.maintainerUpdateMessage
+	}
+	maintainer, err := store.UpsertMaintainer(projectID, name, email, githubHandle, company)
+	if err != nil {
+		return nil, nil, err
+	}
+	after, err := maintainerAuditFields(tx, maintainer.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	event, err := a.maintainerEvent(action, maintainer.ID, &projectID, before, after, AuditMetadata{}, message)
+	if err != nil {
+		return nil, nil, err
+	}
+	return maintainer, event, nil
+}
+
+// MarkMaintainerEmailVerified records the verification and a MAINTAINER_EMAIL_VERIFIED entry.
+func (a *AuditedStore) MarkMaintainerEmailVerified(maintainerID uint, email string, verifiedAt time.Time) error {
+	return a.write(func(tx *gorm.DB, store *SQLStore) ([]model.AuditLog, error) {
+		before, err := maintainerAuditFields(tx, maintainerID)
+		if err != nil {
+			return nil, err
+		}
+		if err := store.MarkMaintainerEmailVerified(maintainerID, email, verifiedAt); err != nil {
+			return nil, err
+		}
+		after, err := maintainerAuditFields(tx, maintainerID)
+		if err != nil {
+			return nil, err
+		}
+		event, err := a.maintainerEvent("MAINTAINER_EMAIL_VERIFIED", maintainerID, nil, before, after, AuditMetadata{}, func([]string) string {
+			return "Maintainer verified their email address"
+		})
+		if err != nil || event == nil {
+			return nil, err
+		}
+		return []model.AuditLog{*event}, nil
+	})
+}
+
+// membershipAuditFields loads the audited view of a membership.
+func membershipAuditFields(tx *gorm.DB, maintainerID, projectID uint) (map[string]string, error) {
+	membership, err := NewSQLStore(tx).GetMembership(maintainerID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{
+		"status": string(membership.Status),
+		"role":   string(membership.Role),
+		"leftAt": formatAuditTime(membership.LeftAt),
+	}, nil
+}
+
+// UpdateMembership changes a membership's status and/or role and records a MEMBERSHIP_UPDATE entry.
+func (a *AuditedStore) UpdateMembership(maintainerID, projectID uint, status model.MaintainerStatus, role model.MembershipRole) (*model.MaintainerProject, error) {
+	var membership *model.MaintainerProject
+	err := a.write(func(tx *gorm.DB, store *SQLStore) ([]model.AuditLog, error) {
+		before, err := membershipAuditFields(tx, maintainerID, projectID)
+		if err != nil {
+			return nil, err
+		}
+		if membership, err = store.UpdateMembership(maintainerID, projectID, status, role); err != nil {
+			return nil, err
+		}
+		after, err := membershipAuditFields(tx, maintainerID, projectID)
+		if err != nil {
+			return nil, err
+		}
+		event, err := a.maintainerEvent("MEMBERSHIP_UPDATE", maintainerID, &projectID, before, after, AuditMetadata{}, func(fields []string) string {
+			return fmt.Sprintf("Membership [%s] updated by %s", strings.Join(fields, ", "), a.actor.DisplayName())
+		})
+		if err != nil || event == nil {
+			return nil, err
+		}
+		return []model.AuditLog{*event}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return membership, nil
+}
+
+// RemoveMaintainerFromProject detaches a maintainer from a project and records a MEMBERSHIP_REMOVE entry.
+func (a *AuditedStore) RemoveMaintainerFromProject(projectID, maintainerID uint, cascade bool) (*MembershipRemoval, error) {
+	removals, err := a.RemoveMaintainersFromProject(projectID, []uint{maintainerID}, cascade)
+	if err != nil {
+		return nil, err
+	}
+	return &removals[0], nil
+}
+
+// RemoveMaintainersFromProject detaches maintainers from a project and records a MEMBERSHIP_REMOVE entry for each,
+// grouped as a batch when there is more than one.
+func (a *AuditedStore) RemoveMaintainersFromProject(projectID uint, maintainerIDs []uint, cascade bool) ([]MembershipRemoval, error) {
+	var batch *AuditBatch
+	if len(maintainerIDs) > 1 {
+		batch = &AuditBatch{ID: newAuditBatchID(), Size: len(maintainerIDs)}
+	}
+	var removals []MembershipRemoval
+	err := a.write(func(tx *gorm.DB, store *SQLStore) ([]model.AuditLog, error) {
+		var err error
+		if removals, err = store.RemoveMaintainersFromProject(projectID, maintainerIDs, cascade); err != nil {
+			return nil, err
+		}
+		events := make([]model.AuditLog, 0, len(removals))
+		for _, removal := range removals {
+			event, err := a.maintainerEvent("MEMBERSHIP_REMOVE", removal.Membership.MaintainerID, &projectID,
+				membershipRemovalAuditFields(removal), map[string]string{}, AuditMetadata{Batch: batch},
+				func([]string) string {
+					return fmt.Sprintf("Maintainer removed from project by %s", a.actor.DisplayName())
+				})
+			if err != nil {
+				return nil, err
+			}
+			if event != nil {
+				events = append(events, *event)
+			}
+		}
+		return events, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return removals, nil
+}
+
+// membershipRemovalAuditFields is the audited view of a removed membership. serviceTeams lists the IDs of the
+// service teams the maintainer was unlinked from.
+func membershipRemovalAuditFields(removal MembershipRemoval) map[string]string {
+	membership := removal.Membership
+	teamIDs := make([]string, 0, len(removal.ServiceTeams))
+	for _, link := range removal.ServiceTeams {
+		teamIDs = append(teamIDs, strconv.FormatUint(uint64(link.ServiceTeamID), 10))
+	}
+	return map[string]string{
+		"status":       string(membership.Status),
+		"role":         string(membership.Role),
+		"joinedAt":     formatAuditTime(&membership.JoinedAt),
+		"leftAt":       formatAuditTime(membership.LeftAt),
+		"serviceTeams": strings.Join(teamIDs, ","),
+	}
+}
+
+// CreateCompany creates a company and records a COMPANY_CREATE entry.
+func (a *AuditedStore) CreateCompany(name string) (*model.Company, error) {
+	var company *model.Company
+	err := a.write(func(tx *gorm.DB, store *SQLStore) ([]model.AuditLog, error) {
+		var err error
+		if company, err = store.CreateCompany(name); err != nil {
+			return nil, err
+		}
+		event, err := a.event("COMPANY_CREATE",
+			fmt.Sprintf("Company created by %s", a.actor.DisplayName()),
+			AuditMetadata{Changes: map[string]AuditChange{
+				"companyId": {To: formatAuditID(&company.ID)},
+				"name":      {To: company.Name},
+			}})
+		if err != nil {
+			return nil, err
+		}
+		return []model.AuditLog{event}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return company, nil
+}
+
+// MergeCompanies merges fromID into toID and records a COMPANY_MERGE entry listing the maintainers that moved.
+func (a *AuditedStore) MergeCompanies(fromID, toID uint) error {
+	return a.write(func(tx *gorm.DB, store *SQLStore) ([]model.AuditLog, error) {
+		var moved []uint
+		if err := tx.Model(&model.Maintainer{}).
+			Where("company_id = ?", fromID).
+			Order("id").
+			Pluck("id", &moved).Error; err != nil {
+			return nil, err
+		}
+		var domains []string
+		if err := tx.Model(&model.CompanyDomain{}).
+			Where("company_id = ?", fromID).
+			Order("domain").
+			Pluck("domain", &domains).Error; err != nil {
+			return nil, err
+		}
+		var aliases []string
+		if err := tx.Model(&model.CompanyAlias{}).
+			Where("company_id = ?", fromID).
+			Order("normalized_name").
+			Pluck("normalized_name", &aliases).Error; err != nil {
+			return nil, err
+		}
+		if err := store.MergeCompanies(fromID, toID); err != nil {
+			return nil, err
+		}
+		var names []model.Company
+		if err := tx.Unscoped().Where("id IN ?", []uint{fromID, toID}).Find(&names).Error; err != nil {
+			return nil, err
+		}
+		fromName, toName := "", ""
+		for _, c := range names {
+			if c.ID == fromID {
+				fromName = c.Name
+			} else {
+				toName = c.Name
+			}
+		}
+		if moved == nil {
+			moved = []uint{}
+		}
+		event, err := a.event("COMPANY_MERGE",
+			fmt.Sprintf("Company %s merged into %s by %s", fromName, toName, a.actor.DisplayName()),
+			AuditMetadata{
+				Changes: map[string]AuditChange{"company": {From: fromName, To: toName}},
+				Merge:   &AuditCompanyMerge{FromCompanyID: fromID, ToCompanyID: toID, MaintainerIDs: moved, Domains: domains, Aliases: aliases},
+			})
+		if err != nil {
+			return nil, err
+		}
+		return []model.AuditLog{event}, nil
+	})
+}
+
+// MergeMaintainers merges maintainer fromID into toID and records a MAINTAINER_MERGE entry against the target with
+// the fields it filled in and what moved.
+func (a *AuditedStore) MergeMaintainers(fromID, toID uint) (*MaintainerMerge, error) {
+	var merge *MaintainerMerge
+	err := a.write(func(tx *gorm.DB, store *SQLStore) ([]model.AuditLog, error) {
+		var source model.Maintainer
+		if err := tx.Select("id", "name").First(&source, fromID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("source maintainer %d not found", fromID)
+			}
+			return nil, err
+		}
+		before, err := maintainerAuditFields(tx, toID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("target maintainer %d not found", toID)
+			}
+			return nil, err
+		}
+		if merge, err = store.MergeMaintainers(fromID, toID); err != nil {
+			return nil, err
+		}
+		after, err := maintainerAuditFields(tx, toID)
+		if err != nil {
+			return nil, err
+		}
+		// Memberships are restored from the merge record, not the target's project list.
+		delete(before, "projectIds")
+		delete(after, "projectIds")
+		event, err := a.event("MAINTAINER_MERGE",
+			fmt.Sprintf("Maintainer %s merged into %s by %s", strings.TrimSpace(source.Name), after["name"], a.actor.DisplayName()),
+			AuditMetadata{Changes: DiffAuditFields(before, after), MaintainerMerge: merge})
+		if err != nil {
+			return nil, err
+		}
+		event.MaintainerID = &toID
+		return []model.AuditLog{event}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return merge, nil
+}
+
+// companyDomainsAuditField loads a company's domains as a comma-separated, sorted list.
+func companyDomainsAuditField(tx *gorm.DB, companyID uint) (string, error) {
+	var domains []string
+	if err := tx.Model(&model.CompanyDomain{}).
+		Where("company_id = ?", companyID).
+		Order("domain").
+		Pluck("domain", &domains).Error; err != nil {
+		return "", err
+	}
+	return strings.Join(domains, ","), nil
+}
+
+// updateCompanyList runs fn and records an action entry if the company's list field, as loaded by load, changed.
+func (a *AuditedStore) updateCompanyList(companyID uint, action, field string, load func(tx *gorm.DB, companyID uint) (string, error), fn func(store *SQLStore) error) error {
+	return a.write(func(tx *gorm.DB, store *SQLStore) ([]model.AuditLog, error) {
+		before, err := load(tx, companyID)
+		if err != nil {
+			return nil, err
+		}
+		if err := fn(store); err != nil {
+			return nil, err
+		}
+		after, err := load(tx, companyID)
+		if err != nil || before == after {
+			return nil, err
+		}
+		var company model.Company
+		if err := tx.Select("name").First(&company, companyID).Error; err != nil {
+			return nil, err
+		}
+		event, err := a.event(action,
+			fmt.Sprintf("Company %s %s updated by %s", company.Name, field, a.actor.DisplayName()),
+			AuditMetadata{
+				Changes:   map[string]AuditChange{field: {From: before, To: after}},
+				CompanyID: &companyID,
+			})
+		if err != nil {
+			return nil, err
+		}
+		return []model.AuditLog{event}, nil
+	})
+}
+
+// AddCompanyDomain gives a company an email domain and records a COMPANY_DOMAINS_UPDATE entry.
+func (a *AuditedStore) AddCompanyDomain(companyID uint, domain string) (*model.CompanyDomain, error) {
+	var added *model.CompanyDomain
+	err := a.updateCompanyList(companyID, "COMPANY_DOMAINS_UPDATE", "domains", companyDomainsAuditField, func(store *SQLStore) error {
+		var err error
+		added, err = store.AddCompanyDomain(companyID, domain)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return added, nil
+}
+
+// RemoveCompanyDomain removes an email domain from a company and records a COMPANY_DOMAINS_UPDATE entry.
+func (a *AuditedStore) RemoveCompanyDomain(companyID uint, domain string) (bool, error) {
+	var removed bool
+	err := a.updateCompanyList(companyID, "COMPANY_DOMAINS_UPDATE", "domains", companyDomainsAuditField, func(store *SQLStore) error {
+		var err error
+		removed, err = store.RemoveCompanyDomain(companyID, domain)
+		return err
+	})
+	return removed, err
+}
+
+// SetCompanyAliases replaces a company's aliases and records a COMPANY_ALIASES_UPDATE entry.
+func (a *AuditedStore) SetCompanyAliases(companyID uint, names []string) ([]model.CompanyAlias, error) {
+	var aliases []model.CompanyAlias
+	err := a.updateCompanyList(companyID, "COMPANY_ALIASES_UPDATE", "aliases", companyAliasesAuditField, func(store *SQLStore) error {
+		var err error
+		aliases, err = store.SetCompanyAliases(companyID, names)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return aliases, nil
+}
+
+// UpdateCompany edits a company's name, website, notes or tags and records a COMPANY_UPDATE entry.
+func (a *AuditedStore) UpdateCompany(companyID uint, update CompanyUpdate) (*model.Company, error) {
+	var company *model.Company
+	err := a.write(func(tx *gorm.DB, store *SQLStore) ([]model.AuditLog, error) {
+		before, err := store.GetCompany(companyID)
+		if err != nil {
+			return nil, err
+		}
+		if company, err = store.UpdateCompany(companyID, update); err != nil {
+			return nil, err
+		}
+		changes := DiffAuditFields(companyAuditFields(*before), companyAuditFields(*company))
+		if len(changes) == 0 {
+			return nil, nil
+		}
+		event, err := a.event("COMPANY_UPDATE",
+			fmt.Sprintf("Company %s [%s] updated by %s", company.Name, strings.Join(changedFieldNames(changes), ", "), a.actor.DisplayName()),
+			AuditMetadata{Changes: changes, CompanyID: &companyID})
+		if err != nil {
+			return nil, err
+		}
+		return []model.AuditLog{event}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return company, nil
+}
+
+// DeleteCompany deletes an unused company and records a COMPANY_DELETE entry with everything it removed.
+func (a *AuditedStore) DeleteCompany(companyID uint) error {
+	return a.write(func(tx *gorm.DB, store *SQLStore) ([]model.AuditLog, error) {
+		company, err := store.GetCompany(companyID)
+		if err != nil {
+			return nil, err
+		}
+		before := companyAuditFields(*company)
+		if before["domains"], err = companyDomainsAuditField(tx, companyID); err != nil {
+			return nil, err
+		}
+		if before["aliases"], err = companyAliasesAuditField(tx, companyID); err != nil {
+			return nil, err
+		}
+		if err := store.DeleteCompany(companyID); err != nil {
+			return nil, err
+		}
+		event, err := a.event("COMPANY_DELETE",
+			fmt.Sprintf("Company %s deleted by %s", company.Name, a.actor.DisplayName()),
+			AuditMetadata{Changes: DiffAuditFields(before, nil), CompanyID: &companyID})
+		if err != nil {
+			return nil, err
+		}
+		return []model.AuditLog{event}, nil
+	})
+}
+
+// CreateFoundation creates a foundation and records a FOUNDATION_CREATE entry.
+func (a *AuditedStore) CreateFoundation(name string) (*model.Foundation, error) {
+	var foundation *model.Foundation
+	err := a.write(func(tx *gorm.DB, store *SQLStore) ([]model.AuditLog, error) {
+		var err error
+		if foundation, err = store.CreateFoundation(name); err != nil {
+			return nil, err
+		}
+		event, err := a.event("FOUNDATION_CREATE",
+			fmt.Sprintf("Foundation created by %s", a.actor.DisplayName()),
+			AuditMetadata{Changes: map[string]AuditChange{"name": {To: foundation.Name}}, FoundationID: &foundation.ID})
+		if err != nil {
+			return nil, err
+		}
+		return []model.AuditLog{event}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return foundation, nil
+}
+
+// RenameFoundation renames a foundation and records a FOUNDATION_UPDATE entry.
+func (a *AuditedStore) RenameFoundation(foundationID uint, name string) (*model.Foundation, error) {
+	var foundation *model.Foundation
+	err := a.write(func(tx *gorm.DB, store *SQLStore) ([]model.AuditLog, error) {
+		before, err := store.GetFoundation(foundationID)
+		if err != nil {
+			return nil, err
+		}
+		if foundation, err = store.RenameFoundation(foundationID, name); err != nil {
+			return nil, err
+		}
+		if before.Name == foundation.Name {
+			return nil, nil
+		}
+		event, err := a.event("FOUNDATION_UPDATE",
+			fmt.Sprintf("Foundation %s renamed by %s", before.Name, a.actor.DisplayName()),
+			AuditMetadata{Changes: map[string]AuditChange{"name": {From: before.Name, To: foundation.Name}}, FoundationID: &foundationID})
+		if err != nil {
+			return nil, err
+		}
+		return []model.AuditLog{event}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return foundation, nil
+}
+
+// DeleteFoundation deletes a foundation without staff and records a FOUNDATION_DELETE entry.
+func (a *AuditedStore) DeleteFoundation(foundationID uint) error {
+	return a.write(func(tx *gorm.DB, store *SQLStore) ([]model.AuditLog, error) {
+		foundation, err := store.GetFoundation(foundationID)
+		if err != nil {
+			return nil, err
+		}
+		if err := store.DeleteFoundation(foundationID); err != nil {
+			return nil, err
+		}
+		event, err := a.event("FOUNDATION_DELETE",
+			fmt.Sprintf("Foundation %s deleted by %s", foundation.Name, a.actor.DisplayName()),
+			AuditMetadata{Changes: map[string]AuditChange{"name": {From: foundation.Name}}, FoundationID: &foundationID})
+		if err != nil {
+			return nil, err
+		}
+		return []model.AuditLog{event}, nil
+	})
+}
+
+// CreateStaffMember adds a staff member and records a STAFF_CREATE entry.
+func (a *AuditedStore) CreateStaffMember(input StaffMemberInput) (*model.StaffMember, error) {
+	var staff *model.StaffMember
+	err := a.write(func(tx *gorm.DB, store *SQLStore) ([]model.AuditLog, error) {
+		var err error
+		if staff, err = store.CreateStaffMember(input); err != nil {
+			return nil, err
+		}
+		event, err := a.event("STAFF_CREATE",
+			fmt.Sprintf("Staff member %s added by %s", staff.Name, a.actor.DisplayName()),
+			AuditMetadata{Changes: DiffAuditFields(nil, staffAuditFields(*staff)), StaffMemberID: &staff.ID})
+		if err != nil {
+			return nil, err
+		}
+		return []model.AuditLog{event}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return staff, nil
+}
+
+// UpdateStaffMember changes a staff member and records a STAFF_UPDATE entry.
+func (a *AuditedStore) UpdateStaffMember(id uint, update StaffMemberUpdate) (*model.StaffMember, error) {
+	return a.writeStaff(id, "STAFF_UPDATE", "updated", func(store *SQLStore) (*model.StaffMember, error) {
+		return store.UpdateStaffMember(id, update)
+	})
+}
+
+// DeactivateStaffMember revokes a staff member's access and records a STAFF_DEACTIVATE entry.
+func (a *AuditedStore) DeactivateStaffMember(id uint) (*model.StaffMember, error) {
+	return a.writeStaff(id, "STAFF_DEACTIVATE", "deactivated", func(store *SQLStore) (*model.StaffMember, error) {
+		return store.DeactivateStaffMember(id)
+	})
+}
+
+// ReactivateStaffMember restores a staff member's access and records a STAFF_REACTIVATE entry.
+func (a *AuditedStore) ReactivateStaffMember(id uint) (*model.StaffMember, error) {
+	return a.writeStaff(id, "STAFF_REACTIVATE", "reactivated", func(store *SQLStore) (*model.StaffMember, error) {
+		return store.ReactivateStaffMember(id)
+	})
+}
+
+// writeStaff applies a change to staff member id and records it as action, unless nothing changed.
+func (a *AuditedStore) writeStaff(id uint, action, verb string, apply func(store *SQLStore) (*model.StaffMember, error)) (*model.StaffMember, error) {
+	var staff *model.StaffMember
+	err := a.write(func(tx *gorm.DB, store *SQLStore) ([]model.AuditLog, error) {
+		before, err := store.GetStaffMember(id)
+		if err != nil {
+			return nil, err
+		}
+		if staff, err = apply(store); err != nil {
+			return nil, err
+		}
+		changes := DiffAuditFields(staffAuditFields(*before), staffAuditFields(*staff))
+		if len(changes) == 0 {
+			return nil, nil
+		}
+		event, err := a.event(action,
+			fmt.Sprintf("Staff member %s %s by %s", before.Name, verb, a.actor.DisplayName()),
+			AuditMetadata{Changes: changes, StaffMemberID: &id})
+		if err != nil {
+			return nil, err
+		}
+		return []model.AuditLog{event}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return staff, nil
+}
+
+// CreateCollaborator adds a collaborator and records a COLLABORATOR_CREATE entry.
+func (a *AuditedStore) CreateCollaborator(input CollaboratorInput) (*model.Collaborator, error) {
+	var collaborator *model.Collaborator
+	err := a.write(func(tx *gorm.DB, store *SQLStore) ([]model.AuditLog, error) {
+		var err error
+		if collaborator, err = store.CreateCollaborator(input); err != nil {
+			return nil, err
+		}
+		after, err := collaboratorAuditFields(tx, collaborator.ID)
+		if err != nil {
+			return nil, err
+		}
+		event, err := a.event("COLLABORATOR_CREATE",
+			fmt.Sprintf("Collaborator %s added by %s", collaborator.Name, a.actor.DisplayName()),
+			AuditMetadata{Changes: DiffAuditFields(nil, after), CollaboratorID: &collaborator.ID})
+		if err != nil {
+			return nil, err
+		}
+		return []model.AuditLog{event}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return collaborator, nil
+}
+
+// UpdateCollaborator changes a collaborator and records a COLLABORATOR_UPDATE entry.
+func (a *AuditedStore) UpdateCollaborator(id uint, update CollaboratorUpdate) (*model.Collaborator, error) {
+	var collaborator *model.Collaborator
+	err := a.write(func(tx *gorm.DB, store *SQLStore) ([]model.AuditLog, error) {
+		before, err := collaboratorAuditFields(tx, id)
+		if err != nil {
+			return nil, err
+		}
+		if collaborator, err = store.UpdateCollaborator(id, update); err != nil {
+			return nil, err
+		}
+		after, err := collaboratorAuditFields(tx, id)
+		if err != nil {
+			return nil, err
+		}
+		changes := DiffAuditFields(before, after)
+		if len(changes) == 0 {
+			return nil, nil
+		}
+		event, err := a.event("COLLABORATOR_UPDATE",
+			fmt.Sprintf("Collaborator %s [%s] updated by %s", collaborator.Name, strings.Join(changedFieldNames(changes), ", "), a.actor.DisplayName()),
+			AuditMetadata{Changes: changes, CollaboratorID: &id})
+		if err != nil {
+			return nil, err
+		}
+		return []model.AuditLog{event}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return collaborator, nil
+}
+
+// PromoteCollaborator makes a collaborator a maintainer and records a COLLABORATOR_PROMOTE entry against the
+// maintainer, with the maintainer's field-level diff.
+func (a *AuditedStore) PromoteCollaborator(id uint, projectIDs []uint) (*CollaboratorPromotion, error) {
+	var promotion *CollaboratorPromotion
+	err := a.write(func(tx *gorm.DB, store *SQLStore) ([]model.AuditLog, error) {
+		collaborator, err := store.GetCollaborator(id)
+		if err != nil {
+			return nil, err
+		}
+		existing, err := findUpsertMaintainer(tx, collaboratorEmail(*collaborator), collaboratorGitHub(*collaborator))
+		if err != nil {
+			return nil, err
+		}
+		var before map[string]string
+		if existing != nil {
+			if before, err = maintainerAuditFields(tx, existing.ID); err != nil {
+				return nil, err
+			}
+		}
+		if promotion, err = store.PromoteCollaborator(id, projectIDs); err != nil {
+			return nil, err
+		}
+		after, err := maintainerAuditFields(tx, promotion.MaintainerID)
+		if err != nil {
+			return nil, err
+		}
+		event, err := a.event("COLLABORATOR_PROMOTE",
+			fmt.Sprintf("Collaborator %s promoted to maintainer by %s", collaborator.Name, a.actor.DisplayName()),
+			AuditMetadata{Changes: DiffAuditFields(before, after), CollaboratorID: &id, Promotion: promotion})
+		if err != nil {
+			return nil, err
+		}
+		event.MaintainerID = &promotion.MaintainerID
+		return []model.AuditLog{event}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return promotion, nil
+}
+
+// CreateFoundationOfficer registers a foundation officer and records an OFFICER_CREATE entry.
+func (a *AuditedStore) CreateFoundationOfficer(input FoundationOfficerInput) (*model.FoundationOfficer, error) {
+	var officer *model.FoundationOfficer
+	err := a.write(func(tx *gorm.DB, store *SQLStore) ([]model.AuditLog, error) {
+		var err error
+		if officer, err = store.CreateFoundationOfficer(input); err != nil {
+			return nil, err
+		}
+		after, err := officerAuditFields(tx, officer.ID)
+		if err != nil {
+			return nil, err
+		}
+		event, err := a.event("OFFICER_CREATE",
+			fmt.Sprintf("Foundation officer %s registered by %s", officer.Name, a.actor.DisplayName()),
+			AuditMetadata{Changes: DiffAuditFields(nil, after), OfficerID: &officer.ID})
+		if err != nil {
+			return nil, err
+		}
+		return []model.AuditLog{event}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return officer, nil
+}
+
+// UpdateFoundationOfficer changes an officer and records an OFFICER_UPDATE entry.
+func (a *AuditedStore) UpdateFoundationOfficer(id uint, update FoundationOfficerUpdate) (*model.FoundationOfficer, error) {
+	var officer *model.FoundationOfficer
+	err := a.writeOfficer(id, "OFFICER_UPDATE", func(store *SQLStore) error {
+		var err error
+		officer, err = store.UpdateFoundationOfficer(id, update)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return officer, nil
+}
+
+// DeleteFoundationOfficer removes an officer and records an OFFICER_DELETE entry.
+func (a *AuditedStore) DeleteFoundationOfficer(id uint) error {
+	return a.writeOfficer(id, "OFFICER_DELETE", func(store *SQLStore) error {
+		return store.DeleteFoundationOfficer(id)
+	})
+}
+
+// LinkOfficerService links an officer to a service account and records an OFFICER_SERVICE_LINK entry.
+func (a *AuditedStore) LinkOfficerService(officerID uint, input OfficerServiceInput) (*model.ServiceUser, error) {
+	var user *model.ServiceUser
+	err := a.writeOfficer(officerID, "OFFICER_SERVICE_LINK", func(store *SQLStore) error {
+		var err error
+		user, err = store.LinkOfficerService(officerID, input)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// UnlinkOfficerService unlinks an officer from a service account and records an OFFICER_SERVICE_UNLINK entry.
+func (a *AuditedStore) UnlinkOfficerService(officerID, serviceUserID uint) error {
+	return a.writeOfficer(officerID, "OFFICER_SERVICE_UNLINK", func(store *SQLStore) error {
+		return store.UnlinkOfficerService(officerID, serviceUserID)
+	})
+}
+
+// writeOfficer applies a change to officer id and records it as action, unless nothing changed.
+func (a *AuditedStore) writeOfficer(id uint, action string, apply func(store *SQLStore) error) error {
+	return a.write(func(tx *gorm.DB, store *SQLStore) ([]model.AuditLog, error) {
+		officer, err := store.GetFoundationOfficer(id)
+		if err != nil {
+			return nil, err
+		}
+		before, err := officerAuditFields(tx, id)
+		if err != nil {
+			return nil, err
+		}
+		if err := apply(store); err != nil {
+			return nil, err
+		}
+		after, err := officerAuditFields(tx, id)
+		if err != nil {
+			return nil, err
+		}
+		changes := DiffAuditFields(before, after)
+		if len(changes) == 0 {
+			return nil, nil
+		}
+		message := fmt.Sprintf("Foundation officer %s [%s] updated by %s", officer.Name, strings.Join(changedFieldNames(changes), ", "), a.actor.DisplayName())
+		if action == "OFFICER_DELETE" {
+			message = fmt.Sprintf("Foundation officer %s removed by %s", officer.Name, a.actor.DisplayName())
+		}
+		event, err := a.event(action, message, AuditMetadata{Changes: changes, OfficerID: &id})
+		if err != nil {
+			return nil, err
+		}
+		return []model.AuditLog{event}, nil
+	})
+}
+
+// SetActingOfficer chooses the acting officer for a service and records a SERVICE_ACTING_OFFICER_UPDATE entry.
+func (a *AuditedStore) SetActingOfficer(serviceID, officerID uint) (*model.Service, error) {
+	var service *model.Service
+	err := a.write(func(tx *gorm.DB, store *SQLStore) ([]model.AuditLog, error) {
+		var before model.Service
+		if err := tx.First(&before, serviceID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrServiceNotFound
+			}
+			return nil, err
+		}
+		var err error
+		if service, err = store.SetActingOfficer(serviceID, officerID); err != nil {
+			return nil, err
+		}
+		changes := DiffAuditFields(
+			map[string]string{"actingOfficerId": formatAuditID(before.ActingOfficerID)},
+			map[string]string{"actingOfficerId": formatAuditID(service.ActingOfficerID)})
+		if len(changes) == 0 {
+			return nil, nil
+		}
+		event, err := a.event("SERVICE_ACTING_OFFICER_UPDATE",
+			fmt.Sprintf("Acting officer for %s changed by %s", service.Name, a.actor.DisplayName()),
+			AuditMetadata{Changes: changes, OfficerID: service.ActingOfficerID})
+		if err != nil {
+			return nil, err
+		}
+		event.ServiceID = &service.ID
+		return []model.AuditLog{event}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return service, nil
+}
+
+// LogServiceAction records an action the actor triggered on the named remote service, attributing it to both the
+// actor and the service's acting officer identity. event's ProjectID and MaintainerID identify what was acted on.
+func (a *AuditedStore) LogServiceAction(serviceName string, event model.AuditLog) error {
+	built, err := a.event(event.Action, event.Message, AuditMetadata{})
+	if err != nil {
+		return err
+	}
+	built.ProjectID = event.ProjectID
+	if event.MaintainerID != nil {
+		built.MaintainerID = event.MaintainerID
+	}
+	return a.SQLStore.LogServiceAction(serviceName, built)
+}
@@ -0,0 +1,216 @@
+package db
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"maintainerd/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func setupAuditedStore(t *testing.T) (*gorm.DB, *AuditedStore, model.StaffMember) {
+	t.Helper()
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&model.Foundation{}, &model.StaffMember{}, &model.AuditLog{}))
+	staff := model.StaffMember{Name: "Sam Staff", GitHubAccount: "sam"}
+	require.NoError(t, db.Create(&staff).Error)
+	store := NewSQLStore(db).WithActor(AuditActor{Login: "sam", Role: "staff", Name: staff.Name, StaffID: &staff.ID})
+	return db, store, staff
+}
+
+func auditEntries(t *testing.T, db *gorm.DB, action string) []model.AuditLog {
+	t.Helper()
+	var entries []model.AuditLog
+	require.NoError(t, db.Where("action = ?", action).Order("id").Find(&entries).Error)
+	return entries
+}
+
+func auditMetadata(t *testing.T, entry model.AuditLog) *AuditMetadata {
+	t.Helper()
+	metadata, err := ParseAuditMetadata(entry.Metadata)
+	require.NoError(t, err)
+	return metadata
+}
+
+func TestAuditedStoreProjectUpdates(t *testing.T) {
+	db, store, staff := setupAuditedStore(t)
+	_, project, _, _, _, _ := seedTestData(t, db)
+
+	require.NoError(t, store.UpdateProjectMaturity(project.ID, model.Incubating))
+	require.NoError(t, store.UpdateProjectMaturity(project.ID, model.Incubating))
+	assert.ErrorIs(t, store.UpdateProjectMaturity(9999, model.Sandbox), ErrProjectNotFound)
+
+	entries := auditEntries(t, db, "PROJECT_MATURITY_UPDATE")
+	require.Len(t, entries, 1, "no-op updates are not recorded")
+	entry := entries[0]
+	assert.Equal(t, project.ID, *entry.ProjectID)
+	assert.Equal(t, staff.ID, *entry.StaffID)
+	assert.Equal(t, "Project maturity updated by Sam Staff", entry.Message)
+	metadata := auditMetadata(t, entry)
+	assert.Equal(t, "sam", metadata.Actor.Login)
+	assert.Equal(t, map[string]AuditChange{"maturity": {From: "Graduated", To: "Incubating"}}, metadata.Changes)
+
+	require.NoError(t, store.UpdateProjectLegacyMaintainerRef(project.ID, "https://github.com/k8s/k8s/MAINTAINERS"))
+	entries = auditEntries(t, db, "PROJECT_MAINTAINER_REF_UPDATE")
+	require.Len(t, entries, 1)
+	assert.Equal(t, map[string]AuditChange{"maintainerRef": {To: "https://github.com/k8s/k8s/MAINTAINERS"}}, auditMetadata(t, entries[0]).Changes)
+
+	created := model.Project{Name: "envoy", Maturity: model.Sandbox, GitHubOrg: "envoyproxy"}
+	require.NoError(t, store.CreateProject(&created))
+	entries = auditEntries(t, db, "PROJECT_CREATE")
+	require.Len(t, entries, 1)
+	assert.Equal(t, created.ID, *entries[0].ProjectID)
+	assert.Equal(t, map[string]AuditChange{
+		"projectName": {To: "envoy"},
+		"maturity":    {To: "Sandbox"},
+		"githubOrg":   {To: "envoyproxy"},
+	}, auditMetadata(t, entries[0]).Changes)
+
+	report, err := store.VerifyAuditChain(context.Background())
+	require.NoError(t, err)
+	assert.True(t, report.OK())
+}
+
+func TestAuditedStoreMaintainerUpdates(t *testing.T) {
+	db, store, _ := setupAuditedStore(t)
+	company, project1, project2, alice, bob, charlie := seedTestData(t, db)
+	other := model.Company{Name: "Other Co"}
+	require.NoError(t, db.Create(&other).Error)
+
+	t.Run("details", func(t *testing.T) {
+		_, err := store.UpdateMaintainerDetails(alice.ID, "Alice D", "alice@new.example", "alice", model.ActiveMaintainer, &other.ID)
+		require.NoError(t, err)
+
+		entries := auditEntries(t, db, "MAINTAINER_UPDATE")
+		require.Len(t, entries, 1)
+		assert.Equal(t, alice.ID, *entries[0].MaintainerID)
+		assert.Equal(t, "Maintainer [company, companyId, email, name] updated by Sam Staff", entries[0].Message)
+		assert.Equal(t, map[string]AuditChange{
+			"name":      {From: "Alice Developer", To: "Alice D"},
+			"email":     {From: "alice@example.com", To: "alice@new.example"},
+			"company":   {From: company.Name, To: other.Name},
+			"companyId": {From: formatAuditID(&company.ID), To: formatAuditID(&other.ID)},
+		}, auditMetadata(t, entries[0]).Changes)
+	})
+
+	t.Run("bulk status shares a batch", func(t *testing.T) {
+		require.NoError(t, store.UpdateMaintainersStatus([]uint{bob.ID, charlie.ID}, model.EmeritusMaintainer))
+
+		entries := auditEntries(t, db, "MAINTAINER_STATUS_UPDATE")
+		require.Len(t, entries, 1, "charlie was already emeritus")
+		assert.Equal(t, bob.ID, *entries[0].MaintainerID)
+		metadata := auditMetadata(t, entries[0])
+		require.NotNil(t, metadata.Batch)
+		assert.Equal(t, 2, metadata.Batch.Size)
+		require.NotNil(t, entries[0].BatchID)
+		assert.Equal(t, metadata.Batch.ID, *entries[0].BatchID, "the batch is indexed by its own column")
+		assert.Equal(t, map[string]AuditChange{"status": {From: "Active", To: "Emeritus"}}, metadata.Changes)
+	})
+
+	t.Run("upsert creates and links", func(t *testing.T) {
+		created, err := store.UpsertMaintainer(project1.ID, "Dana", "dana@example.com", "dana", "")
+		require.NoError(t, err)
+		_, err = store.UpsertMaintainer(project2.ID, "", "", "DANA", "")
+		require.NoError(t, err)
+
+		entries := auditEntries(t, db, "MAINTAINER_CREATE")
+		require.Len(t, entries, 1)
+		assert.Equal(t, created.ID, *entries[0].MaintainerID)
+		assert.Equal(t, project1.ID, *entries[0].ProjectID)
+		changes := auditMetadata(t, entries[0]).Changes
+		assert.Equal(t, AuditChange{To: "dana"}, changes["github"])
+		assert.Equal(t, AuditChange{To: formatAuditID(&project1.ID)}, changes["projectIds"])
+
+		var updates []model.AuditLog
+		require.NoError(t, db.Where("action = ? AND maintainer_id = ?", "MAINTAINER_UPDATE", created.ID).Find(&updates).Error)
+		require.Len(t, updates, 1)
+		assert.Equal(t, AuditChange{
+			From: formatAuditID(&project1.ID),
+			To:   formatAuditID(&project1.ID) + "," + formatAuditID(&project2.ID),
+		}, auditMetadata(t, updates[0]).Changes["projectIds"])
+	})
+}
+
+func TestAuditedStoreCompanies(t *testing.T) {
+	db, store, _ := setupAuditedStore(t)
+	company, _, _, alice, bob, charlie := seedTestData(t, db)
+
+	target, err := store.CreateCompany("Acme")
+	require.NoError(t, err)
+	entries := auditEntries(t, db, "COMPANY_CREATE")
+	require.Len(t, entries, 1)
+	assert.Equal(t, AuditChange{To: "Acme"}, auditMetadata(t, entries[0]).Changes["name"])
+
+	require.NoError(t, store.MergeCompanies(company.ID, target.ID))
+	entries = auditEntries(t, db, "COMPANY_MERGE")
+	require.Len(t, entries, 1)
+	assert.Equal(t, "Company Test Company merged into Acme by Sam Staff", entries[0].Message)
+	metadata := auditMetadata(t, entries[0])
+	require.NotNil(t, metadata.Merge)
+	assert.Equal(t, company.ID, metadata.Merge.FromCompanyID)
+	assert.Equal(t, target.ID, metadata.Merge.ToCompanyID)
+	assert.Equal(t, []uint{alice.ID, bob.ID, charlie.ID}, metadata.Merge.MaintainerIDs)
+
+	_, err = store.CreateCompany("acme")
+	assert.ErrorIs(t, err, ErrCompanyExists)
+	assert.Len(t, auditEntries(t, db, "COMPANY_CREATE"), 1, "failed writes are not recorded")
+}
+
+// TestAuditedStoreOverridesStoreWrites fails when a Store write is added without an AuditedStore method of its own,
+// since the embedded SQLStore would otherwise handle it unaudited.
+func TestAuditedStoreOverridesStoreWrites(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	require.NoError(t, err)
+	overridden := map[string]bool{}
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), name, nil, parser.SkipObjectResolution)
+		require.NoError(t, err)
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil {
+				continue
+			}
+			if star, ok := fn.Recv.List[0].Type.(*ast.StarExpr); ok {
+				if ident, ok := star.X.(*ast.Ident); ok && ident.Name == "AuditedStore" {
+					overridden[fn.Name.Name] = true
+				}
+			}
+		}
+	}
+
+	store := reflect.TypeOf((*Store)(nil)).Elem()
+	var writes []string
+	for i := 0; i < store.NumMethod(); i++ {
+		name := store.Method(i).Name
+		// LogAuditEvent writes the audit log itself.
+		if strings.HasPrefix(name, "Get") || strings.HasPrefix(name, "List") || name == "LogAuditEvent" {
+			continue
+		}
+		writes = append(writes, name)
+		assert.True(t, overridden[name], "AuditedStore does not audit Store.%s", name)
+	}
+	assert.ElementsMatch(t, []string{
+		"CreateCompany",
+		"MarkMaintainerEmailVerified",
+		"MergeCompanies",
+		"RemoveMaintainersFromProject",
+		"UpdateMaintainerDetails",
+		"UpdateMaintainerStatus",
+		"UpdateMaintainersStatus",
+		"UpdateProjectLegacyMaintainerRef",
+		"UpdateProjectMaturity",
+		"UpsertMaintainer",
+	}, writes)
+}
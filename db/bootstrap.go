@@ -13,8 +13,6 @@ import (
 
 	"gorm.io/gorm/logger"
 
-	"google.golang.org/api/option"
-	"google.golang.org/api/sheets/v4"
 	"gorm.io/gorm"
 )
 
@@ -33,7 +31,10 @@ const (
 	MailingListAddrHdr   string = "Mailing List Address"
 )
 
-func BootstrapDB(driver, dsn, spreadsheetID, worksheetCredentialsPath, fossaToken string, seed bool) (*gorm.DB, error) {
+// BootstrapDB applies pending schema migrations and, when seed is set, loads maintainers, projects and staff from
+// source and syncs FOSSA teams. The FOSSA sync is skipped when fossaToken is empty, so a CSV or JSON source
+// bootstraps entirely offline.
+func BootstrapDB(driver, dsn string, source ImportSource, fossaToken string, seed bool) (*gorm.DB, error) {
 	newLogger := logger.New(
 		log.New(os.Stdout, "\r\n", log.LstdFlags), // io writer
 		logger.Config{
@@ -53,21 +54,12 @@ func BootstrapDB(driver, dsn, spreadsheetID, worksheetCredentialsPath, fossaToke
 		return nil, fmt.Errorf("failed to open DB: %w", err)
 	}
 
-	if err := db.AutoMigrate(
-		&model.Company{},
-		&model.Foundation{},
-		&model.Project{},
-		&model.Maintainer{},
-		&model.StaffMember{},
-		&model.FoundationOfficer{},
-		&model.Collaborator{},
-		&model.MaintainerProject{},
-		&model.Service{},
-		&model.ServiceTeam{},
-		&model.ServiceUser{},
-		&model.ServiceUserTeams{},
-	); err != nil {
-		return nil, fmt.Errorf("auto-migration failed: %w", err)
+	applied, err := MigrateUp(db, 0)
+	if err != nil {
+		return nil, fmt.Errorf("schema migration failed: %w", err)
+	}
+	for _, migration := range applied {
+		log.Printf("bootstrap: applied migration %d %s", migration.Version, migration.Name)
 	}
 
 	if !seed {
@@ -92,16 +84,21 @@ func BootstrapDB(driver, dsn, spreadsheetID, worksheetCredentialsPath, fossaToke
 		return nil, err
 	}
 
-	if err := loadMaintainersAndProjects(db, spreadsheetID, worksheetCredentialsPath); err != nil {
+	if source == nil {
+		return nil, errors.New("bootstrap: an import source is required to seed data")
+	}
+	if err := loadMaintainersAndProjects(db, source); err != nil {
 		return nil, fmt.Errorf("bootstrap: failed to load maintainers and projects: %w", err)
 	}
 
-	if err := loadStaff(db, spreadsheetID, worksheetCredentialsPath); err != nil {
+	if err := loadStaff(db, source); err != nil {
 		return nil, fmt.Errorf("bootstrap: failed to load staff: %w", err)
 	}
 
 	//fossaService := model.Service{Model: gorm.Model{ID: 1}, Name: "FOSSA"}
-	if err := loadFOSSA(db, fossaToken); err != nil {
+	if fossaToken == "" {
+		log.Println("bootstrap: no FOSSA token, skipping FOSSA sync")
+	} else if err := loadFOSSA(db, fossaToken); err != nil {
 		return nil, fmt.Errorf("bootstrap: failed to load FOSSA projects: %w", err)
 	}
 
@@ -111,36 +108,14 @@ func BootstrapDB(driver, dsn, spreadsheetID, worksheetCredentialsPath, fossaToke
 
 // BootstrapSQLite is kept for backwards compatibility.
 func BootstrapSQLite(dbPath, spreadsheetID, worksheetCredentialsPath, fossaToken string, seed bool) (*gorm.DB, error) {
-	return BootstrapDB("sqlite", dbPath, spreadsheetID, worksheetCredentialsPath, fossaToken, seed)
+	return BootstrapDB("sqlite", dbPath, NewSheetsSource(spreadsheetID, worksheetCredentialsPath), fossaToken, seed)
 }
 
-// Reads data from spreadsheetID inserts it into db.
-func loadMaintainersAndProjects(db *gorm.DB, spreadsheetID, credentialsPath string) error {
-	ctx := context.Background()
-
-	srv, err := sheets.NewService(
-		ctx,
-		option.WithCredentialsFile(credentialsPath),
-		option.WithScopes(sheets.SpreadsheetsReadonlyScope),
-	)
-
-	if err != nil {
-		return fmt.Errorf("maintainerd: backend: loadMaintainersAndProjects: unable to retrieve Sheets client: %w", err)
-	}
-
-	rows, err := readSheetRows(
-		ctx,
-		srv,
-		spreadsheetID,
-		"Active!A:J",
-		ProjectHdr,
-		StatusHdr,
-		MaintainerFileRefHdr,
-		MailingListAddrHdr,
-	)
-
+// Reads maintainer rows from source and inserts them into db.
+func loadMaintainersAndProjects(db *gorm.DB, source ImportSource) error {
+	rows, err := source.MaintainerRows(context.Background())
 	if err != nil {
-		return fmt.Errorf("maintainerd-backend: loadMaintainersAndProjects - readSheetRows: %w", err)
+		return fmt.Errorf("maintainerd-backend: loadMaintainersAndProjects - MaintainerRows: %w", err)
 	}
 
 	for _, row := range rows {
@@ -211,10 +186,13 @@ func loadMaintainersAndProjects(db *gorm.DB, spreadsheetID, credentialsPath stri
 			}
 
 			company := model.Company{Name: companyName}
-			if companyName != "" {
-				if err := tx.FirstOrCreate(&company, model.Company{Name: company.Name}).Error; err != nil {
-					return fmt.Errorf("ERR, loadMaintainersAndProjects - failed calling FirstOrCreate on company %v: error %v", company, err)
+			if strings.TrimSpace(companyName) != "" {
+				// Resolve aliases first so names of merged companies do not recreate them.
+				resolved, err := findOrCreateCompany(tx, companyName)
+				if err != nil {
+					return fmt.Errorf("ERR, loadMaintainersAndProjects - failed resolving company %q: error %v", companyName, err)
 				}
+				company = *resolved
 			}
 
 			maintainer := model.Maintainer{
@@ -228,12 +206,48 @@ func loadMaintainersAndProjects(db *gorm.DB, spreadsheetID, credentialsPath stri
 				maintainer.CompanyID = &company.ID
 			}
 
-			if err := tx.Where("email = ?", email).FirstOrCreate(&maintainer).Error; err != nil {
+			// A row naming a maintainer that was merged away attaches to the one it was merged into.
+			var live int64
+			if err := tx.Model(&model.Maintainer{}).Where("email = ?", email).Count(&live).Error; err != nil {
+				return fmt.Errorf("ERR, loadMaintainersAndProjects - failed looking up maintainer %q: error %v", email, err)
+			}
+			var merged *model.Maintainer
+			if live == 0 {
+				var err error
+				if merged, err = mergedMaintainer(tx, "email = ?", email); err != nil {
+					return fmt.Errorf("ERR, loadMaintainersAndProjects - failed resolving merged maintainer %q: error %v", email, err)
+				}
+			}
+			if merged != nil {
+				maintainer = *merged
+			} else if err := tx.Where("email = ?", email).FirstOrCreate(&maintainer).Error; err != nil {
 				return fmt.Errorf("ERR, loadMaintainersAndProjects - failed calling FirstOrCreate on maintainer %v: error %v", maintainer, err)
 			}
 
 			// Ensure the association (in case the maintainer existed already)
-			return tx.Model(&maintainer).Association("Projects").Append(&project)
+			var linked int64
+			if err := tx.Model(&model.MaintainerProject{}).
+				Where("maintainer_id = ? AND project_id = ?", maintainer.ID, project.ID).
+				Count(&linked).Error; err != nil {
+				return fmt.Errorf("ERR, loadMaintainersAndProjects - failed looking up membership %v: error %v", maintainer.ID, err)
+			}
+			if linked > 0 {
+				return nil
+			}
+			if err := tx.Model(&maintainer).Association("Projects").Append(&project); err != nil {
+				return err
+			}
+			// Recording the sheet as the source lets a later re-import retire the membership.
+			return tx.Create(&model.MembershipHistory{
+				MaintainerID: maintainer.ID,
+				ProjectID:    project.ID,
+				Event:        "JOIN",
+				ToStatus:     string(model.ActiveMaintainer),
+				ToRole:       string(model.MaintainerRole),
+				ActorLogin:   "bootstrap",
+				Source:       model.MembershipSourceSheet,
+				CreatedAt:    time.Now(),
+			}).Error
 		}); err != nil {
 			log.Printf("WARN, loadMaintainersAndProjects Database transaction not committed, row skipped %v : error %v ", row, err)
 		}
@@ -241,30 +255,11 @@ func loadMaintainersAndProjects(db *gorm.DB, spreadsheetID, credentialsPath stri
 	return nil
 }
 
-// Reads data from spreadsheetID inserts it into db.
-func loadStaff(db *gorm.DB, spreadsheetID, credentialsPath string) error {
-	ctx := context.Background()
-
-	srv, err := sheets.NewService(
-		ctx,
-		option.WithCredentialsFile(credentialsPath),
-		option.WithScopes(sheets.SpreadsheetsReadonlyScope),
-	)
-
+// Reads staff rows from source and inserts them into db.
+func loadStaff(db *gorm.DB, source ImportSource) error {
+	rows, err := source.StaffRows(context.Background())
 	if err != nil {
-		return fmt.Errorf("maintainerd: backend: loadStaff: unable to retrieve Sheets client: %w", err)
-	}
-
-	rows, err := readSheetRows(
-		ctx,
-		srv,
-		spreadsheetID,
-		"Staff!A:F",
-		FoundationHdr,
-	)
-
-	if err != nil {
-		return fmt.Errorf("maintainerd-backend: loadStaff - readSheetRows: %w", err)
+		return fmt.Errorf("maintainerd-backend: loadStaff - StaffRows: %w", err)
 	}
 
 	for _, row := range rows {
@@ -336,78 +331,6 @@ func loadStaff(db *gorm.DB, spreadsheetID, credentialsPath string) error {
 	return nil
 }
 
-// readSheetRows returns every row as a map keyed by the header row and carries forward the last non‐empty Project and
-// Status values when those cells are blank or missing.
-// The readRange must include the header row.
-// The
-func readSheetRows(ctx context.Context, srv *sheets.Service, spreadsheetID, readRange string, carryForwardHeaders ...string) ([]map[string]string, error) {
-	resp, err := srv.Spreadsheets.Values.
-		Get(spreadsheetID, readRange).
-		Context(ctx).
-		Do()
-	if err != nil {
-		return nil, fmt.Errorf("db: Using %s unable to retrieve worksheet data (%s): %w", spreadsheetID, readRange, err)
-	}
-	if len(resp.Values) == 0 {
-		return nil, fmt.Errorf("db: %s worksheet is empty", spreadsheetID)
-	}
-
-	// First row → headers
-	headers := make([]string, len(resp.Values[0]))
-	for i, cell := range resp.Values[0] {
-		headers[i] = strings.TrimSpace(fmt.Sprint(cell))
-	}
-
-	carryForwardIdx := make(map[int]struct{}, len(carryForwardHeaders))
-	for _, hdr := range carryForwardHeaders {
-		for i, h := range headers {
-			if h == hdr {
-				carryForwardIdx[i] = struct{}{}
-				break
-			}
-		}
-	}
-
-	var rows []map[string]string
-	lastVals := make(map[int]string, len(carryForwardIdx))
-
-	// Remaining rows → maps
-	for _, r := range resp.Values[1:] {
-		rowMap := make(map[string]string, len(headers))
-		hasAnyValue := false
-
-		for i, h := range headers {
-			// read raw cell if present
-			var cellVal string
-			if i < len(r) {
-				cellVal = strings.TrimSpace(fmt.Sprint(r[i]))
-			}
-			if cellVal != "" {
-				hasAnyValue = true
-			}
-
-			if _, ok := carryForwardIdx[i]; ok {
-				if cellVal != "" {
-					lastVals[i] = cellVal
-				}
-				rowMap[h] = lastVals[i]
-				continue
-			}
-
-			rowMap[h] = cellVal
-		}
-
-		// Skip fully empty rows.
-		if !hasAnyValue {
-			continue
-		}
-
-		rows = append(rows, rowMap)
-	}
-
-	return rows, nil
-}
-
 // loadFOSSA synchronizes all data in CNCF FOSSA
 func loadFOSSA(db *gorm.DB, token string) error {
 	users, teams, err := FetchFossaData(token)
@@ -512,7 +435,7 @@ func MapFossaUserCollaborator(db *gorm.DB, email string, github string, user fos
 		LastLogin:     user.LastVisit,
 		RegisteredAt:  user.CreatedAt,
 	}
-	// TODO Fill out the Projects field
+	// Projects are linked from the collaborator's service teams by LinkServiceUserToTeam.
 
 	if github != "" {
 		if err := db.
@@ -584,6 +507,13 @@ func LinkServiceUserToTeam(
 		if st == nil {
 			continue
 		}
+		if collaborator != nil {
+			project := model.CollaboratorProject{CollaboratorID: collaborator.ID, ProjectID: st.ProjectID}
+			if err := db.Where(&project).FirstOrCreate(&project).Error; err != nil {
+				linkErrors = append(linkErrors,
+					fmt.Sprintf("failed to link collaborator %d to project %d: %v", collaborator.ID, st.ProjectID, err))
+			}
+		}
 
 		serviceUserTeams := model.ServiceUserTeams{
 			ServiceID:     1, // TODO Remove Magic Number
@@ -0,0 +1,362 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"maintainerd/model"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCollaboratorNotFound = errors.New("collaborator not found")
+	ErrCollaboratorExists   = errors.New("a collaborator with that email or GitHub account already exists")
+	ErrAlreadyMaintainer    = errors.New("a maintainer with that email or GitHub account already exists")
+	ErrCollaboratorIdentity = errors.New("an email or GitHub account is required")
+	ErrNoProjects           = errors.New("at least one project is required")
+)
+
+// CollaboratorInput describes a collaborator to add.
+type CollaboratorInput struct {
+	Name          string
+	Email         string
+	GitHubAccount string
+	ProjectIDs    []uint
+}
+
+// CollaboratorUpdate holds the collaborator fields to change; nil fields are left as they are. ProjectIDs, when set,
+// replaces the collaborator's projects.
+type CollaboratorUpdate struct {
+	Name          *string
+	Email         *string
+	GitHubAccount *string
+	ProjectIDs    *[]uint
+}
+
+// CollaboratorPromotion records what PromoteCollaborator did.
+type CollaboratorPromotion struct {
+	CollaboratorID uint `json:"collaboratorId"`
+	MaintainerID   uint `json:"maintainerId"`
+	// Created is false when the collaborator matched an existing maintainer, who was added to the projects instead.
+	Created    bool   `json:"created"`
+	ProjectIDs []uint `json:"projectIds"`
+	// ServiceUserTeamIDs are the collaborator's service team links that now belong to the maintainer.
+	ServiceUserTeamIDs []uint `json:"serviceUserTeamIds,omitempty"`
+}
+
+// ListCollaborators returns collaborators with their projects, ordered by name. A non-zero projectID limits the
+// list to that project's collaborators.
+func (s *SQLStore) ListCollaborators(projectID uint) ([]model.Collaborator, error) {
+	query := s.db.Preload("Projects", func(db *gorm.DB) *gorm.DB { return db.Order("projects.name") }).Order("name, id")
+	if projectID != 0 {
+		query = query.Where("id IN (?)", s.db.Model(&model.CollaboratorProject{}).Select("collaborator_id").Where("project_id = ?", projectID))
+	}
+	var collaborators []model.Collaborator
+	if err := query.Find(&collaborators).Error; err != nil {
+		return nil, err
+	}
+	return collaborators, nil
+}
+
+// GetCollaborator returns the collaborator with id and their projects, or ErrCollaboratorNotFound.
+func (s *SQLStore) GetCollaborator(id uint) (*model.Collaborator, error) {
+	var collaborator model.Collaborator
+	err := s.db.Preload("Projects", func(db *gorm.DB) *gorm.DB { return db.Order("projects.name") }).First(&collaborator, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCollaboratorNotFound
+		}
+		return nil, err
+	}
+	return &collaborator, nil
+}
+
+// CreateCollaborator adds a collaborator to the given projects. People who are already maintainers cannot be added.
+func (s *SQLStore) CreateCollaborator(input CollaboratorInput) (*model.Collaborator, error) {
+	name := strings.TrimSpace(input.Name)
+	email := strings.TrimSpace(input.Email)
+	account := strings.TrimSpace(input.GitHubAccount)
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	if email == "" && account == "" {
+		return nil, ErrCollaboratorIdentity
+	}
+	collaborator := model.Collaborator{
+		Name:         name,
+		Email:        normalizeOrSentinel(email, "EMAIL_MISSING"),
+		RegisteredAt: time.Now().UTC(),
+	}
+	if account != "" {
+		collaborator.GitHubAccount = &account
+	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkCollaboratorUnique(tx, email, account, 0); err != nil {
+			return err
+		}
+		existing, err := findUpsertMaintainer(tx, email, account)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			return fmt.Errorf("%w: maintainer %d", ErrAlreadyMaintainer, existing.ID)
+		}
+		if err := tx.Create(&collaborator).Error; err != nil {
+			return err
+		}
+		return setCollaboratorProjects(tx, collaborator.ID, input.ProjectIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetCollaborator(collaborator.ID)
+}
+
+// UpdateCollaborator changes a collaborator's details or projects.
+func (s *SQLStore) UpdateCollaborator(id uint, update CollaboratorUpdate) (*model.Collaborator, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var collaborator model.Collaborator
+		if err := tx.First(&collaborator, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrCollaboratorNotFound
+			}
+			return err
+		}
+		updates := make(map[string]any)
+		if update.Name != nil {
+			name := strings.TrimSpace(*update.Name)
+			if name == "" {
+				return fmt.Errorf("name is required")
+			}
+			updates["name"] = name
+		}
+		email, account := collaboratorEmail(collaborator), collaboratorGitHub(collaborator)
+		if update.Email != nil {
+			email = strings.TrimSpace(*update.Email)
+			updates["email"] = normalizeOrSentinel(email, "EMAIL_MISSING")
+		}
+		if update.GitHubAccount != nil {
+			account = strings.TrimSpace(*update.GitHubAccount)
+			updates["git_hub_account"] = normalizeOrSentinel(account, "GITHUB_MISSING")
+		}
+		if email == "" && account == "" {
+			return ErrCollaboratorIdentity
+		}
+		if err := checkCollaboratorUnique(tx, email, account, id); err != nil {
+			return err
+		}
+		if len(updates) > 0 {
+			if err := tx.Model(&collaborator).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		if update.ProjectIDs != nil {
+			return setCollaboratorProjects(tx, id, *update.ProjectIDs)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetCollaborator(id)
+}
+
+// PromoteCollaborator makes a collaborator a maintainer of projectIDs, or of their own projects when projectIDs is
+// empty. A maintainer with the same GitHub account or email is reused rather than duplicated. The collaborator's
+// service team links move to the maintainer, and the collaborator is soft-deleted with PromotedToID set.
+func (s *SQLStore) PromoteCollaborator(id uint, projectIDs []uint) (*CollaboratorPromotion, error) {
+	promotion := &CollaboratorPromotion{CollaboratorID: id}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		store := &SQLStore{db: tx, actorLogin: s.actorLogin}
+		var collaborator model.Collaborator
+		if err := tx.First(&collaborator, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrCollaboratorNotFound
+			}
+			return err
+		}
+		if len(projectIDs) == 0 {
+			if err := tx.Model(&model.CollaboratorProject{}).Where("collaborator_id = ?", id).
+				Order("project_id").Pluck("project_id", &projectIDs).Error; err != nil {
+				return err
+			}
+		}
+		if len(projectIDs) == 0 {
+			return ErrNoProjects
+		}
+		email, account := collaboratorEmail(collaborator), collaboratorGitHub(collaborator)
+		existing, err := findUpsertMaintainer(tx, email, account)
+		if err != nil {
+			return err
+		}
+		promotion.Created = existing == nil
+		for _, projectID := range uniqueUints(projectIDs) {
+			maintainer, err := store.UpsertMaintainer(projectID, collaborator.Name, email, account, "")
+			if err != nil {
+				return fmt.Errorf("project %d: %w", projectID, err)
+			}
+			promotion.MaintainerID = maintainer.ID
+			promotion.ProjectIDs = append(promotion.ProjectIDs, projectID)
+		}
+
+		var links []model.ServiceUserTeams
+		if err := tx.Where("collaborator_id = ?", id).Order("id").Find(&links).Error; err != nil {
+			return err
+		}
+		for _, link := range links {
+			var duplicate int64
+			if err := tx.Model(&model.ServiceUserTeams{}).
+				Where("service_team_id = ? AND service_user_id = ? AND maintainer_id = ?", link.ServiceTeamID, link.ServiceUserID, promotion.MaintainerID).
+				Count(&duplicate).Error; err != nil {
+				return err
+			}
+			if duplicate > 0 {
+				if err := tx.Delete(&link).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			if err := tx.Model(&link).Updates(map[string]any{"maintainer_id": promotion.MaintainerID, "collaborator_id": nil}).Error; err != nil {
+				return err
+			}
+			promotion.ServiceUserTeamIDs = append(promotion.ServiceUserTeamIDs, link.ID)
+		}
+
+		if err := tx.Where("collaborator_id = ?", id).Delete(&model.CollaboratorProject{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&collaborator).Update("promoted_to_id", promotion.MaintainerID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&collaborator).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return promotion, nil
+}
+
+// BackfillCollaboratorProjects links collaborators to the projects of the service teams they belong to, for
+// collaborators added before collaborator projects existed. It returns the number of links added and is safe to run
+// repeatedly.
+func (s *SQLStore) BackfillCollaboratorProjects() (int, error) {
+	var links []model.CollaboratorProject
+	if err := s.db.Table("service_user_teams sut").
+		Select("DISTINCT sut.collaborator_id, st.project_id").
+		Joins("JOIN service_teams st ON st.id = sut.service_team_id AND st.deleted_at IS NULL").
+		Joins("JOIN collaborators c ON c.id = sut.collaborator_id AND c.deleted_at IS NULL").
+		Joins("JOIN projects p ON p.id = st.project_id AND p.deleted_at IS NULL").
+		Where("sut.deleted_at IS NULL").
+		Where("NOT EXISTS (SELECT 1 FROM collaborator_projects cp WHERE cp.collaborator_id = sut.collaborator_id AND cp.project_id = st.project_id)").
+		Scan(&links).Error; err != nil {
+		return 0, err
+	}
+	if len(links) == 0 {
+		return 0, nil
+	}
+	if err := s.db.Create(&links).Error; err != nil {
+		return 0, err
+	}
+	return len(links), nil
+}
+
+// setCollaboratorProjects replaces a collaborator's projects with projectIDs.
+func setCollaboratorProjects(tx *gorm.DB, collaboratorID uint, projectIDs []uint) error {
+	projectIDs = uniqueUints(projectIDs)
+	if len(projectIDs) > 0 {
+		var found int64
+		if err := tx.Model(&model.Project{}).Where("id IN ?", projectIDs).Count(&found).Error; err != nil {
+			return err
+		}
+		if int(found) != len(projectIDs) {
+			return ErrProjectNotFound
+		}
+	}
+	if err := tx.Where("collaborator_id = ?", collaboratorID).Delete(&model.CollaboratorProject{}).Error; err != nil {
+		return err
+	}
+	for _, projectID := range projectIDs {
+		if err := tx.Create(&model.CollaboratorProject{CollaboratorID: collaboratorID, ProjectID: projectID}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkCollaboratorUnique returns ErrCollaboratorExists if a collaborator other than exceptID has the email or
+// GitHub account. Empty values are not checked.
+func checkCollaboratorUnique(tx *gorm.DB, email, account string, exceptID uint) error {
+	for column, value := range map[string]string{"email": email, "git_hub_account": account} {
+		if value == "" {
+			continue
+		}
+		var count int64
+		if err := tx.Model(&model.Collaborator{}).
+			Where("LOWER("+column+") = ? AND id <> ?", strings.ToLower(value), exceptID).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrCollaboratorExists
+		}
+	}
+	return nil
+}
+
+func collaboratorEmail(collaborator model.Collaborator) string {
+	if collaborator.Email == "EMAIL_MISSING" {
+		return ""
+	}
+	return strings.TrimSpace(collaborator.Email)
+}
+
+func collaboratorGitHub(collaborator model.Collaborator) string {
+	if collaborator.GitHubAccount == nil || *collaborator.GitHubAccount == "GITHUB_MISSING" {
+		return ""
+	}
+	return strings.TrimSpace(*collaborator.GitHubAccount)
+}
+
+func uniqueUints(values []uint) []uint {
+	seen := make(map[uint]bool, len(values))
+	unique := make([]uint, 0, len(values))
+	for _, value := range values {
+		if value != 0 && !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
+
+// collaboratorAuditFields loads the audited view of a collaborator.
+func collaboratorAuditFields(tx *gorm.DB, collaboratorID uint) (map[string]string, error) {
+	var collaborator model.Collaborator
+	if err := tx.First(&collaborator, collaboratorID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCollaboratorNotFound
+		}
+		return nil, err
+	}
+	var projectIDs []uint
+	if err := tx.Model(&model.CollaboratorProject{}).Where("collaborator_id = ?", collaboratorID).
+		Pluck("project_id", &projectIDs).Error; err != nil {
+		return nil, err
+	}
+	sort.Slice(projectIDs, func(i, j int) bool { return projectIDs[i] < projectIDs[j] })
+	ids := make([]string, 0, len(projectIDs))
+	for _, id := range projectIDs {
+		ids = append(ids, strconv.FormatUint(uint64(id), 10))
+	}
+	return map[string]string{
+		"name":       collaborator.Name,
+		"email":      collaboratorEmail(collaborator),
+		"github":     collaboratorGitHub(collaborator),
+		"projectIds": strings.Join(ids, ","),
+	}, nil
+}
@@ -0,0 +1,149 @@
+package db
+
+import (
+	"testing"
+
+	"maintainerd/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollaboratorCRUD(t *testing.T) {
+	db, store, _ := setupAuditedStore(t)
+	_, project1, project2, _, _, _ := seedTestData(t, db)
+
+	carol, err := store.CreateCollaborator(CollaboratorInput{Name: "Carol", GitHubAccount: "carol", ProjectIDs: []uint{project2.ID, project1.ID, project2.ID}})
+	require.NoError(t, err)
+	require.Len(t, carol.Projects, 2)
+	assert.Equal(t, "kubernetes", carol.Projects[0].Name)
+	assert.Equal(t, "EMAIL_MISSING", carol.Email)
+
+	_, err = store.CreateCollaborator(CollaboratorInput{Name: "Carol Again", GitHubAccount: "CAROL"})
+	assert.ErrorIs(t, err, ErrCollaboratorExists)
+	_, err = store.CreateCollaborator(CollaboratorInput{Name: "Alice", Email: "Alice@example.com"})
+	assert.ErrorIs(t, err, ErrAlreadyMaintainer)
+	_, err = store.CreateCollaborator(CollaboratorInput{Name: "Nobody"})
+	assert.ErrorIs(t, err, ErrCollaboratorIdentity)
+	_, err = store.CreateCollaborator(CollaboratorInput{Name: "Dave", Email: "dave@example.org", ProjectIDs: []uint{999}})
+	assert.ErrorIs(t, err, ErrProjectNotFound)
+
+	onlyProject2 := []uint{project2.ID}
+	_, err = store.UpdateCollaborator(carol.ID, CollaboratorUpdate{Email: stringPtr("carol@example.org"), ProjectIDs: &onlyProject2})
+	require.NoError(t, err)
+	listed, err := store.ListCollaborators(project1.ID)
+	require.NoError(t, err)
+	assert.Empty(t, listed)
+	listed, err = store.ListCollaborators(project2.ID)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+
+	updates := auditEntries(t, db, "COLLABORATOR_UPDATE")
+	require.Len(t, updates, 1)
+	changes := auditMetadata(t, updates[0]).Changes
+	assert.Equal(t, AuditChange{To: "carol@example.org"}, changes["email"])
+	_, err = store.RevertAuditEntry(updates[0].ID)
+	require.NoError(t, err)
+	reverted, err := store.GetCollaborator(carol.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "EMAIL_MISSING", reverted.Email)
+	assert.Len(t, reverted.Projects, 2)
+
+	_, err = store.RevertAuditEntry(auditEntries(t, db, "COLLABORATOR_CREATE")[0].ID)
+	require.NoError(t, err)
+	_, err = store.GetCollaborator(carol.ID)
+	assert.ErrorIs(t, err, ErrCollaboratorNotFound)
+}
+
+func TestPromoteCollaborator(t *testing.T) {
+	db, store, _ := setupAuditedStore(t)
+	_, project1, project2, _, bob, _ := seedTestData(t, db)
+	carol, err := store.CreateCollaborator(CollaboratorInput{Name: "Carol", Email: "carol@example.org", GitHubAccount: "carol", ProjectIDs: []uint{project2.ID}})
+	require.NoError(t, err)
+	team := model.ServiceTeam{ProjectID: project2.ID, ServiceID: 1, ServiceTeamID: 20}
+	require.NoError(t, db.Create(&team).Error)
+	link := model.ServiceUserTeams{ServiceID: 1, ServiceUserID: 5, ServiceTeamID: team.ID, CollaboratorID: &carol.ID}
+	require.NoError(t, db.Create(&link).Error)
+
+	promotion, err := store.PromoteCollaborator(carol.ID, nil)
+	require.NoError(t, err)
+	assert.True(t, promotion.Created)
+	assert.Equal(t, []uint{project2.ID}, promotion.ProjectIDs)
+	assert.Equal(t, []uint{link.ID}, promotion.ServiceUserTeamIDs)
+
+	var maintainer model.Maintainer
+	require.NoError(t, db.First(&maintainer, promotion.MaintainerID).Error)
+	assert.Equal(t, "carol", maintainer.GitHubAccount)
+	assert.Equal(t, []uint{project2.ID}, membershipProjectIDs(t, db, maintainer.ID))
+	require.NoError(t, db.First(&link, link.ID).Error)
+	require.NotNil(t, link.MaintainerID)
+	assert.Equal(t, maintainer.ID, *link.MaintainerID)
+	assert.Nil(t, link.CollaboratorID)
+	var history int64
+	require.NoError(t, db.Model(&model.MembershipHistory{}).Where("maintainer_id = ? AND event = ?", maintainer.ID, "JOIN").Count(&history).Error)
+	assert.Equal(t, int64(1), history)
+
+	var gone model.Collaborator
+	require.NoError(t, db.Unscoped().First(&gone, carol.ID).Error)
+	assert.True(t, gone.DeletedAt.Valid)
+	require.NotNil(t, gone.PromotedToID)
+	assert.Equal(t, maintainer.ID, *gone.PromotedToID)
+
+	entries := auditEntries(t, db, "COLLABORATOR_PROMOTE")
+	require.Len(t, entries, 1)
+	require.NotNil(t, entries[0].MaintainerID)
+	assert.Equal(t, maintainer.ID, *entries[0].MaintainerID)
+	assert.Equal(t, "Collaborator Carol promoted to maintainer by Sam Staff", entries[0].Message)
+	_, err = store.RevertAuditEntry(entries[0].ID)
+	assert.ErrorIs(t, err, ErrRevertUnsupported)
+
+	// A collaborator who is already a maintainer elsewhere is added to the projects, not duplicated.
+	dup := model.Collaborator{Name: "Bob", Email: "bob@example.com"}
+	require.NoError(t, db.Create(&dup).Error)
+	promotion, err = store.PromoteCollaborator(dup.ID, []uint{project1.ID, project2.ID})
+	require.NoError(t, err)
+	assert.False(t, promotion.Created)
+	assert.Equal(t, bob.ID, promotion.MaintainerID)
+
+	empty := model.Collaborator{Name: "Eve", Email: "eve@example.org"}
+	require.NoError(t, db.Create(&empty).Error)
+	_, err = store.PromoteCollaborator(empty.ID, nil)
+	assert.ErrorIs(t, err, ErrNoProjects)
+}
+
+func TestBackfillCollaboratorProjects(t *testing.T) {
+	db, store, _ := setupAuditedStore(t)
+	_, project1, project2, _, _, _ := seedTestData(t, db)
+	carol := model.Collaborator{Name: "Carol", Email: "carol@example.org"}
+	promoted := model.Collaborator{Name: "Dave", Email: "dave@example.org"}
+	require.NoError(t, db.Create(&carol).Error)
+	require.NoError(t, db.Create(&promoted).Error)
+	require.NoError(t, db.Create(&model.CollaboratorProject{CollaboratorID: carol.ID, ProjectID: project1.ID}).Error)
+	teams := []model.ServiceTeam{
+		{ProjectID: project1.ID, ServiceID: 1, ServiceTeamID: 10},
+		{ProjectID: project2.ID, ServiceID: 1, ServiceTeamID: 20},
+		{ProjectID: project2.ID, ServiceID: 2, ServiceTeamID: 21},
+	}
+	require.NoError(t, db.Create(&teams).Error)
+	for _, link := range []model.ServiceUserTeams{
+		{ServiceID: 1, ServiceUserID: 5, ServiceTeamID: teams[0].ID, CollaboratorID: &carol.ID},
+		{ServiceID: 1, ServiceUserID: 5, ServiceTeamID: teams[1].ID, CollaboratorID: &carol.ID},
+		{ServiceID: 2, ServiceUserID: 6, ServiceTeamID: teams[2].ID, CollaboratorID: &carol.ID},
+		{ServiceID: 1, ServiceUserID: 7, ServiceTeamID: teams[1].ID, CollaboratorID: &promoted.ID},
+	} {
+		require.NoError(t, db.Create(&link).Error)
+	}
+	require.NoError(t, db.Delete(&promoted).Error)
+
+	added, err := store.BackfillCollaboratorProjects()
+	require.NoError(t, err)
+	assert.Equal(t, 1, added, "existing links, duplicate teams and deleted collaborators are skipped")
+	backfilled, err := store.GetCollaborator(carol.ID)
+	require.NoError(t, err)
+	require.Len(t, backfilled.Projects, 2)
+	assert.Equal(t, project2.ID, backfilled.Projects[1].ID)
+
+	added, err = store.BackfillCollaboratorProjects()
+	require.NoError(t, err)
+	assert.Zero(t, added)
+}
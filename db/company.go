@@ -0,0 +1,191 @@
+package db
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"maintainerd/model"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCompanyNotFound = errors.New("company not found")
+	ErrCompanyInUse    = errors.New("company still has maintainers or officers")
+	ErrInvalidWebsite  = errors.New("website must be an http or https URL")
+	ErrInvalidTag      = errors.New("tag keys must not be blank")
+)
+
+// CompanyUpdate holds the company fields to change; nil fields are left as they are.
+type CompanyUpdate struct {
+	Name    *string
+	Website *string
+	Notes   *string
+	// Tags, when set, replaces the whole tag map.
+	Tags *map[string]string
+}
+
+// NormalizeCompanyWebsite trims website and checks that it is empty or an absolute http(s) URL.
+func NormalizeCompanyWebsite(website string) (string, error) {
+	website = strings.TrimSpace(website)
+	if website == "" {
+		return "", nil
+	}
+	parsed, err := url.Parse(website)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return "", fmt.Errorf("%w: %q", ErrInvalidWebsite, website)
+	}
+	return website, nil
+}
+
+func normalizeCompanyTags(tags map[string]string) (map[string]string, error) {
+	if len(tags) == 0 {
+		return nil, nil
+	}
+	normalized := make(map[string]string, len(tags))
+	for key, value := range tags {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return nil, ErrInvalidTag
+		}
+		normalized[key] = strings.TrimSpace(value)
+	}
+	return normalized, nil
+}
+
+// GetCompany returns the company with id, or ErrCompanyNotFound.
+func (s *SQLStore) GetCompany(id uint) (*model.Company, error) {
+	var company model.Company
+	if err := s.db.First(&company, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCompanyNotFound
+		}
+		return nil, err
+	}
+	return &company, nil
+}
+
+// UpdateCompany renames a company or edits its website, notes and tags. A new name must not match another company
+// or alias.
+func (s *SQLStore) UpdateCompany(id uint, update CompanyUpdate) (*model.Company, error) {
+	var company model.Company
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&company, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrCompanyNotFound
+			}
+			return err
+		}
+		updates := make(map[string]any)
+		if update.Name != nil {
+			name := strings.TrimSpace(*update.Name)
+			if name == "" {
+				return fmt.Errorf("company name is required")
+			}
+			if name != company.Name {
+				owner, err := resolveCompany(tx, name)
+				if err != nil {
+					return err
+				}
+				if owner != nil && owner.ID != id {
+					return ErrCompanyExists
+				}
+				updates["name"] = name
+			}
+		}
+		if update.Website != nil {
+			website, err := NormalizeCompanyWebsite(*update.Website)
+			if err != nil {
+				return err
+			}
+			updates["website"] = website
+		}
+		if update.Notes != nil {
+			updates["notes"] = strings.TrimSpace(*update.Notes)
+		}
+		if update.Tags != nil {
+			tags, err := normalizeCompanyTags(*update.Tags)
+			if err != nil {
+				return err
+			}
+			company.Tags = tags
+			if err := tx.Model(&company).Select("tags").Updates(&model.Company{Tags: tags}).Error; err != nil {
+				return err
+			}
+		}
+		if len(updates) > 0 {
+			if err := tx.Model(&company).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		return tx.First(&company, id).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &company, nil
+}
+
+// DeleteCompany removes a company with no maintainers or foundation officers, together with its domains and
+// aliases. Companies with people attached must be merged instead.
+func (s *SQLStore) DeleteCompany(id uint) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Select("id").First(&model.Company{}, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrCompanyNotFound
+			}
+			return err
+		}
+		var maintainers, officers int64
+		if err := tx.Model(&model.Maintainer{}).Where("company_id = ?", id).Count(&maintainers).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&model.FoundationOfficer{}).Where("company_id = ?", id).Count(&officers).Error; err != nil {
+			return err
+		}
+		if maintainers+officers > 0 {
+			return fmt.Errorf("%w: %d maintainers, %d officers", ErrCompanyInUse, maintainers, officers)
+		}
+		if err := tx.Where("company_id = ?", id).Delete(&model.CompanyDomain{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("company_id = ?", id).Delete(&model.CompanyAlias{}).Error; err != nil {
+			return err
+		}
+		// Deleted outright so the name can be reused; the audit log keeps what was removed.
+		return tx.Unscoped().Delete(&model.Company{}, id).Error
+	})
+}
+
+// companyAuditFields is the audited view of a company's own fields.
+func companyAuditFields(company model.Company) map[string]string {
+	return map[string]string{
+		"name":    company.Name,
+		"website": company.Website,
+		"notes":   company.Notes,
+		"tags":    formatAuditTags(company.Tags),
+	}
+}
+
+// formatAuditTags encodes tags as JSON with sorted keys, or "" when there are none.
+func formatAuditTags(tags map[string]string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	raw, _ := json.Marshal(tags)
+	return string(raw)
+}
+
+func parseAuditTags(value string) (map[string]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+	var tags map[string]string
+	if err := json.Unmarshal([]byte(value), &tags); err != nil {
+		return nil, fmt.Errorf("invalid tags %q: %w", value, err)
+	}
+	return tags, nil
+}
@@ -0,0 +1,231 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"maintainerd/model"
+
+	"gorm.io/gorm"
+)
+
+var ErrAliasClaimed = errors.New("name already belongs to another company")
+
+// NormalizeCompanyName lower-cases name and collapses its whitespace, for matching company names and aliases.
+func NormalizeCompanyName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// ResolveCompany returns the company called name, matching company names first and then aliases, ignoring case
+// and spacing. It returns nil if no company matches.
+func (s *SQLStore) ResolveCompany(name string) (*model.Company, error) {
+	return resolveCompany(s.db, name)
+}
+
+func resolveCompany(tx *gorm.DB, name string) (*model.Company, error) {
+	normalized := NormalizeCompanyName(name)
+	if normalized == "" {
+		return nil, nil
+	}
+	company, err := findCompanyByNormalizedName(tx, normalized)
+	if err != nil || company != nil {
+		return company, err
+	}
+	var alias model.CompanyAlias
+	err = tx.Where("normalized_name = ?", normalized).First(&alias).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	company = &model.Company{}
+	if err := tx.First(company, alias.CompanyID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return company, nil
+}
+
+// findCompanyByNormalizedName returns the oldest company whose name normalizes to normalized. Stored names are not
+// normalized, so the query only narrows the candidates to names containing each word in order, and the match is
+// made with NormalizeCompanyName.
+func findCompanyByNormalizedName(tx *gorm.DB, normalized string) (*model.Company, error) {
+	words := strings.Fields(normalized)
+	for i, word := range words {
+		words[i] = escapeLike(word)
+	}
+	var candidates []model.Company
+	if err := tx.Where("LOWER(name) LIKE ? ESCAPE '\\'", "%"+strings.Join(words, "%")+"%").
+		Order("id").
+		Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+	for i := range candidates {
+		if NormalizeCompanyName(candidates[i].Name) == normalized {
+			return &candidates[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// findOrCreateCompany resolves name to an existing company or alias, creating the company if there is none.
+func findOrCreateCompany(tx *gorm.DB, name string) (*model.Company, error) {
+	company, err := resolveCompany(tx, name)
+	if err != nil || company != nil {
+		return company, err
+	}
+	company = &model.Company{Name: strings.TrimSpace(name)}
+	if err := tx.Create(company).Error; err != nil {
+		return nil, err
+	}
+	return company, nil
+}
+
+// ListCompanyAliases returns a company's aliases ordered by name.
+func (s *SQLStore) ListCompanyAliases(companyID uint) ([]model.CompanyAlias, error) {
+	var aliases []model.CompanyAlias
+	err := s.db.Where("company_id = ?", companyID).Order("normalized_name").Find(&aliases).Error
+	return aliases, err
+}
+
+// SetCompanyAliases replaces a company's aliases with names. Blank names, duplicates and the company's own name are
+// dropped. It fails with ErrAliasClaimed if a name is another company's name or alias.
+func (s *SQLStore) SetCompanyAliases(companyID uint, names []string) ([]model.CompanyAlias, error) {
+	var aliases []model.CompanyAlias
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var company model.Company
+		if err := tx.First(&company, companyID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("company %d not found", companyID)
+			}
+			return err
+		}
+		wanted := make(map[string]string, len(names))
+		for _, name := range names {
+			normalized := NormalizeCompanyName(name)
+			if normalized == "" || normalized == NormalizeCompanyName(company.Name) {
+				continue
+			}
+			if _, ok := wanted[normalized]; !ok {
+				wanted[normalized] = strings.Join(strings.Fields(name), " ")
+			}
+		}
+
+		var existing []model.CompanyAlias
+		if err := tx.Where("company_id = ?", companyID).Find(&existing).Error; err != nil {
+			return err
+		}
+		for _, alias := range existing {
+			name, ok := wanted[alias.NormalizedName]
+			switch {
+			case !ok:
+				if err := tx.Delete(&alias).Error; err != nil {
+					return err
+				}
+			case name != alias.Name:
+				if err := tx.Model(&alias).Update("name", name).Error; err != nil {
+					return err
+				}
+			}
+			delete(wanted, alias.NormalizedName)
+		}
+
+		for normalized, name := range wanted {
+			owner, err := resolveCompany(tx, normalized)
+			if err != nil {
+				return err
+			}
+			if owner != nil {
+				return fmt.Errorf("%w: %q is %s", ErrAliasClaimed, name, owner.Name)
+			}
+			if err := tx.Create(&model.CompanyAlias{CompanyID: companyID, Name: name, NormalizedName: normalized}).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Where("company_id = ?", companyID).Order("normalized_name").Find(&aliases).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return aliases, nil
+}
+
+// mergeCompanyAliases moves the source company's aliases to the target and adds the source's name as an alias, so
+// that later imports using the old name resolve to the target.
+func mergeCompanyAliases(tx *gorm.DB, source, target model.Company) error {
+	if err := tx.Model(&model.CompanyAlias{}).Where("company_id = ?", source.ID).Update("company_id", target.ID).Error; err != nil {
+		return err
+	}
+	normalized := NormalizeCompanyName(source.Name)
+	if normalized == "" || normalized == NormalizeCompanyName(target.Name) {
+		return nil
+	}
+	var alias model.CompanyAlias
+	err := tx.Where("normalized_name = ?", normalized).First(&alias).Error
+	if err == nil {
+		return tx.Model(&alias).Update("company_id", target.ID).Error
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return tx.Create(&model.CompanyAlias{CompanyID: target.ID, Name: strings.TrimSpace(source.Name), NormalizedName: normalized}).Error
+}
+
+// companyAliasesAuditField formats a company's aliases for the audit log, sorted and separated by "; ".
+func companyAliasesAuditField(tx *gorm.DB, companyID uint) (string, error) {
+	var names []string
+	if err := tx.Model(&model.CompanyAlias{}).
+		Where("company_id = ?", companyID).
+		Order("normalized_name").
+		Pluck("name", &names).Error; err != nil {
+		return "", err
+	}
+	return strings.Join(names, "; "), nil
+}
+
+func splitAuditAliases(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, "; ")
+}
+
+// BackfillCompanyAliases adds the alias a merge would now record for each company merged before aliases existed,
+// using the COMPANY_MERGE audit entries. It returns the number of aliases added and is safe to run repeatedly.
+func (s *SQLStore) BackfillCompanyAliases() (int, error) {
+	var entries []model.AuditLog
+	if err := s.db.Where("action = ?", "COMPANY_MERGE").Order("id").Find(&entries).Error; err != nil {
+		return 0, err
+	}
+	added := 0
+	for _, entry := range entries {
+		metadata, err := ParseAuditMetadata(entry.Metadata)
+		if err != nil || metadata.Merge == nil {
+			continue
+		}
+		var source, target model.Company
+		if err := s.db.Unscoped().First(&source, metadata.Merge.FromCompanyID).Error; err != nil || !source.DeletedAt.Valid {
+			continue
+		}
+		if err := s.db.First(&target, metadata.Merge.ToCompanyID).Error; err != nil {
+			continue
+		}
+		normalized := NormalizeCompanyName(source.Name)
+		owner, err := resolveCompany(s.db, normalized)
+		if err != nil {
+			return added, err
+		}
+		if owner != nil || normalized == "" {
+			continue
+		}
+		if err := s.db.Create(&model.CompanyAlias{CompanyID: target.ID, Name: strings.TrimSpace(source.Name), NormalizedName: normalized}).Error; err != nil {
+			return added, err
+		}
+		added++
+	}
+	return added, nil
+}
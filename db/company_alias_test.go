@@ -0,0 +1,151 @@
+package db
+
+import (
+	"testing"
+
+	"maintainerd/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetCompanyAliases(t *testing.T) {
+	db := setupTestDB(t)
+	store := NewSQLStore(db)
+	company, _, _, _, _, _ := seedTestData(t, db)
+	other := model.Company{Name: "Red Hat"}
+	require.NoError(t, db.Create(&other).Error)
+
+	aliases, err := store.SetCompanyAliases(company.ID, []string{"TestCo", " testco ", "", "Test  Company Inc", "test company"})
+	require.NoError(t, err)
+	require.Len(t, aliases, 2, "duplicates, blanks and the company's own name are dropped")
+	assert.Equal(t, "Test Company Inc", aliases[0].Name)
+	assert.Equal(t, "TestCo", aliases[1].Name)
+
+	_, err = store.SetCompanyAliases(company.ID, []string{"TestCo", "red hat"})
+	assert.ErrorIs(t, err, ErrAliasClaimed)
+	_, err = store.SetCompanyAliases(other.ID, []string{"testco"})
+	assert.ErrorIs(t, err, ErrAliasClaimed)
+
+	resolved, err := store.ResolveCompany("TESTCO")
+	require.NoError(t, err)
+	require.NotNil(t, resolved)
+	assert.Equal(t, company.ID, resolved.ID)
+
+	_, err = store.CreateCompany("testco")
+	assert.ErrorIs(t, err, ErrCompanyExists)
+
+	aliases, err = store.SetCompanyAliases(company.ID, nil)
+	require.NoError(t, err)
+	assert.Empty(t, aliases)
+	resolved, err = store.ResolveCompany("TestCo")
+	require.NoError(t, err)
+	assert.Nil(t, resolved)
+}
+
+func TestResolveCompanyNormalizesStoredNames(t *testing.T) {
+	db := setupTestDB(t)
+	store := NewSQLStore(db)
+	spaced := model.Company{Name: "Red  Hat "}
+	require.NoError(t, db.Create(&spaced).Error)
+	percent := model.Company{Name: "100% Co"}
+	require.NoError(t, db.Create(&percent).Error)
+
+	resolved, err := store.ResolveCompany("red hat")
+	require.NoError(t, err)
+	require.NotNil(t, resolved, "a stored name with extra spaces matches")
+	assert.Equal(t, spaced.ID, resolved.ID)
+
+	resolved, err = store.ResolveCompany("Red Hatter")
+	require.NoError(t, err)
+	assert.Nil(t, resolved)
+
+	resolved, err = store.ResolveCompany("100%  co")
+	require.NoError(t, err)
+	require.NotNil(t, resolved)
+	assert.Equal(t, percent.ID, resolved.ID)
+
+	resolved, err = store.ResolveCompany("100_ Co")
+	require.NoError(t, err)
+	assert.Nil(t, resolved)
+
+	_, err = store.CreateCompany("RED HAT")
+	assert.ErrorIs(t, err, ErrCompanyExists)
+}
+
+func TestMergeCompaniesKeepsSourceNameAsAlias(t *testing.T) {
+	db, store, _ := setupAuditedStore(t)
+	company, project, _, _, _, _ := seedTestData(t, db)
+	target := model.Company{Name: "Acme"}
+	require.NoError(t, db.Create(&target).Error)
+	_, err := store.SetCompanyAliases(company.ID, []string{"Test Co"})
+	require.NoError(t, err)
+
+	require.NoError(t, store.MergeCompanies(company.ID, target.ID))
+	aliases, err := store.ListCompanyAliases(target.ID)
+	require.NoError(t, err)
+	require.Len(t, aliases, 2)
+	assert.Equal(t, "Test Co", aliases[0].Name)
+	assert.Equal(t, "Test Company", aliases[1].Name)
+
+	created, err := store.UpsertMaintainer(project.ID, "Dana", "dana@example.org", "dana", "test company")
+	require.NoError(t, err)
+	require.NotNil(t, created.CompanyID)
+	assert.Equal(t, target.ID, *created.CompanyID, "the merged name resolves to the surviving company")
+	var companies int64
+	require.NoError(t, db.Model(&model.Company{}).Count(&companies).Error)
+	assert.Equal(t, int64(1), companies)
+
+	require.NoError(t, db.Model(&model.Maintainer{}).Where("id = ?", created.ID).Update("company_id", nil).Error)
+	_, err = store.RevertAuditEntry(auditEntries(t, db, "COMPANY_MERGE")[0].ID)
+	require.NoError(t, err)
+	aliases, err = store.ListCompanyAliases(target.ID)
+	require.NoError(t, err)
+	assert.Empty(t, aliases)
+	aliases, err = store.ListCompanyAliases(company.ID)
+	require.NoError(t, err)
+	require.Len(t, aliases, 1)
+	assert.Equal(t, "Test Co", aliases[0].Name)
+}
+
+func TestAuditedSetCompanyAliasesRevert(t *testing.T) {
+	db, store, _ := setupAuditedStore(t)
+	company, _, _, _, _, _ := seedTestData(t, db)
+
+	_, err := store.SetCompanyAliases(company.ID, []string{"TestCo, Inc", "TC"})
+	require.NoError(t, err)
+	_, err = store.SetCompanyAliases(company.ID, []string{"TC"})
+	require.NoError(t, err)
+	entries := auditEntries(t, db, "COMPANY_ALIASES_UPDATE")
+	require.Len(t, entries, 2)
+	assert.Equal(t, AuditChange{From: "TC; TestCo, Inc", To: "TC"}, auditMetadata(t, entries[1]).Changes["aliases"])
+	assert.Equal(t, "Company Test Company aliases updated by Sam Staff", entries[1].Message)
+
+	_, err = store.RevertAuditEntry(entries[1].ID)
+	require.NoError(t, err)
+	aliases, err := store.ListCompanyAliases(company.ID)
+	require.NoError(t, err)
+	assert.Len(t, aliases, 2)
+}
+
+func TestBackfillCompanyAliases(t *testing.T) {
+	db, store, _ := setupAuditedStore(t)
+	company, _, _, _, _, _ := seedTestData(t, db)
+	target := model.Company{Name: "Acme"}
+	require.NoError(t, db.Create(&target).Error)
+	require.NoError(t, store.MergeCompanies(company.ID, target.ID))
+	// Simulate a merge made before aliases were recorded.
+	require.NoError(t, db.Where("company_id = ?", target.ID).Delete(&model.CompanyAlias{}).Error)
+
+	added, err := store.BackfillCompanyAliases()
+	require.NoError(t, err)
+	assert.Equal(t, 1, added)
+	resolved, err := store.ResolveCompany("Test Company")
+	require.NoError(t, err)
+	require.NotNil(t, resolved)
+	assert.Equal(t, target.ID, resolved.ID)
+
+	added, err = store.BackfillCompanyAliases()
+	require.NoError(t, err)
+	assert.Zero(t, added)
+}
@@ -0,0 +1,249 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"maintainerd/model"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidDomain  = errors.New("invalid email domain")
+	ErrFreemailDomain = errors.New("free email provider domains cannot belong to a company")
+	ErrDomainClaimed  = errors.New("domain already belongs to another company")
+)
+
+// freemailDomains are shared email providers; an address at one of them says nothing about the holder's employer.
+var freemailDomains = map[string]bool{
+	"163.com":                  true,
+	"126.com":                  true,
+	"aol.com":                  true,
+	"fastmail.com":             true,
+	"gmail.com":                true,
+	"gmx.com":                  true,
+	"gmx.de":                   true,
+	"gmx.net":                  true,
+	"googlemail.com":           true,
+	"hey.com":                  true,
+	"hotmail.com":              true,
+	"icloud.com":               true,
+	"live.com":                 true,
+	"mac.com":                  true,
+	"mail.com":                 true,
+	"me.com":                   true,
+	"msn.com":                  true,
+	"naver.com":                true,
+	"outlook.com":              true,
+	"pm.me":                    true,
+	"proton.me":                true,
+	"protonmail.com":           true,
+	"qq.com":                   true,
+	"users.noreply.github.com": true,
+	"web.de":                   true,
+	"yahoo.com":                true,
+	"yandex.ru":                true,
+	"zoho.com":                 true,
+}
+
+// IsFreemailDomain reports whether domain is a shared email provider.
+func IsFreemailDomain(domain string) bool {
+	return freemailDomains[strings.ToLower(strings.TrimSpace(domain))]
+}
+
+// EmailDomain returns the lower-cased domain of email, or "" if email is missing or malformed.
+func EmailDomain(email string) string {
+	email = strings.TrimSpace(email)
+	at := strings.LastIndex(email, "@")
+	if at <= 0 || at == len(email)-1 {
+		return ""
+	}
+	return strings.ToLower(email[at+1:])
+}
+
+// NormalizeCompanyDomain cleans up a domain entered by staff, accepting a bare domain, an "@domain" or a full
+// address.
+func NormalizeCompanyDomain(value string) (string, error) {
+	domain := strings.ToLower(strings.TrimSpace(value))
+	if at := strings.LastIndex(domain, "@"); at >= 0 {
+		domain = domain[at+1:]
+	}
+	domain = strings.TrimSuffix(domain, ".")
+	if domain == "" || len(domain) > 253 || !strings.Contains(domain, ".") {
+		return "", fmt.Errorf("%w: %q", ErrInvalidDomain, value)
+	}
+	for _, label := range strings.Split(domain, ".") {
+		if label == "" || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return "", fmt.Errorf("%w: %q", ErrInvalidDomain, value)
+		}
+		for _, r := range label {
+			if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+				return "", fmt.Errorf("%w: %q", ErrInvalidDomain, value)
+			}
+		}
+	}
+	return domain, nil
+}
+
+// ListCompanyDomains returns the domains owned by a company, sorted.
+func (s *SQLStore) ListCompanyDomains(companyID uint) ([]model.CompanyDomain, error) {
+	var domains []model.CompanyDomain
+	err := s.db.Where("company_id = ?", companyID).Order("domain").Find(&domains).Error
+	return domains, err
+}
+
+// AddCompanyDomain gives a company ownership of an email domain. Adding a domain the company already owns is a
+// no-op; a domain owned by another company fails with ErrDomainClaimed.
+func (s *SQLStore) AddCompanyDomain(companyID uint, value string) (*model.CompanyDomain, error) {
+	domain, err := NormalizeCompanyDomain(value)
+	if err != nil {
+		return nil, err
+	}
+	if IsFreemailDomain(domain) {
+		return nil, fmt.Errorf("%w: %s", ErrFreemailDomain, domain)
+	}
+	var result model.CompanyDomain
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Select("id").First(&model.Company{}, companyID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("company %d not found", companyID)
+			}
+			return err
+		}
+		err := tx.Where("domain = ?", domain).First(&result).Error
+		if err == nil {
+			if result.CompanyID != companyID {
+				return fmt.Errorf("%w: %s", ErrDomainClaimed, domain)
+			}
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		result = model.CompanyDomain{CompanyID: companyID, Domain: domain}
+		return tx.Create(&result).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// RemoveCompanyDomain removes a domain from a company. It returns false if the company did not own the domain.
+func (s *SQLStore) RemoveCompanyDomain(companyID uint, value string) (bool, error) {
+	domain, err := NormalizeCompanyDomain(value)
+	if err != nil {
+		return false, err
+	}
+	result := s.db.Where("company_id = ? AND domain = ?", companyID, domain).Delete(&model.CompanyDomain{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// InferCompanyFromEmail returns the company owning the domain of email, matching parent domains so that an address
+// at eng.example.com is attributed to the owner of example.com. It returns nil if no company owns the domain.
+func (s *SQLStore) InferCompanyFromEmail(email string) (*model.Company, error) {
+	return inferCompanyFromEmail(s.db, email)
+}
+
+func inferCompanyFromEmail(tx *gorm.DB, email string) (*model.Company, error) {
+	candidates := domainCandidates(EmailDomain(email))
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+	var domains []model.CompanyDomain
+	if err := tx.Where("domain IN ?", candidates).Find(&domains).Error; err != nil {
+		return nil, err
+	}
+	if len(domains) == 0 {
+		return nil, nil
+	}
+	// The most specific domain wins.
+	sort.Slice(domains, func(i, j int) bool { return len(domains[i].Domain) > len(domains[j].Domain) })
+	var company model.Company
+	if err := tx.First(&company, domains[0].CompanyID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &company, nil
+}
+
+// domainCandidates returns domain and each of its parents that could be owned by a company, most specific first.
+func domainCandidates(domain string) []string {
+	if domain == "" || IsFreemailDomain(domain) {
+		return nil
+	}
+	var candidates []string
+	for strings.Contains(domain, ".") {
+		candidates = append(candidates, domain)
+		domain = domain[strings.Index(domain, ".")+1:]
+	}
+	return candidates
+}
+
+// CompanyDomainConflict is a maintainer whose email domain belongs to a company other than the one recorded for
+// them.
+type CompanyDomainConflict struct {
+	MaintainerID      uint   `json:"maintainerId"`
+	MaintainerName    string `json:"maintainerName"`
+	Email             string `json:"email"`
+	RecordedCompanyID uint   `json:"recordedCompanyId"`
+	RecordedCompany   string `json:"recordedCompany"`
+	DomainCompanyID   uint   `json:"domainCompanyId"`
+	DomainCompany     string `json:"domainCompany"`
+}
+
+// FindCompanyDomainConflicts checks every maintainer with a recorded company against the company owning their email
+// domain. Maintainers without a company, or whose domain no company owns, are not conflicts.
+func (s *SQLStore) FindCompanyDomainConflicts() ([]CompanyDomainConflict, error) {
+	var domains []model.CompanyDomain
+	if err := s.db.Find(&domains).Error; err != nil {
+		return nil, err
+	}
+	if len(domains) == 0 {
+		return nil, nil
+	}
+	owners := make(map[string]uint, len(domains))
+	for _, domain := range domains {
+		owners[domain.Domain] = domain.CompanyID
+	}
+	var companies []model.Company
+	if err := s.db.Find(&companies).Error; err != nil {
+		return nil, err
+	}
+	names := make(map[uint]string, len(companies))
+	for _, company := range companies {
+		names[company.ID] = company.Name
+	}
+
+	var maintainers []model.Maintainer
+	if err := s.db.Where("company_id IS NOT NULL").Order("id").Find(&maintainers).Error; err != nil {
+		return nil, err
+	}
+	var conflicts []CompanyDomainConflict
+	for _, maintainer := range maintainers {
+		for _, candidate := range domainCandidates(EmailDomain(maintainer.Email)) {
+			owner, ok := owners[candidate]
+			if !ok {
+				continue
+			}
+			if owner != *maintainer.CompanyID {
+				conflicts = append(conflicts, CompanyDomainConflict{
+					MaintainerID:      maintainer.ID,
+					MaintainerName:    strings.TrimSpace(maintainer.Name),
+					Email:             maintainer.Email,
+					RecordedCompanyID: *maintainer.CompanyID,
+					RecordedCompany:   names[*maintainer.CompanyID],
+					DomainCompanyID:   owner,
+					DomainCompany:     names[owner],
+				})
+			}
+			break
+		}
+	}
+	return conflicts, nil
+}
@@ -0,0 +1,165 @@
+package db
+
+import (
+	"testing"
+
+	"maintainerd/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeCompanyDomain(t *testing.T) {
+	for input, want := range map[string]string{
+		"Example.COM":           "example.com",
+		"@example.com":          "example.com",
+		"jane@eng.acme.io":      "eng.acme.io",
+		" example.org. ":        "example.org",
+		"xn--bcher-kva.example": "xn--bcher-kva.example",
+	} {
+		got, err := NormalizeCompanyDomain(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+	for _, input := range []string{"", "localhost", "exa mple.com", "-bad.com", "a..b"} {
+		_, err := NormalizeCompanyDomain(input)
+		assert.ErrorIs(t, err, ErrInvalidDomain, input)
+	}
+}
+
+func TestCompanyDomainOwnership(t *testing.T) {
+	db := setupTestDB(t)
+	store := NewSQLStore(db)
+	company, _, _, _, _, _ := seedTestData(t, db)
+	other := model.Company{Name: "Other Co"}
+	require.NoError(t, db.Create(&other).Error)
+
+	_, err := store.AddCompanyDomain(company.ID, "@Example.com")
+	require.NoError(t, err)
+	_, err = store.AddCompanyDomain(company.ID, "example.com")
+	require.NoError(t, err, "re-adding is a no-op")
+	_, err = store.AddCompanyDomain(other.ID, "example.com")
+	assert.ErrorIs(t, err, ErrDomainClaimed)
+	_, err = store.AddCompanyDomain(other.ID, "gmail.com")
+	assert.ErrorIs(t, err, ErrFreemailDomain)
+
+	domains, err := store.ListCompanyDomains(company.ID)
+	require.NoError(t, err)
+	require.Len(t, domains, 1)
+	assert.Equal(t, "example.com", domains[0].Domain)
+
+	removed, err := store.RemoveCompanyDomain(other.ID, "example.com")
+	require.NoError(t, err)
+	assert.False(t, removed)
+	removed, err = store.RemoveCompanyDomain(company.ID, "example.com")
+	require.NoError(t, err)
+	assert.True(t, removed)
+}
+
+func TestInferCompanyFromEmail(t *testing.T) {
+	db := setupTestDB(t)
+	store := NewSQLStore(db)
+	company, _, _, _, _, _ := seedTestData(t, db)
+	subsidiary := model.Company{Name: "Example Labs"}
+	require.NoError(t, db.Create(&subsidiary).Error)
+	_, err := store.AddCompanyDomain(company.ID, "example.com")
+	require.NoError(t, err)
+	_, err = store.AddCompanyDomain(subsidiary.ID, "labs.example.com")
+	require.NoError(t, err)
+
+	inferred, err := store.InferCompanyFromEmail("dev@eng.example.com")
+	require.NoError(t, err)
+	require.NotNil(t, inferred)
+	assert.Equal(t, company.ID, inferred.ID)
+
+	inferred, err = store.InferCompanyFromEmail("dev@x.labs.example.com")
+	require.NoError(t, err)
+	require.NotNil(t, inferred)
+	assert.Equal(t, subsidiary.ID, inferred.ID, "the most specific domain wins")
+
+	for _, email := range []string{"dev@gmail.com", "dev@unknown.org", "EMAIL_MISSING", ""} {
+		inferred, err = store.InferCompanyFromEmail(email)
+		require.NoError(t, err)
+		assert.Nil(t, inferred, email)
+	}
+}
+
+func TestUpsertMaintainerInfersCompany(t *testing.T) {
+	db := setupTestDB(t)
+	store := NewSQLStore(db)
+	_, project, _, _, _, _ := seedTestData(t, db)
+	acme := model.Company{Name: "Acme"}
+	require.NoError(t, db.Create(&acme).Error)
+	_, err := store.AddCompanyDomain(acme.ID, "acme.io")
+	require.NoError(t, err)
+
+	created, err := store.UpsertMaintainer(project.ID, "Dana", "dana@acme.io", "dana", "")
+	require.NoError(t, err)
+	require.NotNil(t, created.CompanyID)
+	assert.Equal(t, acme.ID, *created.CompanyID)
+
+	named, err := store.UpsertMaintainer(project.ID, "Eve", "eve@acme.io", "eve", "Independent")
+	require.NoError(t, err)
+	require.NotNil(t, named.CompanyID)
+	assert.NotEqual(t, acme.ID, *named.CompanyID, "an explicit company wins over the domain")
+}
+
+func TestFindCompanyDomainConflicts(t *testing.T) {
+	db := setupTestDB(t)
+	store := NewSQLStore(db)
+	company, _, _, alice, _, _ := seedTestData(t, db)
+	other := model.Company{Name: "Example Inc"}
+	require.NoError(t, db.Create(&other).Error)
+
+	conflicts, err := store.FindCompanyDomainConflicts()
+	require.NoError(t, err)
+	assert.Empty(t, conflicts)
+
+	_, err = store.AddCompanyDomain(other.ID, "example.com")
+	require.NoError(t, err)
+	conflicts, err = store.FindCompanyDomainConflicts()
+	require.NoError(t, err)
+	require.Len(t, conflicts, 3, "every seeded maintainer has an example.com address")
+	assert.Equal(t, alice.ID, conflicts[0].MaintainerID)
+	assert.Equal(t, company.Name, conflicts[0].RecordedCompany)
+	assert.Equal(t, "Example Inc", conflicts[0].DomainCompany)
+}
+
+func TestAuditedCompanyDomainsRevert(t *testing.T) {
+	db, store, _ := setupAuditedStore(t)
+	company, _, _, _, _, _ := seedTestData(t, db)
+
+	_, err := store.AddCompanyDomain(company.ID, "example.com")
+	require.NoError(t, err)
+	_, err = store.AddCompanyDomain(company.ID, "example.com")
+	require.NoError(t, err)
+	entries := auditEntries(t, db, "COMPANY_DOMAINS_UPDATE")
+	require.Len(t, entries, 1, "no-op additions are not recorded")
+	assert.Equal(t, AuditChange{To: "example.com"}, auditMetadata(t, entries[0]).Changes["domains"])
+
+	_, err = store.RevertAuditEntry(entries[0].ID)
+	require.NoError(t, err)
+	domains, err := store.ListCompanyDomains(company.ID)
+	require.NoError(t, err)
+	assert.Empty(t, domains)
+}
+
+func TestMergeCompaniesMovesDomains(t *testing.T) {
+	db, store, _ := setupAuditedStore(t)
+	company, _, _, _, _, _ := seedTestData(t, db)
+	target := model.Company{Name: "Acme"}
+	require.NoError(t, db.Create(&target).Error)
+	_, err := store.AddCompanyDomain(company.ID, "example.com")
+	require.NoError(t, err)
+
+	require.NoError(t, store.MergeCompanies(company.ID, target.ID))
+	inferred, err := store.InferCompanyFromEmail("x@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, target.ID, inferred.ID)
+
+	_, err = store.RevertAuditEntry(auditEntries(t, db, "COMPANY_MERGE")[0].ID)
+	require.NoError(t, err)
+	inferred, err = store.InferCompanyFromEmail("x@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, company.ID, inferred.ID)
+}
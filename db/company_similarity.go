@@ -0,0 +1,290 @@
+package db
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"maintainerd/model"
+
+	"gorm.io/gorm"
+)
+
+// DefaultCompanySimilarityThreshold is the score at or above which two company names are suggested for merging.
+const DefaultCompanySimilarityThreshold = 0.7
+
+// trigramCandidateThreshold is the highest raw pg_trgm similarity used to pick candidate pairs on Postgres. It is
+// lower than the default suggestion threshold because raw names still carry legal suffixes and punctuation;
+// candidates are rescored in Go. Callers asking for a lower threshold get it as the cut-off instead.
+const trigramCandidateThreshold = 0.3
+
+// legalSuffixes are trailing words that do not distinguish one company from another.
+var legalSuffixes = map[string]struct{}{
+	"ab": {}, "ag": {}, "bv": {}, "co": {}, "company": {}, "corp": {}, "corporation": {}, "gmbh": {},
+	"inc": {}, "incorporated": {}, "kk": {}, "limited": {}, "llc": {}, "llp": {}, "lp": {}, "ltd": {},
+	"nv": {}, "oy": {}, "plc": {}, "pty": {}, "sa": {}, "sarl": {}, "spa": {}, "srl": {},
+}
+
+// CompanySummary identifies a company in a merge suggestion.
+type CompanySummary struct {
+	ID              uint   `json:"id"`
+	Name            string `json:"name"`
+	MaintainerCount int64  `json:"maintainerCount"`
+}
+
+// CompanyMergeSuggestion pairs two companies whose names look like the same organisation. Target is the company
+// with more maintainers, or the older one on a tie, so merging Source into it moves the fewest records.
+type CompanyMergeSuggestion struct {
+	Source CompanySummary `json:"source"`
+	Target CompanySummary `json:"target"`
+	Score  float64        `json:"score"`
+}
+
+// CanonicalCompanyName reduces a company name to the words that identify it: lower-cased, with punctuation removed
+// and trailing legal suffixes such as Inc, LLC, GmbH and Ltd stripped. A name made only of a suffix is kept as is.
+func CanonicalCompanyName(name string) string {
+	name = strings.ToLower(strings.NewReplacer(".", "", "'", "", "’", "").Replace(name))
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for len(words) > 1 {
+		if _, ok := legalSuffixes[words[len(words)-1]]; !ok {
+			break
+		}
+		words = words[:len(words)-1]
+	}
+	return strings.Join(words, " ")
+}
+
+// CompanyNameSimilarity scores how alike two company names are, from 0 to 1. Names with the same canonical form,
+// ignoring spaces, score 1; otherwise the score is the higher of their trigram similarity and edit-distance ratio.
+func CompanyNameSimilarity(a, b string) float64 {
+	return canonicalSimilarity(CanonicalCompanyName(a), CanonicalCompanyName(b))
+}
+
+func canonicalSimilarity(a, b string) float64 {
+	if a == "" || b == "" {
+		return 0
+	}
+	compactA, compactB := strings.ReplaceAll(a, " ", ""), strings.ReplaceAll(b, " ", "")
+	if compactA == compactB {
+		return 1
+	}
+	score := trigramSimilarity(trigrams(a), trigrams(b))
+	if ratio := editRatio(compactA, compactB); ratio > score {
+		score = ratio
+	}
+	return score
+}
+
+// trigrams returns the set of three-character sequences in s the way pg_trgm builds them: each word is padded with
+// two spaces in front and one behind.
+func trigrams(s string) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, word := range strings.Fields(s) {
+		runes := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			set[string(runes[i:i+3])] = struct{}{}
+		}
+	}
+	return set
+}
+
+func trigramSimilarity(a, b map[string]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for gram := range a {
+		if _, ok := b[gram]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// editRatio is one minus the Levenshtein distance between a and b divided by the longer length.
+func editRatio(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	if longest == 0 {
+		return 1
+	}
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return 1 - float64(prev[len(rb)])/float64(longest)
+}
+
+type companyPair struct {
+	a, b uint
+}
+
+func orderedPair(a, b uint) companyPair {
+	if a > b {
+		a, b = b, a
+	}
+	return companyPair{a: a, b: b}
+}
+
+// FindCompanyMergeSuggestions returns pairs of companies whose names score at least threshold, best matches first.
+// On Postgres candidate pairs come from pg_trgm; elsewhere every pair is scored in Go. Both are rescored with
+// CompanyNameSimilarity so results do not depend on the database.
+func (s *SQLStore) FindCompanyMergeSuggestions(threshold float64) ([]CompanyMergeSuggestion, error) {
+	if threshold <= 0 || threshold > 1 {
+		threshold = DefaultCompanySimilarityThreshold
+	}
+	var companies []model.Company
+	if err := s.db.Where("TRIM(name) <> ''").Order("id").Find(&companies).Error; err != nil {
+		return nil, err
+	}
+	counts, err := companyMaintainerCounts(s.db)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint]model.Company, len(companies))
+	canonical := make(map[uint]string, len(companies))
+	for _, company := range companies {
+		byID[company.ID] = company
+		canonical[company.ID] = CanonicalCompanyName(company.Name)
+	}
+
+	var candidates []companyPair
+	if s.db.Name() == "postgres" {
+		candidates, err = trigramCandidatePairs(s.db, trigramCandidateCutoff(threshold))
+		if err != nil {
+			return nil, err
+		}
+		// Names equal once suffixes are stripped can still fall below the raw trigram cut-off.
+		buckets := make(map[string][]uint)
+		for _, company := range companies {
+			key := strings.ReplaceAll(canonical[company.ID], " ", "")
+			buckets[key] = append(buckets[key], company.ID)
+		}
+		for _, ids := range buckets {
+			for i := range ids {
+				for j := i + 1; j < len(ids); j++ {
+					candidates = append(candidates, orderedPair(ids[i], ids[j]))
+				}
+			}
+		}
+	} else {
+		for i := range companies {
+			for j := i + 1; j < len(companies); j++ {
+				candidates = append(candidates, orderedPair(companies[i].ID, companies[j].ID))
+			}
+		}
+	}
+
+	seen := make(map[companyPair]struct{}, len(candidates))
+	suggestions := []CompanyMergeSuggestion{}
+	for _, pair := range candidates {
+		if _, ok := seen[pair]; ok {
+			continue
+		}
+		seen[pair] = struct{}{}
+		a, okA := byID[pair.a]
+		b, okB := byID[pair.b]
+		if !okA || !okB {
+			continue
+		}
+		score := canonicalSimilarity(canonical[a.ID], canonical[b.ID])
+		if score < threshold {
+			continue
+		}
+		source := CompanySummary{ID: b.ID, Name: b.Name, MaintainerCount: counts[b.ID]}
+		target := CompanySummary{ID: a.ID, Name: a.Name, MaintainerCount: counts[a.ID]}
+		if source.MaintainerCount > target.MaintainerCount {
+			source, target = target, source
+		}
+		suggestions = append(suggestions, CompanyMergeSuggestion{Source: source, Target: target, Score: score})
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		si, sj := suggestions[i], suggestions[j]
+		if si.Score != sj.Score {
+			return si.Score > sj.Score
+		}
+		ci := si.Source.MaintainerCount + si.Target.MaintainerCount
+		cj := sj.Source.MaintainerCount + sj.Target.MaintainerCount
+		if ci != cj {
+			return ci > cj
+		}
+		pi, pj := orderedPair(si.Source.ID, si.Target.ID), orderedPair(sj.Source.ID, sj.Target.ID)
+		if pi.a != pj.a {
+			return pi.a < pj.a
+		}
+		return pi.b < pj.b
+	})
+	return suggestions, nil
+}
+
+// trigramCandidateCutoff returns the pg_trgm similarity threshold for finding candidates scoring at least threshold,
+// so a threshold below trigramCandidateThreshold is not pre-filtered at the higher value.
+func trigramCandidateCutoff(threshold float64) float64 {
+	return min(threshold, trigramCandidateThreshold)
+}
+
+// trigramCandidatePairs uses the pg_trgm % operator, backed by idx_companies_name_trgm, to find company pairs whose
+// raw names have a trigram similarity of at least cutoff.
+func trigramCandidatePairs(db *gorm.DB, cutoff float64) ([]companyPair, error) {
+	var rows []struct {
+		AID uint `gorm:"column:a_id"`
+		BID uint `gorm:"column:b_id"`
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SET LOCAL pg_trgm.similarity_threshold = " + strconv.FormatFloat(cutoff, 'f', -1, 64)).Error; err != nil {
+			return err
+		}
+		return tx.Raw(`
+			SELECT a.id AS a_id, b.id AS b_id
+			FROM companies a
+			JOIN companies b ON a.id < b.id AND lower(a.name) % lower(b.name)
+			WHERE a.deleted_at IS NULL AND b.deleted_at IS NULL`).Scan(&rows).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	pairs := make([]companyPair, 0, len(rows))
+	for _, row := range rows {
+		pairs = append(pairs, orderedPair(row.AID, row.BID))
+	}
+	return pairs, nil
+}
+
+func companyMaintainerCounts(db *gorm.DB) (map[uint]int64, error) {
+	var rows []struct {
+		CompanyID uint
+		Count     int64
+	}
+	if err := db.Model(&model.Maintainer{}).
+		Select("company_id, COUNT(*) AS count").
+		Where("company_id IS NOT NULL").
+		Group("company_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	counts := make(map[uint]int64, len(rows))
+	for _, row := range rows {
+		counts[row.CompanyID] = row.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,69 @@
+package db
+
+import (
+	"testing"
+
+	"maintainerd/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalCompanyName(t *testing.T) {
+	for input, want := range map[string]string{
+		"Red Hat, Inc.":        "red hat",
+		"RedHat Inc":           "redhat",
+		"Acme GmbH":            "acme",
+		"Foo Bar Co., Ltd.":    "foo bar",
+		"S.A.P. SE":            "sap se",
+		"Inc":                  "inc",
+		"  O'Reilly   Media  ": "oreilly media",
+	} {
+		assert.Equal(t, want, CanonicalCompanyName(input), input)
+	}
+}
+
+func TestCompanyNameSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, CompanyNameSimilarity("Red Hat", "RedHat Inc."))
+	assert.Equal(t, 1.0, CompanyNameSimilarity("Red Hat", "Red Hat, Inc"))
+	assert.GreaterOrEqual(t, CompanyNameSimilarity("Microsoft", "Microsft Corporation"), DefaultCompanySimilarityThreshold)
+	assert.Less(t, CompanyNameSimilarity("Google", "Amazon"), DefaultCompanySimilarityThreshold)
+	assert.Less(t, CompanyNameSimilarity("IBM", "HPE"), DefaultCompanySimilarityThreshold)
+	assert.Zero(t, CompanyNameSimilarity("", "Acme"))
+}
+
+func TestTrigramCandidateCutoff(t *testing.T) {
+	assert.Equal(t, trigramCandidateThreshold, trigramCandidateCutoff(DefaultCompanySimilarityThreshold))
+	assert.Equal(t, 0.2, trigramCandidateCutoff(0.2), "a lower threshold is not pre-filtered at the default cut-off")
+}
+
+func TestFindCompanyMergeSuggestions(t *testing.T) {
+	db := setupTestDB(t)
+	store := NewSQLStore(db)
+	seedTestData(t, db)
+	names := []string{"Red Hat", "RedHat Inc.", "Red Hat, Inc", "Amazon"}
+	companies := make([]model.Company, len(names))
+	for i, name := range names {
+		companies[i] = model.Company{Name: name}
+		require.NoError(t, db.Create(&companies[i]).Error)
+	}
+	require.NoError(t, db.Create(&model.Maintainer{Name: "Dana", Email: "dana@redhat.com", MaintainerStatus: model.ActiveMaintainer, CompanyID: &companies[2].ID}).Error)
+
+	suggestions, err := store.FindCompanyMergeSuggestions(0)
+	require.NoError(t, err)
+	require.Len(t, suggestions, 3, "every pair of Red Hat spellings, and nothing else")
+	for _, suggestion := range suggestions {
+		assert.Equal(t, 1.0, suggestion.Score)
+		assert.NotEqual(t, companies[3].ID, suggestion.Source.ID)
+		assert.NotEqual(t, companies[3].ID, suggestion.Target.ID)
+	}
+	assert.Equal(t, companies[2].ID, suggestions[0].Target.ID, "pairs with more maintainers rank first")
+	assert.Equal(t, int64(1), suggestions[0].Target.MaintainerCount)
+	assert.Equal(t, companies[0].ID, suggestions[2].Target.ID, "the older company is the target on a tie")
+	assert.Equal(t, companies[1].ID, suggestions[2].Source.ID)
+
+	require.NoError(t, db.Delete(&companies[1]).Error)
+	suggestions, err = store.FindCompanyMergeSuggestions(0)
+	require.NoError(t, err)
+	assert.Len(t, suggestions, 1, "merged companies are ignored")
+}
@@ -0,0 +1,193 @@
+package db
+
+import (
+	"testing"
+
+	"maintainerd/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func stringPtr(s string) *string { return &s }
+
+func TestUpdateCompany(t *testing.T) {
+	db := setupTestDB(t)
+	store := NewSQLStore(db)
+	company, _, _, _, _, _ := seedTestData(t, db)
+	other := model.Company{Name: "Other Corp"}
+	require.NoError(t, db.Create(&other).Error)
+	_, err := store.SetCompanyAliases(other.ID, []string{"Other Inc"})
+	require.NoError(t, err)
+
+	tags := map[string]string{" tier ": " gold ", "region": "eu"}
+	updated, err := store.UpdateCompany(company.ID, CompanyUpdate{
+		Website: stringPtr(" https://example.com "),
+		Notes:   stringPtr("Founding member"),
+		Tags:    &tags,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com", updated.Website)
+
+	var reloaded model.Company
+	require.NoError(t, db.First(&reloaded, company.ID).Error)
+	assert.Equal(t, "Founding member", reloaded.Notes)
+	assert.Equal(t, map[string]string{"tier": "gold", "region": "eu"}, reloaded.Tags)
+
+	_, err = store.UpdateCompany(company.ID, CompanyUpdate{Name: stringPtr("other inc")})
+	assert.ErrorIs(t, err, ErrCompanyExists, "a new name must not match another company's alias")
+	_, err = store.UpdateCompany(company.ID, CompanyUpdate{Website: stringPtr("example.com")})
+	assert.ErrorIs(t, err, ErrInvalidWebsite)
+	_, err = store.UpdateCompany(company.ID, CompanyUpdate{Tags: &map[string]string{" ": "x"}})
+	assert.ErrorIs(t, err, ErrInvalidTag)
+	_, err = store.UpdateCompany(9999, CompanyUpdate{Notes: stringPtr("x")})
+	assert.ErrorIs(t, err, ErrCompanyNotFound)
+}
+
+func TestDeleteCompany(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&model.FoundationOfficer{}))
+	store := NewSQLStore(db)
+	company, _, _, _, _, _ := seedTestData(t, db)
+
+	err := store.DeleteCompany(company.ID)
+	assert.ErrorIs(t, err, ErrCompanyInUse)
+
+	empty := model.Company{Name: "Empty Corp"}
+	require.NoError(t, db.Create(&empty).Error)
+	require.NoError(t, db.Create(&model.FoundationOfficer{Name: "Olga", CompanyID: &empty.ID}).Error)
+	assert.ErrorIs(t, store.DeleteCompany(empty.ID), ErrCompanyInUse, "officers also keep a company in use")
+	require.NoError(t, db.Unscoped().Where("company_id = ?", empty.ID).Delete(&model.FoundationOfficer{}).Error)
+
+	require.NoError(t, db.Create(&model.CompanyDomain{CompanyID: empty.ID, Domain: "empty.example"}).Error)
+	require.NoError(t, store.DeleteCompany(empty.ID))
+	var count int64
+	require.NoError(t, db.Unscoped().Model(&model.Company{}).Where("id = ?", empty.ID).Count(&count).Error)
+	assert.Zero(t, count)
+	require.NoError(t, db.Model(&model.CompanyDomain{}).Where("company_id = ?", empty.ID).Count(&count).Error)
+	assert.Zero(t, count)
+
+	_, err = store.CreateCompany("Empty Corp")
+	assert.NoError(t, err, "the name of a deleted company can be reused")
+	assert.ErrorIs(t, store.DeleteCompany(empty.ID), ErrCompanyNotFound)
+}
+
+func TestAuditedUpdateCompanyRevert(t *testing.T) {
+	db, store, _ := setupAuditedStore(t)
+	company, _, _, _, _, _ := seedTestData(t, db)
+
+	tags := map[string]string{"tier": "gold"}
+	_, err := store.UpdateCompany(company.ID, CompanyUpdate{
+		Name:    stringPtr("Test Company Ltd"),
+		Website: stringPtr("https://example.com"),
+		Tags:    &tags,
+	})
+	require.NoError(t, err)
+	_, err = store.UpdateCompany(company.ID, CompanyUpdate{Website: stringPtr("https://example.com")})
+	require.NoError(t, err)
+
+	entries := auditEntries(t, db, "COMPANY_UPDATE")
+	require.Len(t, entries, 1, "an update that changes nothing is not audited")
+	changes := auditMetadata(t, entries[0]).Changes
+	assert.Equal(t, AuditChange{From: "Test Company", To: "Test Company Ltd"}, changes["name"])
+	assert.Equal(t, AuditChange{To: `{"tier":"gold"}`}, changes["tags"])
+	assert.NotContains(t, changes, "notes")
+
+	_, err = store.RevertAuditEntry(entries[0].ID)
+	require.NoError(t, err)
+	var reverted model.Company
+	require.NoError(t, db.First(&reverted, company.ID).Error)
+	assert.Equal(t, "Test Company", reverted.Name)
+	assert.Empty(t, reverted.Website)
+	assert.Empty(t, reverted.Tags)
+}
+
+func TestAuditedDeleteCompanyRevert(t *testing.T) {
+	db, store, _ := setupAuditedStore(t)
+	require.NoError(t, db.AutoMigrate(&model.FoundationOfficer{}))
+	company, err := store.CreateCompany("Acme")
+	require.NoError(t, err)
+	_, err = store.UpdateCompany(company.ID, CompanyUpdate{Notes: stringPtr("Gone soon")})
+	require.NoError(t, err)
+	require.NoError(t, db.Create(&model.CompanyDomain{CompanyID: company.ID, Domain: "acme.example"}).Error)
+	_, err = store.SetCompanyAliases(company.ID, []string{"Acme Inc"})
+	require.NoError(t, err)
+
+	require.NoError(t, store.DeleteCompany(company.ID))
+	entries := auditEntries(t, db, "COMPANY_DELETE")
+	require.Len(t, entries, 1)
+	assert.Equal(t, "Company Acme deleted by Sam Staff", entries[0].Message)
+
+	_, err = store.RevertAuditEntry(entries[0].ID)
+	require.NoError(t, err)
+	var restored model.Company
+	require.NoError(t, db.First(&restored, company.ID).Error)
+	assert.Equal(t, "Acme", restored.Name)
+	assert.Equal(t, "Gone soon", restored.Notes)
+	var domains []string
+	require.NoError(t, db.Model(&model.CompanyDomain{}).Where("company_id = ?", company.ID).Pluck("domain", &domains).Error)
+	assert.Equal(t, []string{"acme.example"}, domains)
+	var aliases []string
+	require.NoError(t, db.Model(&model.CompanyAlias{}).Where("company_id = ?", company.ID).Pluck("name", &aliases).Error)
+	assert.Equal(t, []string{"Acme Inc"}, aliases)
+}
+
+func TestAuditedDeleteCompanyRevertConflict(t *testing.T) {
+	db, store, _ := setupAuditedStore(t)
+	require.NoError(t, db.AutoMigrate(&model.FoundationOfficer{}))
+	company, err := store.CreateCompany("Acme")
+	require.NoError(t, err)
+	require.NoError(t, store.DeleteCompany(company.ID))
+	_, err = store.CreateCompany("acme")
+	require.NoError(t, err)
+
+	_, err = store.RevertAuditEntry(auditEntries(t, db, "COMPANY_DELETE")[0].ID)
+	assert.ErrorIs(t, err, ErrRevertConflict)
+}
+
+func TestAuditedFoundationLifecycle(t *testing.T) {
+	db, store, staff := setupAuditedStore(t)
+
+	foundation, err := store.CreateFoundation(" CNCF ")
+	require.NoError(t, err)
+	assert.Equal(t, "CNCF", foundation.Name)
+	_, err = store.CreateFoundation("cncf")
+	assert.ErrorIs(t, err, ErrFoundationExists)
+
+	_, err = store.RenameFoundation(foundation.ID, "Cloud Native Computing Foundation")
+	require.NoError(t, err)
+	require.NoError(t, db.Model(&staff).Update("foundation_id", foundation.ID).Error)
+	summaries, err := store.ListFoundations()
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, int64(1), summaries[0].StaffCount)
+	assert.ErrorIs(t, store.DeleteFoundation(foundation.ID), ErrFoundationInUse)
+
+	require.NoError(t, db.Model(&staff).Update("foundation_id", nil).Error)
+	require.NoError(t, store.DeleteFoundation(foundation.ID))
+	_, err = store.GetFoundation(foundation.ID)
+	assert.ErrorIs(t, err, ErrFoundationNotFound)
+
+	deleted := auditEntries(t, db, "FOUNDATION_DELETE")
+	require.Len(t, deleted, 1)
+	_, err = store.RevertAuditEntry(deleted[0].ID)
+	require.NoError(t, err)
+	restored, err := store.GetFoundation(foundation.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Cloud Native Computing Foundation", restored.Name)
+
+	renamed := auditEntries(t, db, "FOUNDATION_UPDATE")
+	require.Len(t, renamed, 1)
+	_, err = store.RevertAuditEntry(renamed[0].ID)
+	require.NoError(t, err)
+	restored, err = store.GetFoundation(foundation.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "CNCF", restored.Name)
+
+	created := auditEntries(t, db, "FOUNDATION_CREATE")
+	require.Len(t, created, 1)
+	_, err = store.RevertAuditEntry(created[0].ID)
+	require.NoError(t, err)
+	_, err = store.GetFoundation(foundation.ID)
+	assert.ErrorIs(t, err, ErrFoundationNotFound)
+}
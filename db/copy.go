@@ -0,0 +1,304 @@
+package db
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+const defaultCopyBatchSize = 500
+
+var (
+	// ErrDestinationNotEmpty is returned when a copy that is not resuming finds rows in the destination.
+	ErrDestinationNotEmpty = errors.New("destination database is not empty")
+	// ErrCopyMismatch is returned when a table's row count or checksum differs between the databases after a copy or
+	// comparison.
+	ErrCopyMismatch = errors.New("databases differ")
+)
+
+// CopyOptions controls CopyDatabase.
+type CopyOptions struct {
+	// BatchSize is the number of rows read and written per transaction (default 500).
+	BatchSize int
+	// Resume continues a copy that stopped part-way instead of requiring an empty destination. Rows are copied in
+	// primary key order a batch per transaction, so an interrupted copy leaves each table with a prefix of its rows.
+	Resume bool
+	// CompareOnly skips migrating and copying and only compares the databases.
+	CompareOnly bool
+	// Progress, when set, is called after each batch.
+	Progress func(table string, copied, total int64)
+}
+
+// TableCopy reports on one table compared, and copied unless CompareOnly was set, by CopyDatabase.
+type TableCopy struct {
+	Table               string `json:"table"`
+	SourceRows          int64  `json:"sourceRows"`
+	DestinationRows     int64  `json:"destinationRows"`
+	Copied              int64  `json:"copied"`
+	SourceChecksum      string `json:"sourceChecksum"`
+	DestinationChecksum string `json:"destinationChecksum"`
+	Match               bool   `json:"match"`
+}
+
+// CopyDatabase copies every model and many2many join table from src to dst, which may use different drivers,
+// keeping IDs and so the relationships between rows. The destination schema is brought up to date with MigrateUp
+// first, tables are copied parents before children, Postgres ID sequences are moved past the copied IDs, and every
+// table's row count and checksum are compared afterwards. It returns ErrCopyMismatch, along with the report, when any table differs.
+func CopyDatabase(src, dst *gorm.DB, opts CopyOptions) ([]TableCopy, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultCopyBatchSize
+	}
+	tables, err := copyTables(dst)
+	if err != nil {
+		return nil, err
+	}
+	if !opts.CompareOnly {
+		if _, err := MigrateUp(dst, 0); err != nil {
+			return nil, fmt.Errorf("migrate destination: %w", err)
+		}
+		if !opts.Resume {
+			for _, table := range tables {
+				count, err := countRows(dst, table)
+				if err != nil {
+					return nil, err
+				}
+				if count > 0 {
+					return nil, fmt.Errorf("%w: %s has %d rows", ErrDestinationNotEmpty, table.schema.Table, count)
+				}
+			}
+		}
+	}
+
+	report := make([]TableCopy, 0, len(tables))
+	mismatch := false
+	for _, table := range tables {
+		result := TableCopy{Table: table.schema.Table}
+		if result.SourceRows, err = countRows(src, table); err != nil {
+			return report, err
+		}
+		if !opts.CompareOnly {
+			if result.Copied, err = copyTableRows(src, dst, table, result.SourceRows, opts); err != nil {
+				return report, fmt.Errorf("copy %s: %w", table.schema.Table, err)
+			}
+		}
+		if result.DestinationRows, err = countRows(dst, table); err != nil {
+			return report, err
+		}
+		if result.SourceChecksum, err = tableChecksum(src, table, opts.BatchSize); err != nil {
+			return report, fmt.Errorf("checksum %s: %w", table.schema.Table, err)
+		}
+		if result.DestinationChecksum, err = tableChecksum(dst, table, opts.BatchSize); err != nil {
+			return report, fmt.Errorf("checksum %s: %w", table.schema.Table, err)
+		}
+		result.Match = result.SourceRows == result.DestinationRows && result.SourceChecksum == result.DestinationChecksum
+		mismatch = mismatch || !result.Match
+		report = append(report, result)
+	}
+
+	if !opts.CompareOnly {
+		if err := resetSequences(dst, tables); err != nil {
+			return report, err
+		}
+	}
+	if mismatch {
+		return report, ErrCopyMismatch
+	}
+	return report, nil
+}
+
+// copyTable is one table, a model's or a join table GORM manages for a many2many relationship, by its parsed schema.
+type copyTable struct {
+	schema *schema.Schema
+}
+
+// copyTables returns the model tables parents first, followed by the many2many join tables that have no model of
+// their own, since their rows reference both sides.
+func copyTables(db *gorm.DB) ([]copyTable, error) {
+	models := schemaModels()
+	tables := make([]copyTable, 0, len(models))
+	seen := make(map[string]bool, len(models))
+	for _, model := range models {
+		statement := &gorm.Statement{DB: db}
+		if err := statement.Parse(model); err != nil {
+			return nil, err
+		}
+		tables = append(tables, copyTable{schema: statement.Schema})
+		seen[statement.Schema.Table] = true
+	}
+	for _, table := range tables[:len(models)] {
+		for _, relationship := range table.schema.Relationships.Many2Many {
+			join := relationship.JoinTable
+			if seen[join.Table] {
+				continue
+			}
+			seen[join.Table] = true
+			tables = append(tables, copyTable{schema: join})
+		}
+	}
+	return tables, nil
+}
+
+// query selects the table's rows, soft-deleted ones included, in primary key order.
+func (t copyTable) query(db *gorm.DB) *gorm.DB {
+	return db.Unscoped().Table(t.schema.Table).Order(strings.Join(t.schema.PrimaryFieldDBNames, ", "))
+}
+
+// newBatch returns a pointer to an empty slice of the table's model.
+func (t copyTable) newBatch() reflect.Value {
+	return reflect.New(reflect.SliceOf(t.schema.ModelType))
+}
+
+// columnValues returns a row's value for every column, serialized the way GORM would store it.
+func (t copyTable) columnValues(row reflect.Value) map[string]any {
+	ctx := context.Background()
+	values := make(map[string]any, len(t.schema.DBNames))
+	for _, name := range t.schema.DBNames {
+		values[name], _ = t.schema.FieldsByDBName[name].ValueOf(ctx, row)
+	}
+	return values
+}
+
+// countRows counts the table's rows, treating a missing table, as in a snapshot taken before the table existed, as
+// empty.
+func countRows(db *gorm.DB, table copyTable) (int64, error) {
+	if !db.Migrator().HasTable(table.schema.Table) {
+		return 0, nil
+	}
+	var count int64
+	err := db.Unscoped().Table(table.schema.Table).Count(&count).Error
+	return count, err
+}
+
+// copyTableRows copies the source rows past those already in the destination, a batch per transaction. Rows are
+// inserted as column maps rather than models, so GORM leaves zero values, NULLs and timestamps exactly as read
+// instead of applying column defaults and autoCreateTime.
+func copyTableRows(src, dst *gorm.DB, table copyTable, total int64, opts CopyOptions) (int64, error) {
+	done, err := countRows(dst, table)
+	if err != nil {
+		return 0, err
+	}
+	var copied int64
+	for offset := done; offset < total; offset += int64(opts.BatchSize) {
+		batch := table.newBatch()
+		if err := table.query(src).Limit(opts.BatchSize).Offset(int(offset)).Find(batch.Interface()).Error; err != nil {
+			return copied, err
+		}
+		rows := batch.Elem().Len()
+		if rows == 0 {
+			break
+		}
+		values := make([]map[string]any, rows)
+		for i := range values {
+			values[i] = table.columnValues(batch.Elem().Index(i))
+		}
+		err := dst.Transaction(func(tx *gorm.DB) error {
+			return tx.Table(table.schema.Table).Create(&values).Error
+		})
+		if err != nil {
+			return copied, err
+		}
+		copied += int64(rows)
+		if opts.Progress != nil {
+			opts.Progress(table.schema.Table, done+copied, total)
+		}
+	}
+	return copied, nil
+}
+
+// resetSequences moves each Postgres ID sequence past the largest copied ID, since rows were inserted with explicit
+// IDs. SQLite tracks AUTOINCREMENT values itself.
+func resetSequences(db *gorm.DB, tables []copyTable) error {
+	if db.Dialector.Name() != "postgres" {
+		return nil
+	}
+	for _, table := range tables {
+		field := table.schema.PrioritizedPrimaryField
+		if field == nil || !field.AutoIncrement {
+			continue
+		}
+		statement := fmt.Sprintf("SELECT setval(pg_get_serial_sequence(?, ?), COALESCE((SELECT MAX(%s) FROM %s), 0) + 1, false)",
+			db.Statement.Quote(field.DBName), db.Statement.Quote(table.schema.Table))
+		if err := db.Exec(statement, table.schema.Table, field.DBName).Error; err != nil {
+			return fmt.Errorf("reset %s sequence: %w", table.schema.Table, err)
+		}
+	}
+	return nil
+}
+
+// tableChecksum hashes every column of every row in primary key order. Values are read through the models, so the
+// same data hashes the same under either driver; times are compared in UTC to the microsecond, which is all
+// Postgres keeps.
+func tableChecksum(db *gorm.DB, table copyTable, batchSize int) (string, error) {
+	sum := sha256.New()
+	if db.Migrator().HasTable(table.schema.Table) {
+		for offset := 0; ; offset += batchSize {
+			batch := table.newBatch()
+			if err := table.query(db).Limit(batchSize).Offset(offset).Find(batch.Interface()).Error; err != nil {
+				return "", err
+			}
+			rows := batch.Elem()
+			for i := 0; i < rows.Len(); i++ {
+				if err := hashRow(sum, table, rows.Index(i)); err != nil {
+					return "", err
+				}
+			}
+			if rows.Len() < batchSize {
+				break
+			}
+		}
+	}
+	return hex.EncodeToString(sum.Sum(nil)), nil
+}
+
+func hashRow(sum hash.Hash, table copyTable, row reflect.Value) error {
+	values := table.columnValues(row)
+	for _, name := range table.schema.DBNames {
+		formatted, err := checksumValue(values[name])
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		sum.Write([]byte(formatted))
+		sum.Write([]byte{0x1f})
+	}
+	sum.Write([]byte{'\n'})
+	return nil
+}
+
+func checksumValue(value any) (string, error) {
+	if valuer, ok := value.(driver.Valuer); ok {
+		v, err := valuer.Value()
+		if err != nil {
+			return "", err
+		}
+		value = v
+	}
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "NULL", nil
+		}
+		return checksumValue(v.Elem().Interface())
+	}
+	switch v := value.(type) {
+	case nil:
+		return "NULL", nil
+	case time.Time:
+		return v.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano), nil
+	case string:
+		return strconv.Quote(v), nil
+	case []byte:
+		return strconv.Quote(string(v)), nil
+	default:
+		return fmt.Sprintf("%v", v), nil
+	}
+}
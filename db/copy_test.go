@@ -0,0 +1,144 @@
+package db
+
+import (
+	"testing"
+
+	"maintainerd/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func seedCopySource(t *testing.T) *gorm.DB {
+	t.Helper()
+	src := openMigrateTestDB(t)
+	_, err := MigrateUp(src, 0)
+	require.NoError(t, err)
+	company, project, _, alice, _, _ := seedTestData(t, src)
+	company.Tags = map[string]string{"tier": "gold"}
+	require.NoError(t, src.Save(&company).Error)
+	require.NoError(t, src.Delete(&alice).Error)
+	// Creating the project applies the mailing list's column default, so clear it afterwards.
+	require.NoError(t, src.Create(&model.Project{Name: "falco", Maturity: model.Incubating}).Error)
+	require.NoError(t, src.Exec("UPDATE projects SET mailing_list = NULL WHERE name = ?", "falco").Error)
+	// Many2many links live in join tables that have no model of their own.
+	service := model.Service{Name: "fossa"}
+	require.NoError(t, src.Create(&service).Error)
+	require.NoError(t, src.Model(&project).Association("Services").Append(&service))
+	user := model.ServiceUser{ServiceID: service.ID, ServiceUserID: 42, ServiceEmail: "officer@example.com"}
+	require.NoError(t, src.Create(&user).Error)
+	require.NoError(t, src.Create(&model.FoundationOfficer{Name: "Olive", Services: []model.ServiceUser{user}}).Error)
+	return src
+}
+
+func reportByTable(report []TableCopy) map[string]TableCopy {
+	byTable := make(map[string]TableCopy, len(report))
+	for _, table := range report {
+		byTable[table.Table] = table
+	}
+	return byTable
+}
+
+func TestCopyDatabase(t *testing.T) {
+	src := seedCopySource(t)
+	dst := openMigrateTestDB(t)
+
+	report, err := CopyDatabase(src, dst, CopyOptions{BatchSize: 2})
+	require.NoError(t, err)
+	byTable := reportByTable(report)
+	assert.EqualValues(t, 3, byTable["maintainers"].Copied, "soft-deleted rows are copied")
+	assert.EqualValues(t, 4, byTable["maintainer_projects"].DestinationRows)
+	assert.EqualValues(t, 1, byTable["service_projects"].Copied)
+	assert.EqualValues(t, 1, byTable["foundation_officer_service_users"].Copied)
+	for _, table := range report {
+		assert.True(t, table.Match, table.Table)
+	}
+
+	var alice model.Maintainer
+	require.NoError(t, dst.Unscoped().Where("email = ?", "alice@example.com").First(&alice).Error)
+	assert.EqualValues(t, 1, alice.ID, "IDs are kept")
+	assert.True(t, alice.DeletedAt.Valid)
+	var falco model.Project
+	require.NoError(t, dst.Where("name = ?", "falco").First(&falco).Error)
+	assert.Nil(t, falco.MailingList, "NULLs are not replaced with column defaults")
+	var company model.Company
+	require.NoError(t, dst.First(&company).Error)
+	assert.Equal(t, "gold", company.Tags["tier"])
+	var officer model.FoundationOfficer
+	require.NoError(t, dst.Preload("Services").Where("name = ?", "Olive").First(&officer).Error)
+	require.Len(t, officer.Services, 1)
+	assert.Equal(t, 42, officer.Services[0].ServiceUserID)
+
+	_, err = CopyDatabase(src, dst, CopyOptions{})
+	assert.ErrorIs(t, err, ErrDestinationNotEmpty)
+
+	// New rows go after the copied IDs.
+	project := model.Project{Name: "envoy", Maturity: model.Graduated}
+	require.NoError(t, dst.Create(&project).Error)
+	assert.Greater(t, project.ID, falco.ID)
+}
+
+func TestCopyDatabaseResumes(t *testing.T) {
+	src := seedCopySource(t)
+	dst := openMigrateTestDB(t)
+	_, err := CopyDatabase(src, dst, CopyOptions{BatchSize: 2})
+	require.NoError(t, err)
+
+	// Simulate a copy interrupted part-way through maintainers: a prefix of its rows and nothing after it.
+	require.NoError(t, dst.Exec("DELETE FROM maintainer_projects").Error)
+	require.NoError(t, dst.Exec("DELETE FROM maintainers WHERE id > 1").Error)
+
+	report, err := CopyDatabase(src, dst, CopyOptions{BatchSize: 2, Resume: true})
+	require.NoError(t, err)
+	byTable := reportByTable(report)
+	assert.EqualValues(t, 2, byTable["maintainers"].Copied)
+	assert.EqualValues(t, 4, byTable["maintainer_projects"].Copied)
+	assert.Zero(t, byTable["projects"].Copied)
+}
+
+func TestCompareDatabases(t *testing.T) {
+	src := seedCopySource(t)
+	dst := openMigrateTestDB(t)
+	_, err := CopyDatabase(src, dst, CopyOptions{})
+	require.NoError(t, err)
+
+	require.NoError(t, dst.Model(&model.Maintainer{}).Where("email = ?", "bob@example.com").Update("name", "Robert").Error)
+	report, err := CopyDatabase(src, dst, CopyOptions{CompareOnly: true})
+	assert.ErrorIs(t, err, ErrCopyMismatch)
+	byTable := reportByTable(report)
+	assert.False(t, byTable["maintainers"].Match)
+	assert.Equal(t, byTable["maintainers"].SourceRows, byTable["maintainers"].DestinationRows)
+	assert.True(t, byTable["projects"].Match)
+
+	require.NoError(t, dst.Exec("DELETE FROM foundation_officer_service_users").Error)
+	report, err = CopyDatabase(src, dst, CopyOptions{CompareOnly: true})
+	assert.ErrorIs(t, err, ErrCopyMismatch)
+	assert.False(t, reportByTable(report)["foundation_officer_service_users"].Match)
+}
+
+func TestCopyTablesOrderParentsFirst(t *testing.T) {
+	tables, err := copyTables(setupTestDB(t))
+	require.NoError(t, err)
+	position := make(map[string]int, len(tables))
+	for i, table := range tables {
+		position[table.schema.Table] = i
+	}
+	for i, table := range tables {
+		for _, relationship := range table.schema.Relationships.BelongsTo {
+			parent := relationship.FieldSchema.Table
+			if parent == table.schema.Table {
+				continue
+			}
+			assert.Less(t, position[parent], i, "%s references %s", table.schema.Table, parent)
+		}
+		for _, relationship := range append(table.schema.Relationships.HasMany, table.schema.Relationships.HasOne...) {
+			child := relationship.FieldSchema.Table
+			if child == table.schema.Table || (table.schema.Table == "service_user_teams" && relationship.Name == "ServiceTeam") {
+				// Migration 4 drops the constraint GORM infers for ServiceUserTeams.ServiceTeam.
+				continue
+			}
+			assert.Greater(t, position[child], i, "%s references %s", child, table.schema.Table)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"maintainerd/model"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrFoundationNotFound = errors.New("foundation not found")
+	ErrFoundationExists   = errors.New("foundation already exists")
+	ErrFoundationInUse    = errors.New("foundation still has staff members")
+)
+
+// FoundationSummary is a foundation with the number of staff members who belong to it.
+type FoundationSummary struct {
+	ID         uint   `json:"id"`
+	Name       string `json:"name"`
+	StaffCount int64  `json:"staffCount"`
+}
+
+// ListFoundations returns every foundation ordered by name, with its staff count.
+func (s *SQLStore) ListFoundations() ([]FoundationSummary, error) {
+	var foundations []FoundationSummary
+	err := s.db.Model(&model.Foundation{}).
+		Select("foundations.id, foundations.name, COUNT(staff_members.id) AS staff_count").
+		Joins("LEFT JOIN staff_members ON staff_members.foundation_id = foundations.id AND staff_members.deleted_at IS NULL").
+		Group("foundations.id, foundations.name").
+		Order("foundations.name").
+		Scan(&foundations).Error
+	return foundations, err
+}
+
+// GetFoundation returns the foundation with id, or ErrFoundationNotFound.
+func (s *SQLStore) GetFoundation(id uint) (*model.Foundation, error) {
+	var foundation model.Foundation
+	if err := s.db.First(&foundation, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrFoundationNotFound
+		}
+		return nil, err
+	}
+	return &foundation, nil
+}
+
+// foundationNameTaken reports whether another foundation, other than exceptID, is called name, ignoring case.
+func foundationNameTaken(tx *gorm.DB, name string, exceptID uint) (bool, error) {
+	var count int64
+	err := tx.Unscoped().Model(&model.Foundation{}).
+		Where("LOWER(name) = ? AND id <> ?", strings.ToLower(name), exceptID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// CreateFoundation creates a foundation, failing with ErrFoundationExists if the name is taken.
+func (s *SQLStore) CreateFoundation(name string) (*model.Foundation, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("foundation name is required")
+	}
+	foundation := model.Foundation{Name: name}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		taken, err := foundationNameTaken(tx, name, 0)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return ErrFoundationExists
+		}
+		return tx.Create(&foundation).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &foundation, nil
+}
+
+// RenameFoundation changes a foundation's name, failing with ErrFoundationExists if the name is taken.
+func (s *SQLStore) RenameFoundation(id uint, name string) (*model.Foundation, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("foundation name is required")
+	}
+	var foundation model.Foundation
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&foundation, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrFoundationNotFound
+			}
+			return err
+		}
+		taken, err := foundationNameTaken(tx, name, id)
+		if err != nil {
+			return err
+		}
+		if taken {
+			return ErrFoundationExists
+		}
+		return tx.Model(&foundation).Update("name", name).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &foundation, nil
+}
+
+// DeleteFoundation removes a foundation no staff member belongs to.
+func (s *SQLStore) DeleteFoundation(id uint) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Select("id").First(&model.Foundation{}, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrFoundationNotFound
+			}
+			return err
+		}
+		var staff int64
+		if err := tx.Model(&model.StaffMember{}).Where("foundation_id = ?", id).Count(&staff).Error; err != nil {
+			return err
+		}
+		if staff > 0 {
+			return fmt.Errorf("%w: %d staff members", ErrFoundationInUse, staff)
+		}
+		// Deleted outright so the name can be reused; the audit log keeps what was removed.
+		return tx.Unscoped().Delete(&model.Foundation{}, id).Error
+	})
+}
@@ -0,0 +1,254 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"maintainerd/model"
+
+	"gorm.io/gorm"
+)
+
+// ErrGitHubUserNotFound is returned by a GitHubUserResolver when no GitHub account has the requested ID.
+var ErrGitHubUserNotFound = errors.New("github user not found")
+
+// GitHubUserResolver looks up the current login for an immutable GitHub user ID.
+type GitHubUserResolver interface {
+	LoginByID(ctx context.Context, id int64) (string, error)
+}
+
+// GitHubIdentity is the result of matching an authenticated GitHub user against staff and maintainer records.
+// Either pointer may be nil.
+type GitHubIdentity struct {
+	Staff      *model.StaffMember
+	Maintainer *model.Maintainer
+}
+
+// LinkGitHubIdentity matches an authenticated GitHub user to staff and maintainer records. Records already linked
+// to githubID match regardless of handle, and their stored handle is updated if the user has been renamed. Records
+// matched by handle alone are linked to githubID on this first login; a record whose handle matches but which is
+// linked to a different ID is never returned, because the handle has been reused by someone else. Deactivated staff
+// members are linked but not returned.
+func (s *SQLStore) LinkGitHubIdentity(login string, githubID int64) (*GitHubIdentity, error) {
+	login = strings.TrimSpace(login)
+	if login == "" || githubID == 0 {
+		return nil, fmt.Errorf("login and github id are required")
+	}
+	identity := &GitHubIdentity{}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var staff model.StaffMember
+		found, err := linkGitHubRecord(tx, &staff, login, githubID)
+		if err != nil {
+			return err
+		}
+		if found && staff.DeactivatedAt == nil {
+			identity.Staff = &staff
+		}
+		var maintainer model.Maintainer
+		found, err = linkGitHubRecord(tx, &maintainer, login, githubID)
+		if err != nil {
+			return err
+		}
+		if found {
+			identity.Maintainer = &maintainer
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+// linkGitHubRecord loads into dest (a *model.StaffMember or *model.Maintainer) the record for githubID, falling back
+// to an unlinked record with a matching handle, and keeps its handle and ID in sync with GitHub. Each change is
+// audited in tx, attributed to the user logging in.
+func linkGitHubRecord(tx *gorm.DB, dest any, login string, githubID int64) (bool, error) {
+	err := tx.Where("git_hub_id = ?", githubID).First(dest).Error
+	if err == nil {
+		stored := gitHubAccountOf(dest)
+		if !strings.EqualFold(stored, login) {
+			if err := tx.Model(dest).Update("git_hub_account", login).Error; err != nil {
+				return false, err
+			}
+			event, err := gitHubLoginAuditEvent(dest, "_GITHUB_RENAME", login, githubID,
+				fmt.Sprintf("GitHub handle @%s renamed to @%s", stored, login),
+				map[string]map[string]string{"github": {"from": stored, "to": login}})
+			if err != nil {
+				return false, err
+			}
+			if err := AppendAuditLogTx(tx, &event); err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, err
+	}
+	err = tx.Where("LOWER(git_hub_account) = ? AND git_hub_id IS NULL", strings.ToLower(login)).First(dest).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if err := tx.Model(dest).Update("git_hub_id", githubID).Error; err != nil {
+		return false, err
+	}
+	event, err := gitHubLoginAuditEvent(dest, "_GITHUB_LINK", login, githubID,
+		fmt.Sprintf("GitHub handle @%s linked to GitHub user ID %d", gitHubAccountOf(dest), githubID), nil)
+	if err != nil {
+		return false, err
+	}
+	if err := AppendAuditLogTx(tx, &event); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// gitHubLoginAuditEvent builds the audit entry for a change linkGitHubRecord made to record at login. The action is
+// the record kind (STAFF or MAINTAINER) followed by suffix.
+func gitHubLoginAuditEvent(record any, suffix, login string, githubID int64, message string, changes map[string]map[string]string) (model.AuditLog, error) {
+	kind := "maintainer"
+	fields := map[string]any{"githubId": githubID}
+	if staff, ok := record.(*model.StaffMember); ok {
+		kind = "staff"
+		fields["staffMemberId"] = staff.ID
+	}
+	fields["actor"] = map[string]string{"login": login, "role": kind}
+	if changes != nil {
+		fields["changes"] = changes
+	}
+	metadata, err := json.Marshal(fields)
+	if err != nil {
+		return model.AuditLog{}, err
+	}
+	event := model.AuditLog{Message: message, Metadata: string(metadata)}
+	switch r := record.(type) {
+	case *model.StaffMember:
+		event.Action = "STAFF" + suffix
+		event.StaffID = &r.ID
+	case *model.Maintainer:
+		event.Action = "MAINTAINER" + suffix
+		event.MaintainerID = &r.ID
+	}
+	return event, nil
+}
+
+func gitHubAccountOf(record any) string {
+	switch r := record.(type) {
+	case *model.StaffMember:
+		return r.GitHubAccount
+	case *model.Maintainer:
+		return r.GitHubAccount
+	default:
+		return ""
+	}
+}
+
+// GetMaintainerByGitHubID returns the maintainer linked to the GitHub user ID.
+func (s *SQLStore) GetMaintainerByGitHubID(githubID int64) (*model.Maintainer, error) {
+	var maintainer model.Maintainer
+	if err := s.db.Where("git_hub_id = ?", githubID).First(&maintainer).Error; err != nil {
+		return nil, err
+	}
+	return &maintainer, nil
+}
+
+// GitHubRename records a stored handle that no longer belongs to the linked GitHub user.
+type GitHubRename struct {
+	Kind     string `json:"kind"`
+	RecordID uint   `json:"recordId"`
+	GitHubID int64  `json:"githubId"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+}
+
+// GitHubRenameReport summarises a DetectGitHubRenames run.
+type GitHubRenameReport struct {
+	Checked  int            `json:"checked"`
+	Renamed  []GitHubRename `json:"renamed"`
+	Missing  []GitHubRename `json:"missing"`
+	Failures []string       `json:"failures,omitempty"`
+}
+
+// DetectGitHubRenames re-resolves every linked staff member and maintainer by GitHub ID and, unless dryRun is set,
+// updates handles that have changed and writes a *_GITHUB_RENAME audit entry for each. Accounts that no longer exist
+// on GitHub are reported as missing and left untouched.
+func (s *SQLStore) DetectGitHubRenames(ctx context.Context, resolver GitHubUserResolver, dryRun bool) (*GitHubRenameReport, error) {
+	report := &GitHubRenameReport{}
+
+	var staff []model.StaffMember
+	if err := s.db.Where("git_hub_id IS NOT NULL").Find(&staff).Error; err != nil {
+		return nil, err
+	}
+	for i := range staff {
+		s.checkGitHubRename(ctx, resolver, dryRun, report, "staff", staff[i].ID, *staff[i].GitHubID, staff[i].GitHubAccount, &staff[i])
+	}
+
+	var maintainers []model.Maintainer
+	if err := s.db.Where("git_hub_id IS NOT NULL").Find(&maintainers).Error; err != nil {
+		return nil, err
+	}
+	for i := range maintainers {
+		s.checkGitHubRename(ctx, resolver, dryRun, report, "maintainer", maintainers[i].ID, *maintainers[i].GitHubID, maintainers[i].GitHubAccount, &maintainers[i])
+	}
+	return report, nil
+}
+
+func (s *SQLStore) checkGitHubRename(ctx context.Context, resolver GitHubUserResolver, dryRun bool, report *GitHubRenameReport, kind string, id uint, githubID int64, stored string, record any) {
+	report.Checked++
+	current, err := resolver.LoginByID(ctx, githubID)
+	if errors.Is(err, ErrGitHubUserNotFound) {
+		report.Missing = append(report.Missing, GitHubRename{Kind: kind, RecordID: id, GitHubID: githubID, From: stored})
+		return
+	}
+	if err != nil {
+		report.Failures = append(report.Failures, fmt.Sprintf("%s %d (github id %d): %v", kind, id, githubID, err))
+		return
+	}
+	if strings.EqualFold(current, stored) {
+		return
+	}
+	rename := GitHubRename{Kind: kind, RecordID: id, GitHubID: githubID, From: stored, To: current}
+	if !dryRun {
+		if err := s.applyGitHubRename(rename, record); err != nil {
+			report.Failures = append(report.Failures, fmt.Sprintf("%s %d: %v", kind, id, err))
+			return
+		}
+	}
+	report.Renamed = append(report.Renamed, rename)
+}
+
+func (s *SQLStore) applyGitHubRename(rename GitHubRename, record any) error {
+	metadata, err := json.Marshal(map[string]any{
+		"actor": map[string]string{"login": "github-rename-detector", "role": "system"},
+		"changes": map[string]map[string]string{
+			"github": {"from": rename.From, "to": rename.To},
+		},
+		"githubId": rename.GitHubID,
+	})
+	if err != nil {
+		return err
+	}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(record).Update("git_hub_account", rename.To).Error; err != nil {
+			return err
+		}
+		event := model.AuditLog{
+			Action:   strings.ToUpper(rename.Kind) + "_GITHUB_RENAME",
+			Message:  fmt.Sprintf("GitHub handle @%s renamed to @%s", rename.From, rename.To),
+			Metadata: string(metadata),
+		}
+		if rename.Kind == "maintainer" {
+			event.MaintainerID = &rename.RecordID
+		} else {
+			event.StaffID = &rename.RecordID
+		}
+		return AppendAuditLogTx(tx, &event)
+	})
+}
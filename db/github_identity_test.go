@@ -0,0 +1,164 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"maintainerd/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeResolver map[int64]string
+
+func (f fakeResolver) LoginByID(_ context.Context, id int64) (string, error) {
+	login, ok := f[id]
+	if !ok {
+		return "", ErrGitHubUserNotFound
+	}
+	if login == "!error" {
+		return "", errors.New("boom")
+	}
+	return login, nil
+}
+
+func int64Ptr(v int64) *int64 { return &v }
+
+func TestLinkGitHubIdentity(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&model.Foundation{}, &model.StaffMember{}, &model.AuditLog{}))
+	store := NewSQLStore(db)
+	_, _, _, alice, bob, _ := seedTestData(t, db)
+
+	staff := model.StaffMember{Name: "Sam Staff", GitHubAccount: "Sam"}
+	require.NoError(t, db.Create(&staff).Error)
+
+	t.Run("first login links by handle", func(t *testing.T) {
+		identity, err := store.LinkGitHubIdentity("alice", 101)
+		require.NoError(t, err)
+		require.NotNil(t, identity.Maintainer)
+		assert.Nil(t, identity.Staff)
+		assert.Equal(t, alice.ID, identity.Maintainer.ID)
+
+		var refreshed model.Maintainer
+		require.NoError(t, db.First(&refreshed, alice.ID).Error)
+		require.NotNil(t, refreshed.GitHubID)
+		assert.Equal(t, int64(101), *refreshed.GitHubID)
+
+		linked := auditEntries(t, db, "MAINTAINER_GITHUB_LINK")
+		require.Len(t, linked, 1)
+		require.NotNil(t, linked[0].MaintainerID)
+		assert.Equal(t, alice.ID, *linked[0].MaintainerID)
+		assert.Contains(t, linked[0].Metadata, `"githubId":101`)
+		assert.Equal(t, "alice", auditMetadata(t, linked[0]).Actor.Login)
+	})
+
+	t.Run("staff handle match is case-insensitive", func(t *testing.T) {
+		identity, err := store.LinkGitHubIdentity("sam", 202)
+		require.NoError(t, err)
+		require.NotNil(t, identity.Staff)
+		assert.Equal(t, staff.ID, identity.Staff.ID)
+
+		linked := auditEntries(t, db, "STAFF_GITHUB_LINK")
+		require.Len(t, linked, 1)
+		require.NotNil(t, linked[0].StaffID)
+		assert.Equal(t, staff.ID, *linked[0].StaffID)
+	})
+
+	t.Run("renamed user still matches by id and handle is updated", func(t *testing.T) {
+		identity, err := store.LinkGitHubIdentity("alice-renamed", 101)
+		require.NoError(t, err)
+		require.NotNil(t, identity.Maintainer)
+		assert.Equal(t, alice.ID, identity.Maintainer.ID)
+
+		var refreshed model.Maintainer
+		require.NoError(t, db.First(&refreshed, alice.ID).Error)
+		assert.Equal(t, "alice-renamed", refreshed.GitHubAccount)
+
+		renamed := auditEntries(t, db, "MAINTAINER_GITHUB_RENAME")
+		require.Len(t, renamed, 1)
+		assert.Equal(t, "GitHub handle @alice renamed to @alice-renamed", renamed[0].Message)
+		assert.Contains(t, renamed[0].Metadata, `"github":{"from":"alice","to":"alice-renamed"}`)
+
+		_, err = store.LinkGitHubIdentity("alice-renamed", 101)
+		require.NoError(t, err)
+		assert.Len(t, auditEntries(t, db, "MAINTAINER_GITHUB_RENAME"), 1, "an unchanged login writes no entry")
+		assert.Len(t, auditEntries(t, db, "MAINTAINER_GITHUB_LINK"), 1)
+	})
+
+	t.Run("reused handle does not match a linked record", func(t *testing.T) {
+		require.NoError(t, db.Model(&bob).Update("git_hub_id", 303).Error)
+		identity, err := store.LinkGitHubIdentity("bob", 999)
+		require.NoError(t, err)
+		assert.Nil(t, identity.Maintainer)
+		assert.Nil(t, identity.Staff)
+	})
+
+	t.Run("lookup by github id", func(t *testing.T) {
+		maintainer, err := store.GetMaintainerByGitHubID(101)
+		require.NoError(t, err)
+		assert.Equal(t, alice.ID, maintainer.ID)
+	})
+}
+
+func TestDetectGitHubRenames(t *testing.T) {
+	db := setupTestDB(t)
+	require.NoError(t, db.AutoMigrate(&model.Foundation{}, &model.StaffMember{}, &model.AuditLog{}))
+	store := NewSQLStore(db)
+	_, _, _, alice, bob, charlie := seedTestData(t, db)
+
+	require.NoError(t, db.Model(&alice).Update("git_hub_id", 1).Error)
+	require.NoError(t, db.Model(&bob).Update("git_hub_id", 2).Error)
+	require.NoError(t, db.Model(&charlie).Update("git_hub_id", 3).Error)
+	staff := model.StaffMember{Name: "Sam", GitHubAccount: "sam", GitHubID: int64Ptr(4)}
+	require.NoError(t, db.Create(&staff).Error)
+
+	resolver := fakeResolver{1: "Alice", 2: "bobby", 4: "samuel"}
+
+	t.Run("dry run reports without writing", func(t *testing.T) {
+		report, err := store.DetectGitHubRenames(context.Background(), resolver, true)
+		require.NoError(t, err)
+		assert.Equal(t, 4, report.Checked)
+		assert.Len(t, report.Renamed, 2)
+		require.Len(t, report.Missing, 1)
+		assert.Equal(t, charlie.ID, report.Missing[0].RecordID)
+
+		var refreshed model.Maintainer
+		require.NoError(t, db.First(&refreshed, bob.ID).Error)
+		assert.Equal(t, "bob", refreshed.GitHubAccount)
+	})
+
+	t.Run("applies renames and audits them", func(t *testing.T) {
+		report, err := store.DetectGitHubRenames(context.Background(), resolver, false)
+		require.NoError(t, err)
+		assert.Empty(t, report.Failures)
+		assert.ElementsMatch(t, []GitHubRename{
+			{Kind: "staff", RecordID: staff.ID, GitHubID: 4, From: "sam", To: "samuel"},
+			{Kind: "maintainer", RecordID: bob.ID, GitHubID: 2, From: "bob", To: "bobby"},
+		}, report.Renamed)
+
+		var refreshed model.Maintainer
+		require.NoError(t, db.First(&refreshed, bob.ID).Error)
+		assert.Equal(t, "bobby", refreshed.GitHubAccount)
+		var unchanged model.Maintainer
+		require.NoError(t, db.First(&unchanged, alice.ID).Error)
+		assert.Equal(t, "alice", unchanged.GitHubAccount, "case-only differences are not renames")
+
+		var logs []model.AuditLog
+		require.NoError(t, db.Order("id").Find(&logs).Error)
+		require.Len(t, logs, 2)
+		assert.Equal(t, "STAFF_GITHUB_RENAME", logs[0].Action)
+		assert.Equal(t, "MAINTAINER_GITHUB_RENAME", logs[1].Action)
+		assert.Equal(t, bob.ID, *logs[1].MaintainerID)
+		assert.Contains(t, logs[1].Metadata, `"github":{"from":"bob","to":"bobby"}`)
+	})
+
+	t.Run("resolver errors are reported", func(t *testing.T) {
+		report, err := store.DetectGitHubRenames(context.Background(), fakeResolver{1: "!error", 2: "bobby", 4: "samuel"}, false)
+		require.NoError(t, err)
+		assert.Len(t, report.Failures, 1)
+		assert.Empty(t, report.Renamed)
+	})
+}
@@ -0,0 +1,396 @@
+package db
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"maintainerd/model"
+
+	"gorm.io/gorm"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	ErrImportConflicts    = errors.New("import has conflicting rows")
+	ErrImportFormat       = errors.New("import format must be csv or yaml")
+	ErrImportMissingField = errors.New("import is missing a required column")
+)
+
+// ImportRow is one maintainer-on-project row of a bulk import.
+type ImportRow struct {
+	// Line is the row's line in a CSV source, or its 1-based position in a YAML list.
+	Line    int    `json:"line,omitempty"`
+	Project string `json:"project"`
+	Name    string `json:"name,omitempty"`
+	Email   string `json:"email,omitempty"`
+	GitHub  string `json:"github,omitempty"`
+	Company string `json:"company,omitempty"`
+}
+
+// ImportAction is what applying an import row would do.
+type ImportAction string
+
+const (
+	// ImportCreate creates a maintainer and adds them to the project.
+	ImportCreate ImportAction = "create"
+	// ImportUpdate adds an existing maintainer to the project.
+	ImportUpdate ImportAction = "update"
+	// ImportUnchanged matches a maintainer who is already on the project.
+	ImportUnchanged ImportAction = "unchanged"
+	// ImportConflict cannot be applied; Reason says why.
+	ImportConflict ImportAction = "conflict"
+)
+
+// ImportPlanRow is the planned outcome of one import row.
+type ImportPlanRow struct {
+	ImportRow
+	Action       ImportAction `json:"action"`
+	ProjectID    uint         `json:"projectId,omitempty"`
+	MaintainerID uint         `json:"maintainerId,omitempty"`
+	// Reason explains a conflict, or notes something applying will not change.
+	Reason string `json:"reason,omitempty"`
+}
+
+// ImportPlan is the dry-run result of a bulk import, or the result of applying it.
+type ImportPlan struct {
+	Rows      []ImportPlanRow `json:"rows"`
+	Creates   int             `json:"creates"`
+	Updates   int             `json:"updates"`
+	Unchanged int             `json:"unchanged"`
+	Conflicts int             `json:"conflicts"`
+	Applied   bool            `json:"applied"`
+}
+
+// importColumns maps normalised CSV header names to ImportRow fields.
+var importColumns = map[string]string{
+	"project":        "project",
+	"projectname":    "project",
+	"name":           "name",
+	"maintainer":     "name",
+	"maintainername": "name",
+	"email":          "email",
+	"github":         "github",
+	"githubhandle":   "github",
+	"githubaccount":  "github",
+	"company":        "company",
+}
+
+// ParseImportRows reads import rows in the given format: csv, or yaml (which also accepts JSON).
+func ParseImportRows(format string, r io.Reader) ([]ImportRow, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "csv":
+		return ParseImportCSV(r)
+	case "yaml", "yml", "json":
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		return ParseImportYAML(data)
+	default:
+		return nil, ErrImportFormat
+	}
+}
+
+// ParseImportCSV reads import rows from CSV with a header row naming the project, name, email, github and company
+// columns. Header names ignore case, spaces, dashes and underscores, so "GitHub Handle" is accepted.
+func ParseImportCSV(r io.Reader) ([]ImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return []ImportRow{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		key := strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.ToLower(strings.TrimSpace(name)))
+		if field, ok := importColumns[key]; ok {
+			if _, seen := columns[field]; !seen {
+				columns[field] = i
+			}
+		}
+	}
+	if _, ok := columns["project"]; !ok {
+		return nil, fmt.Errorf("%w: project", ErrImportMissingField)
+	}
+	_, hasEmail := columns["email"]
+	_, hasGitHub := columns["github"]
+	if !hasEmail && !hasGitHub {
+		return nil, fmt.Errorf("%w: email or github", ErrImportMissingField)
+	}
+
+	rows := []ImportRow{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		value := func(field string) string {
+			i, ok := columns[field]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		row := ImportRow{
+			Project: value("project"),
+			Name:    value("name"),
+			Email:   value("email"),
+			GitHub:  value("github"),
+			Company: value("company"),
+		}
+		if row == (ImportRow{}) {
+			continue
+		}
+		row.Line, _ = reader.FieldPos(0)
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// ParseImportYAML reads import rows from a YAML or JSON list of objects with project, name, email, github and
+// company keys.
+func ParseImportYAML(data []byte) ([]ImportRow, error) {
+	rows := []ImportRow{}
+	if err := yaml.Unmarshal(data, &rows); err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		if rows[i].Line == 0 {
+			rows[i].Line = i + 1
+		}
+	}
+	return rows, nil
+}
+
+// PlanImport returns what applying rows would do, without changing anything. Rows are matched to maintainers with
+// the same rules as UpsertMaintainer.
+func (s *SQLStore) PlanImport(rows []ImportRow) (*ImportPlan, error) {
+	return planImport(s.db, rows)
+}
+
+// ApplyImport applies rows in one transaction and returns the plan it followed. Nothing is applied if any row
+// conflicts; the plan is returned with ErrImportConflicts.
+func (s *SQLStore) ApplyImport(rows []ImportRow) (*ImportPlan, error) {
+	var plan *ImportPlan
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if plan, err = planImport(tx, rows); err != nil {
+			return err
+		}
+		if plan.Conflicts > 0 {
+			return ErrImportConflicts
+		}
+		store := &SQLStore{db: tx, actorLogin: s.actorLogin}
+		return plan.apply(func(row ImportPlanRow) (*model.Maintainer, error) {
+			return store.UpsertMaintainer(row.ProjectID, row.Name, row.Email, row.GitHub, row.Company)
+		})
+	})
+	if err != nil && plan == nil {
+		return nil, err
+	}
+	return plan, err
+}
+
+// apply runs upsert for every row that changes something, recording created maintainer IDs in the plan.
+func (p *ImportPlan) apply(upsert func(row ImportPlanRow) (*model.Maintainer, error)) error {
+	for i, row := range p.Rows {
+		if row.Action != ImportCreate && row.Action != ImportUpdate {
+			continue
+		}
+		maintainer, err := upsert(row)
+		if err != nil {
+			return fmt.Errorf("import line %d: %w", row.Line, err)
+		}
+		p.Rows[i].MaintainerID = maintainer.ID
+	}
+	p.Applied = true
+	return nil
+}
+
+// importPending tracks maintainers and memberships that earlier rows of the same import will create.
+type importPending struct {
+	// maintainers maps "github:<handle>" and "email:<address>" keys to the row index that creates the maintainer.
+	maintainers map[string]int
+	// memberships holds "<maintainer>/<project>" keys, where maintainer is an ID or a pending row index.
+	memberships map[string]struct{}
+}
+
+func planImport(tx *gorm.DB, rows []ImportRow) (*ImportPlan, error) {
+	plan := &ImportPlan{Rows: make([]ImportPlanRow, 0, len(rows))}
+	pending := importPending{maintainers: make(map[string]int), memberships: make(map[string]struct{})}
+	for i, row := range rows {
+		planned, err := planImportRow(tx, i, trimImportRow(row), &pending)
+		if err != nil {
+			return nil, err
+		}
+		if planned.Line == 0 {
+			planned.Line = i + 1
+		}
+		switch planned.Action {
+		case ImportCreate:
+			plan.Creates++
+		case ImportUpdate:
+			plan.Updates++
+		case ImportUnchanged:
+			plan.Unchanged++
+		case ImportConflict:
+			plan.Conflicts++
+		}
+		plan.Rows = append(plan.Rows, planned)
+	}
+	return plan, nil
+}
+
+func planImportRow(tx *gorm.DB, index int, row ImportRow, pending *importPending) (ImportPlanRow, error) {
+	planned := ImportPlanRow{ImportRow: row, Action: ImportConflict}
+	if row.Project == "" {
+		planned.Reason = "project is required"
+		return planned, nil
+	}
+	project, err := resolveProject(tx, row.Project)
+	if err != nil {
+		return planned, err
+	}
+	if project == nil {
+		planned.Reason = fmt.Sprintf("unknown project %q", row.Project)
+		return planned, nil
+	}
+	planned.ProjectID = project.ID
+	if row.Email == "" && row.GitHub == "" {
+		planned.Reason = "an email or GitHub handle is required"
+		return planned, nil
+	}
+	if reason, err := importIdentityConflict(tx, row); err != nil || reason != "" {
+		planned.Reason = reason
+		return planned, err
+	}
+
+	existing, err := findUpsertMaintainer(tx, row.Email, row.GitHub)
+	if err != nil {
+		return planned, err
+	}
+	var maintainerKey string
+	if existing != nil {
+		planned.MaintainerID = existing.ID
+		maintainerKey = fmt.Sprint(existing.ID)
+		var linked int64
+		if err := tx.Model(&model.MaintainerProject{}).
+			Where("maintainer_id = ? AND project_id = ?", existing.ID, project.ID).
+			Count(&linked).Error; err != nil {
+			return planned, err
+		}
+		if linked > 0 {
+			pending.memberships[maintainerKey+"/"+fmt.Sprint(project.ID)] = struct{}{}
+		}
+		planned.Reason, err = importCompanyNote(tx, *existing, row.Company)
+		if err != nil {
+			return planned, err
+		}
+	} else if creator, ok := pending.lookup(row); ok {
+		maintainerKey = fmt.Sprintf("row%d", creator)
+	} else {
+		if row.Name == "" {
+			planned.Reason = "name is required for a new maintainer"
+			return planned, nil
+		}
+		planned.Action = ImportCreate
+		maintainerKey = fmt.Sprintf("row%d", index)
+		pending.add(row, index)
+		pending.memberships[maintainerKey+"/"+fmt.Sprint(project.ID)] = struct{}{}
+		return planned, nil
+	}
+
+	membershipKey := maintainerKey + "/" + fmt.Sprint(project.ID)
+	if _, ok := pending.memberships[membershipKey]; ok {
+		planned.Action = ImportUnchanged
+		return planned, nil
+	}
+	pending.memberships[membershipKey] = struct{}{}
+	planned.Action = ImportUpdate
+	return planned, nil
+}
+
+// importIdentityConflict reports when a row's GitHub handle and email point at different people, which
+// UpsertMaintainer would otherwise resolve silently in favour of the GitHub handle.
+func importIdentityConflict(tx *gorm.DB, row ImportRow) (string, error) {
+	var byGitHub, byEmail model.Maintainer
+	if row.GitHub != "" {
+		if err := tx.Where("LOWER(git_hub_account) = ?", strings.ToLower(row.GitHub)).First(&byGitHub).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", err
+		}
+	}
+	if row.Email != "" {
+		if err := tx.Where("LOWER(email) = ?", strings.ToLower(row.Email)).First(&byEmail).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", err
+		}
+	}
+	switch {
+	case byGitHub.ID != 0 && byEmail.ID != 0 && byGitHub.ID != byEmail.ID:
+		return fmt.Sprintf("GitHub handle matches maintainer %d but email matches maintainer %d", byGitHub.ID, byEmail.ID), nil
+	case byGitHub.ID == 0 && byEmail.ID != 0 && row.GitHub != "" &&
+		byEmail.GitHubAccount != "" && byEmail.GitHubAccount != "GITHUB_MISSING":
+		return fmt.Sprintf("email belongs to maintainer %d with GitHub handle %s", byEmail.ID, byEmail.GitHubAccount), nil
+	}
+	return "", nil
+}
+
+// importCompanyNote notes when a row names a different company than an existing maintainer's, which
+// UpsertMaintainer leaves unchanged.
+func importCompanyNote(tx *gorm.DB, maintainer model.Maintainer, company string) (string, error) {
+	if company == "" || maintainer.CompanyID == nil {
+		return "", nil
+	}
+	var recorded model.Company
+	if err := tx.Select("id", "name").First(&recorded, *maintainer.CompanyID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+	if strings.EqualFold(recorded.Name, company) {
+		return "", nil
+	}
+	return fmt.Sprintf("company %q differs from recorded %q and is not changed", company, recorded.Name), nil
+}
+
+func (p *importPending) lookup(row ImportRow) (int, bool) {
+	if row.GitHub != "" {
+		if index, ok := p.maintainers["github:"+strings.ToLower(row.GitHub)]; ok {
+			return index, true
+		}
+	}
+	if row.Email != "" {
+		if index, ok := p.maintainers["email:"+strings.ToLower(row.Email)]; ok {
+			return index, true
+		}
+	}
+	return 0, false
+}
+
+func (p *importPending) add(row ImportRow, index int) {
+	if row.GitHub != "" {
+		p.maintainers["github:"+strings.ToLower(row.GitHub)] = index
+	}
+	if row.Email != "" {
+		p.maintainers["email:"+strings.ToLower(row.Email)] = index
+	}
+}
+
+func trimImportRow(row ImportRow) ImportRow {
+	row.Project = strings.TrimSpace(row.Project)
+	row.Name = strings.TrimSpace(row.Name)
+	row.Email = strings.TrimSpace(row.Email)
+	row.GitHub = strings.TrimPrefix(strings.TrimSpace(row.GitHub), "@")
+	row.Company = strings.TrimSpace(row.Company)
+	return row
+}
@@ -0,0 +1,259 @@
+package db
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"google.golang.org/api/option"
+	"google.golang.org/api/sheets/v4"
+)
+
+// Worksheet ranges read by the Sheets import source. Each must include the header row.
+const (
+	ActiveSheetRange = "Active!A:J"
+	StaffSheetRange  = "Staff!A:F"
+)
+
+// File names read by the CSV directory import source.
+const (
+	ActiveCSVFile = "active.csv"
+	StaffCSVFile  = "staff.csv"
+)
+
+// ImportSource supplies the rows BootstrapDB seeds maintainers, projects and staff from. Rows are keyed by the
+// worksheet headers (ProjectHdr, EmailHdr, ...), with blank Project, Status, OWNERS/MAINTAINERS and mailing list
+// cells already filled from the row above for maintainers, and blank Foundation cells for staff.
+type ImportSource interface {
+	MaintainerRows(ctx context.Context) ([]map[string]string, error)
+	StaffRows(ctx context.Context) ([]map[string]string, error)
+}
+
+// maintainerCarryForward and staffCarryForward are the columns a worksheet leaves blank when they repeat the row
+// above.
+var (
+	maintainerCarryForward = []string{ProjectHdr, StatusHdr, MaintainerFileRefHdr, MailingListAddrHdr}
+	staffCarryForward      = []string{FoundationHdr}
+)
+
+// NewSheetsSource returns an ImportSource reading the Active and Staff worksheets of a Google spreadsheet. The
+// Sheets client is created on first use.
+func NewSheetsSource(spreadsheetID, credentialsPath string) ImportSource {
+	return &sheetsSource{spreadsheetID: spreadsheetID, credentialsPath: credentialsPath}
+}
+
+type sheetsSource struct {
+	spreadsheetID   string
+	credentialsPath string
+
+	once sync.Once
+	srv  *sheets.Service
+	err  error
+}
+
+func (s *sheetsSource) MaintainerRows(ctx context.Context) ([]map[string]string, error) {
+	return s.read(ctx, ActiveSheetRange, maintainerCarryForward)
+}
+
+func (s *sheetsSource) StaffRows(ctx context.Context) ([]map[string]string, error) {
+	return s.read(ctx, StaffSheetRange, staffCarryForward)
+}
+
+func (s *sheetsSource) read(ctx context.Context, readRange string, carryForward []string) ([]map[string]string, error) {
+	s.once.Do(func() {
+		s.srv, s.err = sheets.NewService(
+			ctx,
+			option.WithCredentialsFile(s.credentialsPath),
+			option.WithScopes(sheets.SpreadsheetsReadonlyScope),
+		)
+	})
+	if s.err != nil {
+		return nil, fmt.Errorf("db: unable to retrieve Sheets client: %w", s.err)
+	}
+	resp, err := s.srv.Spreadsheets.Values.
+		Get(s.spreadsheetID, readRange).
+		Context(ctx).
+		Do()
+	if err != nil {
+		return nil, fmt.Errorf("db: Using %s unable to retrieve worksheet data (%s): %w", s.spreadsheetID, readRange, err)
+	}
+	if len(resp.Values) == 0 {
+		return nil, fmt.Errorf("db: %s worksheet is empty", s.spreadsheetID)
+	}
+	values := make([][]string, len(resp.Values))
+	for i, row := range resp.Values {
+		values[i] = make([]string, len(row))
+		for j, cell := range row {
+			values[i][j] = fmt.Sprint(cell)
+		}
+	}
+	return tableRows(values, carryForward...), nil
+}
+
+// NewCSVDirSource returns an ImportSource reading active.csv and staff.csv from dir, laid out like the Active and
+// Staff worksheets with a header row. staff.csv is optional.
+func NewCSVDirSource(dir string) ImportSource {
+	return csvDirSource{dir: dir}
+}
+
+type csvDirSource struct {
+	dir string
+}
+
+func (s csvDirSource) MaintainerRows(context.Context) ([]map[string]string, error) {
+	return s.read(ActiveCSVFile, true, maintainerCarryForward)
+}
+
+func (s csvDirSource) StaffRows(context.Context) ([]map[string]string, error) {
+	return s.read(StaffCSVFile, false, staffCarryForward)
+}
+
+func (s csvDirSource) read(name string, required bool, carryForward []string) ([]map[string]string, error) {
+	path := filepath.Join(s.dir, name)
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && !required {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("db: %w", err)
+	}
+	defer f.Close()
+	reader := csv.NewReader(f)
+	reader.FieldsPerRecord = -1
+	values, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("db: reading %s: %w", path, err)
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("db: %s is empty", path)
+	}
+	return tableRows(values, carryForward...), nil
+}
+
+// JSONSourceFile is the layout NewJSONSource reads: rows keyed by the worksheet headers, with every cell given
+// explicitly.
+type JSONSourceFile struct {
+	Maintainers []map[string]string `json:"maintainers"`
+	Staff       []map[string]string `json:"staff"`
+}
+
+// NewJSONSource returns an ImportSource reading a JSONSourceFile from path.
+func NewJSONSource(path string) ImportSource {
+	return &jsonSource{path: path}
+}
+
+type jsonSource struct {
+	path string
+
+	once sync.Once
+	file JSONSourceFile
+	err  error
+}
+
+func (s *jsonSource) MaintainerRows(context.Context) ([]map[string]string, error) {
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return trimRows(s.file.Maintainers), nil
+}
+
+func (s *jsonSource) StaffRows(context.Context) ([]map[string]string, error) {
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return trimRows(s.file.Staff), nil
+}
+
+func (s *jsonSource) load() error {
+	s.once.Do(func() {
+		f, err := os.Open(s.path)
+		if err != nil {
+			s.err = fmt.Errorf("db: %w", err)
+			return
+		}
+		defer f.Close()
+		if err := json.NewDecoder(f).Decode(&s.file); err != nil && !errors.Is(err, io.EOF) {
+			s.err = fmt.Errorf("db: reading %s: %w", s.path, err)
+		}
+	})
+	return s.err
+}
+
+func trimRows(rows []map[string]string) []map[string]string {
+	trimmed := make([]map[string]string, 0, len(rows))
+	for _, row := range rows {
+		clean := make(map[string]string, len(row))
+		for key, value := range row {
+			clean[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+		trimmed = append(trimmed, clean)
+	}
+	return trimmed
+}
+
+// tableRows returns every row after the header row as a map keyed by the header row and carries forward the last
+// non‐empty value of the carryForwardHeaders columns when those cells are blank or missing. Fully empty rows are
+// skipped.
+func tableRows(values [][]string, carryForwardHeaders ...string) []map[string]string {
+	// First row → headers
+	headers := make([]string, len(values[0]))
+	for i, cell := range values[0] {
+		headers[i] = strings.TrimSpace(strings.TrimPrefix(cell, "\ufeff"))
+	}
+
+	carryForwardIdx := make(map[int]struct{}, len(carryForwardHeaders))
+	for _, hdr := range carryForwardHeaders {
+		for i, h := range headers {
+			if h == hdr {
+				carryForwardIdx[i] = struct{}{}
+				break
+			}
+		}
+	}
+
+	var rows []map[string]string
+	lastVals := make(map[int]string, len(carryForwardIdx))
+
+	// Remaining rows → maps
+	for _, r := range values[1:] {
+		rowMap := make(map[string]string, len(headers))
+		hasAnyValue := false
+
+		for i, h := range headers {
+			// read raw cell if present
+			var cellVal string
+			if i < len(r) {
+				cellVal = strings.TrimSpace(r[i])
+			}
+			if cellVal != "" {
+				hasAnyValue = true
+			}
+
+			if _, ok := carryForwardIdx[i]; ok {
+				if cellVal != "" {
+					lastVals[i] = cellVal
+				}
+				rowMap[h] = lastVals[i]
+				continue
+			}
+
+			rowMap[h] = cellVal
+		}
+
+		// Skip fully empty rows.
+		if !hasAnyValue {
+			continue
+		}
+
+		rows = append(rows, rowMap)
+	}
+
+	return rows
+}
@@ -0,0 +1,103 @@
+package db
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"maintainerd/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testActiveCSV = "\ufeffProject,Status,Maintainer Name,Company,Emails,Github Name,GitHub Email,Parent Project,OWNERS/MAINTAINERS,Mailing List Address\n" +
+	"Kubernetes,Graduated,Alice,Acme,alice@acme.example,alice,,,https://github.com/kubernetes/kubernetes/blob/master/OWNERS,kube-dev@lists.cncf.io\n" +
+	",,Bob,,bob@example.com,bob\n" +
+	",,,,,,,,,\n" +
+	"SIG Node,,Carol,Acme,carol@acme.example,carol,,Kubernetes\n"
+
+const testStaffCSV = "Foundation,Staff Member Name,Emails,Github Name\n" +
+	"CNCF,Sam Staff,sam@cncf.io,sam\n" +
+	",Pat Staff,pat@cncf.io,pat\n"
+
+func writeTestFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+}
+
+func TestCSVDirSourceCarriesForward(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, ActiveCSVFile, testActiveCSV)
+	source := NewCSVDirSource(dir)
+
+	rows, err := source.MaintainerRows(context.Background())
+	require.NoError(t, err)
+	require.Len(t, rows, 3, "fully empty rows are skipped")
+	assert.Equal(t, "Kubernetes", rows[1][ProjectHdr])
+	assert.Equal(t, "Graduated", rows[1][StatusHdr])
+	assert.Equal(t, "kube-dev@lists.cncf.io", rows[1][MailingListAddrHdr])
+	assert.Equal(t, "SIG Node", rows[2][ProjectHdr])
+	assert.Equal(t, "Kubernetes", rows[2][ParentProjectHdr])
+	assert.Empty(t, rows[2][GitHubEmail], "short rows leave trailing cells empty")
+
+	staff, err := source.StaffRows(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, staff, "staff.csv is optional")
+
+	_, err = NewCSVDirSource(t.TempDir()).MaintainerRows(context.Background())
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestJSONSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "seed.json")
+	writeTestFile(t, filepath.Dir(path), filepath.Base(path), `{
+		"maintainers": [{"Project": "Falco", "Status": "Incubating", "Emails": " amy@example.com "}],
+		"staff": [{"Foundation": "CNCF", "Emails": "sam@cncf.io"}]
+	}`)
+	source := NewJSONSource(path)
+
+	rows, err := source.MaintainerRows(context.Background())
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, "amy@example.com", rows[0][EmailHdr])
+	staff, err := source.StaffRows(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "CNCF", staff[0][FoundationHdr])
+}
+
+func TestBootstrapDBOffline(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, ActiveCSVFile, testActiveCSV)
+	writeTestFile(t, dir, StaffCSVFile, testStaffCSV)
+
+	database, err := BootstrapDB("sqlite", filepath.Join(dir, "maintainers.db"), NewCSVDirSource(dir), "", true)
+	require.NoError(t, err)
+
+	var projects []model.Project
+	require.NoError(t, database.Order("name").Find(&projects).Error)
+	require.Len(t, projects, 2)
+	assert.Equal(t, "Kubernetes", projects[0].Name)
+	assert.Equal(t, model.Graduated, projects[1].Maturity, "sub-projects take their parent's maturity")
+	require.NotNil(t, projects[1].ParentProjectID)
+	assert.Equal(t, projects[0].ID, *projects[1].ParentProjectID)
+
+	var maintainers int64
+	require.NoError(t, database.Model(&model.Maintainer{}).Count(&maintainers).Error)
+	assert.EqualValues(t, 3, maintainers)
+	var memberships, sheetJoins int64
+	require.NoError(t, database.Model(&model.MaintainerProject{}).Count(&memberships).Error)
+	require.NoError(t, database.Model(&model.MembershipHistory{}).
+		Where("event = ? AND source = ?", "JOIN", model.MembershipSourceSheet).Count(&sheetJoins).Error)
+	assert.Equal(t, memberships, sheetJoins, "each membership records the sheet as its source")
+	var staff []model.StaffMember
+	require.NoError(t, database.Order("email").Find(&staff).Error)
+	require.Len(t, staff, 2)
+	assert.Equal(t, staff[0].FoundationID, staff[1].FoundationID, "blank foundation cells repeat the row above")
+
+	_, err = BootstrapDB("sqlite", filepath.Join(dir, "empty.db"), nil, "", true)
+	assert.Error(t, err, "seeding needs a source")
+	_, err = BootstrapDB("sqlite", filepath.Join(dir, "schema.db"), nil, "", false)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,103 @@
+package db
+
+import (
+	"strings"
+	"testing"
+
+	"maintainerd/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseImportRows(t *testing.T) {
+	rows, err := ParseImportRows("csv", strings.NewReader("Project,Maintainer Name,Email,GitHub Handle,Company\n"+
+		"kubernetes,Dana Dev,dana@example.com,@dana,Acme\n"+
+		",,,,\n"+
+		"prometheus, Erin ,,erin,\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []ImportRow{
+		{Line: 2, Project: "kubernetes", Name: "Dana Dev", Email: "dana@example.com", GitHub: "@dana", Company: "Acme"},
+		{Line: 4, Project: "prometheus", Name: "Erin", GitHub: "erin"},
+	}, rows)
+
+	_, err = ParseImportRows("csv", strings.NewReader("project,name\nkubernetes,Dana\n"))
+	assert.ErrorIs(t, err, ErrImportMissingField)
+
+	rows, err = ParseImportRows("yaml", strings.NewReader("- project: kubernetes\n  name: Dana Dev\n  github: dana\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []ImportRow{{Line: 1, Project: "kubernetes", Name: "Dana Dev", GitHub: "dana"}}, rows)
+
+	_, err = ParseImportRows("xlsx", strings.NewReader(""))
+	assert.ErrorIs(t, err, ErrImportFormat)
+}
+
+func TestPlanAndApplyImport(t *testing.T) {
+	db, store, _ := setupAuditedStore(t)
+	_, project1, project2, alice, bob, _ := seedTestData(t, db)
+
+	rows := []ImportRow{
+		{Project: "kubernetes", Name: "Alice Developer", GitHub: "alice"},
+		{Project: "Prometheus", Name: "Alice Developer", Email: "ALICE@example.com", Company: "Other Co"},
+		{Project: "kubernetes", Name: "Dana Dev", Email: "dana@example.com", GitHub: "@dana"},
+		{Project: "prometheus", GitHub: "dana"},
+		{Project: "kubernetes", GitHub: "dana"},
+		{Project: "kubernetes", Email: "erin@example.com"},
+		{Project: "thanos", Name: "Finn", GitHub: "finn"},
+		{Project: "kubernetes", Name: "Mixed", Email: "bob@example.com", GitHub: "alice"},
+	}
+	plan, err := store.PlanImport(rows)
+	require.NoError(t, err)
+	actions := make([]ImportAction, 0, len(plan.Rows))
+	for _, row := range plan.Rows {
+		actions = append(actions, row.Action)
+	}
+	assert.Equal(t, []ImportAction{
+		ImportUnchanged, ImportUpdate, ImportCreate, ImportUpdate, ImportUnchanged,
+		ImportConflict, ImportConflict, ImportConflict,
+	}, actions)
+	assert.Equal(t, alice.ID, plan.Rows[1].MaintainerID)
+	assert.Equal(t, project2.ID, plan.Rows[1].ProjectID)
+	assert.Contains(t, plan.Rows[1].Reason, "is not changed")
+	assert.Equal(t, "name is required for a new maintainer", plan.Rows[5].Reason)
+	assert.Equal(t, `unknown project "thanos"`, plan.Rows[6].Reason)
+	assert.Contains(t, plan.Rows[7].Reason, "email matches maintainer")
+	assert.Equal(t, 3, plan.Conflicts)
+
+	applied, err := store.ApplyImport(rows)
+	assert.ErrorIs(t, err, ErrImportConflicts)
+	require.NotNil(t, applied)
+	assert.False(t, applied.Applied)
+	var count int64
+	require.NoError(t, db.Model(&model.Maintainer{}).Where("git_hub_account = ?", "dana").Count(&count).Error)
+	assert.Zero(t, count, "nothing is applied while rows conflict")
+
+	applied, err = store.ApplyImport(rows[:5])
+	require.NoError(t, err)
+	assert.True(t, applied.Applied)
+	dana := applied.Rows[2].MaintainerID
+	require.NotZero(t, dana)
+	assert.Equal(t, []uint{project1.ID, project2.ID}, membershipProjectIDs(t, db, dana))
+	assert.Equal(t, []uint{project1.ID, project2.ID}, membershipProjectIDs(t, db, alice.ID))
+	assert.Equal(t, []uint{project1.ID, project2.ID}, membershipProjectIDs(t, db, bob.ID))
+	assert.Len(t, auditEntries(t, db, "MAINTAINER_CREATE"), 1)
+	updates := auditEntries(t, db, "MAINTAINER_UPDATE")
+	require.Len(t, updates, 2)
+	var companies int64
+	require.NoError(t, db.Model(&model.Company{}).Where("name = ?", "Other Co").Count(&companies).Error)
+	assert.Zero(t, companies, "a maintainer's existing company is kept without creating the listed one")
+
+	again, err := store.PlanImport(rows[:5])
+	require.NoError(t, err)
+	assert.Equal(t, 5, again.Unchanged, "re-running an applied import changes nothing")
+
+	// The import's entries share a batch, so reverting one update reverts the import's other updates too.
+	batch := auditMetadata(t, updates[0]).Batch
+	require.NotNil(t, batch)
+	assert.Equal(t, 3, batch.Size)
+	reverts, err := store.RevertAuditEntry(updates[0].ID)
+	require.NoError(t, err)
+	assert.Len(t, reverts, 2)
+	assert.Equal(t, []uint{project1.ID}, membershipProjectIDs(t, db, alice.ID))
+	assert.Equal(t, []uint{project1.ID}, membershipProjectIDs(t, db, dana))
+}
@@ -0,0 +1,420 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"maintainerd/model"
+
+	"gorm.io/gorm"
+)
+
+// maxMergeChain bounds how many merges mergedMaintainer follows, in case merges ever form a loop.
+const maxMergeChain = 16
+
+// maintainerNameSimilarityThreshold is the name similarity at which two maintainers at the same company are suggested
+// as duplicates.
+const maintainerNameSimilarityThreshold = 0.85
+
+// Reasons a pair of maintainers is suggested as duplicates.
+const (
+	DuplicateGitHub         = "github"
+	DuplicateGitHubEmail    = "githubEmail"
+	DuplicateEmail          = "email"
+	DuplicateNameAndCompany = "nameAndCompany"
+)
+
+// MaintainerMerge records what MergeMaintainers moved from one maintainer to another, so the merge can be undone.
+type MaintainerMerge struct {
+	FromMaintainerID uint `json:"fromMaintainerId"`
+	ToMaintainerID   uint `json:"toMaintainerId"`
+	// MovedProjectIDs are the source's memberships that now belong to the target.
+	MovedProjectIDs []uint `json:"movedProjectIds"`
+	// DroppedMemberships are the source's memberships of projects the target already belonged to. The target's
+	// membership is kept as it was.
+	DroppedMemberships []MergedMembership `json:"droppedMemberships,omitempty"`
+	HistoryIDs         []uint             `json:"historyIds,omitempty"`
+	ServiceUserTeamIDs []uint             `json:"serviceUserTeamIds,omitempty"`
+	// DroppedServiceUserTeamIDs are the source's service team links the target already had; they are soft-deleted.
+	DroppedServiceUserTeamIDs []uint `json:"droppedServiceUserTeamIds,omitempty"`
+	// GitHubID is set when the source's GitHub user ID moved to the target.
+	GitHubID *int64 `json:"githubId,omitempty"`
+}
+
+// MergedMembership is a membership dropped by a maintainer merge.
+type MergedMembership struct {
+	ProjectID uint       `json:"projectId"`
+	Status    string     `json:"status"`
+	Role      string     `json:"role"`
+	JoinedAt  time.Time  `json:"joinedAt"`
+	LeftAt    *time.Time `json:"leftAt,omitempty"`
+}
+
+// MergeMaintainers folds maintainer fromID into toID: the source's project memberships, membership history and
+// service team links move to the target, fields the target is missing are copied from the source, and the source is
+// soft-deleted with MergedIntoID set so later imports naming it resolve to the target. Ref caches of the affected
+// projects are cleared so the next sync reconciles them against the merged record. Audit entries are append-only
+// and keep the source's ID; audit queries for the target include them.
+func (s *SQLStore) MergeMaintainers(fromID, toID uint) (*MaintainerMerge, error) {
+	if fromID == toID {
+		return nil, fmt.Errorf("fromID and toID must differ")
+	}
+	merge := &MaintainerMerge{FromMaintainerID: fromID, ToMaintainerID: toID, MovedProjectIDs: []uint{}}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var target model.Maintainer
+		if err := tx.First(&target, toID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("target maintainer %d not found", toID)
+			}
+			return err
+		}
+		var source model.Maintainer
+		if err := tx.First(&source, fromID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("source maintainer %d not found", fromID)
+			}
+			return err
+		}
+
+		if err := mergeMaintainerMemberships(tx, merge); err != nil {
+			return err
+		}
+		if err := tx.Model(&model.MembershipHistory{}).
+			Where("maintainer_id = ?", fromID).
+			Order("id").
+			Pluck("id", &merge.HistoryIDs).Error; err != nil {
+			return err
+		}
+		if len(merge.HistoryIDs) > 0 {
+			if err := tx.Model(&model.MembershipHistory{}).
+				Where("id IN ?", merge.HistoryIDs).
+				Update("maintainer_id", toID).Error; err != nil {
+				return err
+			}
+		}
+		if err := mergeMaintainerServiceTeams(tx, merge); err != nil {
+			return err
+		}
+
+		projectIDs := append([]uint{}, merge.MovedProjectIDs...)
+		for _, dropped := range merge.DroppedMemberships {
+			projectIDs = append(projectIDs, dropped.ProjectID)
+		}
+		if len(projectIDs) > 0 {
+			if err := tx.Where("project_id IN ?", projectIDs).Delete(&model.MaintainerRefCache{}).Error; err != nil {
+				return err
+			}
+		}
+
+		updates := mergedMaintainerFields(source, target)
+		if source.GitHubID != nil && target.GitHubID == nil {
+			githubID := *source.GitHubID
+			merge.GitHubID = &githubID
+			// The unique index also covers soft-deleted rows, so release the ID before the target takes it.
+			if err := tx.Model(&source).Update("git_hub_id", nil).Error; err != nil {
+				return err
+			}
+			updates["git_hub_id"] = githubID
+		}
+		if len(updates) > 0 {
+			if err := tx.Model(&target).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&source).Update("merged_into_id", toID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&source).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return merge, nil
+}
+
+// mergedMaintainerFields returns the column updates that fill the target's missing fields from the source.
+func mergedMaintainerFields(source, target model.Maintainer) map[string]any {
+	updates := make(map[string]any)
+	missing := func(value, sentinel string) bool {
+		value = strings.TrimSpace(value)
+		return value == "" || value == sentinel
+	}
+	if strings.TrimSpace(target.Name) == "" && strings.TrimSpace(source.Name) != "" {
+		updates["name"] = source.Name
+	}
+	if missing(target.Email, "EMAIL_MISSING") && !missing(source.Email, "EMAIL_MISSING") {
+		updates["email"] = source.Email
+		updates["email_verified_at"] = source.EmailVerifiedAt
+	}
+	if missing(target.GitHubAccount, "GITHUB_MISSING") && !missing(source.GitHubAccount, "GITHUB_MISSING") {
+		updates["git_hub_account"] = source.GitHubAccount
+	}
+	if missing(target.GitHubEmail, "GITHUB_MISSING") && !missing(source.GitHubEmail, "GITHUB_MISSING") {
+		updates["git_hub_email"] = source.GitHubEmail
+	}
+	if target.CompanyID == nil && source.CompanyID != nil {
+		updates["company_id"] = *source.CompanyID
+	}
+	return updates
+}
+
+func mergeMaintainerMemberships(tx *gorm.DB, merge *MaintainerMerge) error {
+	var memberships []model.MaintainerProject
+	if err := tx.Where("maintainer_id = ?", merge.FromMaintainerID).Order("project_id").Find(&memberships).Error; err != nil {
+		return err
+	}
+	for _, membership := range memberships {
+		var existing int64
+		if err := tx.Model(&model.MaintainerProject{}).
+			Where("maintainer_id = ? AND project_id = ?", merge.ToMaintainerID, membership.ProjectID).
+			Count(&existing).Error; err != nil {
+			return err
+		}
+		scope := tx.Model(&model.MaintainerProject{}).
+			Where("maintainer_id = ? AND project_id = ?", merge.FromMaintainerID, membership.ProjectID)
+		if existing > 0 {
+			merge.DroppedMemberships = append(merge.DroppedMemberships, MergedMembership{
+				ProjectID: membership.ProjectID,
+				Status:    string(membership.Status),
+				Role:      string(membership.Role),
+				JoinedAt:  membership.JoinedAt,
+				LeftAt:    membership.LeftAt,
+			})
+			if err := scope.Delete(&model.MaintainerProject{}).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		if err := scope.Update("maintainer_id", merge.ToMaintainerID).Error; err != nil {
+			return err
+		}
+		merge.MovedProjectIDs = append(merge.MovedProjectIDs, membership.ProjectID)
+	}
+	return nil
+}
+
+func mergeMaintainerServiceTeams(tx *gorm.DB, merge *MaintainerMerge) error {
+	var links []model.ServiceUserTeams
+	if err := tx.Where("maintainer_id = ?", merge.FromMaintainerID).Order("id").Find(&links).Error; err != nil {
+		return err
+	}
+	for _, link := range links {
+		var existing int64
+		if err := tx.Model(&model.ServiceUserTeams{}).
+			Where("maintainer_id = ? AND service_team_id = ?", merge.ToMaintainerID, link.ServiceTeamID).
+			Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			if err := tx.Delete(&model.ServiceUserTeams{}, link.ID).Error; err != nil {
+				return err
+			}
+			merge.DroppedServiceUserTeamIDs = append(merge.DroppedServiceUserTeamIDs, link.ID)
+			continue
+		}
+		if err := tx.Model(&model.ServiceUserTeams{}).Where("id = ?", link.ID).Update("maintainer_id", merge.ToMaintainerID).Error; err != nil {
+			return err
+		}
+		merge.ServiceUserTeamIDs = append(merge.ServiceUserTeamIDs, link.ID)
+	}
+	return nil
+}
+
+// mergedMaintainer returns the live maintainer that a merged-away maintainer matching query was folded into, or nil
+// if no merged maintainer matches.
+func mergedMaintainer(tx *gorm.DB, query string, args ...any) (*model.Maintainer, error) {
+	var maintainer model.Maintainer
+	err := tx.Unscoped().Where("merged_into_id IS NOT NULL").Where(query, args...).Order("id").First(&maintainer).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < maxMergeChain && maintainer.MergedIntoID != nil; i++ {
+		nextID := *maintainer.MergedIntoID
+		maintainer = model.Maintainer{}
+		if err := tx.Unscoped().First(&maintainer, nextID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		if !maintainer.DeletedAt.Valid {
+			return &maintainer, nil
+		}
+	}
+	return nil, nil
+}
+
+// mergedMaintainerIDs returns ids together with the IDs of every maintainer merged into them, directly or through
+// earlier merges.
+func mergedMaintainerIDs(tx *gorm.DB, ids []uint) ([]uint, error) {
+	all := append([]uint{}, ids...)
+	frontier := ids
+	for i := 0; i < maxMergeChain && len(frontier) > 0; i++ {
+		var merged []uint
+		if err := tx.Unscoped().Model(&model.Maintainer{}).
+			Where("merged_into_id IN ?", frontier).
+			Pluck("id", &merged).Error; err != nil {
+			return nil, err
+		}
+		frontier = nil
+		for _, id := range merged {
+			if !containsUint(all, id) {
+				all = append(all, id)
+				frontier = append(frontier, id)
+			}
+		}
+	}
+	return all, nil
+}
+
+// MaintainerSummary identifies a maintainer in a merge suggestion.
+type MaintainerSummary struct {
+	ID            uint   `json:"id"`
+	Name          string `json:"name"`
+	Email         string `json:"email"`
+	GitHubAccount string `json:"github"`
+	CompanyID     *uint  `json:"companyId,omitempty"`
+	ProjectCount  int64  `json:"projectCount"`
+}
+
+// MaintainerMergeSuggestion pairs two maintainers that look like the same person. Target is the record to keep:
+// the one on more projects, then the one linked to a GitHub login, then the older one.
+type MaintainerMergeSuggestion struct {
+	Source  MaintainerSummary `json:"source"`
+	Target  MaintainerSummary `json:"target"`
+	Reasons []string          `json:"reasons"`
+	Score   float64           `json:"score"`
+}
+
+// FindMaintainerDuplicates returns pairs of maintainers sharing a GitHub handle, GitHub email or email address, or
+// with similar names at the same company, strongest matches first.
+func (s *SQLStore) FindMaintainerDuplicates() ([]MaintainerMergeSuggestion, error) {
+	var maintainers []model.Maintainer
+	if err := s.db.Order("id").Find(&maintainers).Error; err != nil {
+		return nil, err
+	}
+	var counts []struct {
+		MaintainerID uint
+		Count        int64
+	}
+	if err := s.db.Model(&model.MaintainerProject{}).
+		Select("maintainer_id, COUNT(*) AS count").
+		Group("maintainer_id").
+		Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+	projectCounts := make(map[uint]int64, len(counts))
+	for _, row := range counts {
+		projectCounts[row.MaintainerID] = row.Count
+	}
+
+	byID := make(map[uint]model.Maintainer, len(maintainers))
+	reasons := make(map[companyPair][]string)
+	scores := make(map[companyPair]float64)
+	addPair := func(a, b uint, reason string, score float64) {
+		pair := orderedPair(a, b)
+		if !containsString(reasons[pair], reason) {
+			reasons[pair] = append(reasons[pair], reason)
+		}
+		if score > scores[pair] {
+			scores[pair] = score
+		}
+	}
+	bucket := func(reason string, key func(model.Maintainer) string) {
+		groups := make(map[string][]uint)
+		for _, m := range maintainers {
+			if k := key(m); k != "" {
+				groups[k] = append(groups[k], m.ID)
+			}
+		}
+		for _, ids := range groups {
+			for i := range ids {
+				for j := i + 1; j < len(ids); j++ {
+					addPair(ids[i], ids[j], reason, 1)
+				}
+			}
+		}
+	}
+	identifier := func(value string, sentinels ...string) string {
+		value = strings.ToLower(strings.TrimSpace(value))
+		for _, sentinel := range sentinels {
+			if value == strings.ToLower(sentinel) {
+				return ""
+			}
+		}
+		return value
+	}
+
+	byCompany := make(map[uint][]model.Maintainer)
+	for _, m := range maintainers {
+		byID[m.ID] = m
+		if m.CompanyID != nil && strings.TrimSpace(m.Name) != "" {
+			byCompany[*m.CompanyID] = append(byCompany[*m.CompanyID], m)
+		}
+	}
+	bucket(DuplicateGitHub, func(m model.Maintainer) string { return identifier(m.GitHubAccount, "GITHUB_MISSING") })
+	bucket(DuplicateGitHubEmail, func(m model.Maintainer) string {
+		return identifier(m.GitHubEmail, "GITHUB_MISSING", "GITHUB_EMAIL_MISSING")
+	})
+	bucket(DuplicateEmail, func(m model.Maintainer) string { return identifier(m.Email, "EMAIL_MISSING") })
+	for _, colleagues := range byCompany {
+		for i := range colleagues {
+			for j := i + 1; j < len(colleagues); j++ {
+				score := canonicalSimilarity(NormalizeCompanyName(colleagues[i].Name), NormalizeCompanyName(colleagues[j].Name))
+				if score >= maintainerNameSimilarityThreshold {
+					addPair(colleagues[i].ID, colleagues[j].ID, DuplicateNameAndCompany, score)
+				}
+			}
+		}
+	}
+
+	summary := func(m model.Maintainer) MaintainerSummary {
+		return MaintainerSummary{
+			ID:            m.ID,
+			Name:          strings.TrimSpace(m.Name),
+			Email:         m.Email,
+			GitHubAccount: m.GitHubAccount,
+			CompanyID:     m.CompanyID,
+			ProjectCount:  projectCounts[m.ID],
+		}
+	}
+	suggestions := make([]MaintainerMergeSuggestion, 0, len(reasons))
+	for pair, why := range reasons {
+		older, newer := byID[pair.a], byID[pair.b]
+		target, source := older, newer
+		switch {
+		case projectCounts[newer.ID] > projectCounts[older.ID]:
+			target, source = newer, older
+		case projectCounts[newer.ID] == projectCounts[older.ID] && newer.GitHubID != nil && older.GitHubID == nil:
+			target, source = newer, older
+		}
+		sort.Strings(why)
+		suggestions = append(suggestions, MaintainerMergeSuggestion{
+			Source:  summary(source),
+			Target:  summary(target),
+			Reasons: why,
+			Score:   scores[pair],
+		})
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		si, sj := suggestions[i], suggestions[j]
+		if si.Score != sj.Score {
+			return si.Score > sj.Score
+		}
+		if len(si.Reasons) != len(sj.Reasons) {
+			return len(si.Reasons) > len(sj.Reasons)
+		}
+		pi, pj := orderedPair(si.Source.ID, si.Target.ID), orderedPair(sj.Source.ID, sj.Target.ID)
+		if pi.a != pj.a {
+			return pi.a < pj.a
+		}
+		return pi.b < pj.b
+	})
+	return suggestions, nil
+}
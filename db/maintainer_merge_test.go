@@ -0,0 +1,148 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"maintainerd/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestFindMaintainerDuplicates(t *testing.T) {
+	db := setupTestDB(t)
+	store := NewSQLStore(db)
+	company, project1, _, alice, bob, _ := seedTestData(t, db)
+	handle := model.Maintainer{Name: "A. Developer", Email: "adev@example.org", GitHubAccount: "ALICE", MaintainerStatus: model.ActiveMaintainer}
+	require.NoError(t, db.Create(&handle).Error)
+	namesake := model.Maintainer{Name: "Bob  engineer", Email: "robert@example.com", MaintainerStatus: model.ActiveMaintainer, CompanyID: &company.ID}
+	require.NoError(t, db.Create(&namesake).Error)
+	elsewhere := model.Maintainer{Name: "Bob Engineer", Email: "bob@other.org", MaintainerStatus: model.ActiveMaintainer}
+	require.NoError(t, db.Create(&elsewhere).Error)
+	require.NoError(t, db.Model(&project1).Association("Maintainers").Append(&handle))
+
+	suggestions, err := store.FindMaintainerDuplicates()
+	require.NoError(t, err)
+	require.Len(t, suggestions, 2, "a shared name alone, without a shared company, is not a match")
+
+	byReason := map[string]MaintainerMergeSuggestion{}
+	for _, suggestion := range suggestions {
+		require.Len(t, suggestion.Reasons, 1)
+		byReason[suggestion.Reasons[0]] = suggestion
+	}
+	github := byReason[DuplicateGitHub]
+	assert.Equal(t, alice.ID, github.Target.ID, "the older record wins a tie")
+	assert.Equal(t, handle.ID, github.Source.ID)
+	named := byReason[DuplicateNameAndCompany]
+	assert.Equal(t, bob.ID, named.Target.ID, "the record on more projects is kept")
+	assert.Equal(t, namesake.ID, named.Source.ID)
+	assert.Equal(t, 1.0, named.Score)
+}
+
+func TestMergeMaintainersAndRevert(t *testing.T) {
+	db, store, _ := setupAuditedStore(t)
+	company, project1, _, _, bob, _ := seedTestData(t, db)
+	project3 := model.Project{Name: "envoy", Maturity: model.Graduated}
+	require.NoError(t, db.Create(&project3).Error)
+	githubID := int64(42)
+	dup := model.Maintainer{
+		Name:             "Bob Engineer",
+		Email:            "robert@example.com",
+		GitHubAccount:    "GITHUB_MISSING",
+		GitHubEmail:      "bob@users.noreply.github.com",
+		GitHubID:         &githubID,
+		MaintainerStatus: model.ActiveMaintainer,
+		CompanyID:        &company.ID,
+	}
+	require.NoError(t, db.Create(&dup).Error)
+	require.NoError(t, db.Model(&dup).Association("Projects").Append(&project1, &project3))
+	history := model.MembershipHistory{MaintainerID: dup.ID, ProjectID: project3.ID, Event: "JOIN", CreatedAt: time.Now()}
+	require.NoError(t, db.Create(&history).Error)
+	require.NoError(t, db.Create(&model.MaintainerRefCache{ProjectID: project3.ID, ETag: "abc"}).Error)
+
+	shared := model.ServiceTeam{ProjectID: project1.ID, ServiceID: 1, ServiceTeamID: 10}
+	own := model.ServiceTeam{ProjectID: project3.ID, ServiceID: 1, ServiceTeamID: 30}
+	require.NoError(t, db.Create(&shared).Error)
+	require.NoError(t, db.Create(&own).Error)
+	bobLink := model.ServiceUserTeams{ServiceID: 1, ServiceUserID: 1, ServiceTeamID: shared.ID, MaintainerID: &bob.ID}
+	dupShared := model.ServiceUserTeams{ServiceID: 1, ServiceUserID: 2, ServiceTeamID: shared.ID, MaintainerID: &dup.ID}
+	dupOwn := model.ServiceUserTeams{ServiceID: 1, ServiceUserID: 2, ServiceTeamID: own.ID, MaintainerID: &dup.ID}
+	for _, link := range []*model.ServiceUserTeams{&bobLink, &dupShared, &dupOwn} {
+		require.NoError(t, db.Create(link).Error)
+	}
+	require.NoError(t, store.AppendAuditLog(&model.AuditLog{Action: "INVITE_SENT", MaintainerID: &dup.ID}))
+
+	merge, err := store.MergeMaintainers(dup.ID, bob.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{project3.ID}, merge.MovedProjectIDs)
+	require.Len(t, merge.DroppedMemberships, 1)
+	assert.Equal(t, project1.ID, merge.DroppedMemberships[0].ProjectID)
+	assert.Equal(t, []uint{history.ID}, merge.HistoryIDs)
+	assert.Equal(t, []uint{dupOwn.ID}, merge.ServiceUserTeamIDs)
+	assert.Equal(t, []uint{dupShared.ID}, merge.DroppedServiceUserTeamIDs)
+
+	var merged model.Maintainer
+	require.NoError(t, db.First(&merged, bob.ID).Error)
+	assert.Equal(t, "bob@users.noreply.github.com", merged.GitHubEmail, "missing fields are filled from the duplicate")
+	assert.Equal(t, "bob@example.com", merged.Email, "the target's own fields win")
+	require.NotNil(t, merged.GitHubID)
+	assert.Equal(t, githubID, *merged.GitHubID)
+	var gone model.Maintainer
+	require.NoError(t, db.Unscoped().First(&gone, dup.ID).Error)
+	assert.True(t, gone.DeletedAt.Valid)
+	require.NotNil(t, gone.MergedIntoID)
+	assert.Equal(t, bob.ID, *gone.MergedIntoID)
+	var caches int64
+	require.NoError(t, db.Model(&model.MaintainerRefCache{}).Count(&caches).Error)
+	assert.Zero(t, caches)
+
+	entries := auditEntries(t, db, "MAINTAINER_MERGE")
+	require.Len(t, entries, 1)
+	assert.Equal(t, "Maintainer Bob Engineer merged into Bob Engineer by Sam Staff", entries[0].Message)
+	assert.Equal(t, AuditChange{From: "GITHUB_MISSING", To: "bob@users.noreply.github.com"}, auditMetadata(t, entries[0]).Changes["githubEmail"])
+	logs, _, err := store.ListAuditLogs(AuditFilter{MaintainerIDs: []uint{bob.ID}, Actions: []string{"INVITE_SENT"}}, 10, 0)
+	require.NoError(t, err)
+	assert.Len(t, logs, 1, "audit entries of the duplicate are listed under the survivor")
+
+	again, err := store.UpsertMaintainer(project3.ID, "Robert", "Robert@example.com", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, bob.ID, again.ID, "importing the merged email resolves to the survivor")
+
+	_, err = store.RevertAuditEntry(entries[0].ID)
+	require.NoError(t, err)
+	var restored model.Maintainer
+	require.NoError(t, db.First(&restored, dup.ID).Error)
+	assert.Nil(t, restored.MergedIntoID)
+	require.NotNil(t, restored.GitHubID)
+	assert.Equal(t, githubID, *restored.GitHubID)
+	require.NoError(t, db.First(&merged, bob.ID).Error)
+	assert.Nil(t, merged.GitHubID)
+	assert.Equal(t, "GITHUB_MISSING", merged.GitHubEmail)
+	assert.ElementsMatch(t, []uint{project1.ID, project3.ID}, membershipProjectIDs(t, db, dup.ID))
+	require.NoError(t, db.First(&history, history.ID).Error)
+	assert.Equal(t, dup.ID, history.MaintainerID)
+	var links []model.ServiceUserTeams
+	require.NoError(t, db.Where("maintainer_id = ?", dup.ID).Order("id").Find(&links).Error)
+	require.Len(t, links, 2)
+}
+
+func TestMergeMaintainersRevertConflict(t *testing.T) {
+	db, store, _ := setupAuditedStore(t)
+	_, _, project2, alice, _, charlie := seedTestData(t, db)
+
+	_, err := store.MergeMaintainers(charlie.ID, alice.ID)
+	require.NoError(t, err)
+	require.NoError(t, db.Where("maintainer_id = ? AND project_id = ?", alice.ID, project2.ID).Delete(&model.MaintainerProject{}).Error)
+
+	_, err = store.RevertAuditEntry(auditEntries(t, db, "MAINTAINER_MERGE")[0].ID)
+	assert.ErrorIs(t, err, ErrRevertConflict)
+}
+
+func membershipProjectIDs(t *testing.T, db *gorm.DB, maintainerID uint) []uint {
+	t.Helper()
+	var ids []uint
+	require.NoError(t, db.Model(&model.MaintainerProject{}).Where("maintainer_id = ?", maintainerID).Pluck("project_id", &ids).Error)
+	return ids
+}
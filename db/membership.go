@@ -0,0 +1,279 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"maintainerd/model"
+
+	"gorm.io/gorm"
+)
+
+var ErrMembershipNotFound = errors.New("membership not found")
+
+// GetProjectMemberships returns the memberships of a project with their maintainers preloaded.
+func (s *SQLStore) GetProjectMemberships(projectID uint) ([]model.MaintainerProject, error) {
+	var memberships []model.MaintainerProject
+	err := s.db.
+		Preload("Maintainer.Company").
+		Where("project_id = ?", projectID).
+		Order("joined_at").
+		Find(&memberships).Error
+	return memberships, err
+}
+
+// GetMaintainerMemberships returns a maintainer's memberships with their projects preloaded.
+func (s *SQLStore) GetMaintainerMemberships(maintainerID uint) ([]model.MaintainerProject, error) {
+	var memberships []model.MaintainerProject
+	err := s.db.
+		Preload("Project").
+		Where("maintainer_id = ?", maintainerID).
+		Order("joined_at").
+		Find(&memberships).Error
+	return memberships, err
+}
+
+// ListMemberships returns every membership with its project and maintainer preloaded.
+func (s *SQLStore) ListMemberships() ([]model.MaintainerProject, error) {
+	var memberships []model.MaintainerProject
+	err := s.db.
+		Joins("Project").
+		Joins("Maintainer").
+		Order("maintainer_projects.project_id, maintainer_projects.maintainer_id").
+		Find(&memberships).Error
+	return memberships, err
+}
+
+// GetMembership returns one membership.
+func (s *SQLStore) GetMembership(maintainerID, projectID uint) (*model.MaintainerProject, error) {
+	var membership model.MaintainerProject
+	err := s.db.Where("maintainer_id = ? AND project_id = ?", maintainerID, projectID).First(&membership).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrMembershipNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &membership, nil
+}
+
+// GetMembershipHistory returns the recorded changes to a membership, oldest first.
+func (s *SQLStore) GetMembershipHistory(maintainerID, projectID uint) ([]model.MembershipHistory, error) {
+	var history []model.MembershipHistory
+	err := s.db.
+		Where("maintainer_id = ? AND project_id = ?", maintainerID, projectID).
+		Order("created_at, id").
+		Find(&history).Error
+	return history, err
+}
+
+// UpdateMembership changes the status and/or role of a membership and records each change in the membership
+// history. An empty status or role leaves that field unchanged.
+func (s *SQLStore) UpdateMembership(maintainerID, projectID uint, status model.MaintainerStatus, role model.MembershipRole) (*model.MaintainerProject, error) {
+	if status != "" && !status.IsValid() {
+		return nil, fmt.Errorf("invalid maintainer status %q", status)
+	}
+	if role != "" && !role.IsValid() {
+		return nil, fmt.Errorf("invalid membership role %q", role)
+	}
+	var membership *model.MaintainerProject
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		current, err := NewSQLStore(tx).GetMembership(maintainerID, projectID)
+		if err != nil {
+			return err
+		}
+		updates := map[string]any{}
+		now := time.Now()
+		if status != "" && status != current.Status {
+			updates["status"] = status
+			switch {
+			case status == model.ActiveMaintainer:
+				updates["left_at"] = nil
+			case current.Status == model.ActiveMaintainer || current.LeftAt == nil:
+				updates["left_at"] = now
+			}
+			if err := s.recordMembershipHistory(tx, model.MembershipHistory{
+				MaintainerID: maintainerID,
+				ProjectID:    projectID,
+				Event:        "STATUS_CHANGE",
+				FromStatus:   string(current.Status),
+				ToStatus:     string(status),
+				CreatedAt:    now,
+			}); err != nil {
+				return err
+			}
+		}
+		if role != "" && role != current.Role {
+			updates["role"] = role
+			if err := s.recordMembershipHistory(tx, model.MembershipHistory{
+				MaintainerID: maintainerID,
+				ProjectID:    projectID,
+				Event:        "ROLE_CHANGE",
+				FromRole:     string(current.Role),
+				ToRole:       string(role),
+				CreatedAt:    now,
+			}); err != nil {
+				return err
+			}
+		}
+		if len(updates) > 0 {
+			if err := tx.Model(&model.MaintainerProject{}).
+				Where("maintainer_id = ? AND project_id = ?", maintainerID, projectID).
+				Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+		membership, err = NewSQLStore(tx).GetMembership(maintainerID, projectID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return membership, nil
+}
+
+// recordMembershipHistory inserts a history entry attributed to the store's actor and source, if it has them.
+func (s *SQLStore) recordMembershipHistory(tx *gorm.DB, entry model.MembershipHistory) error {
+	entry.ActorLogin = s.actorLogin
+	entry.Source = s.membershipSource
+	return tx.Create(&entry).Error
+}
+
+// BackfillMembershipHistory gives memberships that predate per-project status their maintainer's overall status and
+// a JOIN history entry. Memberships that already have history are left alone, so it is safe to run repeatedly.
+func (s *SQLStore) BackfillMembershipHistory() (int, error) {
+	var memberships []model.MaintainerProject
+	if err := s.db.
+		Preload("Maintainer", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Where("NOT EXISTS (SELECT 1 FROM membership_histories h WHERE h.maintainer_id = maintainer_projects.maintainer_id AND h.project_id = maintainer_projects.project_id)").
+		Find(&memberships).Error; err != nil {
+		return 0, err
+	}
+	for _, membership := range memberships {
+		status := membership.Maintainer.MaintainerStatus
+		if !status.IsValid() {
+			status = model.ActiveMaintainer
+		}
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			if status != membership.Status {
+				updates := map[string]any{"status": status}
+				if status != model.ActiveMaintainer {
+					updates["left_at"] = membership.Maintainer.UpdatedAt
+				}
+				if err := tx.Model(&model.MaintainerProject{}).
+					Where("maintainer_id = ? AND project_id = ?", membership.MaintainerID, membership.ProjectID).
+					Updates(updates).Error; err != nil {
+					return err
+				}
+			}
+			role := membership.Role
+			if role == "" {
+				role = model.MaintainerRole
+			}
+			return tx.Create(&model.MembershipHistory{
+				MaintainerID: membership.MaintainerID,
+				ProjectID:    membership.ProjectID,
+				Event:        "JOIN",
+				ToStatus:     string(status),
+				ToRole:       string(role),
+				ActorLogin:   "backfill",
+				CreatedAt:    membership.JoinedAt,
+			}).Error
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(memberships), nil
+}
+
+// MembershipRemoval describes a maintainer detached from a project.
+type MembershipRemoval struct {
+	Membership model.MaintainerProject
+	// ServiceTeams are the maintainer's links to the project's service teams that were removed with the membership.
+	ServiceTeams []model.ServiceUserTeams
+}
+
+// RemoveMaintainerFromProject detaches a maintainer from one project, leaving the maintainer and their other
+// memberships in place, and records a LEAVE entry in the membership history. If cascade is set, the maintainer's
+// links to the project's service teams are removed too; they are returned so the caller can revoke the access on
+// the remote service.
+func (s *SQLStore) RemoveMaintainerFromProject(projectID, maintainerID uint, cascade bool) (*MembershipRemoval, error) {
+	removals, err := s.RemoveMaintainersFromProject(projectID, []uint{maintainerID}, cascade)
+	if err != nil {
+		return nil, err
+	}
+	return &removals[0], nil
+}
+
+// RemoveMaintainersFromProject detaches each maintainer from the project in one transaction. It fails with
+// ErrMembershipNotFound, removing nobody, if any of them is not a member.
+func (s *SQLStore) RemoveMaintainersFromProject(projectID uint, maintainerIDs []uint, cascade bool) ([]MembershipRemoval, error) {
+	removals := make([]MembershipRemoval, 0, len(maintainerIDs))
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		removals = removals[:0]
+		for _, maintainerID := range maintainerIDs {
+			removal, err := s.removeMembership(tx, projectID, maintainerID, cascade)
+			if err != nil {
+				return err
+			}
+			removals = append(removals, *removal)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return removals, nil
+}
+
+func (s *SQLStore) removeMembership(tx *gorm.DB, projectID, maintainerID uint, cascade bool) (*MembershipRemoval, error) {
+	var membership model.MaintainerProject
+	err := tx.
+		Preload("Maintainer").
+		Where("maintainer_id = ? AND project_id = ?", maintainerID, projectID).
+		First(&membership).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: maintainer %d on project %d", ErrMembershipNotFound, maintainerID, projectID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	removal := &MembershipRemoval{Membership: membership}
+	if cascade {
+		var teams []model.ServiceTeam
+		if err := tx.Where("project_id = ?", projectID).Find(&teams).Error; err != nil {
+			return nil, err
+		}
+		for _, team := range teams {
+			var links []model.ServiceUserTeams
+			if err := tx.Where("maintainer_id = ? AND service_team_id = ?", maintainerID, team.ID).Find(&links).Error; err != nil {
+				return nil, err
+			}
+			for _, link := range links {
+				if err := tx.Delete(&model.ServiceUserTeams{}, link.ID).Error; err != nil {
+					return nil, err
+				}
+				// The association is ambiguous to GORM, so the team is attached here rather than preloaded.
+				link.ServiceTeam = team
+				removal.ServiceTeams = append(removal.ServiceTeams, link)
+			}
+		}
+	}
+	if err := tx.Where("maintainer_id = ? AND project_id = ?", maintainerID, projectID).
+		Delete(&model.MaintainerProject{}).Error; err != nil {
+		return nil, err
+	}
+	if err := s.recordMembershipHistory(tx, model.MembershipHistory{
+		MaintainerID: maintainerID,
+		ProjectID:    projectID,
+		Event:        "LEAVE",
+		FromStatus:   string(membership.Status),
+		FromRole:     string(membership.Role),
+		CreatedAt:    time.Now(),
+	}); err != nil {
+		return nil, err
+	}
+	return removal, nil
+}
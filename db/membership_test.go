@@ -0,0 +1,217 @@
+package db
+
+import (
+	"testing"
+
+	"maintainerd/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateMembership(t *testing.T) {
+	db := setupTestDB(t)
+	store := NewSQLStore(db)
+	_, project1, project2, _, bob, _ := seedTestData(t, db)
+
+	membership, err := store.GetMembership(bob.ID, project1.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.ActiveMaintainer, membership.Status)
+	assert.Equal(t, model.MaintainerRole, membership.Role)
+	assert.Nil(t, membership.LeftAt)
+
+	updated, err := store.UpdateMembership(bob.ID, project1.ID, model.EmeritusMaintainer, model.LeadRole)
+	require.NoError(t, err)
+	assert.Equal(t, model.EmeritusMaintainer, updated.Status)
+	assert.Equal(t, model.LeadRole, updated.Role)
+	require.NotNil(t, updated.LeftAt)
+
+	other, err := store.GetMembership(bob.ID, project2.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.ActiveMaintainer, other.Status, "status is per project")
+
+	updated, err = store.UpdateMembership(bob.ID, project1.ID, model.ActiveMaintainer, "")
+	require.NoError(t, err)
+	assert.Nil(t, updated.LeftAt)
+	assert.Equal(t, model.LeadRole, updated.Role)
+
+	history, err := store.GetMembershipHistory(bob.ID, project1.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	assert.Equal(t, "STATUS_CHANGE", history[0].Event)
+	assert.Equal(t, "Active", history[0].FromStatus)
+	assert.Equal(t, "Emeritus", history[0].ToStatus)
+	assert.Equal(t, "ROLE_CHANGE", history[1].Event)
+	assert.Equal(t, "lead", history[1].ToRole)
+	assert.Equal(t, "Active", history[2].ToStatus)
+
+	_, err = store.UpdateMembership(bob.ID, project1.ID, "", "owner")
+	assert.Error(t, err)
+	_, err = store.UpdateMembership(bob.ID, 9999, model.RetiredMaintainer, "")
+	assert.ErrorIs(t, err, ErrMembershipNotFound)
+}
+
+func TestUpsertMaintainerRecordsJoin(t *testing.T) {
+	db := setupTestDB(t)
+	store := NewSQLStore(db)
+	_, project1, project2, alice, _, _ := seedTestData(t, db)
+
+	_, err := store.UpsertMaintainer(project2.ID, "", "", "alice", "")
+	require.NoError(t, err)
+	_, err = store.UpsertMaintainer(project1.ID, "", "", "alice", "")
+	require.NoError(t, err)
+
+	history, err := store.GetMembershipHistory(alice.ID, project2.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, "JOIN", history[0].Event)
+
+	history, err = store.GetMembershipHistory(alice.ID, project1.ID)
+	require.NoError(t, err)
+	assert.Empty(t, history, "existing memberships are not re-joined")
+}
+
+func TestBackfillMembershipHistory(t *testing.T) {
+	db := setupTestDB(t)
+	store := NewSQLStore(db)
+	_, _, project2, _, _, charlie := seedTestData(t, db)
+
+	count, err := store.BackfillMembershipHistory()
+	require.NoError(t, err)
+	assert.Equal(t, 4, count)
+
+	membership, err := store.GetMembership(charlie.ID, project2.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.EmeritusMaintainer, membership.Status, "inherits the overall status")
+	assert.NotNil(t, membership.LeftAt)
+
+	count, err = store.BackfillMembershipHistory()
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
+
+func TestAuditedUpdateMembershipRevert(t *testing.T) {
+	db, store, _ := setupAuditedStore(t)
+	_, project, _, alice, _, _ := seedTestData(t, db)
+
+	_, err := store.UpdateMembership(alice.ID, project.ID, model.RetiredMaintainer, model.SecurityContactRole)
+	require.NoError(t, err)
+	entries := auditEntries(t, db, "MEMBERSHIP_UPDATE")
+	require.Len(t, entries, 1)
+	assert.Equal(t, "Membership [leftAt, role, status] updated by Sam Staff", entries[0].Message)
+	assert.Equal(t, project.ID, *entries[0].ProjectID)
+	assert.Equal(t, alice.ID, *entries[0].MaintainerID)
+
+	history, err := store.GetMembershipHistory(alice.ID, project.ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, history)
+	assert.Equal(t, "sam", history[0].ActorLogin)
+
+	_, err = store.RevertAuditEntry(entries[0].ID)
+	require.NoError(t, err)
+	membership, err := store.GetMembership(alice.ID, project.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.ActiveMaintainer, membership.Status)
+	assert.Equal(t, model.MaintainerRole, membership.Role)
+	assert.Nil(t, membership.LeftAt)
+}
+
+func TestRemoveMaintainerFromProject(t *testing.T) {
+	db := setupTestDB(t)
+	store := NewSQLStore(db)
+	_, project1, project2, _, bob, _ := seedTestData(t, db)
+
+	service := model.Service{Name: "FOSSA"}
+	require.NoError(t, db.Create(&service).Error)
+	team1 := model.ServiceTeam{ProjectID: project1.ID, ServiceID: service.ID, ServiceTeamID: 101}
+	team2 := model.ServiceTeam{ProjectID: project2.ID, ServiceID: service.ID, ServiceTeamID: 102}
+	require.NoError(t, db.Create(&team1).Error)
+	require.NoError(t, db.Create(&team2).Error)
+	for _, team := range []model.ServiceTeam{team1, team2} {
+		require.NoError(t, db.Create(&model.ServiceUserTeams{ServiceID: service.ID, ServiceTeamID: team.ID, MaintainerID: &bob.ID}).Error)
+	}
+
+	removal, err := store.RemoveMaintainerFromProject(project1.ID, bob.ID, true)
+	require.NoError(t, err)
+	assert.Equal(t, "bob@example.com", removal.Membership.Maintainer.Email)
+	require.Len(t, removal.ServiceTeams, 1)
+	assert.Equal(t, 101, removal.ServiceTeams[0].ServiceTeam.ServiceTeamID)
+
+	_, err = store.GetMembership(bob.ID, project1.ID)
+	assert.ErrorIs(t, err, ErrMembershipNotFound)
+	_, err = store.GetMembership(bob.ID, project2.ID)
+	assert.NoError(t, err, "other memberships are kept")
+	var maintainer model.Maintainer
+	require.NoError(t, db.First(&maintainer, bob.ID).Error)
+	assert.Equal(t, model.ActiveMaintainer, maintainer.MaintainerStatus)
+
+	var links []model.ServiceUserTeams
+	require.NoError(t, db.Where("maintainer_id = ?", bob.ID).Find(&links).Error)
+	require.Len(t, links, 1)
+	assert.Equal(t, team2.ID, links[0].ServiceTeamID)
+
+	history, err := store.GetMembershipHistory(bob.ID, project1.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, "LEAVE", history[0].Event)
+	assert.Equal(t, "Active", history[0].FromStatus)
+
+	_, err = store.RemoveMaintainerFromProject(project1.ID, bob.ID, false)
+	assert.ErrorIs(t, err, ErrMembershipNotFound)
+}
+
+func TestRemoveMaintainersFromProjectIsAtomic(t *testing.T) {
+	db := setupTestDB(t)
+	store := NewSQLStore(db)
+	_, project1, _, alice, bob, charlie := seedTestData(t, db)
+
+	_, err := store.RemoveMaintainersFromProject(project1.ID, []uint{alice.ID, charlie.ID}, false)
+	assert.ErrorIs(t, err, ErrMembershipNotFound, "charlie is not on the project")
+	_, err = store.GetMembership(alice.ID, project1.ID)
+	assert.NoError(t, err, "nobody is removed when one removal fails")
+
+	removals, err := store.RemoveMaintainersFromProject(project1.ID, []uint{alice.ID, bob.ID}, false)
+	require.NoError(t, err)
+	assert.Len(t, removals, 2)
+	maintainers, err := store.GetMaintainersByProject(project1.ID)
+	require.NoError(t, err)
+	assert.Empty(t, maintainers)
+}
+
+func TestAuditedRemoveMaintainersFromProjectRevert(t *testing.T) {
+	db, store, _ := setupAuditedStore(t)
+	_, project, _, alice, bob, _ := seedTestData(t, db)
+
+	_, err := store.UpdateMembership(bob.ID, project.ID, "", model.LeadRole)
+	require.NoError(t, err)
+	_, err = store.RemoveMaintainersFromProject(project.ID, []uint{alice.ID, bob.ID}, false)
+	require.NoError(t, err)
+	entries := auditEntries(t, db, "MEMBERSHIP_REMOVE")
+	require.Len(t, entries, 2)
+	assert.Equal(t, "Maintainer removed from project by Sam Staff", entries[0].Message)
+	metadata := auditMetadata(t, entries[1])
+	require.NotNil(t, metadata.Batch)
+	assert.Equal(t, AuditChange{From: "lead"}, metadata.Changes["role"])
+
+	reverts, err := store.RevertAuditEntry(entries[0].ID)
+	require.NoError(t, err)
+	assert.Len(t, reverts, 2)
+	restored, err := store.GetMembership(bob.ID, project.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.LeadRole, restored.Role)
+	_, err = store.GetMembership(alice.ID, project.ID)
+	assert.NoError(t, err)
+}
+
+func TestAuditedRemoveWithServiceTeamsIsNotReverted(t *testing.T) {
+	db, store, _ := setupAuditedStore(t)
+	_, project, _, alice, _, _ := seedTestData(t, db)
+	team := model.ServiceTeam{ProjectID: project.ID, ServiceID: 1, ServiceTeamID: 7}
+	require.NoError(t, db.Create(&team).Error)
+	require.NoError(t, db.Create(&model.ServiceUserTeams{ServiceID: 1, ServiceTeamID: team.ID, MaintainerID: &alice.ID}).Error)
+
+	_, err := store.RemoveMaintainerFromProject(project.ID, alice.ID, true)
+	require.NoError(t, err)
+	_, err = store.RevertAuditEntry(auditEntries(t, db, "MEMBERSHIP_REMOVE")[0].ID)
+	assert.ErrorIs(t, err, ErrRevertUnsupported)
+}
@@ -0,0 +1,366 @@
+package db
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"maintainerd/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// schemaMigrationsTable records the migrations applied to a database, one row per version.
+const schemaMigrationsTable = "schema_migrations"
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+var (
+	ErrUnknownMigration = errors.New("unknown migration version")
+	// ErrMigrationGap is returned when a database has a migration applied that this build does not know about,
+	// which usually means it was migrated by a newer build.
+	ErrMigrationGap = errors.New("database has migrations this build does not know")
+)
+
+// Migration is one versioned schema change. Up and Down steps can differ per dialect ("sqlite" or "postgres");
+// a migration without a step for a dialect is recorded as applied without doing anything there.
+//
+// Each migration is a pair of SQL files in db/migrations named NNNN_name.up.sql and NNNN_name.down.sql, or
+// NNNN_name.<dialect>.up.sql for a change that only applies to, or differs on, one dialect. Migration 1 is the
+// baseline schema the models defined when versioned migrations were introduced; applied migrations are never
+// edited, so model changes need a new migration. On a database AutoMigrate created earlier, the baseline adds the
+// columns its existing tables lack (see adoptTable).
+type Migration struct {
+	Version int
+	Name    string
+
+	up   map[string]migrationStep
+	down map[string]migrationStep
+}
+
+type migrationStep func(tx *gorm.DB) error
+
+// MigrationStatus reports whether a migration has been applied to a database.
+type MigrationStatus struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"appliedAt,omitempty"`
+}
+
+// schemaMigration is a row of schema_migrations.
+type schemaMigration struct {
+	Version   int       `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (schemaMigration) TableName() string { return schemaMigrationsTable }
+
+// schemaModels lists every table the application uses, parents before children.
+func schemaModels() []any {
+	return []any{
+		&model.Company{},
+		&model.CompanyDomain{},
+		&model.CompanyAlias{},
+		&model.Foundation{},
+		&model.Project{},
+		&model.ProjectAlias{},
+		&model.ProjectMaturityEvent{},
+		&model.Maintainer{},
+		&model.MaintainerProject{},
+		&model.MembershipHistory{},
+		&model.MaintainerRefCache{},
+		&model.StaffMember{},
+		&model.FoundationOfficer{},
+		&model.Collaborator{},
+		&model.CollaboratorProject{},
+		&model.Service{},
+		&model.ServiceTeam{},
+		&model.ServiceUser{},
+		&model.ServiceUserTeams{},
+		&model.AuditLog{},
+	}
+}
+
+// Migrations returns every known migration in version order.
+func Migrations() ([]Migration, error) {
+	byVersion := map[int]*Migration{}
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		version, name, dialect, direction, err := parseMigrationFile(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		content, err := migrationFiles.ReadFile(path.Join("migrations", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: name, up: map[string]migrationStep{}, down: map[string]migrationStep{}}
+			byVersion[version] = migration
+		}
+		if migration.Name != name {
+			return nil, fmt.Errorf("migration %d is named both %q and %q", version, migration.Name, name)
+		}
+		steps := migration.up
+		if direction == "down" {
+			steps = migration.down
+		}
+		steps[dialect] = sqlStep(string(content))
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// parseMigrationFile splits NNNN_name[.dialect].(up|down).sql.
+func parseMigrationFile(file string) (version int, name, dialect, direction string, err error) {
+	parts := strings.Split(strings.TrimSuffix(file, ".sql"), ".")
+	if len(parts) < 2 || len(parts) > 3 || (parts[len(parts)-1] != "up" && parts[len(parts)-1] != "down") {
+		return 0, "", "", "", fmt.Errorf("migration file %s: want NNNN_name[.dialect].(up|down).sql", file)
+	}
+	direction = parts[len(parts)-1]
+	if len(parts) == 3 {
+		dialect = parts[1]
+		if dialect != "sqlite" && dialect != "postgres" {
+			return 0, "", "", "", fmt.Errorf("migration file %s: unknown dialect %q", file, dialect)
+		}
+	}
+	number, name, ok := strings.Cut(parts[0], "_")
+	if version, err = strconv.Atoi(number); !ok || err != nil || version < 1 || name == "" {
+		return 0, "", "", "", fmt.Errorf("migration file %s: want a positive version and a name", file)
+	}
+	return version, name, dialect, direction, nil
+}
+
+// step returns the step for dialect, falling back to the step shared by every dialect.
+func (m Migration) step(steps map[string]migrationStep, dialect string) migrationStep {
+	if step, ok := steps[dialect]; ok {
+		return step
+	}
+	return steps[""]
+}
+
+// HasDown reports whether the migration can be rolled back on dialect.
+func (m Migration) HasDown(dialect string) bool {
+	return m.step(m.up, dialect) == nil || m.step(m.down, dialect) != nil
+}
+
+func sqlStep(script string) migrationStep {
+	return func(tx *gorm.DB) error {
+		for _, statement := range splitSQLStatements(script) {
+			if err := adoptTable(tx, statement); err != nil {
+				return fmt.Errorf("%w\n%s", err, statement)
+			}
+			if err := tx.Exec(statement).Error; err != nil {
+				return fmt.Errorf("%w\n%s", err, statement)
+			}
+		}
+		return nil
+	}
+}
+
+// createTablePattern matches the first line of a CREATE TABLE IF NOT EXISTS statement and captures the table name.
+var createTablePattern = regexp.MustCompile("^CREATE TABLE IF NOT EXISTS [`\"](\\w+)[`\"] \\($")
+
+// adoptTable adds the columns a CREATE TABLE IF NOT EXISTS statement defines to the table when it already exists
+// without them, which IF NOT EXISTS alone skips. This is how the baseline adopts a database that AutoMigrate created
+// with older models. It relies on the migrations' layout of one column definition per line.
+func adoptTable(tx *gorm.DB, statement string) error {
+	lines := strings.Split(statement, "\n")
+	match := createTablePattern.FindStringSubmatch(strings.TrimSpace(lines[0]))
+	if match == nil || !tx.Migrator().HasTable(match[1]) {
+		return nil
+	}
+	table := match[1]
+	for _, line := range lines[1:] {
+		definition := strings.TrimSuffix(strings.TrimSpace(line), ",")
+		if !strings.HasPrefix(definition, "`") && !strings.HasPrefix(definition, `"`) {
+			continue // a constraint or the closing parenthesis
+		}
+		column := strings.Trim(strings.Fields(definition)[0], "`\"")
+		if tx.Migrator().HasColumn(table, column) {
+			continue
+		}
+		if err := tx.Exec("ALTER TABLE ? ADD COLUMN "+definition, clause.Table{Name: table}).Error; err != nil {
+			return fmt.Errorf("add %s.%s: %w", table, column, err)
+		}
+	}
+	return nil
+}
+
+// splitSQLStatements splits a script on semicolons that end a line, outside dollar-quoted bodies. Comment-only
+// statements are dropped.
+func splitSQLStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	inBody := false
+	for _, line := range strings.SplitAfter(script, "\n") {
+		if strings.Count(line, "$$")%2 == 1 {
+			inBody = !inBody
+		}
+		current.WriteString(line)
+		if inBody || !strings.HasSuffix(strings.TrimSpace(line), ";") {
+			continue
+		}
+		statements = appendStatement(statements, current.String())
+		current.Reset()
+	}
+	return appendStatement(statements, current.String())
+}
+
+func appendStatement(statements []string, statement string) []string {
+	var lines []string
+	for _, line := range strings.Split(statement, "\n") {
+		if trimmed := strings.TrimSpace(line); trimmed != "" && !strings.HasPrefix(trimmed, "--") {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) == 0 {
+		return statements
+	}
+	return append(statements, strings.TrimSuffix(strings.TrimSpace(strings.Join(lines, "\n")), ";"))
+}
+
+// MigrateUp applies pending migrations in order, each in its own transaction, up to and including target (0 for
+// all). It returns the migrations it applied.
+func MigrateUp(db *gorm.DB, target int) ([]MigrationStatus, error) {
+	migrations, applied, err := loadMigrationState(db)
+	if err != nil {
+		return nil, err
+	}
+	if target != 0 && findMigration(migrations, target) == nil {
+		return nil, fmt.Errorf("%w %d", ErrUnknownMigration, target)
+	}
+	dialect := db.Dialector.Name()
+	var done []MigrationStatus
+	for _, migration := range migrations {
+		if target != 0 && migration.Version > target {
+			break
+		}
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+		now := time.Now().UTC()
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if step := migration.step(migration.up, dialect); step != nil {
+				if err := step(tx); err != nil {
+					return err
+				}
+			}
+			return tx.Create(&schemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: now}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %d %s: %w", migration.Version, migration.Name, err)
+		}
+		done = append(done, MigrationStatus{Version: migration.Version, Name: migration.Name, Applied: true, AppliedAt: &now})
+	}
+	return done, nil
+}
+
+// MigrateDown rolls back the most recently applied migrations, newest first, until only migrations up to and
+// including target remain applied. It returns the migrations it rolled back.
+func MigrateDown(db *gorm.DB, target int) ([]MigrationStatus, error) {
+	migrations, applied, err := loadMigrationState(db)
+	if err != nil {
+		return nil, err
+	}
+	if target != 0 && findMigration(migrations, target) == nil {
+		return nil, fmt.Errorf("%w %d", ErrUnknownMigration, target)
+	}
+	dialect := db.Dialector.Name()
+	var done []MigrationStatus
+	for i := len(migrations) - 1; i >= 0; i-- {
+		migration := migrations[i]
+		if migration.Version <= target {
+			break
+		}
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+		if !migration.HasDown(dialect) {
+			return done, fmt.Errorf("migration %d %s has no down step for %s", migration.Version, migration.Name, dialect)
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if step := migration.step(migration.down, dialect); step != nil {
+				if err := step(tx); err != nil {
+					return err
+				}
+			}
+			return tx.Delete(&schemaMigration{}, migration.Version).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %d %s: %w", migration.Version, migration.Name, err)
+		}
+		done = append(done, MigrationStatus{Version: migration.Version, Name: migration.Name})
+	}
+	return done, nil
+}
+
+// MigrationStatuses lists every known migration and whether db has it applied.
+func MigrationStatuses(db *gorm.DB) ([]MigrationStatus, error) {
+	migrations, applied, err := loadMigrationState(db)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, migration := range migrations {
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if row, ok := applied[migration.Version]; ok {
+			appliedAt := row.AppliedAt
+			status.Applied, status.AppliedAt = true, &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// loadMigrationState creates schema_migrations if needed and returns the known migrations and the applied rows.
+func loadMigrationState(db *gorm.DB) ([]Migration, map[int]schemaMigration, error) {
+	if err := db.AutoMigrate(&schemaMigration{}); err != nil {
+		return nil, nil, fmt.Errorf("create %s: %w", schemaMigrationsTable, err)
+	}
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, nil, err
+	}
+	var rows []schemaMigration
+	if err := db.Order("version").Find(&rows).Error; err != nil {
+		return nil, nil, err
+	}
+	applied := make(map[int]schemaMigration, len(rows))
+	for _, row := range rows {
+		if findMigration(migrations, row.Version) == nil {
+			return nil, nil, fmt.Errorf("%w: version %d (%s)", ErrMigrationGap, row.Version, row.Name)
+		}
+		applied[row.Version] = row
+	}
+	return migrations, applied, nil
+}
+
+func findMigration(migrations []Migration, version int) *Migration {
+	for i := range migrations {
+		if migrations[i].Version == version {
+			return &migrations[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package db
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"maintainerd/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func openMigrateTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "migrate.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	return db
+}
+
+func TestMigrations(t *testing.T) {
+	migrations, err := Migrations()
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(migrations), 3)
+	for i, migration := range migrations {
+		assert.Equal(t, i+1, migration.Version, "versions are contiguous")
+		for _, dialect := range []string{"sqlite", "postgres"} {
+			assert.True(t, migration.HasDown(dialect), "migration %d %s has no down step for %s", migration.Version, migration.Name, dialect)
+		}
+	}
+	assert.Equal(t, "baseline", migrations[0].Name)
+	assert.Equal(t, "search_indexes", migrations[1].Name)
+}
+
+func TestMigrateUpAndDown(t *testing.T) {
+	db := openMigrateTestDB(t)
+
+	applied, err := MigrateUp(db, 0)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(applied), 3)
+	for _, table := range []any{&model.AuditLog{}, &model.MaintainerRefCache{}, &model.ServiceUserTeams{}} {
+		assert.True(t, db.Migrator().HasTable(table))
+	}
+	assert.True(t, db.Migrator().HasIndex(&model.AuditLog{}, "idx_audit_logs_created_at"))
+	assert.False(t, db.Migrator().HasConstraint(&model.ServiceUserTeams{}, "ServiceTeam"))
+
+	applied, err = MigrateUp(db, 0)
+	require.NoError(t, err)
+	assert.Empty(t, applied, "applied migrations are not run again")
+
+	rolledBack, err := MigrateDown(db, 1)
+	require.NoError(t, err)
+	require.Len(t, rolledBack, len(mustMigrations(t))-1)
+	assert.Equal(t, len(mustMigrations(t)), rolledBack[0].Version, "newest first")
+	assert.False(t, db.Migrator().HasIndex(&model.AuditLog{}, "idx_audit_logs_created_at"))
+	assert.True(t, db.Migrator().HasConstraint(&model.ServiceUserTeams{}, "ServiceTeam"), "the baseline is unchanged")
+
+	statuses, err := MigrationStatuses(db)
+	require.NoError(t, err)
+	assert.True(t, statuses[0].Applied)
+	require.NotNil(t, statuses[0].AppliedAt)
+	assert.False(t, statuses[2].Applied)
+
+	applied, err = MigrateUp(db, 3)
+	require.NoError(t, err)
+	assert.Len(t, applied, 2)
+	_, err = MigrateUp(db, 999)
+	assert.ErrorIs(t, err, ErrUnknownMigration)
+
+	_, err = MigrateDown(db, 0)
+	require.NoError(t, err)
+	assert.False(t, db.Migrator().HasTable(&model.Maintainer{}), "rolling back the baseline drops the schema")
+	assert.True(t, db.Migrator().HasTable(schemaMigrationsTable))
+}
+
+func TestMigrationsCoverModels(t *testing.T) {
+	db := openMigrateTestDB(t)
+	_, err := MigrateUp(db, 0)
+	require.NoError(t, err)
+
+	// A model change without a migration to match fails here.
+	assertSchemaCoversModels(t, db)
+}
+
+// assertSchemaCoversModels checks that db has a table for every model and a column for every model field.
+func assertSchemaCoversModels(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	tables, err := copyTables(db)
+	require.NoError(t, err)
+	for _, table := range tables {
+		if !assert.True(t, db.Migrator().HasTable(table.schema.Table), table.schema.Table) {
+			continue
+		}
+		for _, column := range table.schema.DBNames {
+			assert.True(t, db.Migrator().HasColumn(table.schema.Table, column), "%s.%s", table.schema.Table, column)
+		}
+	}
+}
+
+func TestMigrateAdoptsAutoMigratedDatabase(t *testing.T) {
+	db := openMigrateTestDB(t)
+	schema, err := os.ReadFile(filepath.Join("testdata", "automigrate_schema.sqlite.sql"))
+	require.NoError(t, err)
+	for _, statement := range splitSQLStatements(string(schema)) {
+		require.NoError(t, db.Exec(statement).Error)
+	}
+	for _, statement := range []string{
+		"INSERT INTO projects (id, name, maturity) VALUES (1, 'Kubernetes', 'Graduated')",
+		"INSERT INTO maintainers (id, name, email, maintainer_status) VALUES (1, 'Alice', 'alice@example.com', 'Active')",
+		"INSERT INTO maintainer_projects (project_id, maintainer_id) VALUES (1, 1)",
+		"INSERT INTO service_teams (id, project_id, service_id, service_team_id) VALUES (1, 1, 1, 10)",
+	} {
+		require.NoError(t, db.Exec(statement).Error)
+	}
+
+	_, err = MigrateUp(db, 0)
+	require.NoError(t, err)
+	assertSchemaCoversModels(t, db)
+	var maintainer model.Maintainer
+	require.NoError(t, db.First(&maintainer, 1).Error)
+	assert.Equal(t, "alice@example.com", maintainer.Email, "existing rows are kept")
+	var membership model.MaintainerProject
+	require.NoError(t, db.Where("maintainer_id = ? AND project_id = ?", 1, 1).First(&membership).Error)
+	assert.Equal(t, model.ActiveMaintainer, membership.Status, "added columns take their defaults")
+	var teams int64
+	require.NoError(t, db.Model(&model.ServiceTeam{}).Count(&teams).Error)
+	assert.EqualValues(t, 1, teams)
+
+	require.NoError(t, db.Create(&schemaMigration{Version: 999, Name: "from_the_future"}).Error)
+	_, err = MigrationStatuses(db)
+	assert.ErrorIs(t, err, ErrMigrationGap)
+}
+
+func TestMigrateBackfillsAuditBatchIDs(t *testing.T) {
+	db := openMigrateTestDB(t)
+	_, err := MigrateUp(db, 5)
+	require.NoError(t, err)
+	batched := model.AuditLog{Action: "MAINTAINER_STATUS_UPDATE", Metadata: `{"batch":{"id":"0123456789abcdef","size":2}}`}
+	legacy := model.AuditLog{Action: "LEGACY", Metadata: `not json, "batch":{`}
+	for _, entry := range []*model.AuditLog{&batched, &legacy} {
+		require.NoError(t, db.Omit("BatchID").Create(entry).Error)
+	}
+
+	_, err = MigrateUp(db, 0)
+	require.NoError(t, err)
+	require.NoError(t, db.First(&batched, batched.ID).Error)
+	require.NotNil(t, batched.BatchID)
+	assert.Equal(t, "0123456789abcdef", *batched.BatchID, "batch IDs are copied out of existing metadata")
+	require.NoError(t, db.First(&legacy, legacy.ID).Error)
+	assert.Nil(t, legacy.BatchID)
+}
+
+func TestSplitSQLStatements(t *testing.T) {
+	statements := splitSQLStatements(`-- leading comment
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+CREATE OR REPLACE FUNCTION f(text)
+ RETURNS text
+ AS $$ SELECT 1;
+ SELECT unaccent($1); $$;
+DROP INDEX IF EXISTS a;
+-- trailing comment
+`)
+	require.Len(t, statements, 3)
+	assert.Equal(t, "CREATE EXTENSION IF NOT EXISTS unaccent", statements[0])
+	assert.Contains(t, statements[1], "SELECT unaccent($1); $$")
+	assert.Equal(t, "DROP INDEX IF EXISTS a", statements[2])
+
+	_, _, _, _, err := parseMigrationFile("0000_nothing.up.sql")
+	assert.Error(t, err)
+	_, _, _, _, err = parseMigrationFile("0004_x.mysql.up.sql")
+	assert.Error(t, err)
+	version, name, dialect, direction, err := parseMigrationFile("0004_add_things.sqlite.down.sql")
+	require.NoError(t, err)
+	assert.Equal(t, []any{4, "add_things", "sqlite", "down"}, []any{version, name, dialect, direction})
+}
+
+func mustMigrations(t *testing.T) []Migration {
+	t.Helper()
+	migrations, err := Migrations()
+	require.NoError(t, err)
+	return migrations
+}
@@ -0,0 +1,330 @@
+-- The schema as the GORM models defined it when versioned migrations were introduced. This file is frozen: schema
+-- changes go in a new migration. IF NOT EXISTS lets it adopt a database AutoMigrate already created.
+
+CREATE TABLE IF NOT EXISTS "companies" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "name" text,
+    "website" varchar(2048),
+    "notes" text,
+    "tags" text,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_companies_name" ON "companies" ("name");
+CREATE INDEX IF NOT EXISTS "idx_companies_deleted_at" ON "companies" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "company_domains" (
+    "id" bigserial,
+    "company_id" bigint,
+    "domain" varchar(253),
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_companies_domains" FOREIGN KEY ("company_id") REFERENCES "companies"("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_company_domains_domain" ON "company_domains" ("domain");
+CREATE INDEX IF NOT EXISTS "idx_company_domains_company_id" ON "company_domains" ("company_id");
+
+CREATE TABLE IF NOT EXISTS "company_aliases" (
+    "id" bigserial,
+    "company_id" bigint,
+    "name" varchar(255),
+    "normalized_name" varchar(255),
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_companies_aliases" FOREIGN KEY ("company_id") REFERENCES "companies"("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_company_aliases_normalized_name" ON "company_aliases" ("normalized_name");
+CREATE INDEX IF NOT EXISTS "idx_company_aliases_company_id" ON "company_aliases" ("company_id");
+
+CREATE TABLE IF NOT EXISTS "foundations" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "name" text,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_foundations_name" ON "foundations" ("name");
+CREATE INDEX IF NOT EXISTS "idx_foundations_deleted_at" ON "foundations" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "projects" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "name" text,
+    "parent_project_id" bigint,
+    "maturity" text,
+    "git_hub_org" varchar(255),
+    "maintainer_ref" text,
+    "dot_project_yaml_ref" text,
+    "onboarding_issue" text,
+    "mailing_list" varchar(254) DEFAULT 'MML_MISSING',
+    PRIMARY KEY ("id"),
+    CONSTRAINT "chk_projects_name" CHECK (name <> '')
+);
+CREATE INDEX IF NOT EXISTS "idx_projects_parent_project_id" ON "projects" ("parent_project_id");
+CREATE INDEX IF NOT EXISTS "idx_projects_deleted_at" ON "projects" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "services" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "name" text,
+    "description" text,
+    "acting_officer_id" bigint,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_services_acting_officer_id" ON "services" ("acting_officer_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_services_name" ON "services" ("name");
+CREATE INDEX IF NOT EXISTS "idx_services_deleted_at" ON "services" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "service_projects" (
+    "project_id" bigint,
+    "service_id" bigint,
+    PRIMARY KEY ("project_id","service_id"),
+    CONSTRAINT "fk_service_projects_project" FOREIGN KEY ("project_id") REFERENCES "projects"("id"),
+    CONSTRAINT "fk_service_projects_service" FOREIGN KEY ("service_id") REFERENCES "services"("id")
+);
+
+CREATE TABLE IF NOT EXISTS "maintainers" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "name" text,
+    "email" varchar(254) DEFAULT 'EMAIL_MISSING',
+    "git_hub_account" varchar(100) DEFAULT 'GITHUB_MISSING',
+    "git_hub_email" varchar(100) DEFAULT 'GITHUB_MISSING',
+    "maintainer_status" text,
+    "import_warnings" text,
+    "registered_at" timestamptz,
+    "git_hub_id" bigint,
+    "email_verified_at" timestamptz,
+    "company_id" bigint,
+    "merged_into_id" bigint,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_maintainers_company" FOREIGN KEY ("company_id") REFERENCES "companies"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_maintainers_merged_into_id" ON "maintainers" ("merged_into_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_maintainers_git_hub_id" ON "maintainers" ("git_hub_id");
+CREATE INDEX IF NOT EXISTS "idx_maintainers_deleted_at" ON "maintainers" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "maintainer_projects" (
+    "maintainer_id" bigint,
+    "project_id" bigint,
+    "joined_at" timestamptz,
+    "status" text DEFAULT 'Active',
+    "role" text DEFAULT 'maintainer',
+    "left_at" timestamptz,
+    PRIMARY KEY ("maintainer_id","project_id"),
+    CONSTRAINT "fk_maintainer_projects_maintainer" FOREIGN KEY ("maintainer_id") REFERENCES "maintainers"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_maintainer_projects_project" FOREIGN KEY ("project_id") REFERENCES "projects"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_maintainer_projects_project_id" ON "maintainer_projects" ("project_id");
+CREATE INDEX IF NOT EXISTS "idx_maintainer_projects_maintainer_id" ON "maintainer_projects" ("maintainer_id");
+
+CREATE TABLE IF NOT EXISTS "project_aliases" (
+    "id" bigserial,
+    "project_id" bigint,
+    "name" varchar(255),
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_project_aliases_name" ON "project_aliases" ("name");
+CREATE INDEX IF NOT EXISTS "idx_project_aliases_project_id" ON "project_aliases" ("project_id");
+
+CREATE TABLE IF NOT EXISTS "project_maturity_events" (
+    "id" bigserial,
+    "project_id" bigint,
+    "from_maturity" text,
+    "to_maturity" text,
+    "effective_at" timestamptz,
+    "vote_issue_url" varchar(512),
+    "actor_login" text,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_project_maturity_events_project_id" ON "project_maturity_events" ("project_id");
+
+CREATE TABLE IF NOT EXISTS "membership_histories" (
+    "id" bigserial,
+    "maintainer_id" bigint,
+    "project_id" bigint,
+    "event" varchar(32),
+    "from_status" varchar(32),
+    "to_status" varchar(32),
+    "from_role" varchar(32),
+    "to_role" varchar(32),
+    "actor_login" varchar(100),
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_membership_histories_created_at" ON "membership_histories" ("created_at");
+CREATE INDEX IF NOT EXISTS "idx_membership_history_member" ON "membership_histories" ("maintainer_id","project_id");
+
+CREATE TABLE IF NOT EXISTS "maintainer_ref_caches" (
+    "project_id" bigserial,
+    "e_tag" varchar(255),
+    "last_modified" timestamptz,
+    "body_hash" varchar(128),
+    "last_checked" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("project_id")
+);
+
+CREATE TABLE IF NOT EXISTS "staff_members" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "name" text,
+    "email" varchar(254) DEFAULT 'EMAIL_MISSING',
+    "git_hub_account" varchar(100) DEFAULT 'GITHUB_MISSING',
+    "git_hub_email" varchar(254) DEFAULT 'GITHUB_EMAIL_MISSING',
+    "git_hub_id" bigint,
+    "registered_at" timestamptz,
+    "admin" boolean NOT NULL DEFAULT false,
+    "deactivated_at" timestamptz,
+    "foundation_id" bigint,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_staff_members_foundation" FOREIGN KEY ("foundation_id") REFERENCES "foundations"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_staff_members_foundation_id" ON "staff_members" ("foundation_id");
+CREATE INDEX IF NOT EXISTS "idx_staff_members_deactivated_at" ON "staff_members" ("deactivated_at");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_staff_members_git_hub_id" ON "staff_members" ("git_hub_id");
+CREATE INDEX IF NOT EXISTS "idx_staff_members_deleted_at" ON "staff_members" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "foundation_officers" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "name" text,
+    "email" varchar(254) DEFAULT 'EMAIL_MISSING',
+    "git_hub_account" varchar(100) DEFAULT 'GITHUB_MISSING',
+    "registered_at" timestamptz,
+    "company_id" bigint,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_foundation_officers_deleted_at" ON "foundation_officers" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "service_users" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "service_id" bigint,
+    "service_user_id" bigint,
+    "service_email" varchar(254) DEFAULT 'EMAIL_MISSING',
+    "service_ref" varchar(512),
+    "service_git_hub_name" text,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_service_users_service_user_id" ON "service_users" ("service_user_id");
+CREATE INDEX IF NOT EXISTS "idx_service_users_service_id" ON "service_users" ("service_id");
+CREATE INDEX IF NOT EXISTS "idx_service_users_deleted_at" ON "service_users" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "foundation_officer_service_users" (
+    "foundation_officer_id" bigint,
+    "service_user_id" bigint,
+    PRIMARY KEY ("foundation_officer_id","service_user_id"),
+    CONSTRAINT "fk_foundation_officer_service_users_foundation_officer" FOREIGN KEY ("foundation_officer_id") REFERENCES "foundation_officers"("id") ON DELETE CASCADE,
+    CONSTRAINT "fk_foundation_officer_service_users_service_user" FOREIGN KEY ("service_user_id") REFERENCES "service_users"("id") ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS "collaborators" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "name" text,
+    "email" varchar(254) DEFAULT 'EMAIL_MISSING',
+    "git_hub_email" varchar(254) DEFAULT 'GITHUB_EMAIL_MISSING',
+    "git_hub_account" varchar(100) DEFAULT 'GITHUB_MISSING',
+    "last_login" timestamptz,
+    "registered_at" timestamptz,
+    "promoted_to_id" bigint,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_collaborators_promoted_to_id" ON "collaborators" ("promoted_to_id");
+CREATE INDEX IF NOT EXISTS "idx_collaborators_deleted_at" ON "collaborators" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "collaborator_projects" (
+    "collaborator_id" bigint,
+    "project_id" bigint,
+    "created_at" timestamptz,
+    PRIMARY KEY ("collaborator_id","project_id")
+);
+CREATE INDEX IF NOT EXISTS "idx_collaborator_projects_project_id" ON "collaborator_projects" ("project_id");
+CREATE INDEX IF NOT EXISTS "idx_collaborator_projects_collaborator_id" ON "collaborator_projects" ("collaborator_id");
+
+CREATE TABLE IF NOT EXISTS "service_user_teams" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "service_id" bigint,
+    "service_user_id" bigint,
+    "service_team_id" bigint,
+    "maintainer_id" bigint,
+    "collaborator_id" bigint,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_service_user_teams_collaborator_id" ON "service_user_teams" ("collaborator_id");
+CREATE INDEX IF NOT EXISTS "idx_service_user_teams_maintainer_id" ON "service_user_teams" ("maintainer_id");
+CREATE INDEX IF NOT EXISTS "idx_service_user_teams_service_team_id" ON "service_user_teams" ("service_team_id");
+CREATE INDEX IF NOT EXISTS "idx_service_user_teams_service_user_id" ON "service_user_teams" ("service_user_id");
+CREATE INDEX IF NOT EXISTS "idx_service_user_teams_service_id" ON "service_user_teams" ("service_id");
+CREATE INDEX IF NOT EXISTS "idx_service_user_teams_deleted_at" ON "service_user_teams" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "service_teams" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "project_id" bigint,
+    "service_id" bigint,
+    "service_team_id" bigint,
+    "service_team_name" text,
+    "project_name" text,
+    "cleanup_requested_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_service_user_teams_service_team" FOREIGN KEY ("service_team_id") REFERENCES "service_user_teams"("id") ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS "idx_service_teams_cleanup_requested_at" ON "service_teams" ("cleanup_requested_at");
+CREATE INDEX IF NOT EXISTS "idx_service_teams_service_id" ON "service_teams" ("service_id");
+CREATE INDEX IF NOT EXISTS "idx_service_teams_project_id" ON "service_teams" ("project_id");
+CREATE INDEX IF NOT EXISTS "idx_service_teams_deleted_at" ON "service_teams" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "audit_logs" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    "project_id" bigint,
+    "maintainer_id" bigint,
+    "service_id" bigint,
+    "staff_id" bigint,
+    "action" text,
+    "message" text,
+    "metadata" text,
+    "prev_hash" varchar(64),
+    "hash" varchar(64),
+    "revert_of_id" bigint,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_audit_logs_staff" FOREIGN KEY ("staff_id") REFERENCES "staff_members"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_revert_of_id" ON "audit_logs" ("revert_of_id");
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_hash" ON "audit_logs" ("hash");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_audit_logs_prev_hash" ON "audit_logs" ("prev_hash");
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_action" ON "audit_logs" ("action");
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_staff_id" ON "audit_logs" ("staff_id");
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_service_id" ON "audit_logs" ("service_id");
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_maintainer_id" ON "audit_logs" ("maintainer_id");
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_project_id" ON "audit_logs" ("project_id");
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_deleted_at" ON "audit_logs" ("deleted_at");
@@ -0,0 +1,22 @@
+DROP TABLE IF EXISTS `audit_logs`;
+DROP TABLE IF EXISTS `service_user_teams`;
+DROP TABLE IF EXISTS `service_teams`;
+DROP TABLE IF EXISTS `collaborator_projects`;
+DROP TABLE IF EXISTS `collaborators`;
+DROP TABLE IF EXISTS `foundation_officer_service_users`;
+DROP TABLE IF EXISTS `service_users`;
+DROP TABLE IF EXISTS `foundation_officers`;
+DROP TABLE IF EXISTS `staff_members`;
+DROP TABLE IF EXISTS `maintainer_ref_caches`;
+DROP TABLE IF EXISTS `membership_histories`;
+DROP TABLE IF EXISTS `project_maturity_events`;
+DROP TABLE IF EXISTS `project_aliases`;
+DROP TABLE IF EXISTS `service_projects`;
+DROP TABLE IF EXISTS `services`;
+DROP TABLE IF EXISTS `maintainer_projects`;
+DROP TABLE IF EXISTS `maintainers`;
+DROP TABLE IF EXISTS `projects`;
+DROP TABLE IF EXISTS `foundations`;
+DROP TABLE IF EXISTS `company_aliases`;
+DROP TABLE IF EXISTS `company_domains`;
+DROP TABLE IF EXISTS `companies`;
@@ -0,0 +1,312 @@
+-- The schema as the GORM models defined it when versioned migrations were introduced. This file is frozen: schema
+-- changes go in a new migration. IF NOT EXISTS lets it adopt a database AutoMigrate already created.
+
+CREATE TABLE IF NOT EXISTS `companies` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `name` text,
+    `website` text,
+    `notes` text,
+    `tags` text
+);
+CREATE UNIQUE INDEX IF NOT EXISTS `idx_companies_name` ON `companies`(`name`);
+CREATE INDEX IF NOT EXISTS `idx_companies_deleted_at` ON `companies`(`deleted_at`);
+
+CREATE TABLE IF NOT EXISTS `company_domains` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `company_id` integer,
+    `domain` text,
+    `created_at` datetime,
+    CONSTRAINT `fk_companies_domains` FOREIGN KEY (`company_id`) REFERENCES `companies`(`id`)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS `idx_company_domains_domain` ON `company_domains`(`domain`);
+CREATE INDEX IF NOT EXISTS `idx_company_domains_company_id` ON `company_domains`(`company_id`);
+
+CREATE TABLE IF NOT EXISTS `company_aliases` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `company_id` integer,
+    `name` text,
+    `normalized_name` text,
+    `created_at` datetime,
+    CONSTRAINT `fk_companies_aliases` FOREIGN KEY (`company_id`) REFERENCES `companies`(`id`)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS `idx_company_aliases_normalized_name` ON `company_aliases`(`normalized_name`);
+CREATE INDEX IF NOT EXISTS `idx_company_aliases_company_id` ON `company_aliases`(`company_id`);
+
+CREATE TABLE IF NOT EXISTS `foundations` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `name` text
+);
+CREATE UNIQUE INDEX IF NOT EXISTS `idx_foundations_name` ON `foundations`(`name`);
+CREATE INDEX IF NOT EXISTS `idx_foundations_deleted_at` ON `foundations`(`deleted_at`);
+
+CREATE TABLE IF NOT EXISTS `projects` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `name` text,
+    `parent_project_id` integer,
+    `maturity` text,
+    `git_hub_org` text,
+    `maintainer_ref` text,
+    `dot_project_yaml_ref` text,
+    `onboarding_issue` text,
+    `mailing_list` text DEFAULT 'MML_MISSING',
+    CONSTRAINT `chk_projects_name` CHECK (name <> '')
+);
+CREATE INDEX IF NOT EXISTS `idx_projects_parent_project_id` ON `projects`(`parent_project_id`);
+CREATE INDEX IF NOT EXISTS `idx_projects_deleted_at` ON `projects`(`deleted_at`);
+
+CREATE TABLE IF NOT EXISTS `maintainers` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `name` text,
+    `email` text DEFAULT 'EMAIL_MISSING',
+    `git_hub_account` text DEFAULT 'GITHUB_MISSING',
+    `git_hub_email` text DEFAULT 'GITHUB_MISSING',
+    `maintainer_status` text,
+    `import_warnings` text,
+    `registered_at` datetime,
+    `git_hub_id` integer,
+    `email_verified_at` datetime,
+    `company_id` integer,
+    `merged_into_id` integer,
+    CONSTRAINT `fk_maintainers_company` FOREIGN KEY (`company_id`) REFERENCES `companies`(`id`)
+);
+CREATE INDEX IF NOT EXISTS `idx_maintainers_merged_into_id` ON `maintainers`(`merged_into_id`);
+CREATE UNIQUE INDEX IF NOT EXISTS `idx_maintainers_git_hub_id` ON `maintainers`(`git_hub_id`);
+CREATE INDEX IF NOT EXISTS `idx_maintainers_deleted_at` ON `maintainers`(`deleted_at`);
+
+CREATE TABLE IF NOT EXISTS `maintainer_projects` (
+    `maintainer_id` integer,
+    `project_id` integer,
+    `joined_at` datetime,
+    `status` text DEFAULT 'Active',
+    `role` text DEFAULT 'maintainer',
+    `left_at` datetime,
+    PRIMARY KEY (`maintainer_id`,`project_id`),
+    CONSTRAINT `fk_maintainer_projects_maintainer` FOREIGN KEY (`maintainer_id`) REFERENCES `maintainers`(`id`) ON DELETE CASCADE,
+    CONSTRAINT `fk_maintainer_projects_project` FOREIGN KEY (`project_id`) REFERENCES `projects`(`id`) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS `idx_maintainer_projects_project_id` ON `maintainer_projects`(`project_id`);
+CREATE INDEX IF NOT EXISTS `idx_maintainer_projects_maintainer_id` ON `maintainer_projects`(`maintainer_id`);
+
+CREATE TABLE IF NOT EXISTS `services` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `name` text,
+    `description` text,
+    `acting_officer_id` integer
+);
+CREATE INDEX IF NOT EXISTS `idx_services_acting_officer_id` ON `services`(`acting_officer_id`);
+CREATE UNIQUE INDEX IF NOT EXISTS `idx_services_name` ON `services`(`name`);
+CREATE INDEX IF NOT EXISTS `idx_services_deleted_at` ON `services`(`deleted_at`);
+
+CREATE TABLE IF NOT EXISTS `service_projects` (
+    `project_id` integer,
+    `service_id` integer,
+    PRIMARY KEY (`project_id`,`service_id`),
+    CONSTRAINT `fk_service_projects_service` FOREIGN KEY (`service_id`) REFERENCES `services`(`id`),
+    CONSTRAINT `fk_service_projects_project` FOREIGN KEY (`project_id`) REFERENCES `projects`(`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `project_aliases` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `project_id` integer,
+    `name` text,
+    `created_at` datetime
+);
+CREATE UNIQUE INDEX IF NOT EXISTS `idx_project_aliases_name` ON `project_aliases`(`name`);
+CREATE INDEX IF NOT EXISTS `idx_project_aliases_project_id` ON `project_aliases`(`project_id`);
+
+CREATE TABLE IF NOT EXISTS `project_maturity_events` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `project_id` integer,
+    `from_maturity` text,
+    `to_maturity` text,
+    `effective_at` datetime,
+    `vote_issue_url` text,
+    `actor_login` text,
+    `created_at` datetime
+);
+CREATE INDEX IF NOT EXISTS `idx_project_maturity_events_project_id` ON `project_maturity_events`(`project_id`);
+
+CREATE TABLE IF NOT EXISTS `membership_histories` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `maintainer_id` integer,
+    `project_id` integer,
+    `event` text,
+    `from_status` text,
+    `to_status` text,
+    `from_role` text,
+    `to_role` text,
+    `actor_login` text,
+    `created_at` datetime
+);
+CREATE INDEX IF NOT EXISTS `idx_membership_histories_created_at` ON `membership_histories`(`created_at`);
+CREATE INDEX IF NOT EXISTS `idx_membership_history_member` ON `membership_histories`(`maintainer_id`,`project_id`);
+
+CREATE TABLE IF NOT EXISTS `maintainer_ref_caches` (
+    `project_id` integer PRIMARY KEY AUTOINCREMENT,
+    `e_tag` text,
+    `last_modified` datetime,
+    `body_hash` text,
+    `last_checked` datetime,
+    `created_at` datetime,
+    `updated_at` datetime
+);
+
+CREATE TABLE IF NOT EXISTS `staff_members` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `name` text,
+    `email` text DEFAULT 'EMAIL_MISSING',
+    `git_hub_account` text DEFAULT 'GITHUB_MISSING',
+    `git_hub_email` text DEFAULT 'GITHUB_EMAIL_MISSING',
+    `git_hub_id` integer,
+    `registered_at` datetime,
+    `admin` numeric NOT NULL DEFAULT false,
+    `deactivated_at` datetime,
+    `foundation_id` integer,
+    CONSTRAINT `fk_staff_members_foundation` FOREIGN KEY (`foundation_id`) REFERENCES `foundations`(`id`)
+);
+CREATE INDEX IF NOT EXISTS `idx_staff_members_foundation_id` ON `staff_members`(`foundation_id`);
+CREATE INDEX IF NOT EXISTS `idx_staff_members_deactivated_at` ON `staff_members`(`deactivated_at`);
+CREATE UNIQUE INDEX IF NOT EXISTS `idx_staff_members_git_hub_id` ON `staff_members`(`git_hub_id`);
+CREATE INDEX IF NOT EXISTS `idx_staff_members_deleted_at` ON `staff_members`(`deleted_at`);
+
+CREATE TABLE IF NOT EXISTS `foundation_officers` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `name` text,
+    `email` text DEFAULT 'EMAIL_MISSING',
+    `git_hub_account` text DEFAULT 'GITHUB_MISSING',
+    `registered_at` datetime,
+    `company_id` integer
+);
+CREATE INDEX IF NOT EXISTS `idx_foundation_officers_deleted_at` ON `foundation_officers`(`deleted_at`);
+
+CREATE TABLE IF NOT EXISTS `service_users` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `service_id` integer,
+    `service_user_id` integer,
+    `service_email` text DEFAULT 'EMAIL_MISSING',
+    `service_ref` text,
+    `service_git_hub_name` text
+);
+CREATE INDEX IF NOT EXISTS `idx_service_users_service_user_id` ON `service_users`(`service_user_id`);
+CREATE INDEX IF NOT EXISTS `idx_service_users_service_id` ON `service_users`(`service_id`);
+CREATE INDEX IF NOT EXISTS `idx_service_users_deleted_at` ON `service_users`(`deleted_at`);
+
+CREATE TABLE IF NOT EXISTS `foundation_officer_service_users` (
+    `foundation_officer_id` integer,
+    `service_user_id` integer,
+    PRIMARY KEY (`foundation_officer_id`,`service_user_id`),
+    CONSTRAINT `fk_foundation_officer_service_users_foundation_officer` FOREIGN KEY (`foundation_officer_id`) REFERENCES `foundation_officers`(`id`) ON DELETE CASCADE,
+    CONSTRAINT `fk_foundation_officer_service_users_service_user` FOREIGN KEY (`service_user_id`) REFERENCES `service_users`(`id`) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS `collaborators` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `name` text,
+    `email` text DEFAULT 'EMAIL_MISSING',
+    `git_hub_email` text DEFAULT 'GITHUB_EMAIL_MISSING',
+    `git_hub_account` text DEFAULT 'GITHUB_MISSING',
+    `last_login` datetime,
+    `registered_at` datetime,
+    `promoted_to_id` integer
+);
+CREATE INDEX IF NOT EXISTS `idx_collaborators_promoted_to_id` ON `collaborators`(`promoted_to_id`);
+CREATE INDEX IF NOT EXISTS `idx_collaborators_deleted_at` ON `collaborators`(`deleted_at`);
+
+CREATE TABLE IF NOT EXISTS `collaborator_projects` (
+    `collaborator_id` integer,
+    `project_id` integer,
+    `created_at` datetime,
+    PRIMARY KEY (`collaborator_id`,`project_id`)
+);
+CREATE INDEX IF NOT EXISTS `idx_collaborator_projects_project_id` ON `collaborator_projects`(`project_id`);
+CREATE INDEX IF NOT EXISTS `idx_collaborator_projects_collaborator_id` ON `collaborator_projects`(`collaborator_id`);
+
+CREATE TABLE IF NOT EXISTS `service_teams` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `project_id` integer,
+    `service_id` integer,
+    `service_team_id` integer,
+    `service_team_name` text,
+    `project_name` text,
+    `cleanup_requested_at` datetime,
+    CONSTRAINT `fk_service_user_teams_service_team` FOREIGN KEY (`service_team_id`) REFERENCES `service_user_teams`(`id`) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS `idx_service_teams_cleanup_requested_at` ON `service_teams`(`cleanup_requested_at`);
+CREATE INDEX IF NOT EXISTS `idx_service_teams_service_id` ON `service_teams`(`service_id`);
+CREATE INDEX IF NOT EXISTS `idx_service_teams_project_id` ON `service_teams`(`project_id`);
+CREATE INDEX IF NOT EXISTS `idx_service_teams_deleted_at` ON `service_teams`(`deleted_at`);
+
+CREATE TABLE IF NOT EXISTS `service_user_teams` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `service_id` integer,
+    `service_user_id` integer,
+    `service_team_id` integer,
+    `maintainer_id` integer,
+    `collaborator_id` integer
+);
+CREATE INDEX IF NOT EXISTS `idx_service_user_teams_collaborator_id` ON `service_user_teams`(`collaborator_id`);
+CREATE INDEX IF NOT EXISTS `idx_service_user_teams_maintainer_id` ON `service_user_teams`(`maintainer_id`);
+CREATE INDEX IF NOT EXISTS `idx_service_user_teams_service_team_id` ON `service_user_teams`(`service_team_id`);
+CREATE INDEX IF NOT EXISTS `idx_service_user_teams_service_user_id` ON `service_user_teams`(`service_user_id`);
+CREATE INDEX IF NOT EXISTS `idx_service_user_teams_service_id` ON `service_user_teams`(`service_id`);
+CREATE INDEX IF NOT EXISTS `idx_service_user_teams_deleted_at` ON `service_user_teams`(`deleted_at`);
+
+CREATE TABLE IF NOT EXISTS `audit_logs` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `project_id` integer,
+    `maintainer_id` integer,
+    `service_id` integer,
+    `staff_id` integer,
+    `action` text,
+    `message` text,
+    `metadata` text,
+    `prev_hash` text,
+    `hash` text,
+    `revert_of_id` integer,
+    CONSTRAINT `fk_audit_logs_staff` FOREIGN KEY (`staff_id`) REFERENCES `staff_members`(`id`)
+);
+CREATE INDEX IF NOT EXISTS `idx_audit_logs_revert_of_id` ON `audit_logs`(`revert_of_id`);
+CREATE INDEX IF NOT EXISTS `idx_audit_logs_hash` ON `audit_logs`(`hash`);
+CREATE UNIQUE INDEX IF NOT EXISTS `idx_audit_logs_prev_hash` ON `audit_logs`(`prev_hash`);
+CREATE INDEX IF NOT EXISTS `idx_audit_logs_action` ON `audit_logs`(`action`);
+CREATE INDEX IF NOT EXISTS `idx_audit_logs_staff_id` ON `audit_logs`(`staff_id`);
+CREATE INDEX IF NOT EXISTS `idx_audit_logs_service_id` ON `audit_logs`(`service_id`);
+CREATE INDEX IF NOT EXISTS `idx_audit_logs_maintainer_id` ON `audit_logs`(`maintainer_id`);
+CREATE INDEX IF NOT EXISTS `idx_audit_logs_project_id` ON `audit_logs`(`project_id`);
+CREATE INDEX IF NOT EXISTS `idx_audit_logs_deleted_at` ON `audit_logs`(`deleted_at`);
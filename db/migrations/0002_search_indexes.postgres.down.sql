@@ -0,0 +1,17 @@
+-- The pg_trgm and unaccent extensions are left installed: company similarity matching uses pg_trgm directly.
+DROP INDEX IF EXISTS idx_audit_logs_message_tsv;
+DROP INDEX IF EXISTS idx_projects_search_tsv;
+DROP INDEX IF EXISTS idx_maintainers_search_tsv;
+ALTER TABLE projects DROP COLUMN IF EXISTS search_tsv;
+ALTER TABLE maintainers DROP COLUMN IF EXISTS search_tsv;
+
+DROP INDEX IF EXISTS idx_companies_name_trgm;
+DROP INDEX IF EXISTS idx_maintainers_github_trgm;
+DROP INDEX IF EXISTS idx_maintainers_email_trgm;
+DROP INDEX IF EXISTS idx_maintainers_name_trgm;
+DROP INDEX IF EXISTS idx_projects_github_org_trgm;
+DROP INDEX IF EXISTS idx_projects_dotref_trgm;
+DROP INDEX IF EXISTS idx_projects_ref_trgm;
+DROP INDEX IF EXISTS idx_projects_name_trgm;
+
+DROP FUNCTION IF EXISTS unaccent_immutable(text);
@@ -0,0 +1,25 @@
+-- Trigram and full-text search support for the web search endpoints. SQLite falls back to LIKE queries.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+CREATE OR REPLACE FUNCTION unaccent_immutable(text)
+ RETURNS text
+ LANGUAGE sql
+ IMMUTABLE
+ PARALLEL SAFE
+ AS $$ SELECT unaccent($1); $$;
+
+CREATE INDEX IF NOT EXISTS idx_projects_name_trgm ON projects USING gin (lower(name) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_projects_ref_trgm ON projects USING gin (lower(maintainer_ref) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_projects_dotref_trgm ON projects USING gin (lower(dot_project_yaml_ref) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_projects_github_org_trgm ON projects USING gin (lower(git_hub_org) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_maintainers_name_trgm ON maintainers USING gin (lower(name) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_maintainers_email_trgm ON maintainers USING gin (lower(email) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_maintainers_github_trgm ON maintainers USING gin (lower(git_hub_account) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_companies_name_trgm ON companies USING gin (lower(name) gin_trgm_ops);
+
+ALTER TABLE maintainers ADD COLUMN IF NOT EXISTS search_tsv tsvector GENERATED ALWAYS AS (to_tsvector('simple', unaccent_immutable(coalesce(name, '') || ' ' || coalesce(email, '') || ' ' || coalesce(git_hub_account, '')))) STORED;
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS search_tsv tsvector GENERATED ALWAYS AS (to_tsvector('simple', unaccent_immutable(coalesce(name, '') || ' ' || coalesce(maintainer_ref, '') || ' ' || coalesce(dot_project_yaml_ref, '') || ' ' || coalesce(git_hub_org, '')))) STORED;
+CREATE INDEX IF NOT EXISTS idx_maintainers_search_tsv ON maintainers USING gin (search_tsv);
+CREATE INDEX IF NOT EXISTS idx_projects_search_tsv ON projects USING gin (search_tsv);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_message_tsv ON audit_logs USING gin (to_tsvector('simple', coalesce(message, '')));
@@ -0,0 +1,2 @@
+-- The audit log is listed newest first and filtered by date range.
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at ON audit_logs (created_at);
@@ -0,0 +1,4 @@
+-- NOT VALID keeps existing rows, which never satisfied the constraint, from failing the rollback.
+ALTER TABLE service_teams
+    ADD CONSTRAINT fk_service_user_teams_service_team FOREIGN KEY (service_team_id)
+    REFERENCES service_user_teams (id) ON DELETE CASCADE NOT VALID;
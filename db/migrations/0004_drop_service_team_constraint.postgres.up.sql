@@ -0,0 +1,4 @@
+-- ServiceTeam has a ServiceTeamID of its own (the remote team's ID), so GORM read ServiceUserTeams.ServiceTeam as a
+-- has-one and the baseline constrained service_teams.service_team_id to service_user_teams IDs. No real data
+-- satisfies that.
+ALTER TABLE service_teams DROP CONSTRAINT IF EXISTS fk_service_user_teams_service_team;
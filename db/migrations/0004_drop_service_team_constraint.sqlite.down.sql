@@ -0,0 +1,21 @@
+CREATE TABLE `service_teams__old` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `project_id` integer,
+    `service_id` integer,
+    `service_team_id` integer,
+    `service_team_name` text,
+    `project_name` text,
+    `cleanup_requested_at` datetime,
+    CONSTRAINT `fk_service_user_teams_service_team` FOREIGN KEY (`service_team_id`) REFERENCES `service_user_teams`(`id`) ON DELETE CASCADE
+);
+INSERT INTO `service_teams__old` SELECT `id`, `created_at`, `updated_at`, `deleted_at`, `project_id`, `service_id`,
+    `service_team_id`, `service_team_name`, `project_name`, `cleanup_requested_at` FROM `service_teams`;
+DROP TABLE `service_teams`;
+ALTER TABLE `service_teams__old` RENAME TO `service_teams`;
+CREATE INDEX `idx_service_teams_cleanup_requested_at` ON `service_teams`(`cleanup_requested_at`);
+CREATE INDEX `idx_service_teams_service_id` ON `service_teams`(`service_id`);
+CREATE INDEX `idx_service_teams_project_id` ON `service_teams`(`project_id`);
+CREATE INDEX `idx_service_teams_deleted_at` ON `service_teams`(`deleted_at`);
@@ -0,0 +1,23 @@
+-- ServiceTeam has a ServiceTeamID of its own (the remote team's ID), so GORM read ServiceUserTeams.ServiceTeam as a
+-- has-one and the baseline constrained service_teams.service_team_id to service_user_teams IDs. No real data
+-- satisfies that. SQLite cannot drop a constraint, so the table is rebuilt without it.
+CREATE TABLE `service_teams__new` (
+    `id` integer PRIMARY KEY AUTOINCREMENT,
+    `created_at` datetime,
+    `updated_at` datetime,
+    `deleted_at` datetime,
+    `project_id` integer,
+    `service_id` integer,
+    `service_team_id` integer,
+    `service_team_name` text,
+    `project_name` text,
+    `cleanup_requested_at` datetime
+);
+INSERT INTO `service_teams__new` SELECT `id`, `created_at`, `updated_at`, `deleted_at`, `project_id`, `service_id`,
+    `service_team_id`, `service_team_name`, `project_name`, `cleanup_requested_at` FROM `service_teams`;
+DROP TABLE `service_teams`;
+ALTER TABLE `service_teams__new` RENAME TO `service_teams`;
+CREATE INDEX `idx_service_teams_cleanup_requested_at` ON `service_teams`(`cleanup_requested_at`);
+CREATE INDEX `idx_service_teams_service_id` ON `service_teams`(`service_id`);
+CREATE INDEX `idx_service_teams_project_id` ON `service_teams`(`project_id`);
+CREATE INDEX `idx_service_teams_deleted_at` ON `service_teams`(`deleted_at`);
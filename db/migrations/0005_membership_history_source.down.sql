@@ -0,0 +1 @@
+ALTER TABLE membership_histories DROP COLUMN source;
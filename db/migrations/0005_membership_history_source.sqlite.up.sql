@@ -0,0 +1 @@
+ALTER TABLE `membership_histories` ADD COLUMN `source` text;
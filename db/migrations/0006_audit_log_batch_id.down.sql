@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_audit_logs_batch_id;
+ALTER TABLE audit_logs DROP COLUMN batch_id;
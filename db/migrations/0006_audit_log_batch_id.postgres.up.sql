@@ -0,0 +1,17 @@
+ALTER TABLE audit_logs ADD COLUMN batch_id varchar(32);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_batch_id ON audit_logs (batch_id);
+-- Entries written before the column existed carry the batch only in their metadata. Metadata is free text, so each
+-- row is cast on its own and rows that are not valid JSON are left without a batch instead of failing the migration.
+DO $$
+DECLARE
+    entry record;
+BEGIN
+    FOR entry IN SELECT id, metadata FROM audit_logs WHERE metadata LIKE '{%"batch":{%' LOOP
+        BEGIN
+            UPDATE audit_logs SET batch_id = entry.metadata::jsonb -> 'batch' ->> 'id' WHERE id = entry.id;
+        EXCEPTION WHEN invalid_text_representation THEN
+            NULL;
+        END;
+    END LOOP;
+END
+$$;
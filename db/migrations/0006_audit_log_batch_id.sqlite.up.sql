@@ -0,0 +1,5 @@
+ALTER TABLE `audit_logs` ADD COLUMN `batch_id` text;
+CREATE INDEX IF NOT EXISTS `idx_audit_logs_batch_id` ON `audit_logs`(`batch_id`);
+-- Entries written before the column existed carry the batch only in their metadata.
+UPDATE `audit_logs` SET `batch_id` = json_extract(`metadata`, '$.batch.id')
+WHERE `metadata` LIKE '%"batch":{%' AND json_valid(`metadata`);
@@ -0,0 +1 @@
+ALTER TABLE maintainers DROP COLUMN email_verification_sent_at;
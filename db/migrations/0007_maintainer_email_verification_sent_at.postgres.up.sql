@@ -0,0 +1 @@
+ALTER TABLE maintainers ADD COLUMN email_verification_sent_at timestamptz;
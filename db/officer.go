@@ -0,0 +1,507 @@
+package db
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"maintainerd/model"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOfficerNotFound     = errors.New("foundation officer not found")
+	ErrOfficerExists       = errors.New("a foundation officer with that GitHub account or email already exists")
+	ErrServiceNotFound     = errors.New("service not found")
+	ErrServiceUserNotFound = errors.New("service user not found")
+	ErrServiceIdentity     = errors.New("a service user id or email is required")
+	ErrOfficerNotLinked    = errors.New("the officer has no identity linked on that service")
+	ErrOfficerActing       = errors.New("the officer is the acting identity for a service; choose another officer first")
+)
+
+// FoundationOfficerInput describes a foundation officer to register.
+type FoundationOfficerInput struct {
+	Name          string
+	Email         string
+	GitHubAccount string
+	CompanyID     *uint
+}
+
+// FoundationOfficerUpdate holds the officer fields to change; nil fields are left as they are. A CompanyID of 0
+// removes the officer's company.
+type FoundationOfficerUpdate struct {
+	Name          *string
+	Email         *string
+	GitHubAccount *string
+	CompanyID     *uint
+}
+
+// OfficerServiceInput identifies an officer's account on a service. The account is matched on the remote user ID
+// when it is known and on the email otherwise, and is recorded as a ServiceUser if it is not one already.
+type OfficerServiceInput struct {
+	ServiceID uint
+	// RemoteID is the user's ID on the service, such as the FOSSA user ID.
+	RemoteID   int
+	Email      string
+	Ref        string
+	GitHubName string
+}
+
+// OfficerIdentity is the officer and service account that automated actions on a service are performed as. It is
+// recorded in the metadata of the audit entries for those actions.
+type OfficerIdentity struct {
+	OfficerID     uint   `json:"officerId"`
+	Name          string `json:"name"`
+	GitHubAccount string `json:"githubAccount,omitempty"`
+	ServiceID     uint   `json:"serviceId"`
+	Service       string `json:"service"`
+	ServiceUserID uint   `json:"serviceUserId"`
+	RemoteUserID  int    `json:"remoteUserId,omitempty"`
+	ServiceEmail  string `json:"serviceEmail,omitempty"`
+}
+
+// ListFoundationOfficers returns every officer with their service identities, ordered by name.
+func (s *SQLStore) ListFoundationOfficers() ([]model.FoundationOfficer, error) {
+	var officers []model.FoundationOfficer
+	if err := s.db.Preload("Services").Order("name, id").Find(&officers).Error; err != nil {
+		return nil, err
+	}
+	return officers, nil
+}
+
+// GetFoundationOfficer returns the officer with id and their service identities, or ErrOfficerNotFound.
+func (s *SQLStore) GetFoundationOfficer(id uint) (*model.FoundationOfficer, error) {
+	var officer model.FoundationOfficer
+	if err := s.db.Preload("Services", func(db *gorm.DB) *gorm.DB { return db.Order("service_users.id") }).
+		First(&officer, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOfficerNotFound
+		}
+		return nil, err
+	}
+	return &officer, nil
+}
+
+// CreateFoundationOfficer registers a foundation officer.
+func (s *SQLStore) CreateFoundationOfficer(input FoundationOfficerInput) (*model.FoundationOfficer, error) {
+	now := time.Now().UTC()
+	officer := model.FoundationOfficer{
+		Name:          strings.TrimSpace(input.Name),
+		Email:         normalizeOrSentinel(input.Email, "EMAIL_MISSING"),
+		GitHubAccount: normalizeOrSentinel(input.GitHubAccount, "GITHUB_MISSING"),
+		RegisteredAt:  &now,
+	}
+	if officer.Name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkOfficerUnique(tx, officerGitHub(officer), officerEmail(officer), 0); err != nil {
+			return err
+		}
+		if input.CompanyID != nil && *input.CompanyID != 0 {
+			if err := checkCompanyExists(tx, *input.CompanyID); err != nil {
+				return err
+			}
+			officer.CompanyID = input.CompanyID
+		}
+		return tx.Create(&officer).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetFoundationOfficer(officer.ID)
+}
+
+// UpdateFoundationOfficer changes an officer's details or company.
+func (s *SQLStore) UpdateFoundationOfficer(id uint, update FoundationOfficerUpdate) (*model.FoundationOfficer, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var officer model.FoundationOfficer
+		if err := tx.First(&officer, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrOfficerNotFound
+			}
+			return err
+		}
+		updates := make(map[string]any)
+		if update.Name != nil {
+			name := strings.TrimSpace(*update.Name)
+			if name == "" {
+				return fmt.Errorf("name is required")
+			}
+			updates["name"] = name
+		}
+		email, account := "", ""
+		if update.Email != nil {
+			email = strings.TrimSpace(*update.Email)
+			updates["email"] = normalizeOrSentinel(email, "EMAIL_MISSING")
+		}
+		if update.GitHubAccount != nil {
+			account = strings.TrimSpace(*update.GitHubAccount)
+			updates["git_hub_account"] = normalizeOrSentinel(account, "GITHUB_MISSING")
+		}
+		if err := checkOfficerUnique(tx, account, email, id); err != nil {
+			return err
+		}
+		if update.CompanyID != nil {
+			if *update.CompanyID == 0 {
+				updates["company_id"] = nil
+			} else {
+				if err := checkCompanyExists(tx, *update.CompanyID); err != nil {
+					return err
+				}
+				updates["company_id"] = *update.CompanyID
+			}
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+		return tx.Model(&officer).Updates(updates).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetFoundationOfficer(id)
+}
+
+// DeleteFoundationOfficer removes an officer. An officer who is the acting identity for a service cannot be
+// removed until another officer is chosen.
+func (s *SQLStore) DeleteFoundationOfficer(id uint) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Select("id").First(&model.FoundationOfficer{}, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrOfficerNotFound
+			}
+			return err
+		}
+		var acting int64
+		if err := tx.Model(&model.Service{}).Where("acting_officer_id = ?", id).Count(&acting).Error; err != nil {
+			return err
+		}
+		if acting > 0 {
+			return ErrOfficerActing
+		}
+		return tx.Delete(&model.FoundationOfficer{}, id).Error
+	})
+}
+
+// LinkOfficerService links an officer to their account on a service, recording the account as a ServiceUser if it
+// is not one already.
+func (s *SQLStore) LinkOfficerService(officerID uint, input OfficerServiceInput) (*model.ServiceUser, error) {
+	email := strings.TrimSpace(input.Email)
+	if input.RemoteID == 0 && email == "" {
+		return nil, ErrServiceIdentity
+	}
+	var user model.ServiceUser
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var officer model.FoundationOfficer
+		if err := tx.First(&officer, officerID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrOfficerNotFound
+			}
+			return err
+		}
+		if err := tx.Select("id").First(&model.Service{}, input.ServiceID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrServiceNotFound
+			}
+			return err
+		}
+		query := tx.Where("service_id = ?", input.ServiceID)
+		if input.RemoteID != 0 {
+			query = query.Where("service_user_id = ?", input.RemoteID)
+		} else {
+			query = query.Where("LOWER(service_email) = ?", strings.ToLower(email))
+		}
+		err := query.Order("id").First(&user).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			user = model.ServiceUser{
+				ServiceID:     input.ServiceID,
+				ServiceUserID: input.RemoteID,
+				ServiceEmail:  normalizeOrSentinel(email, "EMAIL_MISSING"),
+				ServiceRef:    strings.TrimSpace(input.Ref),
+			}
+			if name := strings.TrimSpace(input.GitHubName); name != "" {
+				user.ServiceGitHubName = &name
+			}
+			if err := tx.Create(&user).Error; err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		}
+		return tx.Model(&officer).Association("Services").Append(&user)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UnlinkOfficerService removes the link between an officer and a service account. The officer's last account on a
+// service they act for cannot be unlinked. The ServiceUser itself is kept.
+func (s *SQLStore) UnlinkOfficerService(officerID, serviceUserID uint) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var officer model.FoundationOfficer
+		if err := tx.First(&officer, officerID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrOfficerNotFound
+			}
+			return err
+		}
+		users, err := officerServiceUsers(tx, officerID)
+		if err != nil {
+			return err
+		}
+		var unlinked *model.ServiceUser
+		remaining := make(map[uint]int)
+		for i := range users {
+			if users[i].ID == serviceUserID {
+				unlinked = &users[i]
+				continue
+			}
+			remaining[users[i].ServiceID]++
+		}
+		if unlinked == nil {
+			return ErrServiceUserNotFound
+		}
+		if remaining[unlinked.ServiceID] == 0 {
+			var acting int64
+			if err := tx.Model(&model.Service{}).
+				Where("id = ? AND acting_officer_id = ?", unlinked.ServiceID, officerID).
+				Count(&acting).Error; err != nil {
+				return err
+			}
+			if acting > 0 {
+				return ErrOfficerActing
+			}
+		}
+		return tx.Model(&officer).Association("Services").Delete(unlinked)
+	})
+}
+
+// ListServices returns the services, ordered by name.
+func (s *SQLStore) ListServices() ([]model.Service, error) {
+	var services []model.Service
+	if err := s.db.Order("name, id").Find(&services).Error; err != nil {
+		return nil, err
+	}
+	return services, nil
+}
+
+// SetActingOfficer chooses the officer whose identity performs automated actions on a service. An officerID of 0
+// clears the choice. The officer must have an account linked on the service.
+func (s *SQLStore) SetActingOfficer(serviceID, officerID uint) (*model.Service, error) {
+	var service model.Service
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&service, serviceID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrServiceNotFound
+			}
+			return err
+		}
+		var acting any
+		if officerID != 0 {
+			if err := tx.Select("id").First(&model.FoundationOfficer{}, officerID).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return ErrOfficerNotFound
+				}
+				return err
+			}
+			if _, err := officerServiceUser(tx, officerID, serviceID); err != nil {
+				return err
+			}
+			acting = officerID
+		}
+		if err := tx.Model(&service).Update("acting_officer_id", acting).Error; err != nil {
+			return err
+		}
+		return tx.First(&service, serviceID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
+
+// ActingOfficerIdentity returns the identity automated actions on the named service are performed as, or nil if
+// the service is unknown or has no acting officer.
+func (s *SQLStore) ActingOfficerIdentity(serviceName string) (*OfficerIdentity, error) {
+	var service model.Service
+	if err := s.db.Where("name = ?", serviceName).First(&service).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if service.ActingOfficerID == nil {
+		return nil, nil
+	}
+	var officer model.FoundationOfficer
+	if err := s.db.First(&officer, *service.ActingOfficerID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	user, err := officerServiceUser(s.db, officer.ID, service.ID)
+	if err != nil {
+		if errors.Is(err, ErrOfficerNotLinked) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	identity := &OfficerIdentity{
+		OfficerID:     officer.ID,
+		Name:          officer.Name,
+		GitHubAccount: officerGitHub(officer),
+		ServiceID:     service.ID,
+		Service:       service.Name,
+		ServiceUserID: user.ID,
+		RemoteUserID:  user.ServiceUserID,
+	}
+	if user.ServiceEmail != "EMAIL_MISSING" {
+		identity.ServiceEmail = user.ServiceEmail
+	}
+	return identity, nil
+}
+
+// LogServiceAction appends event, an action carried out on the named remote service, recording the service's
+// acting officer identity in its metadata and setting its ServiceID. event.Metadata, if set, must hold
+// AuditMetadata JSON. When the service has no acting officer the entry is written without one.
+func (s *SQLStore) LogServiceAction(serviceName string, event model.AuditLog) error {
+	var metadata AuditMetadata
+	if event.Metadata != "" {
+		parsed, err := ParseAuditMetadata(event.Metadata)
+		if err != nil {
+			return fmt.Errorf("parse audit metadata: %w", err)
+		}
+		metadata = *parsed
+	}
+	identity, err := s.ActingOfficerIdentity(serviceName)
+	if err != nil {
+		return err
+	}
+	if identity != nil {
+		metadata.Officer = identity
+		if metadata.Actor.Login == "" {
+			metadata.Actor = auditMetadataActor{Login: identity.GitHubAccount, Role: "officer"}
+		}
+		event.ServiceID = &identity.ServiceID
+		raw, err := json.Marshal(metadata)
+		if err != nil {
+			return err
+		}
+		event.Metadata = string(raw)
+	} else if event.ServiceID == nil {
+		if service, err := s.getServiceByName(serviceName); err == nil {
+			event.ServiceID = &service.ID
+		}
+	}
+	return s.AppendAuditLog(&event)
+}
+
+// officerServiceUsers returns the service accounts linked to an officer, ordered by ID.
+func officerServiceUsers(tx *gorm.DB, officerID uint) ([]model.ServiceUser, error) {
+	var users []model.ServiceUser
+	err := tx.Joins("JOIN foundation_officer_service_users ON foundation_officer_service_users.service_user_id = service_users.id").
+		Where("foundation_officer_service_users.foundation_officer_id = ?", officerID).
+		Order("service_users.id").
+		Find(&users).Error
+	return users, err
+}
+
+// officerServiceUser returns the officer's first linked account on a service, or ErrOfficerNotLinked.
+func officerServiceUser(tx *gorm.DB, officerID, serviceID uint) (*model.ServiceUser, error) {
+	users, err := officerServiceUsers(tx, officerID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range users {
+		if users[i].ServiceID == serviceID {
+			return &users[i], nil
+		}
+	}
+	return nil, ErrOfficerNotLinked
+}
+
+// checkOfficerUnique returns ErrOfficerExists if an officer other than exceptID has the GitHub account or email.
+// Empty values are not checked.
+func checkOfficerUnique(tx *gorm.DB, account, email string, exceptID uint) error {
+	check := func(column, value string) error {
+		if value == "" {
+			return nil
+		}
+		var count int64
+		if err := tx.Model(&model.FoundationOfficer{}).
+			Where("LOWER("+column+") = ? AND id <> ?", strings.ToLower(value), exceptID).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrOfficerExists
+		}
+		return nil
+	}
+	if err := check("git_hub_account", account); err != nil {
+		return err
+	}
+	return check("email", email)
+}
+
+func checkCompanyExists(tx *gorm.DB, id uint) error {
+	if err := tx.Select("id").First(&model.Company{}, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrCompanyNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func officerEmail(officer model.FoundationOfficer) string {
+	if officer.Email == "EMAIL_MISSING" {
+		return ""
+	}
+	return strings.TrimSpace(officer.Email)
+}
+
+func officerGitHub(officer model.FoundationOfficer) string {
+	if officer.GitHubAccount == "GITHUB_MISSING" {
+		return ""
+	}
+	return strings.TrimSpace(officer.GitHubAccount)
+}
+
+// officerAuditFields loads the audited view of an officer, including soft-deleted ones.
+func officerAuditFields(tx *gorm.DB, officerID uint) (map[string]string, error) {
+	var officer model.FoundationOfficer
+	if err := tx.Unscoped().First(&officer, officerID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOfficerNotFound
+		}
+		return nil, err
+	}
+	if officer.DeletedAt.Valid {
+		return map[string]string{}, nil
+	}
+	users, err := officerServiceUsers(tx, officerID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(users))
+	for _, user := range users {
+		ids = append(ids, strconv.FormatUint(uint64(user.ID), 10))
+	}
+	return map[string]string{
+		"name":           officer.Name,
+		"email":          officerEmail(officer),
+		"github":         officerGitHub(officer),
+		"companyId":      formatAuditID(officer.CompanyID),
+		"serviceUserIds": strings.Join(ids, ","),
+	}, nil
+}
@@ -0,0 +1,130 @@
+package db
+
+import (
+	"testing"
+
+	"maintainerd/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFoundationOfficerServices(t *testing.T) {
+	db, store, _ := setupAuditedStore(t)
+	require.NoError(t, db.AutoMigrate(&model.ServiceUser{}, &model.FoundationOfficer{}))
+	fossa := model.Service{Name: "FOSSA"}
+	require.NoError(t, db.Create(&fossa).Error)
+
+	olga, err := store.CreateFoundationOfficer(FoundationOfficerInput{Name: "Olga", Email: "olga@example.org", GitHubAccount: "olga"})
+	require.NoError(t, err)
+	require.NotNil(t, olga.RegisteredAt)
+	_, err = store.CreateFoundationOfficer(FoundationOfficerInput{Name: "Olga Again", GitHubAccount: "OLGA"})
+	assert.ErrorIs(t, err, ErrOfficerExists)
+
+	_, err = store.SetActingOfficer(fossa.ID, olga.ID)
+	assert.ErrorIs(t, err, ErrOfficerNotLinked)
+	_, err = store.LinkOfficerService(olga.ID, OfficerServiceInput{ServiceID: fossa.ID})
+	assert.ErrorIs(t, err, ErrServiceIdentity)
+	admin, err := store.LinkOfficerService(olga.ID, OfficerServiceInput{ServiceID: fossa.ID, RemoteID: 9001, Email: "fossa-admin@example.org"})
+	require.NoError(t, err)
+	again, err := store.LinkOfficerService(olga.ID, OfficerServiceInput{ServiceID: fossa.ID, RemoteID: 9001})
+	require.NoError(t, err)
+	assert.Equal(t, admin.ID, again.ID, "an existing service user is reused")
+
+	links := auditEntries(t, db, "OFFICER_SERVICE_LINK")
+	require.Len(t, links, 1, "relinking the same account changes nothing")
+	assert.Equal(t, AuditChange{To: "1"}, auditMetadata(t, links[0]).Changes["serviceUserIds"])
+
+	service, err := store.SetActingOfficer(fossa.ID, olga.ID)
+	require.NoError(t, err)
+	require.NotNil(t, service.ActingOfficerID)
+	identity, err := store.ActingOfficerIdentity("FOSSA")
+	require.NoError(t, err)
+	require.NotNil(t, identity)
+	assert.Equal(t, OfficerIdentity{
+		OfficerID:     olga.ID,
+		Name:          "Olga",
+		GitHubAccount: "olga",
+		ServiceID:     fossa.ID,
+		Service:       "FOSSA",
+		ServiceUserID: admin.ID,
+		RemoteUserID:  9001,
+		ServiceEmail:  "fossa-admin@example.org",
+	}, *identity)
+
+	assert.ErrorIs(t, store.UnlinkOfficerService(olga.ID, admin.ID), ErrOfficerActing)
+	assert.ErrorIs(t, store.DeleteFoundationOfficer(olga.ID), ErrOfficerActing)
+
+	acting := auditEntries(t, db, "SERVICE_ACTING_OFFICER_UPDATE")
+	require.Len(t, acting, 1)
+	require.NotNil(t, acting[0].ServiceID)
+	_, err = store.RevertAuditEntry(acting[0].ID)
+	require.NoError(t, err)
+	identity, err = store.ActingOfficerIdentity("FOSSA")
+	require.NoError(t, err)
+	assert.Nil(t, identity)
+
+	require.NoError(t, store.UnlinkOfficerService(olga.ID, admin.ID))
+	assert.ErrorIs(t, store.UnlinkOfficerService(olga.ID, admin.ID), ErrServiceUserNotFound)
+	_, err = store.RevertAuditEntry(auditEntries(t, db, "OFFICER_SERVICE_UNLINK")[0].ID)
+	require.NoError(t, err)
+	restored, err := store.GetFoundationOfficer(olga.ID)
+	require.NoError(t, err)
+	require.Len(t, restored.Services, 1)
+	assert.Equal(t, admin.ID, restored.Services[0].ID)
+
+	require.NoError(t, store.DeleteFoundationOfficer(olga.ID))
+	_, err = store.GetFoundationOfficer(olga.ID)
+	assert.ErrorIs(t, err, ErrOfficerNotFound)
+	deleted := auditEntries(t, db, "OFFICER_DELETE")
+	require.Len(t, deleted, 1)
+	assert.Equal(t, "Foundation officer Olga removed by Sam Staff", deleted[0].Message)
+	_, err = store.RevertAuditEntry(deleted[0].ID)
+	require.NoError(t, err)
+	_, err = store.GetFoundationOfficer(olga.ID)
+	assert.NoError(t, err)
+}
+
+func TestLogServiceActionRecordsActingOfficer(t *testing.T) {
+	db, store, sam := setupAuditedStore(t)
+	require.NoError(t, db.AutoMigrate(&model.ServiceUser{}, &model.FoundationOfficer{}))
+	fossa := model.Service{Name: "FOSSA"}
+	require.NoError(t, db.Create(&fossa).Error)
+	projectID := uint(7)
+
+	// Without an acting officer the entry is written but attributed to no officer.
+	require.NoError(t, store.SQLStore.LogServiceAction("FOSSA", model.AuditLog{ProjectID: &projectID, Action: "FOSSA_CREATE_TEAM"}))
+	entries := auditEntries(t, db, "FOSSA_CREATE_TEAM")
+	require.Len(t, entries, 1)
+	require.NotNil(t, entries[0].ServiceID)
+	assert.Equal(t, fossa.ID, *entries[0].ServiceID)
+	assert.Empty(t, entries[0].Metadata)
+
+	olga, err := store.CreateFoundationOfficer(FoundationOfficerInput{Name: "Olga", GitHubAccount: "olga"})
+	require.NoError(t, err)
+	admin, err := store.LinkOfficerService(olga.ID, OfficerServiceInput{ServiceID: fossa.ID, RemoteID: 9001})
+	require.NoError(t, err)
+	_, err = store.SetActingOfficer(fossa.ID, olga.ID)
+	require.NoError(t, err)
+
+	require.NoError(t, store.SQLStore.LogServiceAction("FOSSA", model.AuditLog{ProjectID: &projectID, Action: "FOSSA_ADD_MEMBER"}))
+	entries = auditEntries(t, db, "FOSSA_ADD_MEMBER")
+	require.Len(t, entries, 1)
+	metadata := auditMetadata(t, entries[0])
+	require.NotNil(t, metadata.Officer)
+	assert.Equal(t, admin.ID, metadata.Officer.ServiceUserID)
+	assert.Equal(t, "olga", metadata.Actor.Login)
+	assert.Equal(t, "officer", metadata.Actor.Role)
+
+	// A staff-triggered action keeps the staff member as the actor.
+	maintainerID := uint(3)
+	require.NoError(t, store.LogServiceAction("FOSSA", model.AuditLog{ProjectID: &projectID, MaintainerID: &maintainerID, Action: "FOSSA_REMOVE_MEMBER"}))
+	entries = auditEntries(t, db, "FOSSA_REMOVE_MEMBER")
+	require.Len(t, entries, 1)
+	assert.Equal(t, sam.ID, *entries[0].StaffID)
+	assert.Equal(t, maintainerID, *entries[0].MaintainerID)
+	metadata = auditMetadata(t, entries[0])
+	assert.Equal(t, "sam", metadata.Actor.Login)
+	require.NotNil(t, metadata.Officer)
+	assert.Equal(t, olga.ID, metadata.Officer.OfficerID)
+}
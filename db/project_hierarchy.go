@@ -0,0 +1,164 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"maintainerd/model"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrParentProjectNotFound = errors.New("parent project not found")
+	ErrProjectCycle          = errors.New("a project cannot be nested under itself or one of its sub-projects")
+)
+
+// FamilyMaintainer is a maintainer of at least one project in a project family.
+type FamilyMaintainer struct {
+	Maintainer model.Maintainer
+	// ProjectIDs are the family projects the maintainer belongs to, in family order.
+	ProjectIDs []uint
+}
+
+// ListChildProjects returns a project's direct sub-projects, ordered by name.
+func (s *SQLStore) ListChildProjects(projectID uint) ([]model.Project, error) {
+	var children []model.Project
+	err := s.db.Preload("Maintainers").
+		Where("parent_project_id = ?", projectID).
+		Order("name").
+		Find(&children).Error
+	return children, err
+}
+
+// ListProjectDescendants returns every sub-project below the given projects, level by level and ordered by name
+// within each level.
+func (s *SQLStore) ListProjectDescendants(projectIDs []uint) ([]model.Project, error) {
+	seen := make(map[uint]struct{}, len(projectIDs))
+	for _, id := range projectIDs {
+		seen[id] = struct{}{}
+	}
+	var descendants []model.Project
+	level := projectIDs
+	for len(level) > 0 {
+		var children []model.Project
+		if err := s.db.Preload("Maintainers").
+			Where("parent_project_id IN ?", level).
+			Order("name").
+			Find(&children).Error; err != nil {
+			return nil, err
+		}
+		level = nil
+		for _, child := range children {
+			// Guard against parent loops written before reparenting was checked.
+			if _, ok := seen[child.ID]; ok {
+				continue
+			}
+			seen[child.ID] = struct{}{}
+			descendants = append(descendants, child)
+			level = append(level, child.ID)
+		}
+	}
+	return descendants, nil
+}
+
+// ListProjectFamilyMaintainers returns the rolled-up maintainers of a project and all of its sub-projects, ordered by
+// name. The family's project IDs are returned alongside, starting with the project itself.
+func (s *SQLStore) ListProjectFamilyMaintainers(projectID uint) ([]uint, []FamilyMaintainer, error) {
+	if err := s.db.Select("id").First(&model.Project{}, projectID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrProjectNotFound
+		}
+		return nil, nil, err
+	}
+	descendants, err := s.ListProjectDescendants([]uint{projectID})
+	if err != nil {
+		return nil, nil, err
+	}
+	familyIDs := []uint{projectID}
+	for _, project := range descendants {
+		familyIDs = append(familyIDs, project.ID)
+	}
+	order := make(map[uint]int, len(familyIDs))
+	for i, id := range familyIDs {
+		order[id] = i
+	}
+
+	var memberships []model.MaintainerProject
+	if err := s.db.Where("project_id IN ?", familyIDs).Find(&memberships).Error; err != nil {
+		return nil, nil, err
+	}
+	projectIDsByMaintainer := make(map[uint][]uint)
+	maintainerIDs := make([]uint, 0, len(memberships))
+	for _, membership := range memberships {
+		if _, ok := projectIDsByMaintainer[membership.MaintainerID]; !ok {
+			maintainerIDs = append(maintainerIDs, membership.MaintainerID)
+		}
+		projectIDsByMaintainer[membership.MaintainerID] = append(projectIDsByMaintainer[membership.MaintainerID], membership.ProjectID)
+	}
+	if len(maintainerIDs) == 0 {
+		return familyIDs, []FamilyMaintainer{}, nil
+	}
+	var maintainers []model.Maintainer
+	if err := s.db.Preload("Company").
+		Where("id IN ?", maintainerIDs).
+		Order("name, id").
+		Find(&maintainers).Error; err != nil {
+		return nil, nil, err
+	}
+	result := make([]FamilyMaintainer, 0, len(maintainers))
+	for _, maintainer := range maintainers {
+		projectIDs := projectIDsByMaintainer[maintainer.ID]
+		sort.Slice(projectIDs, func(i, j int) bool { return order[projectIDs[i]] < order[projectIDs[j]] })
+		result = append(result, FamilyMaintainer{Maintainer: maintainer, ProjectIDs: projectIDs})
+	}
+	return familyIDs, result, nil
+}
+
+// SetProjectParent nests a project under parentID, or makes it a top-level project when parentID is nil. It fails
+// with ErrProjectCycle if the parent is the project itself or one of its sub-projects.
+func (s *SQLStore) SetProjectParent(projectID uint, parentID *uint) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Select("id").First(&model.Project{}, projectID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrProjectNotFound
+			}
+			return err
+		}
+		if err := checkProjectParent(tx, projectID, parentID); err != nil {
+			return err
+		}
+		return tx.Model(&model.Project{}).Where("id = ?", projectID).Update("parent_project_id", parentID).Error
+	})
+}
+
+// checkProjectParent walks up from parentID and fails if it reaches projectID.
+func checkProjectParent(tx *gorm.DB, projectID uint, parentID *uint) error {
+	if parentID == nil {
+		return nil
+	}
+	seen := make(map[uint]struct{})
+	next := parentID
+	for next != nil {
+		if *next == projectID {
+			return ErrProjectCycle
+		}
+		if _, ok := seen[*next]; ok {
+			return fmt.Errorf("%w: project %d is already part of a loop", ErrProjectCycle, *next)
+		}
+		seen[*next] = struct{}{}
+		var ancestor model.Project
+		if err := tx.Select("id", "parent_project_id").First(&ancestor, *next).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				if *next == *parentID {
+					return ErrParentProjectNotFound
+				}
+				return nil
+			}
+			return err
+		}
+		next = ancestor.ParentProjectID
+	}
+	return nil
+}
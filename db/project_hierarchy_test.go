@@ -0,0 +1,61 @@
+package db
+
+import (
+	"testing"
+
+	"maintainerd/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectHierarchy(t *testing.T) {
+	db, store, _ := setupAuditedStore(t)
+	_, project1, project2, alice, bob, charlie := seedTestData(t, db)
+	sigNode := model.Project{Name: "sig-node", Maturity: model.Graduated}
+	require.NoError(t, db.Create(&sigNode).Error)
+
+	require.NoError(t, store.SetProjectParent(sigNode.ID, &project1.ID))
+	require.NoError(t, store.SetProjectParent(project2.ID, &sigNode.ID))
+	assert.ErrorIs(t, store.SetProjectParent(project1.ID, &project2.ID), ErrProjectCycle)
+	assert.ErrorIs(t, store.SetProjectParent(project1.ID, &project1.ID), ErrProjectCycle)
+	missing := uint(999)
+	assert.ErrorIs(t, store.SetProjectParent(project1.ID, &missing), ErrParentProjectNotFound)
+
+	children, err := store.ListChildProjects(project1.ID)
+	require.NoError(t, err)
+	require.Len(t, children, 1)
+	assert.Equal(t, "sig-node", children[0].Name)
+
+	descendants, err := store.ListProjectDescendants([]uint{project1.ID})
+	require.NoError(t, err)
+	require.Len(t, descendants, 2)
+	assert.Equal(t, []uint{sigNode.ID, project2.ID}, []uint{descendants[0].ID, descendants[1].ID})
+
+	familyIDs, maintainers, err := store.ListProjectFamilyMaintainers(project1.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []uint{project1.ID, sigNode.ID, project2.ID}, familyIDs)
+	require.Len(t, maintainers, 3)
+	assert.Equal(t, alice.ID, maintainers[0].Maintainer.ID)
+	assert.Equal(t, []uint{project1.ID}, maintainers[0].ProjectIDs)
+	assert.Equal(t, bob.ID, maintainers[1].Maintainer.ID)
+	assert.Equal(t, []uint{project1.ID, project2.ID}, maintainers[1].ProjectIDs, "bob is listed once across the family")
+	assert.Equal(t, charlie.ID, maintainers[2].Maintainer.ID)
+
+	entries := auditEntries(t, db, "PROJECT_PARENT_UPDATE")
+	require.Len(t, entries, 2)
+	assert.Equal(t, AuditChange{To: formatAuditID(&sigNode.ID)}, auditMetadata(t, entries[1]).Changes["parentProjectId"])
+
+	// Detaching sig-node and then nesting kubernetes below it means undoing the detach would create a loop.
+	require.NoError(t, store.SetProjectParent(sigNode.ID, nil))
+	detached := auditEntries(t, db, "PROJECT_PARENT_UPDATE")
+	require.NoError(t, store.SetProjectParent(project1.ID, &sigNode.ID))
+	_, err = store.RevertAuditEntry(detached[len(detached)-1].ID)
+	assert.ErrorIs(t, err, ErrRevertConflict)
+
+	_, err = store.RevertAuditEntry(entries[1].ID)
+	require.NoError(t, err)
+	var reverted model.Project
+	require.NoError(t, db.First(&reverted, project2.ID).Error)
+	assert.Nil(t, reverted.ParentProjectID)
+}
@@ -0,0 +1,302 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"maintainerd/model"
+
+	"gorm.io/gorm"
+)
+
+var ErrInvalidMaturityTransition = errors.New("invalid maturity transition")
+
+// maturityTransitions lists the maturity levels a project can move to from each level. A project with no maturity
+// recorded can move to any level; an archived project cannot move.
+var maturityTransitions = map[model.Maturity][]model.Maturity{
+	model.Sandbox:    {model.Incubating, model.Archived},
+	model.Incubating: {model.Graduated, model.Archived},
+	model.Graduated:  {model.Archived},
+}
+
+// MaturityTransition describes a move to a new maturity level.
+type MaturityTransition struct {
+	To model.Maturity
+	// EffectiveAt is when the move took effect; the zero time means now.
+	EffectiveAt time.Time
+	// VoteIssueURL optionally links the TOC vote issue.
+	VoteIssueURL string
+}
+
+// ProjectLifecycle records what a lifecycle operation on a project did, so that it can be undone.
+type ProjectLifecycle struct {
+	ProjectID uint `json:"projectId"`
+	// MaturityEventID is the ProjectMaturityEvent a maturity transition recorded.
+	MaturityEventID uint `json:"maturityEventId,omitempty"`
+	// ArchivedMemberships are the memberships archival moved to Archived, with their previous status.
+	ArchivedMemberships []ArchivedMembership `json:"archivedMemberships,omitempty"`
+	// CleanupServiceTeamIDs are the service teams archival flagged for cleanup.
+	CleanupServiceTeamIDs []uint `json:"cleanupServiceTeamIds,omitempty"`
+	// AliasID is the alias a rename created for the project's old name.
+	AliasID uint `json:"aliasId,omitempty"`
+}
+
+// ArchivedMembership is a membership archival changed and the status it had before.
+type ArchivedMembership struct {
+	MaintainerID uint                   `json:"maintainerId"`
+	FromStatus   model.MaintainerStatus `json:"fromStatus"`
+}
+
+// CanTransitionMaturity reports whether a project can move from one maturity level to another.
+func CanTransitionMaturity(from, to model.Maturity) bool {
+	if !to.IsValid() {
+		return false
+	}
+	if from == "" {
+		return true
+	}
+	for _, next := range maturityTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionProjectMaturity moves a project to a new maturity level and records a dated ProjectMaturityEvent.
+// Archiving a project also archives its memberships and flags its service teams for cleanup.
+func (s *SQLStore) TransitionProjectMaturity(projectID uint, transition MaturityTransition) (*ProjectLifecycle, error) {
+	var lifecycle *ProjectLifecycle
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var project model.Project
+		if err := tx.First(&project, projectID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrProjectNotFound
+			}
+			return err
+		}
+		if !CanTransitionMaturity(project.Maturity, transition.To) {
+			return fmt.Errorf("%w: %s to %s", ErrInvalidMaturityTransition, displayMaturity(project.Maturity), transition.To)
+		}
+		effectiveAt := transition.EffectiveAt
+		if effectiveAt.IsZero() {
+			effectiveAt = time.Now()
+		}
+		event := model.ProjectMaturityEvent{
+			ProjectID:   projectID,
+			ToMaturity:  transition.To,
+			EffectiveAt: effectiveAt.UTC(),
+			ActorLogin:  s.actorLogin,
+		}
+		if project.Maturity != "" {
+			from := project.Maturity
+			event.FromMaturity = &from
+		}
+		if url := strings.TrimSpace(transition.VoteIssueURL); url != "" {
+			event.VoteIssueURL = &url
+		}
+		if err := tx.Create(&event).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&project).Update("maturity", transition.To).Error; err != nil {
+			return err
+		}
+		lifecycle = &ProjectLifecycle{ProjectID: projectID, MaturityEventID: event.ID}
+		if transition.To == model.Archived {
+			return s.archiveProject(tx, projectID, lifecycle)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lifecycle, nil
+}
+
+// archiveProject moves the project's memberships that are not yet archived to Archived and flags its service teams
+// for cleanup, recording both in lifecycle.
+func (s *SQLStore) archiveProject(tx *gorm.DB, projectID uint, lifecycle *ProjectLifecycle) error {
+	var memberships []model.MaintainerProject
+	if err := tx.Where("project_id = ? AND status <> ?", projectID, model.ArchivedMaintainer).
+		Order("maintainer_id").
+		Find(&memberships).Error; err != nil {
+		return err
+	}
+	store := &SQLStore{db: tx, actorLogin: s.actorLogin}
+	for _, membership := range memberships {
+		if _, err := store.UpdateMembership(membership.MaintainerID, projectID, model.ArchivedMaintainer, ""); err != nil {
+			return err
+		}
+		lifecycle.ArchivedMemberships = append(lifecycle.ArchivedMemberships, ArchivedMembership{
+			MaintainerID: membership.MaintainerID,
+			FromStatus:   membership.Status,
+		})
+	}
+	if err := tx.Model(&model.ServiceTeam{}).
+		Where("project_id = ? AND cleanup_requested_at IS NULL", projectID).
+		Order("id").
+		Pluck("id", &lifecycle.CleanupServiceTeamIDs).Error; err != nil {
+		return err
+	}
+	if len(lifecycle.CleanupServiceTeamIDs) == 0 {
+		return nil
+	}
+	return tx.Model(&model.ServiceTeam{}).
+		Where("id IN ?", lifecycle.CleanupServiceTeamIDs).
+		Update("cleanup_requested_at", time.Now().UTC()).Error
+}
+
+// ListProjectMaturityEvents returns a project's maturity history, oldest first.
+func (s *SQLStore) ListProjectMaturityEvents(projectID uint) ([]model.ProjectMaturityEvent, error) {
+	var events []model.ProjectMaturityEvent
+	err := s.db.Where("project_id = ?", projectID).Order("effective_at, id").Find(&events).Error
+	return events, err
+}
+
+// RenameProject renames a project and keeps its old name as an alias. Renaming a project back to one of its own
+// aliases drops that alias. It fails with ErrProjectExists if the name belongs to another project or alias.
+func (s *SQLStore) RenameProject(projectID uint, name string) (*ProjectLifecycle, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("project name is required")
+	}
+	var lifecycle *ProjectLifecycle
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var project model.Project
+		if err := tx.First(&project, projectID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrProjectNotFound
+			}
+			return err
+		}
+		lifecycle = &ProjectLifecycle{ProjectID: projectID}
+		if project.Name == name {
+			return nil
+		}
+		existing, err := resolveProject(tx, name)
+		if err != nil {
+			return err
+		}
+		if existing != nil && existing.ID != projectID {
+			return ErrProjectExists
+		}
+		if existing != nil && !strings.EqualFold(project.Name, name) {
+			if err := tx.Where("project_id = ? AND LOWER(name) = ?", projectID, strings.ToLower(name)).
+				Delete(&model.ProjectAlias{}).Error; err != nil {
+				return err
+			}
+		}
+		if !strings.EqualFold(project.Name, name) {
+			alias := model.ProjectAlias{ProjectID: projectID, Name: project.Name}
+			if err := tx.Create(&alias).Error; err != nil {
+				return err
+			}
+			lifecycle.AliasID = alias.ID
+		}
+		return tx.Model(&project).Update("name", name).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lifecycle, nil
+}
+
+// ListProjectAliases returns a project's former names, ordered by name.
+func (s *SQLStore) ListProjectAliases(projectID uint) ([]model.ProjectAlias, error) {
+	var aliases []model.ProjectAlias
+	err := s.db.Where("project_id = ?", projectID).Order("name").Find(&aliases).Error
+	return aliases, err
+}
+
+// ResolveProject returns the project called name, matching project names first and then aliases, ignoring case.
+// It returns nil if no project matches.
+func (s *SQLStore) ResolveProject(name string) (*model.Project, error) {
+	return resolveProject(s.db, name)
+}
+
+func resolveProject(tx *gorm.DB, name string) (*model.Project, error) {
+	lowered := strings.ToLower(strings.TrimSpace(name))
+	if lowered == "" {
+		return nil, nil
+	}
+	var project model.Project
+	err := tx.Where("LOWER(name) = ?", lowered).First(&project).Error
+	if err == nil {
+		return &project, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	var alias model.ProjectAlias
+	err = tx.Where("LOWER(name) = ?", lowered).First(&alias).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.First(&project, alias.ProjectID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &project, nil
+}
+
+// ListServiceTeamsForCleanup returns the service teams flagged for cleanup when their project was archived.
+func (s *SQLStore) ListServiceTeamsForCleanup() ([]model.ServiceTeam, error) {
+	var teams []model.ServiceTeam
+	err := s.db.Where("cleanup_requested_at IS NOT NULL").Order("cleanup_requested_at, id").Find(&teams).Error
+	return teams, err
+}
+
+// revertProjectLifecycle undoes a maturity transition or rename recorded in lifecycle. The project fields
+// themselves are restored by the caller.
+func revertProjectLifecycle(tx *gorm.DB, store *SQLStore, lifecycle ProjectLifecycle) error {
+	if lifecycle.MaturityEventID != 0 {
+		if err := tx.Delete(&model.ProjectMaturityEvent{}, lifecycle.MaturityEventID).Error; err != nil {
+			return err
+		}
+	}
+	archived := append([]ArchivedMembership(nil), lifecycle.ArchivedMemberships...)
+	sort.Slice(archived, func(i, j int) bool { return archived[i].MaintainerID < archived[j].MaintainerID })
+	for _, membership := range archived {
+		current, err := store.GetMembership(membership.MaintainerID, lifecycle.ProjectID)
+		if errors.Is(err, ErrMembershipNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if current.Status != model.ArchivedMaintainer {
+			continue
+		}
+		if _, err := store.UpdateMembership(membership.MaintainerID, lifecycle.ProjectID, membership.FromStatus, ""); err != nil {
+			return err
+		}
+	}
+	if len(lifecycle.CleanupServiceTeamIDs) > 0 {
+		if err := tx.Model(&model.ServiceTeam{}).
+			Where("id IN ?", lifecycle.CleanupServiceTeamIDs).
+			Update("cleanup_requested_at", nil).Error; err != nil {
+			return err
+		}
+	}
+	if lifecycle.AliasID != 0 {
+		if err := tx.Delete(&model.ProjectAlias{}, lifecycle.AliasID).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func displayMaturity(maturity model.Maturity) string {
+	if maturity == "" {
+		return "no maturity"
+	}
+	return string(maturity)
+}
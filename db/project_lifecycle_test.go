@@ -0,0 +1,125 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"maintainerd/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransitionProjectMaturity(t *testing.T) {
+	db, store, _ := setupAuditedStore(t)
+	_, project1, _, alice, bob, _ := seedTestData(t, db)
+	cedar := model.Project{Name: "cedar", Maturity: model.Sandbox}
+	require.NoError(t, db.Create(&cedar).Error)
+
+	_, err := store.TransitionProjectMaturity(cedar.ID, MaturityTransition{To: model.Graduated})
+	assert.ErrorIs(t, err, ErrInvalidMaturityTransition, "sandbox projects incubate before graduating")
+
+	voted := time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)
+	_, err = store.TransitionProjectMaturity(cedar.ID, MaturityTransition{To: model.Incubating, EffectiveAt: voted, VoteIssueURL: "https://github.com/cncf/toc/issues/1"})
+	require.NoError(t, err)
+	events, err := store.ListProjectMaturityEvents(cedar.ID)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, model.Sandbox, *events[0].FromMaturity)
+	assert.Equal(t, model.Incubating, events[0].ToMaturity)
+	assert.True(t, voted.Equal(events[0].EffectiveAt))
+	assert.Equal(t, "https://github.com/cncf/toc/issues/1", *events[0].VoteIssueURL)
+	assert.Equal(t, "sam", events[0].ActorLogin)
+
+	_, err = store.UpdateMembership(bob.ID, project1.ID, model.EmeritusMaintainer, "")
+	require.NoError(t, err)
+	team := model.ServiceTeam{ProjectID: project1.ID, ServiceID: 1, ServiceTeamID: 30}
+	require.NoError(t, db.Create(&team).Error)
+
+	lifecycle, err := store.TransitionProjectMaturity(project1.ID, MaturityTransition{To: model.Archived})
+	require.NoError(t, err)
+	assert.Equal(t, []ArchivedMembership{
+		{MaintainerID: alice.ID, FromStatus: model.ActiveMaintainer},
+		{MaintainerID: bob.ID, FromStatus: model.EmeritusMaintainer},
+	}, lifecycle.ArchivedMemberships)
+	assert.Equal(t, []uint{team.ID}, lifecycle.CleanupServiceTeamIDs)
+	membership, err := store.GetMembership(alice.ID, project1.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.ArchivedMaintainer, membership.Status)
+	assert.NotNil(t, membership.LeftAt)
+	cleanup, err := store.ListServiceTeamsForCleanup()
+	require.NoError(t, err)
+	require.Len(t, cleanup, 1)
+
+	_, err = store.TransitionProjectMaturity(project1.ID, MaturityTransition{To: model.Graduated})
+	assert.ErrorIs(t, err, ErrInvalidMaturityTransition)
+
+	entries := auditEntries(t, db, "PROJECT_MATURITY_TRANSITION")
+	require.Len(t, entries, 2)
+	assert.Equal(t, AuditChange{From: "Graduated", To: "Archived"}, auditMetadata(t, entries[1]).Changes["maturity"])
+	_, err = store.RevertAuditEntry(entries[1].ID)
+	require.NoError(t, err)
+
+	var project model.Project
+	require.NoError(t, db.First(&project, project1.ID).Error)
+	assert.Equal(t, model.Graduated, project.Maturity)
+	membership, err = store.GetMembership(alice.ID, project1.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.ActiveMaintainer, membership.Status)
+	assert.Nil(t, membership.LeftAt)
+	membership, err = store.GetMembership(bob.ID, project1.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.EmeritusMaintainer, membership.Status)
+	cleanup, err = store.ListServiceTeamsForCleanup()
+	require.NoError(t, err)
+	assert.Empty(t, cleanup)
+	events, err = store.ListProjectMaturityEvents(project1.ID)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+func TestRenameProject(t *testing.T) {
+	db, store, _ := setupAuditedStore(t)
+	_, project1, project2, _, _, _ := seedTestData(t, db)
+
+	_, err := store.RenameProject(project1.ID, "Prometheus")
+	assert.ErrorIs(t, err, ErrProjectExists)
+
+	lifecycle, err := store.RenameProject(project1.ID, "k8s")
+	require.NoError(t, err)
+	assert.NotZero(t, lifecycle.AliasID)
+
+	resolved, err := store.ResolveProject("Kubernetes")
+	require.NoError(t, err)
+	require.NotNil(t, resolved)
+	assert.Equal(t, "k8s", resolved.Name)
+	byName, err := store.GetProjectMapByName()
+	require.NoError(t, err)
+	assert.Equal(t, project1.ID, byName["kubernetes"].ID, "onboarding lookups by the old name still match")
+	assert.Equal(t, project1.ID, byName["k8s"].ID)
+
+	_, err = store.RenameProject(project2.ID, "kubernetes")
+	assert.ErrorIs(t, err, ErrProjectExists, "another project's former name is taken")
+
+	entries := auditEntries(t, db, "PROJECT_RENAME")
+	require.Len(t, entries, 1)
+	assert.Equal(t, AuditChange{From: "kubernetes", To: "k8s"}, auditMetadata(t, entries[0]).Changes["projectName"])
+	_, err = store.RevertAuditEntry(entries[0].ID)
+	require.NoError(t, err)
+	aliases, err := store.ListProjectAliases(project1.ID)
+	require.NoError(t, err)
+	assert.Empty(t, aliases)
+	resolved, err = store.ResolveProject("kubernetes")
+	require.NoError(t, err)
+	assert.Equal(t, project1.ID, resolved.ID)
+
+	// Renaming back to a former name drops that alias rather than keeping both.
+	_, err = store.RenameProject(project1.ID, "k8s")
+	require.NoError(t, err)
+	_, err = store.RenameProject(project1.ID, "kubernetes")
+	require.NoError(t, err)
+	aliases, err = store.ListProjectAliases(project1.ID)
+	require.NoError(t, err)
+	require.Len(t, aliases, 1)
+	assert.Equal(t, "k8s", aliases[0].Name)
+}
@@ -0,0 +1,835 @@
+package db
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"maintainerd/model"
+
+	"gorm.io/gorm"
+)
+
+// ReimportCategory is a kind of change an incremental re-import from the legacy worksheet can make. Only approved
+// categories are applied; the rest are reported and noted on the maintainers they concern.
+type ReimportCategory string
+
+const (
+	// ReimportNewProjects creates projects the database does not have yet.
+	ReimportNewProjects ReimportCategory = "new-projects"
+	// ReimportNewMaintainers creates people the database does not have yet, with a change per project row.
+	ReimportNewMaintainers ReimportCategory = "new-maintainers"
+	// ReimportNewMemberships adds existing maintainers to projects the sheet lists them on.
+	ReimportNewMemberships ReimportCategory = "new-memberships"
+	// ReimportFieldChanges updates maintainer names, emails, GitHub handles and companies, and project maturity,
+	// maintainer refs and mailing lists, that differ from the sheet.
+	ReimportFieldChanges ReimportCategory = "field-changes"
+	// ReimportRemovals marks active maintainers the sheet no longer lists on a project as emeritus. Only memberships
+	// the sheet added are retired; others are reported as warnings.
+	ReimportRemovals ReimportCategory = "removals"
+)
+
+// ReimportCategories lists every category in the order changes are applied.
+var ReimportCategories = []ReimportCategory{
+	ReimportNewProjects,
+	ReimportFieldChanges,
+	ReimportNewMaintainers,
+	ReimportNewMemberships,
+	ReimportRemovals,
+}
+
+var ErrUnknownReimportCategory = errors.New("unknown re-import category")
+
+// ParseReimportCategories parses a comma-separated list of categories. "all" approves every category.
+func ParseReimportCategories(list string) ([]ReimportCategory, error) {
+	var categories []ReimportCategory
+	for _, part := range strings.Split(list, ",") {
+		name := strings.ToLower(strings.TrimSpace(part))
+		switch {
+		case name == "":
+			continue
+		case name == "all":
+			return append([]ReimportCategory(nil), ReimportCategories...), nil
+		}
+		found := false
+		for _, category := range ReimportCategories {
+			if string(category) == name {
+				categories = append(categories, category)
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%w %q", ErrUnknownReimportCategory, name)
+		}
+	}
+	return categories, nil
+}
+
+// ReimportChange is one difference between the sheet and the database.
+type ReimportChange struct {
+	Category     ReimportCategory `json:"category"`
+	Project      string           `json:"project,omitempty"`
+	ProjectID    uint             `json:"projectId,omitempty"`
+	MaintainerID uint             `json:"maintainerId,omitempty"`
+	Name         string           `json:"name,omitempty"`
+	Email        string           `json:"email,omitempty"`
+	GitHub       string           `json:"github,omitempty"`
+	// Field, From and To describe field changes and removals.
+	Field   string `json:"field,omitempty"`
+	From    string `json:"from,omitempty"`
+	To      string `json:"to,omitempty"`
+	Applied bool   `json:"applied"`
+
+	row sheetRow
+}
+
+// ReimportWarning is a problem with the sheet that no category of change resolves.
+type ReimportWarning struct {
+	Project      string `json:"project,omitempty"`
+	MaintainerID uint   `json:"maintainerId,omitempty"`
+	Email        string `json:"email,omitempty"`
+	Message      string `json:"message"`
+}
+
+// ReimportReport is the machine-readable result of comparing the legacy worksheet with the database, and of
+// applying the approved changes when Applied is set.
+type ReimportReport struct {
+	GeneratedAt time.Time                `json:"generatedAt"`
+	Approved    []ReimportCategory       `json:"approved"`
+	Applied     bool                     `json:"applied"`
+	Counts      map[ReimportCategory]int `json:"counts"`
+	Changes     []ReimportChange         `json:"changes"`
+	Warnings    []ReimportWarning        `json:"warnings"`
+	// ImportWarnings is the ImportWarnings value for every maintainer the sheet mentions, keyed by maintainer ID. A
+	// plan reports what a run that applies nothing would write; ApplyReimport writes the values for what it applied.
+	ImportWarnings map[uint]string `json:"importWarnings"`
+
+	// maintainers holds every existing maintainer a sheet row matched.
+	maintainers map[uint]bool
+}
+
+// sheetRow is one row of the Active worksheet.
+type sheetRow struct {
+	Project       string
+	Status        string
+	Parent        string
+	MaintainerRef string
+	MailingList   string
+	Name          string
+	Company       string
+	Email         string
+	GitHub        string
+	GitHubEmail   string
+}
+
+func newSheetRow(row map[string]string) sheetRow {
+	value := func(header string) string { return strings.TrimSpace(row[header]) }
+	return sheetRow{
+		Project:       value(ProjectHdr),
+		Status:        value(StatusHdr),
+		Parent:        value(ParentProjectHdr),
+		MaintainerRef: value(MaintainerFileRefHdr),
+		MailingList:   value(MailingListAddrHdr),
+		Name:          value(MaintainerNameHdr),
+		Company:       value(CompanyNameHdr),
+		Email:         value(EmailHdr),
+		GitHub:        strings.TrimPrefix(value(GitHubHdr), "@"),
+		GitHubEmail:   value(GitHubEmail),
+	}
+}
+
+// PlanReimport compares rows from ImportSource.MaintainerRows with the database without changing anything. The
+// report's ImportWarnings show what a run applying nothing would record on each maintainer.
+func (s *SQLStore) PlanReimport(rows []map[string]string) (*ReimportReport, error) {
+	return planReimport(s.db, rows)
+}
+
+// ApplyReimport compares rows from ImportSource.MaintainerRows with the database and, in one transaction, applies
+// the changes in the approved categories, recording an audit entry for each under one batch. Every maintainer the
+// sheet mentions has ImportWarnings replaced with the warnings and unapplied changes that concern them.
+func (a *AuditedStore) ApplyReimport(rows []map[string]string, approved []ReimportCategory) (*ReimportReport, error) {
+	var report *ReimportReport
+	err := a.write(func(tx *gorm.DB, store *SQLStore) ([]model.AuditLog, error) {
+		store.membershipSource = model.MembershipSourceSheet
+		var err error
+		if report, err = planReimport(tx, rows); err != nil {
+			return nil, err
+		}
+		report.Approved = approved
+		isApproved := make(map[ReimportCategory]bool, len(approved))
+		for _, category := range approved {
+			isApproved[category] = true
+		}
+		var events []model.AuditLog
+		for _, category := range ReimportCategories {
+			if !isApproved[category] {
+				continue
+			}
+			for i := range report.Changes {
+				change := &report.Changes[i]
+				if change.Category != category {
+					continue
+				}
+				applied, err := a.applyReimportChange(tx, store, change)
+				if err != nil {
+					return nil, fmt.Errorf("re-import %s %s: %w", change.Category, change.describe(), err)
+				}
+				events = append(events, applied...)
+			}
+		}
+		report.planImportWarnings()
+		if err := writeImportWarnings(tx, report); err != nil {
+			return nil, err
+		}
+		report.Applied = true
+		return withAuditBatch(events)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// applyReimportChange applies one change and returns its audit entries. Changes that depend on a project the
+// re-import did not create are left unapplied.
+func (a *AuditedStore) applyReimportChange(tx *gorm.DB, store *SQLStore, change *ReimportChange) ([]model.AuditLog, error) {
+	switch {
+	case change.Category == ReimportNewProjects:
+		project := model.Project{
+			Name:                change.Project,
+			Maturity:            model.Maturity(change.To),
+			LegacyMaintainerRef: change.row.MaintainerRef,
+		}
+		if change.row.MailingList != "" {
+			list := change.row.MailingList
+			project.MailingList = &list
+		}
+		if change.row.Parent != "" {
+			parent, err := resolveProject(tx, change.row.Parent)
+			if err != nil {
+				return nil, err
+			}
+			if parent != nil {
+				project.ParentProjectID = &parent.ID
+			}
+		}
+		if err := store.CreateProject(&project); err != nil {
+			return nil, err
+		}
+		change.ProjectID = project.ID
+		change.Applied = true
+		event, err := a.event("PROJECT_CREATE",
+			fmt.Sprintf("Project created by %s", a.actor.DisplayName()),
+			AuditMetadata{Changes: DiffAuditFields(nil, projectAuditFields(project))})
+		if err != nil {
+			return nil, err
+		}
+		event.ProjectID = &project.ID
+		return []model.AuditLog{event}, nil
+
+	case change.Category == ReimportFieldChanges && change.MaintainerID == 0:
+		return a.applyReimportProjectField(tx, change)
+
+	case change.Category == ReimportFieldChanges:
+		before, err := maintainerAuditFields(tx, change.MaintainerID)
+		if err != nil {
+			return nil, err
+		}
+		updates := map[string]any{}
+		switch change.Field {
+		case "company":
+			company, err := findOrCreateCompany(tx, change.To)
+			if err != nil {
+				return nil, err
+			}
+			updates["company_id"] = company.ID
+		case "email":
+			updates["email"] = change.To
+			updates["email_verified_at"] = nil
+		default:
+			updates[maintainerRevertColumns[change.Field]] = change.To
+		}
+		if err := tx.Model(&model.Maintainer{}).Where("id = ?", change.MaintainerID).Updates(updates).Error; err != nil {
+			return nil, err
+		}
+		after, err := maintainerAuditFields(tx, change.MaintainerID)
+		if err != nil {
+			return nil, err
+		}
+		change.Applied = true
+		event, err := a.maintainerEvent("MAINTAINER_UPDATE", change.MaintainerID, nil, before, after, AuditMetadata{}, a.maintainerUpdateMessage)
+		if err != nil || event == nil {
+			return nil, err
+		}
+		return []model.AuditLog{*event}, nil
+
+	case change.Category == ReimportNewMaintainers || change.Category == ReimportNewMemberships:
+		project, err := resolveProject(tx, change.Project)
+		if err != nil || project == nil {
+			return nil, err
+		}
+		company := ""
+		if change.Category == ReimportNewMaintainers {
+			company = change.row.Company
+		}
+		maintainer, event, err := a.upsertMaintainer(tx, store, project.ID, change.Name, change.Email, change.GitHub, company)
+		if err != nil {
+			return nil, err
+		}
+		change.ProjectID = project.ID
+		change.MaintainerID = maintainer.ID
+		change.Applied = true
+		var events []model.AuditLog
+		if event != nil {
+			events = append(events, *event)
+		}
+		if change.Category == ReimportNewMaintainers && change.row.GitHubEmail != "" &&
+			recordedValue(maintainer.GitHubEmail, "GITHUB_MISSING") == "" {
+			// UpsertMaintainer does not take a GitHub email, so record it as a follow-up field change.
+			more, err := a.applyReimportChange(tx, store, &ReimportChange{
+				Category: ReimportFieldChanges, MaintainerID: maintainer.ID,
+				Field: "githubEmail", To: change.row.GitHubEmail,
+			})
+			if err != nil {
+				return nil, err
+			}
+			events = append(events, more...)
+		}
+		return events, nil
+
+	case change.Category == ReimportRemovals:
+		before, err := membershipAuditFields(tx, change.MaintainerID, change.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := store.UpdateMembership(change.MaintainerID, change.ProjectID, model.EmeritusMaintainer, ""); err != nil {
+			return nil, err
+		}
+		after, err := membershipAuditFields(tx, change.MaintainerID, change.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		change.Applied = true
+		event, err := a.maintainerEvent("MEMBERSHIP_UPDATE", change.MaintainerID, &change.ProjectID, before, after, AuditMetadata{}, func(fields []string) string {
+			return fmt.Sprintf("Membership [%s] updated by %s", strings.Join(fields, ", "), a.actor.DisplayName())
+		})
+		if err != nil || event == nil {
+			return nil, err
+		}
+		return []model.AuditLog{*event}, nil
+	}
+	return nil, nil
+}
+
+// reimportProjectFields maps the project fields a re-import compares to their columns and audit actions.
+var reimportProjectFields = map[string]struct{ column, action string }{
+	"maturity":      {"maturity", "PROJECT_MATURITY_UPDATE"},
+	"maintainerRef": {"maintainer_ref", "PROJECT_MAINTAINER_REF_UPDATE"},
+	"mailingList":   {"mailing_list", "PROJECT_MAILING_LIST_UPDATE"},
+}
+
+func (a *AuditedStore) applyReimportProjectField(tx *gorm.DB, change *ReimportChange) ([]model.AuditLog, error) {
+	field := reimportProjectFields[change.Field]
+	var before model.Project
+	if err := tx.First(&before, change.ProjectID).Error; err != nil {
+		return nil, err
+	}
+	var value any = change.To
+	if change.Field == "maturity" {
+		value = model.Maturity(change.To)
+	}
+	if err := tx.Model(&model.Project{}).Where("id = ?", change.ProjectID).Update(field.column, value).Error; err != nil {
+		return nil, err
+	}
+	var after model.Project
+	if err := tx.First(&after, change.ProjectID).Error; err != nil {
+		return nil, err
+	}
+	change.Applied = true
+	event, err := a.event(field.action, fmt.Sprintf("Project %s updated by %s", change.Field, a.actor.DisplayName()),
+		AuditMetadata{Changes: DiffAuditFields(reimportProjectAuditFields(before), reimportProjectAuditFields(after))})
+	if err != nil {
+		return nil, err
+	}
+	event.ProjectID = &change.ProjectID
+	return []model.AuditLog{event}, nil
+}
+
+// reimportProjectAuditFields extends projectAuditFields with the mailing list, which only re-imports change.
+func reimportProjectAuditFields(project model.Project) map[string]string {
+	fields := projectAuditFields(project)
+	fields["mailingList"] = ""
+	if project.MailingList != nil {
+		fields["mailingList"] = *project.MailingList
+	}
+	return fields
+}
+
+// withAuditBatch groups events written by one operation under a shared batch ID.
+func withAuditBatch(events []model.AuditLog) ([]model.AuditLog, error) {
+	if len(events) < 2 {
+		return events, nil
+	}
+	batch := &AuditBatch{ID: newAuditBatchID(), Size: len(events)}
+	for i := range events {
+		var metadata AuditMetadata
+		if err := json.Unmarshal([]byte(events[i].Metadata), &metadata); err != nil {
+			return nil, err
+		}
+		metadata.Batch = batch
+		raw, err := json.Marshal(metadata)
+		if err != nil {
+			return nil, err
+		}
+		events[i].Metadata = string(raw)
+	}
+	return events, nil
+}
+
+// planImportWarnings sets ImportWarnings for every maintainer the report mentions to its warnings and the changes
+// to them that were not applied, one per line.
+func (r *ReimportReport) planImportWarnings() {
+	notes := make(map[uint][]string, len(r.maintainers))
+	for id := range r.maintainers {
+		notes[id] = nil
+	}
+	mention := func(id uint, note string) {
+		if _, ok := notes[id]; !ok {
+			notes[id] = nil
+		}
+		if note != "" {
+			notes[id] = append(notes[id], note)
+		}
+	}
+	for _, warning := range r.Warnings {
+		if warning.MaintainerID != 0 {
+			mention(warning.MaintainerID, warning.Message)
+		}
+	}
+	for _, change := range r.Changes {
+		if change.MaintainerID == 0 {
+			continue
+		}
+		note := ""
+		if !change.Applied {
+			note = fmt.Sprintf("sheet change not applied (%s): %s", change.Category, change.describe())
+		}
+		mention(change.MaintainerID, note)
+	}
+	r.ImportWarnings = make(map[uint]string, len(notes))
+	for id, lines := range notes {
+		r.ImportWarnings[id] = strings.Join(lines, "\n")
+	}
+}
+
+// writeImportWarnings replaces ImportWarnings on every maintainer in report.ImportWarnings.
+func writeImportWarnings(tx *gorm.DB, report *ReimportReport) error {
+	ids := make([]uint, 0, len(report.ImportWarnings))
+	for id := range report.ImportWarnings {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for _, id := range ids {
+		if err := tx.Model(&model.Maintainer{}).Where("id = ?", id).
+			Update("import_warnings", report.ImportWarnings[id]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// describe returns a short human-readable form of the change.
+func (c ReimportChange) describe() string {
+	who := c.Email
+	if who == "" {
+		who = c.Project
+	}
+	switch {
+	case c.Field != "" && c.Category == ReimportRemovals:
+		return fmt.Sprintf("%s on %s: %s → %s", who, c.Project, c.From, c.To)
+	case c.Field != "":
+		return fmt.Sprintf("%s %s: %q → %q", who, c.Field, c.From, c.To)
+	case c.Category == ReimportNewProjects:
+		return fmt.Sprintf("%s (%s)", c.Project, c.To)
+	default:
+		return fmt.Sprintf("%s on %s", who, c.Project)
+	}
+}
+
+// reimportPlanner accumulates a report while walking the sheet.
+type reimportPlanner struct {
+	tx     *gorm.DB
+	report *ReimportReport
+	// projects maps lower-cased sheet project names to the existing project, or nil for new and skipped ones.
+	projects map[string]*model.Project
+	// maturities maps lower-cased sheet project names to the maturity the sheet gives them.
+	maturities map[string]model.Maturity
+	// listed holds, per existing project, the maintainers the sheet lists on it.
+	listed map[uint]map[uint]bool
+	// seen dedupes changes by key, remembering the index of the first.
+	seen map[string]int
+}
+
+func planReimport(tx *gorm.DB, rows []map[string]string) (*ReimportReport, error) {
+	p := &reimportPlanner{
+		tx: tx,
+		report: &ReimportReport{
+			GeneratedAt: time.Now().UTC(),
+			Approved:    []ReimportCategory{},
+			Counts:      make(map[ReimportCategory]int),
+			Changes:     []ReimportChange{},
+			Warnings:    []ReimportWarning{},
+			maintainers: make(map[uint]bool),
+		},
+		projects:   make(map[string]*model.Project),
+		maturities: make(map[string]model.Maturity),
+		listed:     make(map[uint]map[uint]bool),
+		seen:       make(map[string]int),
+	}
+	for _, raw := range rows {
+		row := newSheetRow(raw)
+		if row.Project == "" {
+			continue
+		}
+		project, err := p.planProject(row)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.planMaintainer(row, project); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.planRemovals(); err != nil {
+		return nil, err
+	}
+	for _, change := range p.report.Changes {
+		p.report.Counts[change.Category]++
+	}
+	p.report.planImportWarnings()
+	return p.report, nil
+}
+
+func (p *reimportPlanner) warn(warning ReimportWarning) {
+	p.report.Warnings = append(p.report.Warnings, warning)
+}
+
+// add records change unless one with the same key was already recorded. It warns when rows disagree about the
+// value a field should take.
+func (p *reimportPlanner) add(key string, change ReimportChange) {
+	if i, ok := p.seen[key]; ok {
+		if first := p.report.Changes[i]; first.Field != "" && first.To != change.To {
+			p.warn(ReimportWarning{
+				Project: change.Project, MaintainerID: change.MaintainerID, Email: change.Email,
+				Message: fmt.Sprintf("sheet rows disagree on %s: %q and %q", change.Field, first.To, change.To),
+			})
+		}
+		return
+	}
+	p.seen[key] = len(p.report.Changes)
+	p.report.Changes = append(p.report.Changes, change)
+}
+
+// planProject returns the existing project a row belongs to, planning its creation or field changes the first time
+// the project appears.
+func (p *reimportPlanner) planProject(row sheetRow) (*model.Project, error) {
+	key := strings.ToLower(row.Project)
+	if project, ok := p.projects[key]; ok {
+		return project, nil
+	}
+	project, err := resolveProject(p.tx, row.Project)
+	if err != nil {
+		return nil, err
+	}
+	p.projects[key] = project
+	maturity, valid := sheetMaturity(row.Status)
+	if row.Status != "" && !valid {
+		p.warn(ReimportWarning{Project: row.Project, Message: fmt.Sprintf("unknown status %q", row.Status)})
+	}
+	if valid {
+		p.maturities[key] = maturity
+	}
+
+	if project == nil {
+		if !valid && row.Parent != "" {
+			maturity, valid = p.parentMaturity(row.Parent)
+		}
+		if !valid {
+			p.warn(ReimportWarning{Project: row.Project, Message: "new project has no status and is not created"})
+			return nil, nil
+		}
+		p.maturities[key] = maturity
+		p.add("project/"+key, ReimportChange{Category: ReimportNewProjects, Project: row.Project, To: string(maturity), row: row})
+		return nil, nil
+	}
+
+	field := func(name, from, to string) {
+		if to == "" || from == to {
+			return
+		}
+		p.add(fmt.Sprintf("project/%d/%s", project.ID, name), ReimportChange{
+			Category: ReimportFieldChanges, Project: project.Name, ProjectID: project.ID,
+			Field: name, From: from, To: to,
+		})
+	}
+	if valid {
+		field("maturity", string(project.Maturity), string(maturity))
+	}
+	field("maintainerRef", strings.TrimSpace(project.LegacyMaintainerRef), row.MaintainerRef)
+	mailingList := ""
+	if project.MailingList != nil {
+		mailingList = recordedValue(*project.MailingList, "MML_MISSING")
+	}
+	field("mailingList", mailingList, row.MailingList)
+	return project, nil
+}
+
+func (p *reimportPlanner) parentMaturity(name string) (model.Maturity, bool) {
+	if maturity, ok := p.maturities[strings.ToLower(name)]; ok {
+		return maturity, true
+	}
+	parent, err := resolveProject(p.tx, name)
+	if err != nil || parent == nil {
+		return "", false
+	}
+	return parent.Maturity, parent.Maturity.IsValid()
+}
+
+func (p *reimportPlanner) planMaintainer(row sheetRow, project *model.Project) error {
+	if row.Email == "" {
+		if row.Name != "" || row.GitHub != "" || row.GitHubEmail != "" || row.Company != "" {
+			p.warn(ReimportWarning{Project: row.Project, Message: fmt.Sprintf("row for %q has no email and is skipped", firstNonEmpty(row.Name, row.GitHub))})
+		}
+		return nil
+	}
+	byEmail, err := findLiveMaintainer(p.tx, "LOWER(email) = ?", strings.ToLower(row.Email))
+	if err != nil {
+		return err
+	}
+	if byEmail == nil {
+		if byEmail, err = mergedMaintainer(p.tx, "LOWER(email) = ?", strings.ToLower(row.Email)); err != nil {
+			return err
+		}
+	}
+	var byGitHub *model.Maintainer
+	if row.GitHub != "" {
+		if byGitHub, err = findLiveMaintainer(p.tx, "LOWER(git_hub_account) = ?", strings.ToLower(row.GitHub)); err != nil {
+			return err
+		}
+	}
+
+	var maintainer model.Maintainer
+	switch {
+	case byEmail != nil && byGitHub != nil && byEmail.ID != byGitHub.ID:
+		p.report.maintainers[byEmail.ID] = true
+		p.report.maintainers[byGitHub.ID] = true
+		message := fmt.Sprintf("sheet lists %s with GitHub handle %s, which belongs to maintainer %d", row.Email, row.GitHub, byGitHub.ID)
+		p.warn(ReimportWarning{Project: row.Project, MaintainerID: byEmail.ID, Email: row.Email, Message: message})
+		p.warn(ReimportWarning{Project: row.Project, MaintainerID: byGitHub.ID, Email: row.Email, Message: message})
+		p.list(project, byEmail.ID)
+		p.list(project, byGitHub.ID)
+		return nil
+	case byEmail != nil:
+		maintainer = *byEmail
+	case byGitHub != nil:
+		maintainer = *byGitHub
+	default:
+		p.add("new/"+strings.ToLower(row.Email)+"/"+strings.ToLower(row.Project), ReimportChange{
+			Category: ReimportNewMaintainers, Project: row.Project,
+			Name: row.Name, Email: row.Email, GitHub: row.GitHub, row: row,
+		})
+		return nil
+	}
+	p.report.maintainers[maintainer.ID] = true
+
+	field := func(name, from, to string, equal func(a, b string) bool) {
+		if to == "" || equal(from, to) {
+			return
+		}
+		p.add(fmt.Sprintf("maintainer/%d/%s", maintainer.ID, name), ReimportChange{
+			Category: ReimportFieldChanges, Project: row.Project, MaintainerID: maintainer.ID,
+			Name: maintainer.Name, Email: row.Email, GitHub: row.GitHub,
+			Field: name, From: from, To: to,
+		})
+	}
+	exact := func(a, b string) bool { return a == b }
+	field("name", strings.TrimSpace(maintainer.Name), row.Name, exact)
+	field("email", recordedValue(maintainer.Email, "EMAIL_MISSING"), row.Email, strings.EqualFold)
+	field("github", recordedValue(maintainer.GitHubAccount, "GITHUB_MISSING"), row.GitHub, strings.EqualFold)
+	field("githubEmail", recordedValue(maintainer.GitHubEmail, "GITHUB_MISSING"), row.GitHubEmail, strings.EqualFold)
+	if row.Company != "" {
+		same, current, err := sameCompany(p.tx, maintainer.CompanyID, row.Company)
+		if err != nil {
+			return err
+		}
+		if !same {
+			field("company", current, row.Company, exact)
+		}
+	}
+
+	if project == nil {
+		if p.projects[strings.ToLower(row.Project)] == nil && p.isPlannedProject(row.Project) {
+			p.add(fmt.Sprintf("membership/%d/%s", maintainer.ID, strings.ToLower(row.Project)), ReimportChange{
+				Category: ReimportNewMemberships, Project: row.Project, MaintainerID: maintainer.ID,
+				Name: maintainer.Name, Email: row.Email, GitHub: row.GitHub,
+			})
+		}
+		return nil
+	}
+	p.list(project, maintainer.ID)
+	var membership model.MaintainerProject
+	err = p.tx.Where("maintainer_id = ? AND project_id = ?", maintainer.ID, project.ID).First(&membership).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		p.add(fmt.Sprintf("membership/%d/%d", maintainer.ID, project.ID), ReimportChange{
+			Category: ReimportNewMemberships, Project: project.Name, ProjectID: project.ID, MaintainerID: maintainer.ID,
+			Name: maintainer.Name, Email: row.Email, GitHub: row.GitHub,
+		})
+	case err != nil:
+		return err
+	case membership.Status != "" && membership.Status != model.ActiveMaintainer:
+		p.warn(ReimportWarning{
+			Project: project.Name, MaintainerID: maintainer.ID, Email: row.Email,
+			Message: fmt.Sprintf("sheet lists %s as active on %s but the membership is %s", row.Email, project.Name, membership.Status),
+		})
+	}
+	return nil
+}
+
+func (p *reimportPlanner) isPlannedProject(name string) bool {
+	_, ok := p.seen["project/"+strings.ToLower(name)]
+	return ok
+}
+
+func (p *reimportPlanner) list(project *model.Project, maintainerID uint) {
+	if project == nil {
+		return
+	}
+	if p.listed[project.ID] == nil {
+		p.listed[project.ID] = make(map[uint]bool)
+	}
+	p.listed[project.ID][maintainerID] = true
+}
+
+// planRemovals plans moving active maintainers the sheet no longer lists to emeritus. Projects whose rows name no
+// maintainers are left alone, since those rows only carry project metadata. Memberships added outside the sheet,
+// through the web UI, a bulk import or a maintainer file sync, or before sources were recorded, are only warned
+// about.
+func (p *reimportPlanner) planRemovals() error {
+	projectIDs := make([]uint, 0, len(p.listed))
+	for id := range p.listed {
+		projectIDs = append(projectIDs, id)
+	}
+	sort.Slice(projectIDs, func(i, j int) bool { return projectIDs[i] < projectIDs[j] })
+	for _, projectID := range projectIDs {
+		var memberships []model.MaintainerProject
+		if err := p.tx.Preload("Maintainer").Preload("Project").
+			Where("project_id = ? AND (status = ? OR status = '' OR status IS NULL)", projectID, model.ActiveMaintainer).
+			Order("maintainer_id").Find(&memberships).Error; err != nil {
+			return err
+		}
+		sources, err := membershipSources(p.tx, projectID)
+		if err != nil {
+			return err
+		}
+		for _, membership := range memberships {
+			if p.listed[projectID][membership.MaintainerID] || membership.Maintainer.ID == 0 {
+				continue
+			}
+			email := recordedValue(membership.Maintainer.Email, "EMAIL_MISSING")
+			if sources[membership.MaintainerID] != model.MembershipSourceSheet {
+				p.warn(ReimportWarning{
+					Project: membership.Project.Name, MaintainerID: membership.MaintainerID, Email: email,
+					Message: "active maintainer is not in the sheet but was not added from it; left unchanged",
+				})
+				continue
+			}
+			p.add(fmt.Sprintf("removal/%d/%d", membership.MaintainerID, projectID), ReimportChange{
+				Category: ReimportRemovals, Project: membership.Project.Name, ProjectID: projectID,
+				MaintainerID: membership.MaintainerID, Name: membership.Maintainer.Name,
+				Email:  email,
+				GitHub: recordedValue(membership.Maintainer.GitHubAccount, "GITHUB_MISSING"),
+				Field:  "status", From: string(model.ActiveMaintainer), To: string(model.EmeritusMaintainer),
+			})
+		}
+	}
+	return nil
+}
+
+// membershipSources returns the source of the latest JOIN of each maintainer on a project.
+func membershipSources(tx *gorm.DB, projectID uint) (map[uint]string, error) {
+	var joins []model.MembershipHistory
+	if err := tx.Where("project_id = ? AND event = ?", projectID, "JOIN").Order("id").Find(&joins).Error; err != nil {
+		return nil, err
+	}
+	sources := make(map[uint]string, len(joins))
+	for _, join := range joins {
+		sources[join.MaintainerID] = join.Source
+	}
+	return sources, nil
+}
+
+func findLiveMaintainer(tx *gorm.DB, query string, args ...any) (*model.Maintainer, error) {
+	var maintainer model.Maintainer
+	err := tx.Where(query, args...).Order("id").First(&maintainer).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &maintainer, nil
+}
+
+// sameCompany reports whether name resolves to the company companyID identifies, and returns the recorded
+// company's name.
+func sameCompany(tx *gorm.DB, companyID *uint, name string) (bool, string, error) {
+	if companyID == nil {
+		return false, "", nil
+	}
+	var current model.Company
+	if err := tx.Select("id", "name").First(&current, *companyID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, "", nil
+		}
+		return false, "", err
+	}
+	if strings.EqualFold(current.Name, name) {
+		return true, current.Name, nil
+	}
+	resolved, err := resolveCompany(tx, name)
+	if err != nil {
+		return false, "", err
+	}
+	return resolved != nil && resolved.ID == current.ID, current.Name, nil
+}
+
+// sheetMaturity parses a Status cell, ignoring case.
+func sheetMaturity(status string) (model.Maturity, bool) {
+	for _, maturity := range []model.Maturity{model.Sandbox, model.Incubating, model.Graduated, model.Archived} {
+		if strings.EqualFold(status, string(maturity)) {
+			return maturity, true
+		}
+	}
+	return "", false
+}
+
+// recordedValue returns value, or "" when it is the placeholder recorded for a missing field.
+func recordedValue(value, sentinel string) string {
+	value = strings.TrimSpace(value)
+	if value == sentinel {
+		return ""
+	}
+	return value
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
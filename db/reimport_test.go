@@ -0,0 +1,169 @@
+package db
+
+import (
+	"strings"
+	"testing"
+
+	"maintainerd/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func reimportRows() []map[string]string {
+	row := func(project, status, name, company, email, github string) map[string]string {
+		return map[string]string{
+			ProjectHdr: project, StatusHdr: status, MaintainerNameHdr: name, CompanyNameHdr: company,
+			EmailHdr: email, GitHubHdr: github, MailingListAddrHdr: "",
+		}
+	}
+	rows := []map[string]string{
+		row("Kubernetes", "Graduated", "Alice Developer", "New Co", "alice@example.com", "alice"),
+		row("kubernetes", "Graduated", "Dana Newcomer", "", "dana@example.com", "@dana"),
+		row("prometheus", "Graduated", "Bob Engineer", "Test Company", "bob@example.com", "bob"),
+		row("prometheus", "Graduated", "Alice Developer", "New Co", "alice@example.com", "alice"),
+		row("Falco", "Incubating", "Eve Falco", "", "eve@example.com", "eve"),
+		row("Falco", "Incubating", "No Email", "", "", "noemail"),
+	}
+	rows[0][MailingListAddrHdr] = "kubernetes-dev@lists.cncf.io"
+	return rows
+}
+
+// joinedFromSheet records that the sheet added maintainerID to projectID, which makes the membership one a
+// re-import may retire.
+func joinedFromSheet(t *testing.T, db *gorm.DB, maintainerID, projectID uint) {
+	t.Helper()
+	require.NoError(t, db.Create(&model.MembershipHistory{
+		MaintainerID: maintainerID, ProjectID: projectID, Event: "JOIN", Source: model.MembershipSourceSheet,
+	}).Error)
+}
+
+func TestPlanReimport(t *testing.T) {
+	db, store, _ := setupAuditedStore(t)
+	_, project1, project2, alice, bob, charlie := seedTestData(t, db)
+	joinedFromSheet(t, db, bob.ID, project1.ID)
+	// Charlie joined prometheus from the sheet and was later added again through the web UI.
+	joinedFromSheet(t, db, charlie.ID, project2.ID)
+	require.NoError(t, db.Create(&model.MembershipHistory{MaintainerID: charlie.ID, ProjectID: project2.ID, Event: "JOIN"}).Error)
+
+	report, err := store.PlanReimport(reimportRows())
+	require.NoError(t, err)
+	assert.False(t, report.Applied)
+	assert.Equal(t, map[ReimportCategory]int{
+		ReimportNewProjects:    1,
+		ReimportNewMaintainers: 2,
+		ReimportNewMemberships: 1,
+		ReimportFieldChanges:   2,
+		ReimportRemovals:       1,
+	}, report.Counts)
+
+	byKey := make(map[string]ReimportChange)
+	for _, change := range report.Changes {
+		byKey[string(change.Category)+"/"+change.Email+"/"+change.Project+"/"+change.Field] = change
+	}
+	assert.Equal(t, "Test Company", byKey["field-changes/alice@example.com/Kubernetes/company"].From)
+	assert.Equal(t, "kubernetes-dev@lists.cncf.io", byKey["field-changes//kubernetes/mailingList"].To)
+	assert.Equal(t, alice.ID, byKey["new-memberships/alice@example.com/prometheus/"].MaintainerID)
+	assert.Equal(t, "dana", byKey["new-maintainers/dana@example.com/kubernetes/"].GitHub)
+	assert.Equal(t, "Incubating", byKey["new-projects//Falco/"].To)
+	removal := byKey["removals/bob@example.com/kubernetes/status"]
+	assert.Equal(t, project1.ID, removal.ProjectID)
+	assert.Equal(t, bob.ID, removal.MaintainerID)
+	assert.Zero(t, byKey["removals/bob@example.com/prometheus/status"].MaintainerID, "listed maintainers stay")
+	assert.Zero(t, byKey["removals/charlie@example.com/prometheus/status"].MaintainerID, "only sheet memberships are retired")
+
+	require.Len(t, report.Warnings, 2)
+	assert.Contains(t, report.Warnings[0].Message, "no email")
+	assert.Equal(t, charlie.ID, report.Warnings[1].MaintainerID)
+	assert.Contains(t, report.Warnings[1].Message, "not added from it")
+
+	assert.Contains(t, report.ImportWarnings[alice.ID], "not applied (field-changes)")
+	assert.Contains(t, report.ImportWarnings[alice.ID], "not applied (new-memberships)")
+	assert.Contains(t, report.ImportWarnings[bob.ID], "not applied (removals)")
+	assert.Contains(t, report.ImportWarnings[charlie.ID], "not added from it")
+
+	var count int64
+	require.NoError(t, db.Model(&model.Project{}).Count(&count).Error)
+	assert.EqualValues(t, 2, count, "planning writes nothing")
+	require.NoError(t, db.Model(&model.Maintainer{}).Where("import_warnings <> ''").Count(&count).Error)
+	assert.Zero(t, count, "planning records no import warnings")
+}
+
+func TestApplyReimport(t *testing.T) {
+	db, store, _ := setupAuditedStore(t)
+	_, project1, project2, alice, bob, charlie := seedTestData(t, db)
+	joinedFromSheet(t, db, bob.ID, project1.ID)
+	joinedFromSheet(t, db, charlie.ID, project2.ID)
+	rows := reimportRows()
+
+	approved, err := ParseReimportCategories("new-projects, new-maintainers,field-changes")
+	require.NoError(t, err)
+	report, err := store.ApplyReimport(rows, approved)
+	require.NoError(t, err)
+	assert.True(t, report.Applied)
+
+	var falco model.Project
+	require.NoError(t, db.Preload("Maintainers").Where("name = ?", "Falco").First(&falco).Error)
+	assert.Equal(t, model.Incubating, falco.Maturity)
+	require.Len(t, falco.Maintainers, 1)
+	assert.Equal(t, "eve@example.com", falco.Maintainers[0].Email)
+
+	var updatedAlice, updatedBob model.Maintainer
+	require.NoError(t, db.Preload("Company").First(&updatedAlice, alice.ID).Error)
+	assert.Equal(t, "New Co", updatedAlice.Company.Name)
+	assert.Contains(t, updatedAlice.ImportWarnings, "not applied (new-memberships)")
+	require.NoError(t, db.First(&updatedBob, bob.ID).Error)
+	assert.Contains(t, updatedBob.ImportWarnings, "not applied (removals)")
+
+	var kubernetes model.Project
+	require.NoError(t, db.First(&kubernetes, project1.ID).Error)
+	require.NotNil(t, kubernetes.MailingList)
+	assert.Equal(t, "kubernetes-dev@lists.cncf.io", *kubernetes.MailingList)
+
+	entries := auditEntries(t, db, "PROJECT_MAILING_LIST_UPDATE")
+	require.Len(t, entries, 1)
+	metadata := auditMetadata(t, entries[0])
+	assert.Equal(t, "kubernetes-dev@lists.cncf.io", metadata.Changes["mailingList"].To)
+	require.NotNil(t, metadata.Batch)
+	assert.Len(t, auditEntries(t, db, "MAINTAINER_CREATE"), 2)
+	assert.Len(t, auditEntries(t, db, "MEMBERSHIP_UPDATE"), 0)
+
+	// Approving the rest settles the differences, clears the notes and leaves nothing to do on the next run.
+	approved, err = ParseReimportCategories("all")
+	require.NoError(t, err)
+	_, err = store.ApplyReimport(rows, approved)
+	require.NoError(t, err)
+
+	var membership model.MaintainerProject
+	require.NoError(t, db.Where("maintainer_id = ? AND project_id = ?", bob.ID, project1.ID).First(&membership).Error)
+	assert.Equal(t, model.EmeritusMaintainer, membership.Status)
+	updatedAlice = model.Maintainer{}
+	require.NoError(t, db.First(&updatedAlice, alice.ID).Error)
+	assert.Empty(t, updatedAlice.ImportWarnings)
+	assert.Len(t, auditEntries(t, db, "MEMBERSHIP_UPDATE"), 2)
+
+	report, err = store.PlanReimport(rows)
+	require.NoError(t, err)
+	assert.Empty(t, report.Changes)
+
+	// Memberships the re-import added came from the sheet too, so dropping a row retires them next time.
+	report, err = store.PlanReimport(rows[:len(rows)-3])
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Counts[ReimportRemovals])
+	assert.Equal(t, alice.ID, report.Changes[0].MaintainerID)
+}
+
+func TestParseReimportCategories(t *testing.T) {
+	categories, err := ParseReimportCategories("")
+	require.NoError(t, err)
+	assert.Empty(t, categories)
+
+	categories, err = ParseReimportCategories("removals,NEW-PROJECTS")
+	require.NoError(t, err)
+	assert.Equal(t, []ReimportCategory{ReimportRemovals, ReimportNewProjects}, categories)
+
+	_, err = ParseReimportCategories("new-projects,everything")
+	assert.ErrorIs(t, err, ErrUnknownReimportCategory)
+	assert.True(t, strings.Contains(err.Error(), "everything"))
+}
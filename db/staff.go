@@ -0,0 +1,267 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"maintainerd/model"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrStaffNotFound = errors.New("staff member not found")
+	ErrStaffExists   = errors.New("a staff member with that GitHub account or email already exists")
+	ErrLastAdmin     = errors.New("at least one active staff admin is required")
+)
+
+// StaffMemberInput describes a staff member to add.
+type StaffMemberInput struct {
+	Name          string
+	Email         string
+	GitHubAccount string
+	FoundationID  *uint
+	Admin         bool
+}
+
+// StaffMemberUpdate holds the staff member fields to change; nil fields are left as they are. A FoundationID of 0
+// removes the member from their foundation.
+type StaffMemberUpdate struct {
+	Name          *string
+	Email         *string
+	GitHubAccount *string
+	FoundationID  *uint
+	Admin         *bool
+}
+
+// ListAllStaffMembers returns every staff member, including deactivated ones, ordered by name.
+func (s *SQLStore) ListAllStaffMembers() ([]model.StaffMember, error) {
+	var staff []model.StaffMember
+	if err := s.db.Preload("Foundation").Order("name, id").Find(&staff).Error; err != nil {
+		return nil, err
+	}
+	return staff, nil
+}
+
+// GetStaffMember returns the staff member with id, including their foundation, or ErrStaffNotFound.
+func (s *SQLStore) GetStaffMember(id uint) (*model.StaffMember, error) {
+	var staff model.StaffMember
+	if err := s.db.Preload("Foundation").First(&staff, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrStaffNotFound
+		}
+		return nil, err
+	}
+	return &staff, nil
+}
+
+// IsStaffAdmin reports whether the session user is an active staff admin, matching on the GitHub user ID when it
+// is known and on the handle otherwise.
+func (s *SQLStore) IsStaffAdmin(login string, githubID int64) (bool, error) {
+	query := s.db.Model(&model.StaffMember{}).Where("admin = ? AND deactivated_at IS NULL", true)
+	if githubID != 0 {
+		query = query.Where("git_hub_id = ?", githubID)
+	} else {
+		query = query.Where("LOWER(git_hub_account) = ?", strings.ToLower(strings.TrimSpace(login)))
+	}
+	var count int64
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+// CreateStaffMember adds a staff member. The GitHub account is required because it is what staff log in with.
+func (s *SQLStore) CreateStaffMember(input StaffMemberInput) (*model.StaffMember, error) {
+	staff := model.StaffMember{
+		Name:          strings.TrimSpace(input.Name),
+		Email:         strings.TrimSpace(input.Email),
+		GitHubAccount: strings.TrimSpace(input.GitHubAccount),
+		Admin:         input.Admin,
+	}
+	if staff.Name == "" || staff.GitHubAccount == "" {
+		return nil, fmt.Errorf("name and github account are required")
+	}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkStaffUnique(tx, staff.GitHubAccount, staff.Email, 0); err != nil {
+			return err
+		}
+		if input.FoundationID != nil && *input.FoundationID != 0 {
+			if err := checkFoundationExists(tx, *input.FoundationID); err != nil {
+				return err
+			}
+			staff.FoundationID = input.FoundationID
+		}
+		return tx.Create(&staff).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetStaffMember(staff.ID)
+}
+
+// UpdateStaffMember changes a staff member's details, foundation or admin permission. The last active admin
+// cannot give up the permission.
+func (s *SQLStore) UpdateStaffMember(id uint, update StaffMemberUpdate) (*model.StaffMember, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var staff model.StaffMember
+		if err := tx.First(&staff, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrStaffNotFound
+			}
+			return err
+		}
+		updates := make(map[string]any)
+		if update.Name != nil {
+			name := strings.TrimSpace(*update.Name)
+			if name == "" {
+				return fmt.Errorf("name is required")
+			}
+			updates["name"] = name
+		}
+		email, account := "", ""
+		if update.Email != nil {
+			email = strings.TrimSpace(*update.Email)
+			updates["email"] = email
+		}
+		if update.GitHubAccount != nil {
+			account = strings.TrimSpace(*update.GitHubAccount)
+			if account == "" {
+				return fmt.Errorf("github account is required")
+			}
+			if !strings.EqualFold(account, staff.GitHubAccount) {
+				// A new handle is a different GitHub user until they log in with it.
+				updates["git_hub_id"] = nil
+			}
+			updates["git_hub_account"] = account
+		}
+		if err := checkStaffUnique(tx, account, email, id); err != nil {
+			return err
+		}
+		if update.FoundationID != nil {
+			if *update.FoundationID == 0 {
+				updates["foundation_id"] = nil
+			} else {
+				if err := checkFoundationExists(tx, *update.FoundationID); err != nil {
+					return err
+				}
+				updates["foundation_id"] = *update.FoundationID
+			}
+		}
+		if update.Admin != nil {
+			if !*update.Admin && staff.Admin && staff.DeactivatedAt == nil {
+				if err := checkOtherAdmins(tx, id); err != nil {
+					return err
+				}
+			}
+			updates["admin"] = *update.Admin
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+		return tx.Model(&staff).Updates(updates).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetStaffMember(id)
+}
+
+// DeactivateStaffMember revokes a staff member's access. The row is kept, and the member can be reactivated.
+func (s *SQLStore) DeactivateStaffMember(id uint) (*model.StaffMember, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var staff model.StaffMember
+		if err := tx.First(&staff, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrStaffNotFound
+			}
+			return err
+		}
+		if staff.DeactivatedAt != nil {
+			return nil
+		}
+		if staff.Admin {
+			if err := checkOtherAdmins(tx, id); err != nil {
+				return err
+			}
+		}
+		return tx.Model(&staff).Update("deactivated_at", time.Now().UTC()).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetStaffMember(id)
+}
+
+// ReactivateStaffMember restores a deactivated staff member's access.
+func (s *SQLStore) ReactivateStaffMember(id uint) (*model.StaffMember, error) {
+	result := s.db.Model(&model.StaffMember{}).Where("id = ? AND deactivated_at IS NOT NULL", id).Update("deactivated_at", nil)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return s.GetStaffMember(id)
+}
+
+// checkStaffUnique returns ErrStaffExists if a staff member other than exceptID, active or not, has the GitHub
+// account or email. Empty values are not checked.
+func checkStaffUnique(tx *gorm.DB, account, email string, exceptID uint) error {
+	check := func(column, value string) error {
+		if value == "" {
+			return nil
+		}
+		var count int64
+		if err := tx.Model(&model.StaffMember{}).
+			Where("LOWER("+column+") = ? AND id <> ?", strings.ToLower(value), exceptID).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrStaffExists
+		}
+		return nil
+	}
+	if err := check("git_hub_account", account); err != nil {
+		return err
+	}
+	return check("email", email)
+}
+
+func checkFoundationExists(tx *gorm.DB, id uint) error {
+	if err := tx.Select("id").First(&model.Foundation{}, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrFoundationNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// checkOtherAdmins returns ErrLastAdmin unless an active admin other than id remains.
+func checkOtherAdmins(tx *gorm.DB, id uint) error {
+	var count int64
+	if err := tx.Model(&model.StaffMember{}).
+		Where("admin = ? AND deactivated_at IS NULL AND id <> ?", true, id).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrLastAdmin
+	}
+	return nil
+}
+
+// staffAuditFields is the audited view of a staff member.
+func staffAuditFields(staff model.StaffMember) map[string]string {
+	admin := ""
+	if staff.Admin {
+		admin = "true"
+	}
+	return map[string]string{
+		"name":          staff.Name,
+		"email":         staff.Email,
+		"github":        staff.GitHubAccount,
+		"foundationId":  formatAuditID(staff.FoundationID),
+		"admin":         admin,
+		"deactivatedAt": formatAuditTime(staff.DeactivatedAt),
+	}
+}
@@ -0,0 +1,101 @@
+package db
+
+import (
+	"testing"
+
+	"maintainerd/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaffMemberLifecycle(t *testing.T) {
+	db, store, sam := setupAuditedStore(t)
+	require.NoError(t, db.Model(&sam).Update("admin", true).Error)
+	foundation, err := store.CreateFoundation("CNCF")
+	require.NoError(t, err)
+
+	admin, err := store.IsStaffAdmin("SAM", 0)
+	require.NoError(t, err)
+	assert.True(t, admin)
+
+	dana, err := store.CreateStaffMember(StaffMemberInput{Name: " Dana ", Email: "dana@example.org", GitHubAccount: "dana", FoundationID: &foundation.ID})
+	require.NoError(t, err)
+	assert.Equal(t, "Dana", dana.Name)
+	assert.Equal(t, "CNCF", dana.Foundation.Name)
+	_, err = store.CreateStaffMember(StaffMemberInput{Name: "Dana Again", GitHubAccount: "DANA"})
+	assert.ErrorIs(t, err, ErrStaffExists)
+	_, err = store.CreateStaffMember(StaffMemberInput{Name: "Nobody", GitHubAccount: "nobody", FoundationID: uintPtr(999)})
+	assert.ErrorIs(t, err, ErrFoundationNotFound)
+
+	noFoundation := uint(0)
+	updated, err := store.UpdateStaffMember(dana.ID, StaffMemberUpdate{FoundationID: &noFoundation, Admin: boolPtr(true)})
+	require.NoError(t, err)
+	assert.Nil(t, updated.FoundationID)
+	assert.True(t, updated.Admin)
+	entries := auditEntries(t, db, "STAFF_UPDATE")
+	require.Len(t, entries, 1)
+	assert.Equal(t, AuditChange{From: "1", To: ""}, auditMetadata(t, entries[0]).Changes["foundationId"])
+	assert.Equal(t, "Staff member Dana updated by Sam Staff", entries[0].Message)
+
+	_, err = store.DeactivateStaffMember(dana.ID)
+	require.NoError(t, err)
+	active, err := store.ListStaffMembers()
+	require.NoError(t, err)
+	require.Len(t, active, 1, "deactivated staff are not listed as staff")
+	assert.Equal(t, sam.ID, active[0].ID)
+	all, err := store.ListAllStaffMembers()
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+	isStaff, err := store.IsStaffGitHubAccount("dana")
+	require.NoError(t, err)
+	assert.False(t, isStaff)
+	identity, err := store.LinkGitHubIdentity("dana", 77)
+	require.NoError(t, err)
+	assert.Nil(t, identity.Staff, "a deactivated member no longer logs in as staff")
+
+	_, err = store.UpdateStaffMember(sam.ID, StaffMemberUpdate{Admin: boolPtr(false)})
+	assert.ErrorIs(t, err, ErrLastAdmin, "dana's admin flag does not count while deactivated")
+	_, err = store.DeactivateStaffMember(sam.ID)
+	assert.ErrorIs(t, err, ErrLastAdmin)
+
+	deactivated := auditEntries(t, db, "STAFF_DEACTIVATE")
+	require.Len(t, deactivated, 1)
+	_, err = store.RevertAuditEntry(deactivated[0].ID)
+	require.NoError(t, err)
+	restored, err := store.GetStaffMember(dana.ID)
+	require.NoError(t, err)
+	assert.Nil(t, restored.DeactivatedAt)
+
+	_, err = store.RevertAuditEntry(entries[0].ID)
+	require.NoError(t, err)
+	restored, err = store.GetStaffMember(dana.ID)
+	require.NoError(t, err)
+	require.NotNil(t, restored.FoundationID)
+	assert.Equal(t, foundation.ID, *restored.FoundationID)
+	assert.False(t, restored.Admin)
+}
+
+func TestRevertStaffCreate(t *testing.T) {
+	db, store, _ := setupAuditedStore(t)
+
+	dana, err := store.CreateStaffMember(StaffMemberInput{Name: "Dana", GitHubAccount: "dana"})
+	require.NoError(t, err)
+	lee, err := store.CreateStaffMember(StaffMemberInput{Name: "Lee", GitHubAccount: "lee"})
+	require.NoError(t, err)
+	require.NoError(t, store.AppendAuditLog(&model.AuditLog{Action: "INVITE_SENT", StaffID: &lee.ID}))
+
+	created := auditEntries(t, db, "STAFF_CREATE")
+	require.Len(t, created, 2)
+	_, err = store.RevertAuditEntry(created[0].ID)
+	require.NoError(t, err)
+	_, err = store.GetStaffMember(dana.ID)
+	assert.ErrorIs(t, err, ErrStaffNotFound)
+
+	_, err = store.RevertAuditEntry(created[1].ID)
+	assert.ErrorIs(t, err, ErrRevertConflict, "a member who has made changes is deactivated, not removed")
+}
+
+func uintPtr(v uint) *uint { return &v }
+
+func boolPtr(v bool) *bool { return &v }
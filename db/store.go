@@ -2,6 +2,7 @@ package db
 
 import (
 	"errors"
+	"time"
 
 	"maintainerd/model"
 
@@ -11,7 +12,10 @@ import (
 var ErrProjectNotFound = errors.New("project not found")
 var ErrProjectExists = errors.New("project already exists")
 var ErrCompanyExists = errors.New("company already exists")
+var ErrEmailMismatch = errors.New("email no longer matches maintainer record")
 
+// Store is the record store the services use. Every write goes through the audit log when the store is an
+// AuditedStore; bookkeeping such as the maintainer ref cache lives on SQLStore alone.
 type Store interface {
 	GetProjectsUsingService(serviceID uint) ([]model.Project, error)
 	GetProjectByID(projectID uint) (*model.Project, error)
@@ -22,18 +26,18 @@ type Store interface {
 	GetServiceTeamByProject(projectID uint, serviceID uint) (*model.ServiceTeam, error)
 	LogAuditEvent(logger *zap.SugaredLogger, event model.AuditLog) error
 	GetMaintainerMapByGitHubAccount() (map[string]model.Maintainer, error)
-	CreateServiceTeamForUser(interface{ any }) (*model.ServiceTeam, error)
 	UpsertMaintainer(projectID uint, name, email, githubHandle, company string) (*model.Maintainer, error)
 	CreateCompany(name string) (*model.Company, error)
 	UpdateProjectMaturity(projectID uint, maturity model.Maturity) error
 	UpdateProjectLegacyMaintainerRef(projectID uint, ref string) error
 	UpdateMaintainerStatus(maintainerID uint, status model.MaintainerStatus) error
 	UpdateMaintainersStatus(ids []uint, status model.MaintainerStatus) error
-	UpdateMaintainerGitHubEmail(maintainerID uint, githubEmail string) error
+	RemoveMaintainersFromProject(projectID uint, maintainerIDs []uint, cascade bool) ([]MembershipRemoval, error)
+	MarkMaintainerEmailVerified(maintainerID uint, email string, verifiedAt time.Time) error
 	UpdateMaintainerDetails(maintainerID uint, name, email, github string, status model.MaintainerStatus, companyID *uint) (*model.Maintainer, error)
 	ListCompanies() ([]model.Company, error)
 	ListStaffMembers() ([]model.StaffMember, error)
-	GetMaintainerRefCache(projectID uint) (*model.MaintainerRefCache, error)
-	UpsertMaintainerRefCache(cache *model.MaintainerRefCache) error
 	MergeCompanies(fromID, toID uint) error
 }
+
+var _ Store = (*AuditedStore)(nil)
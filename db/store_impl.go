@@ -15,6 +15,10 @@ import (
 
 type SQLStore struct {
 	db *gorm.DB
+	// actorLogin is recorded in membership history written through this store.
+	actorLogin string
+	// membershipSource is recorded as the source of membership history written through this store.
+	membershipSource string
 }
 
 func NewSQLStore(db *gorm.DB) *SQLStore {
@@ -105,76 +109,86 @@ func (s *SQLStore) UpdateProjectMaturity(projectID uint, maturity model.Maturity
 	return nil
 }
 
+// UpsertMaintainer finds or creates the maintainer and links them to the project. A maintainer who already has a
+// company keeps it and company is ignored; otherwise, when no company is given, it is inferred from the email domain.
 func (s *SQLStore) UpsertMaintainer(projectID uint, name, email, githubHandle, company string) (*model.Maintainer, error) {
-	tx := s.db.Begin()
-	if tx.Error != nil {
-		return nil, tx.Error
-	}
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
+	var maintainer model.Maintainer
+	var companyModel *model.Company
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var project model.Project
+		if err := tx.First(&project, projectID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrProjectNotFound
+			}
+			return err
 		}
-	}()
 
-	var project model.Project
-	if err := tx.First(&project, projectID).Error; err != nil {
-		tx.Rollback()
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, ErrProjectNotFound
+		existing, err := findUpsertMaintainer(tx, email, githubHandle)
+		if err != nil {
+			return err
 		}
-		return nil, err
-	}
-
-	var maintainer model.Maintainer
-	if githubHandle != "" {
-		if err := tx.Where("LOWER(git_hub_account) = ?", strings.ToLower(githubHandle)).
-			First(&maintainer).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-			tx.Rollback()
-			return nil, err
+		if existing != nil {
+			maintainer = *existing
 		}
-	}
-	if maintainer.ID == 0 && email != "" {
-		if err := tx.Where("LOWER(email) = ?", strings.ToLower(email)).
-			First(&maintainer).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-			tx.Rollback()
-			return nil, err
+
+		// A maintainer who already has a company keeps it, so only maintainers without one need a company resolved.
+		if maintainer.CompanyID == nil {
+			if strings.TrimSpace(company) != "" {
+				if companyModel, err = findOrCreateCompany(tx, company); err != nil {
+					return err
+				}
+			} else {
+				lookup := email
+				if strings.TrimSpace(lookup) == "" {
+					lookup = maintainer.Email
+				}
+				inferred, err := inferCompanyFromEmail(tx, lookup)
+				if err != nil {
+					return err
+				}
+				companyModel = inferred
+			}
 		}
-	}
 
-	var companyModel *model.Company
-	if company != "" {
-		var c model.Company
-		if err := tx.Where("name = ?", company).FirstOrCreate(&c).Error; err != nil {
-			tx.Rollback()
-			return nil, err
+		if maintainer.ID == 0 {
+			maintainer = model.Maintainer{
+				Name:             name,
+				Email:            normalizeOrSentinel(email, "EMAIL_MISSING"),
+				GitHubAccount:    normalizeOrSentinel(githubHandle, "GITHUB_MISSING"),
+				GitHubEmail:      "GITHUB_MISSING",
+				MaintainerStatus: model.ActiveMaintainer,
+			}
+			if companyModel != nil {
+				maintainer.CompanyID = &companyModel.ID
+				maintainer.Company = *companyModel
+			}
+			if err := tx.Create(&maintainer).Error; err != nil {
+				return err
+			}
 		}
-		companyModel = &c
-	}
 
-	if maintainer.ID == 0 {
-		maintainer = model.Maintainer{
-			Name:             name,
-			Email:            normalizeOrSentinel(email, "EMAIL_MISSING"),
-			GitHubAccount:    normalizeOrSentinel(githubHandle, "GITHUB_MISSING"),
-			GitHubEmail:      "GITHUB_MISSING",
-			MaintainerStatus: model.ActiveMaintainer,
+		var linked int64
+		if err := tx.Model(&model.MaintainerProject{}).
+			Where("maintainer_id = ? AND project_id = ?", maintainer.ID, project.ID).
+			Count(&linked).Error; err != nil {
+			return err
 		}
-		if companyModel != nil {
-			maintainer.CompanyID = &companyModel.ID
-			maintainer.Company = *companyModel
+		if linked > 0 {
+			return nil
 		}
-		if err := tx.Create(&maintainer).Error; err != nil {
-			tx.Rollback()
-			return nil, err
+		if err := tx.Model(&maintainer).Association("Projects").Append(&project); err != nil {
+			return err
 		}
-	}
-
-	if err := tx.Model(&maintainer).Association("Projects").Append(&project); err != nil {
-		tx.Rollback()
-		return nil, err
-	}
-
-	if err := tx.Commit().Error; err != nil {
+		return s.recordMembershipHistory(tx, model.MembershipHistory{
+			MaintainerID: maintainer.ID,
+			ProjectID:    project.ID,
+			Event:        "JOIN",
+			ToStatus:     string(model.ActiveMaintainer),
+			ToRole:       string(model.MaintainerRole),
+			CreatedAt:    time.Now(),
+		})
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -209,6 +223,41 @@ func (s *SQLStore) UpsertMaintainer(projectID uint, name, email, githubHandle, c
 	return updatedMaintainer, nil
 }
 
+// findUpsertMaintainer returns the maintainer UpsertMaintainer would update: a GitHub handle match wins over an
+// email match, and live maintainers win over ones merged away. It returns nil if nothing matches.
+func findUpsertMaintainer(tx *gorm.DB, email, githubHandle string) (*model.Maintainer, error) {
+	var maintainer model.Maintainer
+	if githubHandle != "" {
+		err := tx.Where("LOWER(git_hub_account) = ?", strings.ToLower(githubHandle)).First(&maintainer).Error
+		if err == nil {
+			return &maintainer, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+	if email != "" {
+		err := tx.Where("LOWER(email) = ?", strings.ToLower(email)).First(&maintainer).Error
+		if err == nil {
+			return &maintainer, nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+	// Fall back to duplicates merged away, so re-importing a merged person does not recreate them.
+	if githubHandle != "" {
+		merged, err := mergedMaintainer(tx, "LOWER(git_hub_account) = ?", strings.ToLower(githubHandle))
+		if err != nil || merged != nil {
+			return merged, err
+		}
+	}
+	if email != "" {
+		return mergedMaintainer(tx, "LOWER(email) = ?", strings.ToLower(email))
+	}
+	return nil, nil
+}
+
 func normalizeOrSentinel(value, sentinel string) string {
 	trimmed := strings.TrimSpace(value)
 	if trimmed == "" {
@@ -217,18 +266,24 @@ func normalizeOrSentinel(value, sentinel string) string {
 	return trimmed
 }
 
-// CreateCompany creates or retrieves a company by name.
+// CreateProject inserts a new project.
+func (s *SQLStore) CreateProject(project *model.Project) error {
+	return s.db.Create(project).Error
+}
+
+// CreateCompany creates a company, failing with ErrCompanyExists if the name matches an existing company or alias.
 func (s *SQLStore) CreateCompany(name string) (*model.Company, error) {
 	trimmed := strings.TrimSpace(name)
 	if trimmed == "" {
 		return nil, fmt.Errorf("company name is required")
 	}
-	var existing model.Company
-	if err := s.db.Where("LOWER(name) = ?", strings.ToLower(trimmed)).First(&existing).Error; err == nil {
-		return nil, ErrCompanyExists
-	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+	existing, err := resolveCompany(s.db, trimmed)
+	if err != nil {
 		return nil, err
 	}
+	if existing != nil {
+		return nil, ErrCompanyExists
+	}
 
 	company := model.Company{Name: trimmed}
 	if err := s.db.Create(&company).Error; err != nil {
@@ -295,6 +350,11 @@ func (s *SQLStore) UpdateMaintainerDetails(maintainerID uint, name, email, githu
 		}
 	}
 
+	var current model.Maintainer
+	if err := s.db.Select("id", "email").First(&current, maintainerID).Error; err != nil {
+		return nil, err
+	}
+
 	updates := map[string]interface{}{
 		"name":              strings.TrimSpace(name),
 		"email":             normalizeOrSentinel(email, "EMAIL_MISSING"),
@@ -302,6 +362,9 @@ func (s *SQLStore) UpdateMaintainerDetails(maintainerID uint, name, email, githu
 		"maintainer_status": status,
 		"company_id":        companyID,
 	}
+	if !strings.EqualFold(current.Email, updates["email"].(string)) {
+		updates["email_verified_at"] = nil
+	}
 
 	if err := s.db.Model(&model.Maintainer{}).
 		Where("id = ?", maintainerID).
@@ -316,6 +379,27 @@ func (s *SQLStore) UpdateMaintainerDetails(maintainerID uint, name, email, githu
 	return &maintainer, nil
 }
 
+// MarkMaintainerEmailVerified records that the maintainer proved ownership of email. It returns ErrEmailMismatch
+// when the maintainer's address has changed since the verification link was issued.
+func (s *SQLStore) MarkMaintainerEmailVerified(maintainerID uint, email string, verifiedAt time.Time) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var maintainer model.Maintainer
+		if err := tx.First(&maintainer, maintainerID).Error; err != nil {
+			return err
+		}
+		if !strings.EqualFold(strings.TrimSpace(maintainer.Email), strings.TrimSpace(email)) {
+			return ErrEmailMismatch
+		}
+		return tx.Model(&maintainer).Update("email_verified_at", verifiedAt).Error
+	})
+}
+
+// MarkEmailVerificationSent records when a verification link was last emailed to the maintainer.
+func (s *SQLStore) MarkEmailVerificationSent(maintainerID uint, sentAt time.Time) error {
+	return s.db.Model(&model.Maintainer{}).Where("id = ?", maintainerID).
+		Update("email_verification_sent_at", sentAt).Error
+}
+
 func (s *SQLStore) GetServiceTeamByProject(projectID, serviceID uint) (*model.ServiceTeam, error) {
 	var st model.ServiceTeam
 	err := s.db.
@@ -348,7 +432,8 @@ func (s *SQLStore) UpsertMaintainerRefCache(cache *model.MaintainerRefCache) err
 	return s.db.Save(cache).Error
 }
 
-// MergeCompanies reassigns all maintainers from fromID to toID and deletes the source company.
+// MergeCompanies reassigns all maintainers, email domains and aliases from fromID to toID, records the source
+// company's name as an alias of the target and deletes the source company.
 func (s *SQLStore) MergeCompanies(fromID, toID uint) error {
 	if fromID == toID {
 		return fmt.Errorf("fromID and toID must differ")
@@ -374,6 +459,12 @@ func (s *SQLStore) MergeCompanies(fromID, toID uint) error {
 		if err := tx.Model(&model.Maintainer{}).Where("company_id = ?", fromID).Update("company_id", toID).Error; err != nil {
 			return err
 		}
+		if err := tx.Model(&model.CompanyDomain{}).Where("company_id = ?", fromID).Update("company_id", toID).Error; err != nil {
+			return err
+		}
+		if err := mergeCompanyAliases(tx, source, target); err != nil {
+			return err
+		}
 		if err := tx.Delete(&model.Company{}, fromID).Error; err != nil {
 			return err
 		}
@@ -435,6 +526,8 @@ func (s *SQLStore) GetProjectServiceTeamMap(serviceName string) (map[uint]*model
 	return result, nil
 
 }
+
+// GetProjectMapByName returns every project keyed by its name and by each of its former names.
 func (s *SQLStore) GetProjectMapByName() (map[string]model.Project, error) {
 	var projects []model.Project
 	if err := s.db.
@@ -445,18 +538,34 @@ func (s *SQLStore) GetProjectMapByName() (map[string]model.Project, error) {
 	}
 
 	projectsByName := make(map[string]model.Project)
+	projectsByID := make(map[uint]model.Project, len(projects))
 	for _, p := range projects {
 		projectsByName[p.Name] = p
+		projectsByID[p.ID] = p
+	}
+	// Former names still find the project, e.g. in onboarding issues opened before a rename.
+	var aliases []model.ProjectAlias
+	if err := s.db.Find(&aliases).Error; err != nil {
+		return nil, err
+	}
+	for _, alias := range aliases {
+		if _, taken := projectsByName[alias.Name]; taken {
+			continue
+		}
+		if p, ok := projectsByID[alias.ProjectID]; ok {
+			projectsByName[alias.Name] = p
+		}
 	}
 	return projectsByName, nil
 }
 
+// LogAuditEvent appends event to the hash-chained audit log, logging any failure.
 func (s *SQLStore) LogAuditEvent(logger *zap.SugaredLogger, event model.AuditLog) error {
 	if event.Message == "" {
 		event.Message = event.Action
 	}
 
-	err := s.db.WithContext(context.Background()).Create(&event).Error
+	err := s.AppendAuditLog(&event)
 	if err != nil {
 		logger.Errorf("failed to write %v audit log: %v", event, err)
 	}
@@ -496,16 +605,17 @@ func (s *SQLStore) ListCompanies() ([]model.Company, error) {
 	return companies, nil
 }
 
-// ListStaffMembers returns all staff members in the database, including their foundations.
+// ListStaffMembers returns the active staff members, including their foundations. Deactivated members are left out;
+// see ListAllStaffMembers.
 func (s *SQLStore) ListStaffMembers() ([]model.StaffMember, error) {
 	var staffMembers []model.StaffMember
-	if err := s.db.Preload("Foundation").Find(&staffMembers).Error; err != nil {
+	if err := s.db.Preload("Foundation").Where("deactivated_at IS NULL").Find(&staffMembers).Error; err != nil {
 		return nil, err
 	}
 	return staffMembers, nil
 }
 
-// IsStaffGitHubAccount returns true if the GitHub account belongs to a staff member.
+// IsStaffGitHubAccount returns true if the GitHub account belongs to an active staff member.
 func (s *SQLStore) IsStaffGitHubAccount(githubAccount string) (bool, error) {
 	if githubAccount == "" {
 		return false, nil
@@ -513,7 +623,7 @@ func (s *SQLStore) IsStaffGitHubAccount(githubAccount string) (bool, error) {
 	var count int64
 	err := s.db.
 		Model(&model.StaffMember{}).
-		Where("LOWER(git_hub_account) = ?", strings.ToLower(githubAccount)).
+		Where("LOWER(git_hub_account) = ? AND deactivated_at IS NULL", strings.ToLower(githubAccount)).
 		Count(&count).Error
 	return count > 0, err
 }
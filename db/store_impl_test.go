@@ -3,6 +3,7 @@ package db
 import (
 	"maintainerd/model"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -23,8 +24,17 @@ func setupTestDB(t *testing.T) *gorm.DB {
 		&model.Project{},
 		&model.Maintainer{},
 		&model.MaintainerProject{},
+		&model.MembershipHistory{},
+		&model.CompanyDomain{},
+		&model.CompanyAlias{},
 		&model.Service{},
 		&model.ServiceTeam{},
+		&model.ServiceUserTeams{},
+		&model.MaintainerRefCache{},
+		&model.Collaborator{},
+		&model.CollaboratorProject{},
+		&model.ProjectAlias{},
+		&model.ProjectMaturityEvent{},
 	)
 	require.NoError(t, err)
 
@@ -187,3 +197,35 @@ func TestUpsertMaintainer_FillsMissingFields(t *testing.T) {
 		Count(&count).Error)
 	assert.Equal(t, int64(1), count)
 }
+
+func TestMarkMaintainerEmailVerified(t *testing.T) {
+	db := setupTestDB(t)
+	store := NewSQLStore(db)
+	_, _, _, alice, _, _ := seedTestData(t, db)
+	verifiedAt := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	t.Run("rejects a stale address", func(t *testing.T) {
+		err := store.MarkMaintainerEmailVerified(alice.ID, "old-alice@example.com", verifiedAt)
+		assert.ErrorIs(t, err, ErrEmailMismatch)
+	})
+
+	t.Run("marks the current address verified", func(t *testing.T) {
+		require.NoError(t, store.MarkMaintainerEmailVerified(alice.ID, "ALICE@example.com", verifiedAt))
+		var refreshed model.Maintainer
+		require.NoError(t, db.First(&refreshed, alice.ID).Error)
+		require.NotNil(t, refreshed.EmailVerifiedAt)
+		assert.True(t, refreshed.EmailVerifiedAt.Equal(verifiedAt))
+	})
+
+	t.Run("keeps verification when the email is unchanged", func(t *testing.T) {
+		updated, err := store.UpdateMaintainerDetails(alice.ID, "Alice D.", "alice@example.com", "alice", model.ActiveMaintainer, alice.CompanyID)
+		require.NoError(t, err)
+		assert.NotNil(t, updated.EmailVerifiedAt)
+	})
+
+	t.Run("clears verification when the email changes", func(t *testing.T) {
+		updated, err := store.UpdateMaintainerDetails(alice.ID, "Alice D.", "alice@new.example.com", "alice", model.ActiveMaintainer, alice.CompanyID)
+		require.NoError(t, err)
+		assert.Nil(t, updated.EmailVerifiedAt)
+	})
+}
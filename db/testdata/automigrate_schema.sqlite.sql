@@ -0,0 +1,49 @@
+-- The schema AutoMigrate created before versioned migrations existed, dumped from SQLite with the models of that
+-- time. TestMigrateAdoptsAutoMigratedDatabase migrates it to check that existing deployments can adopt migrations.
+CREATE TABLE `audit_logs` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`project_id` integer,`maintainer_id` integer,`service_id` integer,`staff_id` integer,`action` text,`message` text,`metadata` text);
+CREATE TABLE `collaborators` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`name` text,`email` text DEFAULT "EMAIL_MISSING",`git_hub_email` text DEFAULT "GITHUB_EMAIL_MISSING",`git_hub_account` text DEFAULT "GITHUB_MISSING",`last_login` datetime,`registered_at` datetime);
+CREATE TABLE `companies` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`name` text);
+CREATE TABLE `foundation_officer_service_users` (`foundation_officer_id` integer,`service_user_id` integer,PRIMARY KEY (`foundation_officer_id`,`service_user_id`));
+CREATE TABLE `foundation_officers` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`name` text,`email` text DEFAULT "EMAIL_MISSING",`git_hub_account` text DEFAULT "GITHUB_MISSING",`registered_at` datetime,`company_id` integer);
+CREATE TABLE `foundations` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`name` text);
+CREATE TABLE `maintainer_projects` (`project_id` integer,`maintainer_id` integer,PRIMARY KEY (`project_id`,`maintainer_id`));
+CREATE TABLE `maintainer_ref_caches` (`project_id` integer PRIMARY KEY AUTOINCREMENT,`e_tag` text,`last_modified` datetime,`body_hash` text,`last_checked` datetime,`created_at` datetime,`updated_at` datetime);
+CREATE TABLE `maintainers` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`name` text,`email` text DEFAULT "EMAIL_MISSING",`git_hub_account` text DEFAULT "GITHUB_MISSING",`git_hub_email` text DEFAULT "GITHUB_MISSING",`maintainer_status` text,`import_warnings` text,`registered_at` datetime,`company_id` integer);
+CREATE TABLE `projects` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`name` text,`parent_project_id` integer,`maturity` text,`git_hub_org` text,`maintainer_ref` text,`dot_project_yaml_ref` text,`onboarding_issue` text,`mailing_list` text DEFAULT "MML_MISSING",CONSTRAINT `chk_projects_name` CHECK (name <> ''));
+CREATE TABLE `service_projects` (`project_id` integer,`service_id` integer,PRIMARY KEY (`project_id`,`service_id`));
+CREATE TABLE `service_teams` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`project_id` integer,`service_id` integer,`service_team_id` integer,`service_team_name` text,`project_name` text);
+CREATE TABLE `service_user_teams` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`service_id` integer,`service_user_id` integer,`service_team_id` integer,`maintainer_id` integer,`collaborator_id` integer);
+CREATE TABLE `service_users` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`service_id` integer,`service_user_id` integer,`service_email` text DEFAULT "EMAIL_MISSING",`service_ref` text,`service_git_hub_name` text);
+CREATE TABLE `services` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`name` text,`description` text);
+CREATE TABLE `staff_members` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`name` text,`email` text DEFAULT "EMAIL_MISSING",`git_hub_account` text DEFAULT "GITHUB_MISSING",`git_hub_email` text DEFAULT "GITHUB_EMAIL_MISSING",`registered_at` datetime,`foundation_id` integer);
+CREATE INDEX `idx_audit_logs_action` ON `audit_logs`(`action`);
+CREATE INDEX `idx_audit_logs_deleted_at` ON `audit_logs`(`deleted_at`);
+CREATE INDEX `idx_audit_logs_maintainer_id` ON `audit_logs`(`maintainer_id`);
+CREATE INDEX `idx_audit_logs_project_id` ON `audit_logs`(`project_id`);
+CREATE INDEX `idx_audit_logs_service_id` ON `audit_logs`(`service_id`);
+CREATE INDEX `idx_audit_logs_staff_id` ON `audit_logs`(`staff_id`);
+CREATE INDEX `idx_collaborators_deleted_at` ON `collaborators`(`deleted_at`);
+CREATE INDEX `idx_companies_deleted_at` ON `companies`(`deleted_at`);
+CREATE UNIQUE INDEX `idx_companies_name` ON `companies`(`name`);
+CREATE INDEX `idx_foundation_officers_deleted_at` ON `foundation_officers`(`deleted_at`);
+CREATE INDEX `idx_foundations_deleted_at` ON `foundations`(`deleted_at`);
+CREATE UNIQUE INDEX `idx_foundations_name` ON `foundations`(`name`);
+CREATE INDEX `idx_maintainers_deleted_at` ON `maintainers`(`deleted_at`);
+CREATE INDEX `idx_projects_deleted_at` ON `projects`(`deleted_at`);
+CREATE INDEX `idx_projects_parent_project_id` ON `projects`(`parent_project_id`);
+CREATE INDEX `idx_service_teams_deleted_at` ON `service_teams`(`deleted_at`);
+CREATE INDEX `idx_service_teams_project_id` ON `service_teams`(`project_id`);
+CREATE INDEX `idx_service_teams_service_id` ON `service_teams`(`service_id`);
+CREATE INDEX `idx_service_user_teams_collaborator_id` ON `service_user_teams`(`collaborator_id`);
+CREATE INDEX `idx_service_user_teams_deleted_at` ON `service_user_teams`(`deleted_at`);
+CREATE INDEX `idx_service_user_teams_maintainer_id` ON `service_user_teams`(`maintainer_id`);
+CREATE INDEX `idx_service_user_teams_service_id` ON `service_user_teams`(`service_id`);
+CREATE INDEX `idx_service_user_teams_service_team_id` ON `service_user_teams`(`service_team_id`);
+CREATE INDEX `idx_service_user_teams_service_user_id` ON `service_user_teams`(`service_user_id`);
+CREATE INDEX `idx_service_users_deleted_at` ON `service_users`(`deleted_at`);
+CREATE INDEX `idx_service_users_service_id` ON `service_users`(`service_id`);
+CREATE INDEX `idx_service_users_service_user_id` ON `service_users`(`service_user_id`);
+CREATE INDEX `idx_services_deleted_at` ON `services`(`deleted_at`);
+CREATE UNIQUE INDEX `idx_services_name` ON `services`(`name`);
+CREATE INDEX `idx_staff_members_deleted_at` ON `staff_members`(`deleted_at`);
+CREATE INDEX `idx_staff_members_foundation_id` ON `staff_members`(`foundation_id`);
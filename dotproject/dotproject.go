@@ -0,0 +1,216 @@
+// Package dotproject renders projects in the .project.yaml metadata format defined in cncf/automation, which
+// projects keep in their governance repositories as the source of truth for their maintainer lineup.
+package dotproject
+
+import (
+	"bytes"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"maintainerd/model"
+
+	"sigs.k8s.io/yaml"
+)
+
+// SchemaVersion is the .project.yaml schema version Render writes.
+const SchemaVersion = "1.0.0"
+
+// header is written at the top of every rendered file.
+const header = "# Generated by maintainer-d from CNCF project records. Propose changes in a pull request.\n"
+
+// managedKeys are the top-level .project.yaml keys Project holds and maintainer-d writes. Merge keeps every other
+// key of an existing file.
+var managedKeys = map[string]bool{
+	"schema_version": true,
+	"slug":           true,
+	"name":           true,
+	"type":           true,
+	"parent":         true,
+	"maturity_log":   true,
+	"repositories":   true,
+	"mailing_lists":  true,
+	"maintainers":    true,
+	"emeritus":       true,
+}
+
+// Project is the content of a .project.yaml file, as far as maintainer-d manages it.
+type Project struct {
+	SchemaVersion string `json:"schema_version"`
+	Slug          string `json:"slug"`
+	Name          string `json:"name"`
+	// Type is "project", or "subproject" for a project nested under Parent.
+	Type         string          `json:"type"`
+	Parent       string          `json:"parent,omitempty"`
+	MaturityLog  []MaturityEntry `json:"maturity_log,omitempty"`
+	Repositories []string        `json:"repositories,omitempty"`
+	MailingLists []string        `json:"mailing_lists,omitempty"`
+	Maintainers  []Maintainer    `json:"maintainers,omitempty"`
+	Emeritus     []Maintainer    `json:"emeritus,omitempty"`
+}
+
+// MaturityEntry is one step of a project's maturity history.
+type MaturityEntry struct {
+	Phase string     `json:"phase"`
+	Date  *time.Time `json:"date,omitempty"`
+	// Issue links the TOC vote issue for the move.
+	Issue string `json:"issue,omitempty"`
+}
+
+// Maintainer is a person listed in a .project.yaml file.
+type Maintainer struct {
+	GitHub  string `json:"github"`
+	Name    string `json:"name,omitempty"`
+	Email   string `json:"email,omitempty"`
+	Company string `json:"company,omitempty"`
+	// Role is omitted for the default maintainer role.
+	Role string `json:"role,omitempty"`
+}
+
+// Source is the project record a .project.yaml file is built from.
+type Source struct {
+	Project model.Project
+	// Parent is set when Project is a sub-project.
+	Parent *model.Project
+	// Memberships are the project's memberships with Maintainer and Maintainer.Company loaded.
+	Memberships    []model.MaintainerProject
+	MaturityEvents []model.ProjectMaturityEvent
+	// RedactEmails leaves maintainer email addresses out.
+	RedactEmails bool
+}
+
+var slugInvalid = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Slug returns the URL-safe identifier for a project name, such as "open-telemetry" for "Open Telemetry".
+func Slug(name string) string {
+	return strings.Trim(slugInvalid.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+// Build returns the .project.yaml content for a project record. Active members are listed as maintainers and
+// emeritus members as emeritus; retired and archived memberships are left out.
+func Build(src Source) Project {
+	project := Project{
+		SchemaVersion: SchemaVersion,
+		Slug:          Slug(src.Project.Name),
+		Name:          src.Project.Name,
+		Type:          "project",
+	}
+	if src.Parent != nil {
+		project.Type = "subproject"
+		project.Parent = Slug(src.Parent.Name)
+	}
+	for _, event := range src.MaturityEvents {
+		date := event.EffectiveAt.UTC()
+		entry := MaturityEntry{Phase: phase(event.ToMaturity), Date: &date}
+		if event.VoteIssueURL != nil {
+			entry.Issue = *event.VoteIssueURL
+		}
+		project.MaturityLog = append(project.MaturityLog, entry)
+	}
+	if len(project.MaturityLog) == 0 && src.Project.Maturity != "" {
+		project.MaturityLog = []MaturityEntry{{Phase: phase(src.Project.Maturity)}}
+	}
+	if org := strings.TrimSpace(src.Project.GitHubOrg); org != "" {
+		project.Repositories = []string{"https://github.com/" + org}
+	}
+	if list := present(src.Project.MailingList); list != "" {
+		project.MailingLists = []string{list}
+	}
+
+	for _, membership := range src.Memberships {
+		maintainer := membership.Maintainer
+		entry := Maintainer{
+			GitHub:  presentValue(maintainer.GitHubAccount),
+			Name:    strings.TrimSpace(maintainer.Name),
+			Company: strings.TrimSpace(maintainer.Company.Name),
+		}
+		if !src.RedactEmails {
+			entry.Email = presentValue(maintainer.Email)
+		}
+		if membership.Role != "" && membership.Role != model.MaintainerRole {
+			entry.Role = string(membership.Role)
+		}
+		switch membership.Status {
+		case model.ActiveMaintainer, "":
+			project.Maintainers = append(project.Maintainers, entry)
+		case model.EmeritusMaintainer:
+			project.Emeritus = append(project.Emeritus, entry)
+		}
+	}
+	sortMaintainers(project.Maintainers)
+	sortMaintainers(project.Emeritus)
+	return project
+}
+
+// Render returns project as .project.yaml content.
+func Render(project Project) ([]byte, error) {
+	body, err := yaml.Marshal(project)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(header), body...), nil
+}
+
+// Merge renders project over existing .project.yaml content. Top-level keys maintainer-d does not manage, such as
+// description, website or security, are kept as they are, after the ones it writes.
+func Merge(existing []byte, project Project) ([]byte, error) {
+	content, err := Render(project)
+	if err != nil {
+		return nil, err
+	}
+	var current map[string]any
+	if err := yaml.Unmarshal(bytes.TrimSpace(existing), &current); err != nil {
+		return nil, err
+	}
+	unmanaged := make(map[string]any)
+	for key, value := range current {
+		if !managedKeys[key] {
+			unmanaged[key] = value
+		}
+	}
+	if len(unmanaged) == 0 {
+		return content, nil
+	}
+	body, err := yaml.Marshal(unmanaged)
+	if err != nil {
+		return nil, err
+	}
+	return append(content, body...), nil
+}
+
+// Parse reads .project.yaml content.
+func Parse(data []byte) (*Project, error) {
+	var project Project
+	if err := yaml.Unmarshal(bytes.TrimSpace(data), &project); err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+func phase(maturity model.Maturity) string {
+	return strings.ToLower(string(maturity))
+}
+
+func present(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return presentValue(*value)
+}
+
+// presentValue returns value, or "" for the placeholders recorded when a field is missing.
+func presentValue(value string) string {
+	value = strings.TrimSpace(value)
+	switch value {
+	case "EMAIL_MISSING", "GITHUB_MISSING", "MML_MISSING":
+		return ""
+	}
+	return value
+}
+
+func sortMaintainers(maintainers []Maintainer) {
+	sort.SliceStable(maintainers, func(i, j int) bool {
+		return strings.ToLower(maintainers[i].GitHub) < strings.ToLower(maintainers[j].GitHub)
+	})
+}
@@ -0,0 +1,51 @@
+package dotproject
+
+import (
+	"testing"
+	"time"
+
+	"maintainerd/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlug(t *testing.T) {
+	assert.Equal(t, "open-telemetry", Slug("Open Telemetry"))
+	assert.Equal(t, "cert-manager", Slug(" cert-manager "))
+	assert.Equal(t, "k3s", Slug("K3s!"))
+}
+
+func TestBuildAndRender(t *testing.T) {
+	list := "MML_MISSING"
+	src := Source{
+		Project: model.Project{Name: "Falco", Maturity: model.Incubating, GitHubOrg: "falcosecurity", MailingList: &list},
+		Memberships: []model.MaintainerProject{
+			{Status: model.ActiveMaintainer, Role: model.MaintainerRole, Maintainer: model.Maintainer{Name: "Zed", GitHubAccount: "zed", Email: "zed@example.com"}},
+			{Status: model.ActiveMaintainer, Role: model.SecurityContactRole, Maintainer: model.Maintainer{Name: "Amy", GitHubAccount: "amy", Email: "EMAIL_MISSING", Company: model.Company{Name: "Sysdig"}}},
+			{Status: model.RetiredMaintainer, Maintainer: model.Maintainer{Name: "Old", GitHubAccount: "old"}},
+		},
+		RedactEmails: true,
+	}
+	project := Build(src)
+	assert.Equal(t, "falco", project.Slug)
+	assert.Equal(t, "project", project.Type)
+	assert.Equal(t, []MaturityEntry{{Phase: "incubating"}}, project.MaturityLog, "the current maturity stands in for a missing history")
+	assert.Equal(t, []string{"https://github.com/falcosecurity"}, project.Repositories)
+	assert.Empty(t, project.MailingLists)
+	assert.Equal(t, []Maintainer{
+		{GitHub: "amy", Name: "Amy", Company: "Sysdig", Role: "security-contact"},
+		{GitHub: "zed", Name: "Zed"},
+	}, project.Maintainers)
+	assert.Empty(t, project.Emeritus)
+
+	src.MaturityEvents = []model.ProjectMaturityEvent{{ToMaturity: model.Incubating, EffectiveAt: time.Date(2020, 1, 8, 0, 0, 0, 0, time.UTC)}}
+	body, err := Render(Build(src))
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "schema_version: 1.0.0\n")
+	assert.Contains(t, string(body), "date: \"2020-01-08T00:00:00Z\"\n")
+
+	parsed, err := Parse(body)
+	require.NoError(t, err)
+	assert.Equal(t, Build(src), *parsed, "rendered files parse back to the same content")
+}
@@ -0,0 +1,191 @@
+package dotproject
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/go-github/v55/github"
+)
+
+var (
+	ErrNotFound   = errors.New("not found on GitHub")
+	ErrInvalidRef = errors.New("file reference must be a github.com blob or raw.githubusercontent.com URL")
+)
+
+// FileRef locates a file in a GitHub repository.
+type FileRef struct {
+	Owner string
+	Repo  string
+	// Branch is empty for the repository's default branch.
+	Branch string
+	Path   string
+}
+
+// String returns the github.com URL of the file.
+func (f FileRef) String() string {
+	branch := f.Branch
+	if branch == "" {
+		branch = "HEAD"
+	}
+	return fmt.Sprintf("https://github.com/%s/%s/blob/%s/%s", f.Owner, f.Repo, branch, f.Path)
+}
+
+// ParseFileRef parses a github.com blob URL or a raw.githubusercontent.com URL, such as a project's
+// DotProjectYamlRef. A bare github.com/owner/repo URL refers to .project.yaml on the default branch.
+func ParseFileRef(ref string) (FileRef, error) {
+	parsed, err := url.Parse(strings.TrimSpace(ref))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return FileRef{}, ErrInvalidRef
+	}
+	parts := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	switch strings.ToLower(parsed.Host) {
+	case "github.com", "www.github.com":
+		if len(parts) == 2 && parts[0] != "" && parts[1] != "" {
+			return FileRef{Owner: parts[0], Repo: parts[1], Path: ".project.yaml"}, nil
+		}
+		if len(parts) >= 5 && parts[2] == "blob" {
+			return FileRef{Owner: parts[0], Repo: parts[1], Branch: parts[3], Path: strings.Join(parts[4:], "/")}, nil
+		}
+	case "raw.githubusercontent.com":
+		if len(parts) >= 4 {
+			return FileRef{Owner: parts[0], Repo: parts[1], Branch: parts[2], Path: strings.Join(parts[3:], "/")}, nil
+		}
+	}
+	return FileRef{}, ErrInvalidRef
+}
+
+// PullRequest is an open pull request.
+type PullRequest struct {
+	Number int
+	URL    string
+}
+
+// GitHubClient is the GitHub API surface Propose uses. Methods return ErrNotFound for missing repositories,
+// branches and files.
+type GitHubClient interface {
+	DefaultBranch(ctx context.Context, owner, repo string) (string, error)
+	// GetFile returns a file's content and blob SHA on branch.
+	GetFile(ctx context.Context, owner, repo, branch, path string) ([]byte, string, error)
+	// BranchSHA returns the commit SHA branch points at.
+	BranchSHA(ctx context.Context, owner, repo, branch string) (string, error)
+	CreateBranch(ctx context.Context, owner, repo, branch, sha string) error
+	// PutFile creates or, when fileSHA is set, replaces a file on branch in one commit.
+	PutFile(ctx context.Context, owner, repo, branch, path string, content []byte, fileSHA, message string) error
+	// FindPullRequest returns the open pull request from head into base, or nil.
+	FindPullRequest(ctx context.Context, owner, repo, head, base string) (*PullRequest, error)
+	CreatePullRequest(ctx context.Context, owner, repo, head, base, title, body string) (*PullRequest, error)
+	UpdatePullRequest(ctx context.Context, owner, repo string, number int, title, body string) (*PullRequest, error)
+}
+
+// NewGitHubClient returns a GitHubClient backed by the GitHub REST API.
+func NewGitHubClient(client *github.Client) GitHubClient {
+	return &restClient{client: client}
+}
+
+type restClient struct {
+	client *github.Client
+}
+
+func (c *restClient) DefaultBranch(ctx context.Context, owner, repo string) (string, error) {
+	repository, resp, err := c.client.Repositories.Get(ctx, owner, repo)
+	if err != nil {
+		return "", notFound(resp, err)
+	}
+	return repository.GetDefaultBranch(), nil
+}
+
+func (c *restClient) GetFile(ctx context.Context, owner, repo, branch, path string) ([]byte, string, error) {
+	file, _, resp, err := c.client.Repositories.GetContents(ctx, owner, repo, path, &github.RepositoryContentGetOptions{Ref: branch})
+	if err != nil {
+		return nil, "", notFound(resp, err)
+	}
+	if file == nil {
+		return nil, "", fmt.Errorf("%s is a directory", path)
+	}
+	content, err := file.GetContent()
+	if err != nil {
+		return nil, "", err
+	}
+	return []byte(content), file.GetSHA(), nil
+}
+
+func (c *restClient) BranchSHA(ctx context.Context, owner, repo, branch string) (string, error) {
+	ref, resp, err := c.client.Git.GetRef(ctx, owner, repo, "heads/"+branch)
+	if err != nil {
+		return "", notFound(resp, err)
+	}
+	return ref.GetObject().GetSHA(), nil
+}
+
+func (c *restClient) CreateBranch(ctx context.Context, owner, repo, branch, sha string) error {
+	_, _, err := c.client.Git.CreateRef(ctx, owner, repo, &github.Reference{
+		Ref:    github.String("refs/heads/" + branch),
+		Object: &github.GitObject{SHA: github.String(sha)},
+	})
+	return err
+}
+
+func (c *restClient) PutFile(ctx context.Context, owner, repo, branch, path string, content []byte, fileSHA, message string) error {
+	opts := &github.RepositoryContentFileOptions{
+		Message: github.String(message),
+		Content: content,
+		Branch:  github.String(branch),
+	}
+	if fileSHA == "" {
+		_, _, err := c.client.Repositories.CreateFile(ctx, owner, repo, path, opts)
+		return err
+	}
+	opts.SHA = github.String(fileSHA)
+	_, _, err := c.client.Repositories.UpdateFile(ctx, owner, repo, path, opts)
+	return err
+}
+
+func (c *restClient) FindPullRequest(ctx context.Context, owner, repo, head, base string) (*PullRequest, error) {
+	pulls, _, err := c.client.PullRequests.List(ctx, owner, repo, &github.PullRequestListOptions{
+		State: "open",
+		Head:  head,
+		Base:  base,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(pulls) == 0 {
+		return nil, nil
+	}
+	return &PullRequest{Number: pulls[0].GetNumber(), URL: pulls[0].GetHTMLURL()}, nil
+}
+
+func (c *restClient) CreatePullRequest(ctx context.Context, owner, repo, head, base, title, body string) (*PullRequest, error) {
+	pull, _, err := c.client.PullRequests.Create(ctx, owner, repo, &github.NewPullRequest{
+		Title: github.String(title),
+		Head:  github.String(head),
+		Base:  github.String(base),
+		Body:  github.String(body),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &PullRequest{Number: pull.GetNumber(), URL: pull.GetHTMLURL()}, nil
+}
+
+func (c *restClient) UpdatePullRequest(ctx context.Context, owner, repo string, number int, title, body string) (*PullRequest, error) {
+	pull, _, err := c.client.PullRequests.Edit(ctx, owner, repo, number, &github.PullRequest{
+		Title: github.String(title),
+		Body:  github.String(body),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &PullRequest{Number: pull.GetNumber(), URL: pull.GetHTMLURL()}, nil
+}
+
+func notFound(resp *github.Response, err error) error {
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %v", ErrNotFound, err)
+	}
+	return err
+}
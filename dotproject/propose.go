@@ -0,0 +1,298 @@
+package dotproject
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"maintainerd/db"
+	"maintainerd/model"
+
+	"gorm.io/gorm"
+)
+
+// Proposal describes how a project's .project.yaml differs from what its records generate, and the pull request
+// opened to reconcile them.
+type Proposal struct {
+	File FileRef
+	// Base is the branch the file was read from and the pull request targets.
+	Base string
+	// Branch is the branch the generated file is committed to.
+	Branch  string
+	Content []byte
+	// Changed is false when the existing file already matches the records.
+	Changed bool
+	// Exists is false when the repository has no file yet.
+	Exists bool
+	// Diff is a line diff from the existing file to Content.
+	Diff string
+	// Summary lists the changes in plain words, for the pull request description.
+	Summary     []string
+	PullRequest *PullRequest
+	// PullRequestCreated is false when an open pull request was updated instead.
+	PullRequestCreated bool
+}
+
+// LoadSource reads the records .project.yaml content is built from for one project.
+func LoadSource(store *db.SQLStore, projectID uint, redactEmails bool) (Source, error) {
+	tx := store.DB()
+	src := Source{RedactEmails: redactEmails}
+	if err := tx.First(&src.Project, projectID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return Source{}, db.ErrProjectNotFound
+		}
+		return Source{}, err
+	}
+	if src.Project.ParentProjectID != nil {
+		var parent model.Project
+		if err := tx.First(&parent, *src.Project.ParentProjectID).Error; err == nil {
+			src.Parent = &parent
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return Source{}, err
+		}
+	}
+	var memberships []model.MaintainerProject
+	if err := tx.Preload("Maintainer").Preload("Maintainer.Company").
+		Where("project_id = ?", projectID).Order("maintainer_id").Find(&memberships).Error; err != nil {
+		return Source{}, fmt.Errorf("loading memberships: %w", err)
+	}
+	for _, membership := range memberships {
+		// Memberships of deleted or merged maintainers load without one.
+		if membership.Maintainer.ID != 0 {
+			src.Memberships = append(src.Memberships, membership)
+		}
+	}
+	if err := tx.Where("project_id = ?", projectID).Order("effective_at, id").Find(&src.MaturityEvents).Error; err != nil {
+		return Source{}, fmt.Errorf("loading maturity events: %w", err)
+	}
+	return src, nil
+}
+
+// Preview compares project with the file at ref without changing anything.
+func Preview(ctx context.Context, client GitHubClient, ref FileRef, project Project) (*Proposal, error) {
+	content, err := Render(project)
+	if err != nil {
+		return nil, err
+	}
+	proposal := &Proposal{File: ref, Base: ref.Branch, Content: content}
+	if proposal.Base == "" {
+		if proposal.Base, err = client.DefaultBranch(ctx, ref.Owner, ref.Repo); err != nil {
+			return nil, fmt.Errorf("reading %s/%s: %w", ref.Owner, ref.Repo, err)
+		}
+	}
+	existing, _, err := client.GetFile(ctx, ref.Owner, ref.Repo, proposal.Base, ref.Path)
+	switch {
+	case errors.Is(err, ErrNotFound):
+	case err != nil:
+		return nil, fmt.Errorf("reading %s: %w", ref, err)
+	default:
+		proposal.Exists = true
+	}
+
+	var current *Project
+	if proposal.Exists {
+		// A file that does not parse is replaced wholesale.
+		if parsed, err := Parse(existing); err == nil {
+			current = parsed
+			if proposal.Content, err = Merge(existing, project); err != nil {
+				return nil, err
+			}
+			if normalized, err := Merge(existing, *parsed); err == nil && bytes.Equal(normalized, proposal.Content) {
+				return proposal, nil
+			}
+		}
+	}
+	proposal.Changed = true
+	proposal.Diff = Diff(ref.Path, existing, proposal.Content)
+	proposal.Summary = Summarize(current, project)
+	return proposal, nil
+}
+
+// Propose commits project to branch, created from the base branch if needed, and opens a pull request into the
+// base branch, or updates the one already open. Nothing is written when the file already matches.
+func Propose(ctx context.Context, client GitHubClient, ref FileRef, project Project, branch string) (*Proposal, error) {
+	proposal, err := Preview(ctx, client, ref, project)
+	if err != nil || !proposal.Changed {
+		return proposal, err
+	}
+	proposal.Branch = branch
+	if _, err := client.BranchSHA(ctx, ref.Owner, ref.Repo, branch); errors.Is(err, ErrNotFound) {
+		baseSHA, err := client.BranchSHA(ctx, ref.Owner, ref.Repo, proposal.Base)
+		if err != nil {
+			return nil, fmt.Errorf("reading branch %s: %w", proposal.Base, err)
+		}
+		if err := client.CreateBranch(ctx, ref.Owner, ref.Repo, branch, baseSHA); err != nil {
+			return nil, fmt.Errorf("creating branch %s: %w", branch, err)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("reading branch %s: %w", branch, err)
+	}
+
+	// Re-running against a branch that already carries the content leaves it alone.
+	onBranch, fileSHA, err := client.GetFile(ctx, ref.Owner, ref.Repo, branch, ref.Path)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return nil, fmt.Errorf("reading %s on %s: %w", ref.Path, branch, err)
+	}
+	if !bytes.Equal(onBranch, proposal.Content) {
+		message := fmt.Sprintf("Update %s for %s from maintainer-d", ref.Path, project.Name)
+		if err := client.PutFile(ctx, ref.Owner, ref.Repo, branch, ref.Path, proposal.Content, fileSHA, message); err != nil {
+			return nil, fmt.Errorf("committing %s: %w", ref.Path, err)
+		}
+	}
+
+	title := fmt.Sprintf("Sync %s with maintainer-d records for %s", ref.Path, project.Name)
+	body := pullRequestBody(proposal)
+	open, err := client.FindPullRequest(ctx, ref.Owner, ref.Repo, ref.Owner+":"+branch, proposal.Base)
+	if err != nil {
+		return nil, fmt.Errorf("listing pull requests: %w", err)
+	}
+	if open != nil {
+		proposal.PullRequest, err = client.UpdatePullRequest(ctx, ref.Owner, ref.Repo, open.Number, title, body)
+	} else {
+		proposal.PullRequest, err = client.CreatePullRequest(ctx, ref.Owner, ref.Repo, branch, proposal.Base, title, body)
+		proposal.PullRequestCreated = err == nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening pull request: %w", err)
+	}
+	return proposal, nil
+}
+
+// Summarize describes the changes from current, nil for a new file, to next.
+func Summarize(current *Project, next Project) []string {
+	if current == nil {
+		return []string{fmt.Sprintf("Add %s with %d %s", next.Name, len(next.Maintainers), plural("maintainer", len(next.Maintainers)))}
+	}
+	var summary []string
+	if from, to := currentPhase(current.MaturityLog), currentPhase(next.MaturityLog); from != to {
+		summary = append(summary, fmt.Sprintf("Maturity: %s → %s", orNone(from), orNone(to)))
+	}
+	summary = append(summary, listChanges("maintainer", githubHandles(current.Maintainers), githubHandles(next.Maintainers))...)
+	summary = append(summary, listChanges("emeritus maintainer", githubHandles(current.Emeritus), githubHandles(next.Emeritus))...)
+	summary = append(summary, listChanges("mailing list", current.MailingLists, next.MailingLists)...)
+	summary = append(summary, listChanges("repository", current.Repositories, next.Repositories)...)
+	if len(summary) == 0 {
+		summary = append(summary, "Update maintainer details and formatting")
+	}
+	return summary
+}
+
+// Diff returns a line diff from before to after, with every line prefixed by " ", "-" or "+".
+func Diff(path string, before, after []byte) string {
+	a, b := splitLines(before), splitLines(after)
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- a/%s\n+++ b/%s\n", path, path)
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			out.WriteString(" " + a[i] + "\n")
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] >= lcs[i+1][j]):
+			out.WriteString("+" + b[j] + "\n")
+			j++
+		default:
+			out.WriteString("-" + a[i] + "\n")
+			i++
+		}
+	}
+	return out.String()
+}
+
+func pullRequestBody(proposal *Proposal) string {
+	var body strings.Builder
+	body.WriteString("This pull request updates `" + proposal.File.Path + "` to match the project's records in maintainer-d.\n\n")
+	for _, line := range proposal.Summary {
+		body.WriteString("- " + line + "\n")
+	}
+	body.WriteString("\nOther fields in the file are kept as they are. Maintainer email addresses are left out. ")
+	body.WriteString("If a change looks wrong, correct the record in maintainer-d ")
+	body.WriteString("rather than this branch; the next sync overwrites it.\n")
+	return body.String()
+}
+
+func splitLines(data []byte) []string {
+	text := strings.TrimSuffix(string(data), "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+func currentPhase(log []MaturityEntry) string {
+	if len(log) == 0 {
+		return ""
+	}
+	return log[len(log)-1].Phase
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "none"
+	}
+	return value
+}
+
+func githubHandles(maintainers []Maintainer) []string {
+	handles := make([]string, 0, len(maintainers))
+	for _, maintainer := range maintainers {
+		handles = append(handles, maintainer.GitHub)
+	}
+	return handles
+}
+
+// listChanges describes values added to and removed from a list, comparing case-insensitively.
+func listChanges(noun string, before, after []string) []string {
+	seen := make(map[string]bool, len(before))
+	for _, value := range before {
+		seen[strings.ToLower(value)] = true
+	}
+	kept := make(map[string]bool, len(after))
+	var added, removed []string
+	for _, value := range after {
+		kept[strings.ToLower(value)] = true
+		if !seen[strings.ToLower(value)] {
+			added = append(added, value)
+		}
+	}
+	for _, value := range before {
+		if !kept[strings.ToLower(value)] {
+			removed = append(removed, value)
+		}
+	}
+	var changes []string
+	if len(added) > 0 {
+		changes = append(changes, fmt.Sprintf("Add %s: %s", plural(noun, len(added)), strings.Join(added, ", ")))
+	}
+	if len(removed) > 0 {
+		changes = append(changes, fmt.Sprintf("Remove %s: %s", plural(noun, len(removed)), strings.Join(removed, ", ")))
+	}
+	return changes
+}
+
+func plural(noun string, n int) string {
+	switch {
+	case n == 1:
+		return noun
+	case strings.HasSuffix(noun, "y"):
+		return strings.TrimSuffix(noun, "y") + "ies"
+	}
+	return noun + "s"
+}
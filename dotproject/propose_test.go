@@ -0,0 +1,257 @@
+package dotproject
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-github/v55/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGitHub serves the slice of the GitHub REST API Propose uses for a single repository, acme/governance.
+type fakeGitHub struct {
+	mu       sync.Mutex
+	branches map[string]string            // branch -> commit SHA
+	files    map[string]map[string]string // branch -> path -> content
+	pulls    []map[string]any
+	commits  int
+}
+
+func newFakeGitHub(t *testing.T) (*fakeGitHub, GitHubClient) {
+	t.Helper()
+	fake := &fakeGitHub{
+		branches: map[string]string{"main": "base-sha"},
+		files:    map[string]map[string]string{"main": {}},
+	}
+	server := httptest.NewServer(http.StripPrefix("/repos/acme/governance", http.HandlerFunc(fake.serve)))
+	t.Cleanup(server.Close)
+	client := github.NewClient(nil)
+	baseURL, err := url.Parse(server.URL + "/")
+	require.NoError(t, err)
+	client.BaseURL = baseURL
+	return fake, NewGitHubClient(client)
+}
+
+func (f *fakeGitHub) serve(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var body map[string]any
+	if r.Body != nil {
+		_ = json.NewDecoder(r.Body).Decode(&body)
+	}
+	reply := func(status int, v any) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(v)
+	}
+	notFound := func() { reply(http.StatusNotFound, map[string]string{"message": "Not Found"}) }
+	path := r.URL.Path
+
+	switch {
+	case r.Method == http.MethodGet && path == "":
+		reply(http.StatusOK, map[string]string{"default_branch": "main"})
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "/contents/"):
+		content, ok := f.files[r.URL.Query().Get("ref")][strings.TrimPrefix(path, "/contents/")]
+		if !ok {
+			notFound()
+			return
+		}
+		reply(http.StatusOK, map[string]string{
+			"type": "file", "encoding": "base64", "sha": blobSHA(content),
+			"content": base64.StdEncoding.EncodeToString([]byte(content)),
+		})
+	case r.Method == http.MethodPut && strings.HasPrefix(path, "/contents/"):
+		branch := body["branch"].(string)
+		file := strings.TrimPrefix(path, "/contents/")
+		current, exists := f.files[branch][file]
+		if sha, _ := body["sha"].(string); exists != (sha != "") || (exists && sha != blobSHA(current)) {
+			reply(http.StatusConflict, map[string]string{"message": "sha mismatch"})
+			return
+		}
+		content, _ := base64.StdEncoding.DecodeString(body["content"].(string))
+		f.files[branch][file] = string(content)
+		f.commits++
+		reply(http.StatusOK, map[string]any{})
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "/git/ref/heads/"):
+		sha, ok := f.branches[strings.TrimPrefix(path, "/git/ref/heads/")]
+		if !ok {
+			notFound()
+			return
+		}
+		reply(http.StatusOK, map[string]any{"object": map[string]string{"sha": sha}})
+	case r.Method == http.MethodPost && path == "/git/refs":
+		branch := strings.TrimPrefix(body["ref"].(string), "refs/heads/")
+		from := body["sha"].(string)
+		f.branches[branch] = from
+		f.files[branch] = map[string]string{}
+		for base, sha := range f.branches {
+			if sha == from && base != branch {
+				for file, content := range f.files[base] {
+					f.files[branch][file] = content
+				}
+			}
+		}
+		reply(http.StatusCreated, map[string]any{"ref": body["ref"]})
+	case r.Method == http.MethodGet && path == "/pulls":
+		var open []map[string]any
+		for _, pull := range f.pulls {
+			head, base := pull["head"].(map[string]any), pull["base"].(map[string]any)
+			if "acme:"+head["ref"].(string) == r.URL.Query().Get("head") && base["ref"] == r.URL.Query().Get("base") {
+				open = append(open, pull)
+			}
+		}
+		reply(http.StatusOK, open)
+	case r.Method == http.MethodPost && path == "/pulls":
+		pull := map[string]any{
+			"number": len(f.pulls) + 1, "head": map[string]any{"ref": body["head"]}, "base": map[string]any{"ref": body["base"]},
+			"title": body["title"], "body": body["body"],
+			"html_url": fmt.Sprintf("https://github.com/acme/governance/pull/%d", len(f.pulls)+1),
+		}
+		f.pulls = append(f.pulls, pull)
+		reply(http.StatusCreated, pull)
+	case r.Method == http.MethodPatch && strings.HasPrefix(path, "/pulls/"):
+		var number int
+		fmt.Sscanf(strings.TrimPrefix(path, "/pulls/"), "%d", &number)
+		if number < 1 || number > len(f.pulls) {
+			notFound()
+			return
+		}
+		pull := f.pulls[number-1]
+		pull["title"], pull["body"] = body["title"], body["body"]
+		reply(http.StatusOK, pull)
+	default:
+		notFound()
+	}
+}
+
+func blobSHA(content string) string {
+	return fmt.Sprintf("%x", sha1.Sum([]byte(content)))
+}
+
+func TestParseFileRef(t *testing.T) {
+	ref, err := ParseFileRef("https://github.com/falcosecurity/evolution/blob/main/projects/falco/.project.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, FileRef{Owner: "falcosecurity", Repo: "evolution", Branch: "main", Path: "projects/falco/.project.yaml"}, ref)
+
+	ref, err = ParseFileRef("https://raw.githubusercontent.com/falcosecurity/evolution/main/.project.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, FileRef{Owner: "falcosecurity", Repo: "evolution", Branch: "main", Path: ".project.yaml"}, ref)
+
+	ref, err = ParseFileRef("https://github.com/falcosecurity/evolution")
+	require.NoError(t, err)
+	assert.Equal(t, ".project.yaml", ref.Path)
+	assert.Empty(t, ref.Branch)
+
+	_, err = ParseFileRef("https://gitlab.com/falcosecurity/evolution/-/blob/main/.project.yaml")
+	assert.ErrorIs(t, err, ErrInvalidRef)
+}
+
+func TestProposeOpensAndUpdatesPullRequest(t *testing.T) {
+	fake, client := newFakeGitHub(t)
+	ctx := context.Background()
+	ref := FileRef{Owner: "acme", Repo: "governance", Path: "falco/.project.yaml"}
+	project := Project{
+		SchemaVersion: SchemaVersion, Slug: "falco", Name: "Falco", Type: "project",
+		MaturityLog: []MaturityEntry{{Phase: "incubating"}},
+		Maintainers: []Maintainer{{GitHub: "amy", Name: "Amy"}},
+	}
+	existing, err := Render(project)
+	require.NoError(t, err)
+	fake.files["main"][ref.Path] = string(existing)
+
+	proposal, err := Propose(ctx, client, ref, project, "maintainer-d/falco")
+	require.NoError(t, err)
+	assert.False(t, proposal.Changed, "a matching file needs no pull request")
+	assert.Nil(t, proposal.PullRequest)
+	assert.Zero(t, fake.commits)
+
+	project.MaturityLog = append(project.MaturityLog, MaturityEntry{Phase: "graduated"})
+	project.Maintainers = append(project.Maintainers, Maintainer{GitHub: "zed", Name: "Zed"})
+	proposal, err = Propose(ctx, client, ref, project, "maintainer-d/falco")
+	require.NoError(t, err)
+	assert.True(t, proposal.Changed)
+	assert.Equal(t, "main", proposal.Base)
+	assert.Equal(t, []string{"Maturity: incubating → graduated", "Add maintainer: zed"}, proposal.Summary)
+	assert.Contains(t, proposal.Diff, "+- github: zed\n")
+	require.NotNil(t, proposal.PullRequest)
+	assert.True(t, proposal.PullRequestCreated)
+	assert.Equal(t, 1, proposal.PullRequest.Number)
+	assert.Equal(t, string(proposal.Content), fake.files["maintainer-d/falco"][ref.Path])
+	assert.Equal(t, string(existing), fake.files["main"][ref.Path], "the base branch is left alone")
+	assert.Contains(t, fake.pulls[0]["body"], "- Add maintainer: zed\n")
+
+	// A second change lands on the same branch and updates the open pull request.
+	project.Maintainers = project.Maintainers[1:]
+	proposal, err = Propose(ctx, client, ref, project, "maintainer-d/falco")
+	require.NoError(t, err)
+	assert.False(t, proposal.PullRequestCreated)
+	assert.Equal(t, 1, proposal.PullRequest.Number)
+	assert.Len(t, fake.pulls, 1)
+	assert.Equal(t, 2, fake.commits)
+	assert.Contains(t, fake.pulls[0]["body"], "- Remove maintainer: amy\n")
+
+	// Re-running with nothing new does not commit again.
+	_, err = Propose(ctx, client, ref, project, "maintainer-d/falco")
+	require.NoError(t, err)
+	assert.Equal(t, 2, fake.commits)
+}
+
+func TestProposeCreatesMissingFile(t *testing.T) {
+	fake, client := newFakeGitHub(t)
+	ref := FileRef{Owner: "acme", Repo: "governance", Branch: "main", Path: ".project.yaml"}
+	project := Project{SchemaVersion: SchemaVersion, Slug: "k3s", Name: "K3s", Type: "project", Maintainers: []Maintainer{{GitHub: "amy"}}}
+
+	proposal, err := Preview(context.Background(), client, ref, project)
+	require.NoError(t, err)
+	assert.False(t, proposal.Exists)
+	assert.Equal(t, []string{"Add K3s with 1 maintainer"}, proposal.Summary)
+	assert.Empty(t, fake.branches["maintainer-d/k3s"], "previews do not write")
+
+	proposal, err = Propose(context.Background(), client, ref, project, "maintainer-d/k3s")
+	require.NoError(t, err)
+	assert.True(t, proposal.PullRequestCreated)
+	assert.Equal(t, string(proposal.Content), fake.files["maintainer-d/k3s"][".project.yaml"])
+
+	_, err = Preview(context.Background(), client, FileRef{Owner: "acme", Repo: "missing", Path: ".project.yaml"}, project)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestProposeKeepsUnmanagedFields(t *testing.T) {
+	fake, client := newFakeGitHub(t)
+	ref := FileRef{Owner: "acme", Repo: "governance", Branch: "main", Path: ".project.yaml"}
+	fake.files["main"][ref.Path] = `name: Falco
+slug: falco
+description: Cloud native runtime security
+security:
+  policy: https://falco.org/security
+maintainers:
+  - github: amy
+`
+	project := Project{SchemaVersion: SchemaVersion, Slug: "falco", Name: "Falco", Type: "project", Maintainers: []Maintainer{{GitHub: "zed"}}}
+
+	proposal, err := Preview(context.Background(), client, ref, project)
+	require.NoError(t, err)
+	assert.True(t, proposal.Changed)
+	assert.Equal(t, []string{"Add maintainer: zed", "Remove maintainer: amy"}, proposal.Summary)
+	merged, err := Parse(proposal.Content)
+	require.NoError(t, err)
+	assert.Equal(t, project.Maintainers, merged.Maintainers)
+	assert.Contains(t, string(proposal.Content), "description: Cloud native runtime security\n")
+	assert.Contains(t, string(proposal.Content), "security:\n  policy: https://falco.org/security\n")
+
+	// Once merged, the unmanaged fields do not count as a difference.
+	fake.files["main"][ref.Path] = string(proposal.Content)
+	proposal, err = Preview(context.Background(), client, ref, project)
+	require.NoError(t, err)
+	assert.False(t, proposal.Changed)
+}
@@ -0,0 +1,86 @@
+package emailverify
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidToken is returned for malformed tokens or tokens with a bad signature.
+	ErrInvalidToken = errors.New("invalid verification token")
+	// ErrExpiredToken is returned for well-formed tokens past their expiry.
+	ErrExpiredToken = errors.New("verification token expired")
+)
+
+// Claims identifies the maintainer and the exact address a token was issued for.
+type Claims struct {
+	MaintainerID uint      `json:"mid"`
+	Email        string    `json:"email"`
+	ExpiresAt    time.Time `json:"exp"`
+}
+
+// Signer issues and checks HMAC-SHA256 signed verification tokens.
+type Signer struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+// NewSigner returns a Signer using secret, issuing tokens valid for ttl.
+func NewSigner(secret []byte, ttl time.Duration) (*Signer, error) {
+	if len(secret) < 16 {
+		return nil, errors.New("verification secret must be at least 16 bytes")
+	}
+	if ttl <= 0 {
+		return nil, errors.New("verification token ttl must be positive")
+	}
+	return &Signer{secret: secret, ttl: ttl}, nil
+}
+
+// Issue returns a token binding maintainerID to email until now+ttl.
+func (s *Signer) Issue(maintainerID uint, email string, now time.Time) (string, error) {
+	payload, err := json.Marshal(Claims{
+		MaintainerID: maintainerID,
+		Email:        strings.ToLower(strings.TrimSpace(email)),
+		ExpiresAt:    now.Add(s.ttl).UTC().Truncate(time.Second),
+	})
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(s.sign(encoded)), nil
+}
+
+// Parse checks the signature and expiry of token and returns its claims.
+func (s *Signer) Parse(token string, now time.Time) (Claims, error) {
+	encoded, sig, ok := strings.Cut(token, ".")
+	if !ok || encoded == "" || sig == "" {
+		return Claims{}, ErrInvalidToken
+	}
+	gotSig, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(gotSig, s.sign(encoded)) {
+		return Claims{}, ErrInvalidToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.MaintainerID == 0 || claims.Email == "" {
+		return Claims{}, ErrInvalidToken
+	}
+	if !now.Before(claims.ExpiresAt) {
+		return Claims{}, ErrExpiredToken
+	}
+	return claims, nil
+}
+
+func (s *Signer) sign(encoded string) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(encoded))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,107 @@
+package emailverify
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"maintainerd/mailer"
+	"maintainerd/model"
+)
+
+func newTestSigner(t *testing.T) *Signer {
+	signer, err := NewSigner([]byte("0123456789abcdef0123456789abcdef"), time.Hour)
+	require.NoError(t, err)
+	return signer
+}
+
+func TestSignerRoundTrip(t *testing.T) {
+	signer := newTestSigner(t)
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	token, err := signer.Issue(42, " Alice@Example.com ", now)
+	require.NoError(t, err)
+
+	claims, err := signer.Parse(token, now.Add(30*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, uint(42), claims.MaintainerID)
+	assert.Equal(t, "alice@example.com", claims.Email)
+
+	_, err = signer.Parse(token, now.Add(2*time.Hour))
+	assert.ErrorIs(t, err, ErrExpiredToken)
+}
+
+func TestSignerRejectsTampering(t *testing.T) {
+	signer := newTestSigner(t)
+	now := time.Now()
+	token, err := signer.Issue(1, "alice@example.com", now)
+	require.NoError(t, err)
+
+	other, err := NewSigner([]byte("another-secret-another-secret!!"), time.Hour)
+	require.NoError(t, err)
+	_, err = other.Parse(token, now)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	forged, err := signer.Issue(2, "mallory@example.com", now)
+	require.NoError(t, err)
+	payload, _, _ := strings.Cut(forged, ".")
+	_, sig, _ := strings.Cut(token, ".")
+	_, err = signer.Parse(payload+"."+sig, now)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	for _, bad := range []string{"", ".", "abc", "abc.", ".abc", "!!.??"} {
+		_, err = signer.Parse(bad, now)
+		assert.ErrorIs(t, err, ErrInvalidToken, "token %q", bad)
+	}
+}
+
+func TestNewSignerValidation(t *testing.T) {
+	_, err := NewSigner([]byte("short"), time.Hour)
+	assert.Error(t, err)
+	_, err = NewSigner([]byte("0123456789abcdef"), 0)
+	assert.Error(t, err)
+}
+
+func TestVerifierSend(t *testing.T) {
+	dir := t.TempDir()
+	sender, err := mailer.NewFileSender(dir, "noreply@example.org")
+	require.NoError(t, err)
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	v := &Verifier{
+		Signer:  newTestSigner(t),
+		Sender:  sender,
+		BaseURL: "https://maintainerd.example.org/",
+		now:     func() time.Time { return now },
+	}
+
+	maintainer := model.Maintainer{Name: "Alice", Email: "alice@example.com"}
+	maintainer.ID = 7
+	require.NoError(t, v.Send(context.Background(), maintainer))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	raw, err := os.ReadFile(filepath.Join(dir, entries[0].Name()))
+	require.NoError(t, err)
+
+	link, err := v.Link(maintainer)
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), link)
+	assert.True(t, strings.HasPrefix(link, "https://maintainerd.example.org/auth/verify-email?token="))
+
+	parsed, err := url.Parse(link)
+	require.NoError(t, err)
+	claims, err := v.Signer.Parse(parsed.Query().Get("token"), now)
+	require.NoError(t, err)
+	assert.Equal(t, uint(7), claims.MaintainerID)
+
+	missing := model.Maintainer{Name: "Bill", Email: "EMAIL_MISSING"}
+	assert.Error(t, v.Send(context.Background(), missing))
+}
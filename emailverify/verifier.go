@@ -0,0 +1,102 @@
+package emailverify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"maintainerd/mailer"
+	"maintainerd/model"
+)
+
+const (
+	// VerifyPath is the web-bff route that consumes verification tokens.
+	VerifyPath = "/auth/verify-email"
+	defaultTTL = 72 * time.Hour
+)
+
+// Verifier emails maintainers a signed link proving they own their registered address.
+type Verifier struct {
+	Signer  *Signer
+	Sender  mailer.Sender
+	BaseURL string
+	now     func() time.Time
+}
+
+// NewVerifierFromEnv builds a Verifier from EMAIL_VERIFICATION_SECRET, EMAIL_VERIFICATION_BASE_URL
+// (the public web-bff URL) and EMAIL_VERIFICATION_TTL, with the mail sender chosen by mailer.NewFromEnv.
+// It returns nil, nil when no secret is configured so callers can treat verification mail as disabled, and an error
+// when a secret is configured without a sender.
+func NewVerifierFromEnv() (*Verifier, error) {
+	secret := strings.TrimSpace(os.Getenv("EMAIL_VERIFICATION_SECRET"))
+	if secret == "" {
+		return nil, nil
+	}
+	baseURL := strings.TrimSpace(os.Getenv("EMAIL_VERIFICATION_BASE_URL"))
+	if baseURL == "" {
+		return nil, errors.New("EMAIL_VERIFICATION_BASE_URL must be set when EMAIL_VERIFICATION_SECRET is set")
+	}
+	ttl := defaultTTL
+	if raw := strings.TrimSpace(os.Getenv("EMAIL_VERIFICATION_TTL")); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid EMAIL_VERIFICATION_TTL: %w", err)
+		}
+		ttl = parsed
+	}
+	signer, err := NewSigner([]byte(secret), ttl)
+	if err != nil {
+		return nil, err
+	}
+	sender, err := mailer.NewFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("EMAIL_VERIFICATION_SECRET is set: %w", err)
+	}
+	return &Verifier{Signer: signer, Sender: sender, BaseURL: baseURL}, nil
+}
+
+// Link returns the verification URL for maintainer's current email address.
+func (v *Verifier) Link(maintainer model.Maintainer) (string, error) {
+	token, err := v.Signer.Issue(maintainer.ID, maintainer.Email, v.clock())
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(v.BaseURL, "/") + VerifyPath + "?token=" + url.QueryEscape(token), nil
+}
+
+// Send emails the verification link to maintainer's registered address.
+func (v *Verifier) Send(ctx context.Context, maintainer model.Maintainer) error {
+	email := strings.TrimSpace(maintainer.Email)
+	if email == "" || email == "EMAIL_MISSING" {
+		return errors.New("maintainer has no email address")
+	}
+	link, err := v.Link(maintainer)
+	if err != nil {
+		return err
+	}
+	name := strings.TrimSpace(maintainer.Name)
+	if name == "" {
+		name = "there"
+	}
+	body := fmt.Sprintf("Hi %s,\n\n"+
+		"CNCF maintainer-d has %s registered as your maintainer email address.\n"+
+		"Before we use it to invite you to CNCF services, please confirm it is yours by opening:\n\n"+
+		"%s\n\n"+
+		"If you did not expect this message you can ignore it.\n", name, email, link)
+	return v.Sender.Send(ctx, mailer.Message{
+		To:      email,
+		Subject: "Confirm your CNCF maintainer email address",
+		Body:    body,
+	})
+}
+
+func (v *Verifier) clock() time.Time {
+	if v.now != nil {
+		return v.now()
+	}
+	return time.Now()
+}
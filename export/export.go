@@ -0,0 +1,376 @@
+// Package export produces full snapshots of maintainer-d data as JSON, per-table CSV or .project.yaml files, so other
+// tools can consume it without database access.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"maintainerd/db"
+	"maintainerd/dotproject"
+	"maintainerd/model"
+)
+
+// Table names accepted by WriteCSV.
+const (
+	TableProjects     = "projects"
+	TableMaintainers  = "maintainers"
+	TableCompanies    = "companies"
+	TableMemberships  = "memberships"
+	TableServiceTeams = "service-teams"
+)
+
+// Tables lists every table a snapshot can be written as CSV.
+var Tables = []string{TableProjects, TableMaintainers, TableCompanies, TableMemberships, TableServiceTeams}
+
+var ErrUnknownTable = errors.New("unknown export table")
+
+// Options controls what a snapshot includes.
+type Options struct {
+	// RedactEmails leaves email addresses out, for callers who may not see them.
+	RedactEmails bool
+}
+
+// Snapshot is every project, maintainer, company, membership and service team at one point in time.
+type Snapshot struct {
+	GeneratedAt    time.Time     `json:"generatedAt"`
+	EmailsRedacted bool          `json:"emailsRedacted"`
+	Projects       []Project     `json:"projects"`
+	Maintainers    []Maintainer  `json:"maintainers"`
+	Companies      []Company     `json:"companies"`
+	Memberships    []Membership  `json:"memberships"`
+	ServiceTeams   []ServiceTeam `json:"serviceTeams"`
+
+	sources []dotproject.Source
+}
+
+type Project struct {
+	ID              uint      `json:"id"`
+	Name            string    `json:"name"`
+	Slug            string    `json:"slug"`
+	Maturity        string    `json:"maturity,omitempty"`
+	ParentProjectID *uint     `json:"parentProjectId,omitempty"`
+	GitHubOrg       string    `json:"githubOrg,omitempty"`
+	MaintainerRef   string    `json:"maintainerRef,omitempty"`
+	DotProjectRef   string    `json:"dotProjectRef,omitempty"`
+	MailingList     string    `json:"mailingList,omitempty"`
+	OnboardingIssue string    `json:"onboardingIssue,omitempty"`
+	Aliases         []string  `json:"aliases,omitempty"`
+	CreatedAt       time.Time `json:"createdAt"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
+
+type Maintainer struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email,omitempty"`
+	GitHub    string    `json:"github,omitempty"`
+	Status    string    `json:"status"`
+	CompanyID *uint     `json:"companyId,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type Company struct {
+	ID      uint              `json:"id"`
+	Name    string            `json:"name"`
+	Website string            `json:"website,omitempty"`
+	Tags    map[string]string `json:"tags,omitempty"`
+	Domains []string          `json:"domains,omitempty"`
+	Aliases []string          `json:"aliases,omitempty"`
+}
+
+type Membership struct {
+	MaintainerID uint       `json:"maintainerId"`
+	ProjectID    uint       `json:"projectId"`
+	Status       string     `json:"status"`
+	Role         string     `json:"role"`
+	JoinedAt     time.Time  `json:"joinedAt"`
+	LeftAt       *time.Time `json:"leftAt,omitempty"`
+}
+
+type ServiceTeam struct {
+	ID                 uint       `json:"id"`
+	ProjectID          uint       `json:"projectId"`
+	Service            string     `json:"service"`
+	RemoteTeamID       int        `json:"remoteTeamId"`
+	Name               string     `json:"name,omitempty"`
+	CleanupRequestedAt *time.Time `json:"cleanupRequestedAt,omitempty"`
+}
+
+// Load reads a snapshot from store.
+func Load(store *db.SQLStore, opts Options) (*Snapshot, error) {
+	tx := store.DB()
+	snapshot := &Snapshot{GeneratedAt: time.Now().UTC(), EmailsRedacted: opts.RedactEmails}
+
+	var projects []model.Project
+	if err := tx.Order("name").Find(&projects).Error; err != nil {
+		return nil, fmt.Errorf("loading projects: %w", err)
+	}
+	var aliases []model.ProjectAlias
+	if err := tx.Order("name").Find(&aliases).Error; err != nil {
+		return nil, fmt.Errorf("loading project aliases: %w", err)
+	}
+	aliasesByProject := make(map[uint][]string)
+	for _, alias := range aliases {
+		aliasesByProject[alias.ProjectID] = append(aliasesByProject[alias.ProjectID], alias.Name)
+	}
+	var events []model.ProjectMaturityEvent
+	if err := tx.Order("project_id, effective_at, id").Find(&events).Error; err != nil {
+		return nil, fmt.Errorf("loading maturity events: %w", err)
+	}
+	eventsByProject := make(map[uint][]model.ProjectMaturityEvent)
+	for _, event := range events {
+		eventsByProject[event.ProjectID] = append(eventsByProject[event.ProjectID], event)
+	}
+
+	var maintainers []model.Maintainer
+	if err := tx.Preload("Company").Order("name, id").Find(&maintainers).Error; err != nil {
+		return nil, fmt.Errorf("loading maintainers: %w", err)
+	}
+	maintainerByID := make(map[uint]model.Maintainer, len(maintainers))
+	for _, maintainer := range maintainers {
+		maintainerByID[maintainer.ID] = maintainer
+		item := Maintainer{
+			ID:        maintainer.ID,
+			Name:      maintainer.Name,
+			GitHub:    present(maintainer.GitHubAccount),
+			Status:    string(maintainer.MaintainerStatus),
+			CompanyID: maintainer.CompanyID,
+			CreatedAt: maintainer.CreatedAt,
+			UpdatedAt: maintainer.UpdatedAt,
+		}
+		if !opts.RedactEmails {
+			item.Email = present(maintainer.Email)
+		}
+		snapshot.Maintainers = append(snapshot.Maintainers, item)
+	}
+
+	var companies []model.Company
+	if err := tx.Preload("Domains").Preload("Aliases").Order("name").Find(&companies).Error; err != nil {
+		return nil, fmt.Errorf("loading companies: %w", err)
+	}
+	for _, company := range companies {
+		item := Company{ID: company.ID, Name: company.Name, Website: company.Website, Tags: company.Tags}
+		for _, domain := range company.Domains {
+			item.Domains = append(item.Domains, domain.Domain)
+		}
+		for _, alias := range company.Aliases {
+			item.Aliases = append(item.Aliases, alias.Name)
+		}
+		sort.Strings(item.Domains)
+		sort.Strings(item.Aliases)
+		snapshot.Companies = append(snapshot.Companies, item)
+	}
+
+	var memberships []model.MaintainerProject
+	if err := tx.Order("project_id, maintainer_id").Find(&memberships).Error; err != nil {
+		return nil, fmt.Errorf("loading memberships: %w", err)
+	}
+	membershipsByProject := make(map[uint][]model.MaintainerProject)
+	for _, membership := range memberships {
+		maintainer, ok := maintainerByID[membership.MaintainerID]
+		if !ok {
+			// The maintainer was deleted or merged away.
+			continue
+		}
+		snapshot.Memberships = append(snapshot.Memberships, Membership{
+			MaintainerID: membership.MaintainerID,
+			ProjectID:    membership.ProjectID,
+			Status:       string(membership.Status),
+			Role:         string(membership.Role),
+			JoinedAt:     membership.JoinedAt,
+			LeftAt:       membership.LeftAt,
+		})
+		membership.Maintainer = maintainer
+		membershipsByProject[membership.ProjectID] = append(membershipsByProject[membership.ProjectID], membership)
+	}
+
+	var services []model.Service
+	if err := tx.Find(&services).Error; err != nil {
+		return nil, fmt.Errorf("loading services: %w", err)
+	}
+	serviceNames := make(map[uint]string, len(services))
+	for _, service := range services {
+		serviceNames[service.ID] = service.Name
+	}
+	var teams []model.ServiceTeam
+	if err := tx.Order("project_id, service_id, id").Find(&teams).Error; err != nil {
+		return nil, fmt.Errorf("loading service teams: %w", err)
+	}
+	for _, team := range teams {
+		item := ServiceTeam{
+			ID:                 team.ID,
+			ProjectID:          team.ProjectID,
+			Service:            serviceNames[team.ServiceID],
+			RemoteTeamID:       team.ServiceTeamID,
+			CleanupRequestedAt: team.CleanupRequestedAt,
+		}
+		if team.ServiceTeamName != nil {
+			item.Name = *team.ServiceTeamName
+		}
+		snapshot.ServiceTeams = append(snapshot.ServiceTeams, item)
+	}
+
+	projectByID := make(map[uint]model.Project, len(projects))
+	for _, project := range projects {
+		projectByID[project.ID] = project
+	}
+	for _, project := range projects {
+		item := Project{
+			ID:              project.ID,
+			Name:            project.Name,
+			Slug:            dotproject.Slug(project.Name),
+			Maturity:        string(project.Maturity),
+			ParentProjectID: project.ParentProjectID,
+			GitHubOrg:       project.GitHubOrg,
+			MaintainerRef:   strings.TrimSpace(project.LegacyMaintainerRef),
+			DotProjectRef:   project.DotProjectYamlRef,
+			Aliases:         aliasesByProject[project.ID],
+			CreatedAt:       project.CreatedAt,
+			UpdatedAt:       project.UpdatedAt,
+		}
+		if project.MailingList != nil {
+			item.MailingList = present(*project.MailingList)
+		}
+		if project.OnboardingIssue != nil {
+			item.OnboardingIssue = *project.OnboardingIssue
+		}
+		snapshot.Projects = append(snapshot.Projects, item)
+
+		source := dotproject.Source{
+			Project:        project,
+			Memberships:    membershipsByProject[project.ID],
+			MaturityEvents: eventsByProject[project.ID],
+			RedactEmails:   opts.RedactEmails,
+		}
+		if project.ParentProjectID != nil {
+			if parent, ok := projectByID[*project.ParentProjectID]; ok {
+				source.Parent = &parent
+			}
+		}
+		snapshot.sources = append(snapshot.sources, source)
+	}
+	return snapshot, nil
+}
+
+// WriteJSON writes the snapshot as one JSON document.
+func (s *Snapshot) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(s)
+}
+
+// WriteCSV writes one table of the snapshot as CSV with a header row. Free-text cells go through CSVCell.
+func (s *Snapshot) WriteCSV(w io.Writer, table string) error {
+	var header []string
+	var records [][]string
+	switch table {
+	case TableProjects:
+		header = []string{"id", "name", "slug", "maturity", "parent_project_id", "github_org", "maintainer_ref", "dot_project_ref", "mailing_list", "onboarding_issue", "aliases"}
+		for _, p := range s.Projects {
+			records = append(records, []string{formatID(p.ID), CSVCell(p.Name), CSVCell(p.Slug), p.Maturity, formatOptionalID(p.ParentProjectID), CSVCell(p.GitHubOrg), CSVCell(p.MaintainerRef), CSVCell(p.DotProjectRef), CSVCell(p.MailingList), CSVCell(p.OnboardingIssue), CSVCell(strings.Join(p.Aliases, ";"))})
+		}
+	case TableMaintainers:
+		header = []string{"id", "name", "email", "github", "status", "company_id"}
+		for _, m := range s.Maintainers {
+			records = append(records, []string{formatID(m.ID), CSVCell(m.Name), CSVCell(m.Email), CSVCell(m.GitHub), m.Status, formatOptionalID(m.CompanyID)})
+		}
+	case TableCompanies:
+		header = []string{"id", "name", "website", "domains", "aliases"}
+		for _, c := range s.Companies {
+			records = append(records, []string{formatID(c.ID), CSVCell(c.Name), CSVCell(c.Website), CSVCell(strings.Join(c.Domains, ";")), CSVCell(strings.Join(c.Aliases, ";"))})
+		}
+	case TableMemberships:
+		header = []string{"maintainer_id", "project_id", "status", "role", "joined_at", "left_at"}
+		for _, m := range s.Memberships {
+			records = append(records, []string{formatID(m.MaintainerID), formatID(m.ProjectID), m.Status, m.Role, formatTime(&m.JoinedAt), formatTime(m.LeftAt)})
+		}
+	case TableServiceTeams:
+		header = []string{"id", "project_id", "service", "remote_team_id", "name", "cleanup_requested_at"}
+		for _, t := range s.ServiceTeams {
+			records = append(records, []string{formatID(t.ID), formatID(t.ProjectID), CSVCell(t.Service), strconv.Itoa(t.RemoteTeamID), CSVCell(t.Name), formatTime(t.CleanupRequestedAt)})
+		}
+	default:
+		return fmt.Errorf("%w %q", ErrUnknownTable, table)
+	}
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	if err := writer.WriteAll(records); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// DotProjects returns the .project.yaml content of every project in the snapshot, ordered by project name.
+func (s *Snapshot) DotProjects() []dotproject.Project {
+	projects := make([]dotproject.Project, 0, len(s.sources))
+	for _, source := range s.sources {
+		projects = append(projects, dotproject.Build(source))
+	}
+	return projects
+}
+
+// WriteDotProjects writes the .project.yaml content of the projects as one YAML stream, a document per project.
+func WriteDotProjects(w io.Writer, projects []dotproject.Project) error {
+	for i, project := range projects {
+		body, err := dotproject.Render(project)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			if _, err := io.WriteString(w, "---\n"); err != nil {
+				return err
+			}
+		}
+		if _, err := w.Write(body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// present returns value, or "" for the placeholders recorded when a field is missing.
+func present(value string) string {
+	switch value {
+	case "EMAIL_MISSING", "GITHUB_MISSING", "MML_MISSING":
+		return ""
+	}
+	return value
+}
+
+// CSVCell quotes a value that a spreadsheet would otherwise read as a formula. Names and other free text come from
+// user input, so a cell such as "=HYPERLINK(...)" must open as text.
+func CSVCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func formatID(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+func formatOptionalID(id *uint) string {
+	if id == nil {
+		return ""
+	}
+	return formatID(*id)
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
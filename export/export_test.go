@@ -0,0 +1,131 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"maintainerd/db"
+	"maintainerd/dotproject"
+	"maintainerd/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupSnapshotDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	database, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, database.AutoMigrate(
+		&model.Company{},
+		&model.CompanyDomain{},
+		&model.CompanyAlias{},
+		&model.Project{},
+		&model.ProjectAlias{},
+		&model.ProjectMaturityEvent{},
+		&model.Maintainer{},
+		&model.MaintainerProject{},
+		&model.Service{},
+		&model.ServiceTeam{},
+	))
+
+	acme := model.Company{Name: "Acme", Domains: []model.CompanyDomain{{Domain: "acme.example"}}}
+	require.NoError(t, database.Create(&acme).Error)
+	list := "kube-dev@lists.cncf.io"
+	kubernetes := model.Project{Name: "Kubernetes", Maturity: model.Graduated, GitHubOrg: "kubernetes", MailingList: &list}
+	require.NoError(t, database.Create(&kubernetes).Error)
+	sigNode := model.Project{Name: "SIG Node", Maturity: model.Sandbox, ParentProjectID: &kubernetes.ID}
+	require.NoError(t, database.Create(&sigNode).Error)
+	require.NoError(t, database.Create(&model.ProjectAlias{ProjectID: kubernetes.ID, Name: "k8s"}).Error)
+	vote := "https://github.com/cncf/toc/issues/42"
+	require.NoError(t, database.Create(&model.ProjectMaturityEvent{
+		ProjectID: kubernetes.ID, ToMaturity: model.Graduated,
+		EffectiveAt: time.Date(2018, 3, 6, 0, 0, 0, 0, time.UTC), VoteIssueURL: &vote,
+	}).Error)
+
+	alice := model.Maintainer{Name: "Alice", Email: "alice@acme.example", GitHubAccount: "alice", MaintainerStatus: model.ActiveMaintainer, CompanyID: &acme.ID}
+	bob := model.Maintainer{Name: "Bob", Email: "EMAIL_MISSING", GitHubAccount: "bob", MaintainerStatus: model.EmeritusMaintainer}
+	require.NoError(t, database.Create(&alice).Error)
+	require.NoError(t, database.Create(&bob).Error)
+	require.NoError(t, database.Create(&model.MaintainerProject{MaintainerID: alice.ID, ProjectID: kubernetes.ID, Role: model.LeadRole}).Error)
+	require.NoError(t, database.Create(&model.MaintainerProject{MaintainerID: bob.ID, ProjectID: kubernetes.ID, Status: model.EmeritusMaintainer}).Error)
+	require.NoError(t, database.Create(&model.MaintainerProject{MaintainerID: bob.ID, ProjectID: sigNode.ID}).Error)
+
+	fossa := model.Service{Name: "FOSSA"}
+	require.NoError(t, database.Create(&fossa).Error)
+	teamName := "kubernetes"
+	require.NoError(t, database.Create(&model.ServiceTeam{ProjectID: kubernetes.ID, ServiceID: fossa.ID, ServiceTeamID: 7, ServiceTeamName: &teamName}).Error)
+	return database
+}
+
+func TestLoadSnapshot(t *testing.T) {
+	store := db.NewSQLStore(setupSnapshotDB(t))
+
+	snapshot, err := Load(store, Options{})
+	require.NoError(t, err)
+	require.Len(t, snapshot.Projects, 2)
+	assert.Equal(t, []string{"k8s"}, snapshot.Projects[0].Aliases)
+	assert.Equal(t, "sig-node", snapshot.Projects[1].Slug)
+	require.Len(t, snapshot.Maintainers, 2)
+	assert.Equal(t, "alice@acme.example", snapshot.Maintainers[0].Email)
+	assert.Empty(t, snapshot.Maintainers[1].Email, "placeholders are not exported")
+	assert.Equal(t, []string{"acme.example"}, snapshot.Companies[0].Domains)
+	assert.Len(t, snapshot.Memberships, 3)
+	require.Len(t, snapshot.ServiceTeams, 1)
+	assert.Equal(t, "FOSSA", snapshot.ServiceTeams[0].Service)
+
+	var buf bytes.Buffer
+	require.NoError(t, snapshot.WriteCSV(&buf, TableMemberships))
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 4)
+	assert.Equal(t, []string{"maintainer_id", "project_id", "status", "role", "joined_at", "left_at"}, records[0])
+	assert.ErrorIs(t, snapshot.WriteCSV(&buf, "staff"), ErrUnknownTable)
+
+	dotProjects := snapshot.DotProjects()
+	require.Len(t, dotProjects, 2)
+	assert.Equal(t, "subproject", dotProjects[1].Type)
+	assert.Equal(t, "kubernetes", dotProjects[1].Parent)
+	assert.Equal(t, []dotproject.Maintainer{{GitHub: "alice", Name: "Alice", Email: "alice@acme.example", Company: "Acme", Role: "lead"}}, dotProjects[0].Maintainers)
+	assert.Equal(t, []dotproject.Maintainer{{GitHub: "bob", Name: "Bob"}}, dotProjects[0].Emeritus)
+	assert.Equal(t, "https://github.com/cncf/toc/issues/42", dotProjects[0].MaturityLog[0].Issue)
+}
+
+func TestLoadSnapshotRedactsEmails(t *testing.T) {
+	store := db.NewSQLStore(setupSnapshotDB(t))
+
+	snapshot, err := Load(store, Options{RedactEmails: true})
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, snapshot.WriteJSON(&buf))
+	assert.NotContains(t, buf.String(), "@acme.example")
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, true, decoded["emailsRedacted"])
+
+	buf.Reset()
+	require.NoError(t, WriteDotProjects(&buf, snapshot.DotProjects()))
+	assert.NotContains(t, buf.String(), "@acme.example")
+	assert.Equal(t, 1, strings.Count(buf.String(), "\n---\n"))
+}
+
+func TestWriteCSVEscapesFormulas(t *testing.T) {
+	snapshot := &Snapshot{Maintainers: []Maintainer{{ID: 1, Name: "=HYPERLINK(\"https://evil.example\")", GitHub: "@mallory", Status: "Active"}}}
+
+	var buf bytes.Buffer
+	require.NoError(t, snapshot.WriteCSV(&buf, TableMaintainers))
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, []string{"1", `'=HYPERLINK("https://evil.example")`, "", "'@mallory", "Active", ""}, records[1])
+	assert.Equal(t, "'-1", CSVCell("-1"))
+	assert.Equal(t, "'\tx", CSVCell("\tx"))
+	assert.Equal(t, "Acme", CSVCell("Acme"))
+}
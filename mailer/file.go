@@ -0,0 +1,52 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileSender drops each message into Dir as an .eml file. It is meant for local development and tests.
+type FileSender struct {
+	dir  string
+	from string
+	mu   sync.Mutex
+	seq  int
+}
+
+// NewFileSender creates dir if needed and returns a sender writing into it.
+func NewFileSender(dir, from string) (*FileSender, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("create mail drop dir: %w", err)
+	}
+	return &FileSender{dir: dir, from: from}, nil
+}
+
+// Send writes msg to a new file named after the send time and recipient.
+func (s *FileSender) Send(_ context.Context, msg Message) error {
+	if err := validate(msg); err != nil {
+		return err
+	}
+	now := time.Now()
+	s.mu.Lock()
+	s.seq++
+	seq := s.seq
+	s.mu.Unlock()
+	name := fmt.Sprintf("%s-%04d-%s.eml", now.UTC().Format("20060102T150405.000000000"), seq, safeFileComponent(msg.To))
+	return os.WriteFile(filepath.Join(s.dir, name), render(s.from, msg, now), 0o600)
+}
+
+func safeFileComponent(value string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		default:
+			return '_'
+		}
+	}, value)
+}
@@ -0,0 +1,87 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// Message is a plain-text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender delivers messages. Implementations must be safe for concurrent use.
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// ErrNoSender is returned by NewFromEnv when no way to send mail is configured.
+var ErrNoSender = errors.New("no mail sender configured: set MAIL_SMTP_HOST, or MAIL_DROP_DIR for local development")
+
+// NewFromEnv picks a Sender from the environment:
+//   - MAIL_SMTP_HOST set: SMTPSender (MAIL_SMTP_PORT, MAIL_SMTP_USERNAME, MAIL_SMTP_PASSWORD, MAIL_FROM)
+//   - MAIL_DROP_DIR set: FileSender writing one .eml file per message
+//   - otherwise: ErrNoSender. Messages carry sign-in and verification links, so they are never just logged.
+func NewFromEnv() (Sender, error) {
+	from := strings.TrimSpace(os.Getenv("MAIL_FROM"))
+	if from == "" {
+		from = "maintainerd@cncf.io"
+	}
+	if host := strings.TrimSpace(os.Getenv("MAIL_SMTP_HOST")); host != "" {
+		port := strings.TrimSpace(os.Getenv("MAIL_SMTP_PORT"))
+		if port == "" {
+			port = "587"
+		}
+		return NewSMTPSender(
+			net.JoinHostPort(host, port),
+			from,
+			os.Getenv("MAIL_SMTP_USERNAME"),
+			os.Getenv("MAIL_SMTP_PASSWORD"),
+		), nil
+	}
+	if dir := strings.TrimSpace(os.Getenv("MAIL_DROP_DIR")); dir != "" {
+		return NewFileSender(dir, from)
+	}
+	return nil, ErrNoSender
+}
+
+// Delivers reports whether sender hands messages to a mail server, as opposed to keeping them locally like
+// FileSender.
+func Delivers(sender Sender) bool {
+	_, local := sender.(*FileSender)
+	return !local
+}
+
+// render formats msg as an RFC 5322 message with the given sender.
+func render(from string, msg Message, now time.Time) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", sanitizeHeader(msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", now.UTC().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return buf.Bytes()
+}
+
+func sanitizeHeader(value string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(value)
+}
+
+func validate(msg Message) error {
+	to := strings.TrimSpace(msg.To)
+	if to == "" || !strings.Contains(to, "@") || strings.ContainsAny(to, "\r\n") {
+		return fmt.Errorf("invalid recipient %q", msg.To)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package mailer
+
+import (
+	"context"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileSender(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	sender, err := NewFileSender(dir, "noreply@example.org")
+	require.NoError(t, err)
+
+	require.NoError(t, sender.Send(context.Background(), Message{
+		To:      "alice@example.com",
+		Subject: "Verify\r\nBcc: evil@example.com",
+		Body:    "line one\nline two",
+	}))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.True(t, strings.HasSuffix(entries[0].Name(), "alice_example.com.eml"))
+
+	raw, err := os.ReadFile(filepath.Join(dir, entries[0].Name()))
+	require.NoError(t, err)
+	content := string(raw)
+	assert.Contains(t, content, "From: noreply@example.org\r\n")
+	assert.Contains(t, content, "To: alice@example.com\r\n")
+	assert.Contains(t, content, "Subject: Verify  Bcc: evil@example.com\r\n")
+	assert.NotContains(t, content, "\r\nBcc:")
+	assert.Contains(t, content, "line one\r\nline two")
+}
+
+func TestSendersRejectInvalidRecipients(t *testing.T) {
+	sender, err := NewFileSender(t.TempDir(), "noreply@example.org")
+	require.NoError(t, err)
+	for _, to := range []string{"", "not-an-email", "a@example.com\r\nBcc: b@example.com"} {
+		assert.Error(t, sender.Send(context.Background(), Message{To: to}), "recipient %q", to)
+	}
+}
+
+func TestNewFromEnv(t *testing.T) {
+	t.Setenv("MAIL_SMTP_HOST", "")
+	t.Setenv("MAIL_DROP_DIR", "")
+	_, err := NewFromEnv()
+	assert.ErrorIs(t, err, ErrNoSender, "mail is never silently logged")
+
+	t.Setenv("MAIL_DROP_DIR", t.TempDir())
+	sender, err := NewFromEnv()
+	require.NoError(t, err)
+	assert.False(t, Delivers(sender))
+
+	t.Setenv("MAIL_SMTP_HOST", "smtp.example.org")
+	sender, err = NewFromEnv()
+	require.NoError(t, err)
+	assert.True(t, Delivers(sender))
+}
+
+func TestSMTPSender(t *testing.T) {
+	sender := NewSMTPSender("smtp.example.org:587", "noreply@example.org", "user", "secret")
+	var gotAddr, gotFrom string
+	var gotTo []string
+	var gotMsg []byte
+	sender.send = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr, gotFrom, gotTo, gotMsg = addr, from, to, msg
+		assert.NotNil(t, a)
+		return nil
+	}
+
+	require.NoError(t, sender.Send(context.Background(), Message{To: "carol@example.com", Subject: "s", Body: "b"}))
+	assert.Equal(t, "smtp.example.org:587", gotAddr)
+	assert.Equal(t, "noreply@example.org", gotFrom)
+	assert.Equal(t, []string{"carol@example.com"}, gotTo)
+	assert.Contains(t, string(gotMsg), "To: carol@example.com\r\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Error(t, sender.Send(ctx, Message{To: "carol@example.com"}))
+}
@@ -0,0 +1,40 @@
+package mailer
+
+import (
+	"context"
+	"net"
+	"net/smtp"
+	"time"
+)
+
+// SMTPSender delivers messages through an SMTP relay using STARTTLS when offered.
+type SMTPSender struct {
+	addr string
+	from string
+	auth smtp.Auth
+	send func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewSMTPSender returns a sender for the relay at addr (host:port). Username may be empty for unauthenticated relays.
+func NewSMTPSender(addr, from, username, password string) *SMTPSender {
+	var auth smtp.Auth
+	if username != "" {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			host = addr
+		}
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &SMTPSender{addr: addr, from: from, auth: auth, send: smtp.SendMail}
+}
+
+// Send delivers msg. smtp.SendMail has no context support, so ctx is only checked before dialing.
+func (s *SMTPSender) Send(ctx context.Context, msg Message) error {
+	if err := validate(msg); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.send(s.addr, s.auth, s.from, []string{msg.To}, render(s.from, msg, time.Now()))
+}
@@ -94,8 +94,19 @@ type Maintainer struct {
 	ImportWarnings   string
 	Projects         []Project `gorm:"many2many:maintainer_projects;joinForeignKey:MaintainerID;joinReferences:ProjectID"`
 	RegisteredAt     *time.Time
-	CompanyID        *uint
-	Company          Company
+	// GitHubID is the immutable numeric GitHub user ID, recorded on first login. Handles can be renamed and reused;
+	// the ID cannot.
+	GitHubID *int64 `gorm:"uniqueIndex"`
+	// EmailVerifiedAt is set when the maintainer confirms ownership of Email; it is cleared whenever Email changes.
+	EmailVerifiedAt *time.Time
+	// EmailVerificationSentAt records when a verification link was last emailed, so automatic resends are
+	// rate-limited.
+	EmailVerificationSentAt *time.Time
+	CompanyID               *uint
+	Company                 Company
+	// MergedIntoID is set on a soft-deleted maintainer that was merged into another, so imports that still name the
+	// duplicate resolve to the surviving record.
+	MergedIntoID *uint `gorm:"index"`
 }
 
 // MaintainerRefCache stores fetch metadata for a project's maintainer reference file.
@@ -108,15 +119,26 @@ type MaintainerRefCache struct {
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
 }
+
+// A Collaborator is a contributor with access to project services, such as a FOSSA team, who is not a maintainer.
 type Collaborator struct {
 	gorm.Model
 	Name          string
 	Email         string    `gorm:"size:254;default:EMAIL_MISSING"`
 	GitHubEmail   *string   `gorm:"size:254;default:GITHUB_EMAIL_MISSING"`
 	GitHubAccount *string   `gorm:"size:100;default:GITHUB_MISSING"`
-	Projects      []Project `gorm:"many2many:maintainer_projects;joinForeignKey:MaintainerID;joinReferences:ProjectID"`
+	Projects      []Project `gorm:"many2many:collaborator_projects;joinForeignKey:CollaboratorID;joinReferences:ProjectID"`
 	LastLogin     time.Time
 	RegisteredAt  time.Time
+	// PromotedToID is set on a soft-deleted collaborator who was promoted to maintainer.
+	PromotedToID *uint `gorm:"index"`
+}
+
+// CollaboratorProject links a collaborator to a project they contribute to.
+type CollaboratorProject struct {
+	CollaboratorID uint `gorm:"primaryKey;index"`
+	ProjectID      uint `gorm:"primaryKey;index"`
+	CreatedAt      time.Time
 }
 type Project struct {
 	gorm.Model
@@ -132,17 +154,112 @@ type Project struct {
 	Services            []Service    `gorm:"many2many:service_projects;joinForeignKey:ProjectID;joinReferences:ServiceID"`
 }
 
+// A ProjectMaturityEvent records a project moving between maturity levels, such as its graduation or archival.
+type ProjectMaturityEvent struct {
+	ID        uint `gorm:"primaryKey"`
+	ProjectID uint `gorm:"index"`
+	// FromMaturity is nil when the project had no maturity recorded.
+	FromMaturity *Maturity
+	ToMaturity   Maturity
+	// EffectiveAt is when the move took effect, such as the day the TOC vote passed.
+	EffectiveAt time.Time
+	// VoteIssueURL links the TOC vote issue, when there was one.
+	VoteIssueURL *string `gorm:"size:512"`
+	ActorLogin   string
+	CreatedAt    time.Time
+}
+
+// A ProjectAlias is a former name of a Project. Renaming a project keeps its old name as an alias so that lookups
+// by name, such as from onboarding issue titles, still find it.
+type ProjectAlias struct {
+	ID        uint   `gorm:"primaryKey"`
+	ProjectID uint   `gorm:"index"`
+	Name      string `gorm:"size:255;uniqueIndex"`
+	CreatedAt time.Time
+}
+
+type MembershipRole string
+
+const (
+	MaintainerRole      MembershipRole = "maintainer"
+	LeadRole            MembershipRole = "lead"
+	ReviewerRole        MembershipRole = "reviewer"
+	SecurityContactRole MembershipRole = "security-contact"
+)
+
+// IsValid returns true if MembershipRole is known
+func (r MembershipRole) IsValid() bool {
+	switch r {
+	case MaintainerRole, LeadRole, ReviewerRole, SecurityContactRole:
+		return true
+	}
+	return false
+}
+
+// MaintainerProject is a maintainer's membership of a project. Status is per project, so a maintainer can be Active
+// on one project and Emeritus on another; Maintainer.MaintainerStatus is their overall status.
 type MaintainerProject struct {
-	MaintainerID uint       `gorm:"primaryKey;index"` // FK + index
-	ProjectID    uint       `gorm:"primaryKey;index"` // FK + index
-	JoinedAt     time.Time  `gorm:"autoCreateTime"`
-	Maintainer   Maintainer `gorm:"foreignKey:MaintainerID;constraint:OnDelete:CASCADE"`
-	Project      Project    `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE"`
+	MaintainerID uint             `gorm:"primaryKey;index"` // FK + index
+	ProjectID    uint             `gorm:"primaryKey;index"` // FK + index
+	JoinedAt     time.Time        `gorm:"autoCreateTime"`
+	Status       MaintainerStatus `gorm:"type:text;default:Active"`
+	Role         MembershipRole   `gorm:"type:text;default:maintainer"`
+	// LeftAt is set when the membership stops being Active and cleared if it becomes Active again.
+	LeftAt     *time.Time
+	Maintainer Maintainer `gorm:"foreignKey:MaintainerID;constraint:OnDelete:CASCADE"`
+	Project    Project    `gorm:"foreignKey:ProjectID;constraint:OnDelete:CASCADE"`
+}
+
+// MembershipHistory records a change to a MaintainerProject: joining, a status or role change, or leaving.
+type MembershipHistory struct {
+	ID           uint      `gorm:"primaryKey"`
+	MaintainerID uint      `gorm:"index:idx_membership_history_member"`
+	ProjectID    uint      `gorm:"index:idx_membership_history_member"`
+	Event        string    `gorm:"size:32"` // JOIN, STATUS_CHANGE, ROLE_CHANGE, LEAVE
+	FromStatus   string    `gorm:"size:32"`
+	ToStatus     string    `gorm:"size:32"`
+	FromRole     string    `gorm:"size:32"`
+	ToRole       string    `gorm:"size:32"`
+	ActorLogin   string    `gorm:"size:100"`
+	Source       string    `gorm:"size:32"` // where the change came from, e.g. MembershipSourceSheet; empty if unknown
+	CreatedAt    time.Time `gorm:"index"`
 }
 
+// MembershipSourceSheet marks membership history written from the legacy maintainer worksheet. A re-import only
+// retires memberships the sheet added.
+const MembershipSourceSheet = "sheet"
+
 type Company struct {
 	gorm.Model
-	Name string `gorm:"uniqueIndex"`
+	Name    string `gorm:"uniqueIndex"`
+	Website string `gorm:"size:2048"`
+	Notes   string
+	// Tags are free-form labels, mirrored into the Company CRD.
+	Tags    map[string]string `gorm:"serializer:json"`
+	Domains []CompanyDomain
+	Aliases []CompanyAlias
+}
+
+// A CompanyAlias is another name a Company is known by, such as the name of a
+// company merged into it. Imports resolve aliases before creating companies so
+// merged duplicates are not recreated.
+type CompanyAlias struct {
+	ID        uint   `gorm:"primaryKey"`
+	CompanyID uint   `gorm:"index"`
+	Name      string `gorm:"size:255"`
+	// NormalizedName is the lower-cased, whitespace-collapsed Name used for matching.
+	NormalizedName string `gorm:"size:255;uniqueIndex"`
+	CreatedAt      time.Time
+}
+
+// A CompanyDomain is an email domain owned by a Company. Maintainers with an
+// address at the domain, or at one of its subdomains, are inferred to work for
+// that company. A domain belongs to at most one company.
+type CompanyDomain struct {
+	ID        uint   `gorm:"primaryKey"`
+	CompanyID uint   `gorm:"index"`
+	Domain    string `gorm:"size:253;uniqueIndex"`
+	CreatedAt time.Time
 }
 
 // A Foundation represents an organization that employs Staff members working with
@@ -160,7 +277,13 @@ type StaffMember struct {
 	Email         string `gorm:"size:254;default:EMAIL_MISSING"`
 	GitHubAccount string `gorm:"size:100;default:GITHUB_MISSING"`
 	GitHubEmail   string `gorm:"size:254;default:GITHUB_EMAIL_MISSING"`
+	GitHubID      *int64 `gorm:"uniqueIndex"`
 	RegisteredAt  *time.Time
+	// Admin staff members can add, change and deactivate other staff members.
+	Admin bool `gorm:"not null;default:false"`
+	// DeactivatedAt is set when the member's staff access was revoked. The row is kept so audit entries they wrote
+	// still name them.
+	DeactivatedAt *time.Time `gorm:"index"`
 
 	FoundationID *uint `gorm:"index"`
 	Foundation   Foundation
@@ -169,6 +292,9 @@ type Service struct {
 	gorm.Model
 	Name        string `gorm:"uniqueIndex"`
 	Description string
+	// ActingOfficerID is the FoundationOfficer whose identity on this service performs automated actions, such as
+	// onboarding maintainers to FOSSA teams. The officer must have a ServiceUser linked for the service.
+	ActingOfficerID *uint `gorm:"index"`
 }
 
 type ServiceUserTeams struct {
@@ -191,6 +317,8 @@ type ServiceTeam struct {
 	ServiceTeamID   int  // ID on the remote service (e.g., FOSSA team ID)
 	ServiceTeamName *string
 	ProjectName     *string // De-normalised for debugging purposes
+	// CleanupRequestedAt is set when the project is archived, flagging the remote team for removal.
+	CleanupRequestedAt *time.Time `gorm:"index"`
 }
 
 type ServiceUser struct {
@@ -242,6 +370,16 @@ type AuditLog struct {
 	Action       string       `gorm:"index"` // e.g. "ADD_MEMBER", "REMOVE_MEMBER", "INVITE_SENT"
 	Message      string       // human-readable message, optional
 	Metadata     string       // optional JSON blob for advanced inspection
+	// PrevHash is the Hash of the preceding entry ("" for the first chained entry, NULL for entries written before
+	// chaining). The unique index stops two writers from forking the chain.
+	PrevHash *string `gorm:"size:64;uniqueIndex"`
+	// Hash is the SHA-256 of this entry's contents and PrevHash; see db.AuditEntryHash.
+	Hash string `gorm:"size:64;index"`
+	// RevertOfID is set on AUDIT_REVERT entries to the entry that was undone.
+	RevertOfID *uint `gorm:"index"`
+	// BatchID copies the batch ID from Metadata so the entries of one bulk operation can be found by index. It is
+	// derived from Metadata, which the hash already covers.
+	BatchID *string `gorm:"size:32;index"`
 }
 
 type OnboardingTask struct {
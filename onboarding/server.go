@@ -16,9 +16,10 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/google/go-github/v55/github"
-	"go.uber.org/zap"
 
 	"maintainerd/db"
+	"maintainerd/emailverify"
+	"maintainerd/mailer"
 	"maintainerd/plugins/fossa"
 )
 
@@ -31,6 +32,8 @@ type EventListener struct {
 	Projects     map[string]model.Project
 	Repo         sourcerepo.Repo
 	GitHubClient *github.Client
+	// EmailVerifier, when set, emails verification links to maintainers whose address is not yet verified.
+	EmailVerifier *emailverify.Verifier
 }
 
 func (s *EventListener) Init(dbDriver, dbDSN, fossaAPItokenEnvVar, ghToken, org, repo string) error {
@@ -67,6 +70,15 @@ func (s *EventListener) Init(dbDriver, dbDSN, fossaAPItokenEnvVar, ghToken, org,
 	tc := oauth2.NewClient(context.Background(), ts)
 	s.GitHubClient = github.NewClient(tc)
 
+	verifier, err := emailverify.NewVerifierFromEnv()
+	if err != nil {
+		return fmt.Errorf("configure email verification: %w", err)
+	}
+	if verifier == nil {
+		log.Printf("Init: WRN, EMAIL_VERIFICATION_SECRET not set; unverified maintainers will not be sent verification links")
+	}
+	s.EmailVerifier = verifier
+
 	log.Printf("info: EventListener initialized successfully for org %q and repo %q", org, repo)
 	return nil
 }
@@ -396,7 +408,7 @@ func (s *EventListener) signProjectUpForFOSSA(project model.Project) ([]string,
 		return actions, fmt.Errorf("signProjectUpForFOSSA: maintainers not found in db for project %s (ID: %d)", project.Name, project.ID)
 	}
 
-	eligibleMaintainers, skippedMaintainers := filterEligibleMaintainers(maintainers)
+	eligibleMaintainers, skippedMaintainers, unverifiedMaintainers := filterEligibleMaintainers(maintainers)
 	actions = append(actions, fmt.Sprintf("✅  %s has %d maintainers registered in maintainer-d", project.Name, len(eligibleMaintainers)))
 	if len(skippedMaintainers) > 0 {
 		var missing []string
@@ -422,6 +434,9 @@ func (s *EventListener) signProjectUpForFOSSA(project model.Project) ([]string,
 		}
 		actions = append(actions, fmt.Sprintf("⚠️ Maintainers missing email or GitHub handle: %s", strings.Join(missing, " ")))
 	}
+	if len(unverifiedMaintainers) > 0 {
+		actions = append(actions, s.reportUnverifiedMaintainers(unverifiedMaintainers))
+	}
 
 	// Do we have a team already in FOSSA for @project?
 	serviceTeams, err := s.Store.GetProjectServiceTeamMap("FOSSA")
@@ -451,9 +466,18 @@ func (s *EventListener) signProjectUpForFOSSA(project model.Project) ([]string,
 		if err != nil {
 			log.Printf("handleWebhook: WRN, failed to create service team: %v", err)
 		}
+		s.logFossaAction(model.AuditLog{
+			ProjectID: &project.ID,
+			Action:    "FOSSA_CREATE_TEAM",
+			Message:   fmt.Sprintf("Created FOSSA team %s", team.Name),
+		})
 		st = &model.ServiceTeam{ServiceTeamID: team.ID}
 	}
 	if len(eligibleMaintainers) == 0 {
+		if len(unverifiedMaintainers) > 0 {
+			actions = append(actions, fmt.Sprintf("No maintainers of %s have verified their email address yet", project.Name))
+			return actions, fmt.Errorf(":x: no verified maintainer emails for project %d", project.ID)
+		}
 		actions = append(actions, fmt.Sprintf("Maintainers not yet registered, for project %s", project.Name))
 		return actions, fmt.Errorf(":x: no maintainers found for project %d", project.ID)
 	}
@@ -475,6 +499,12 @@ func (s *EventListener) signProjectUpForFOSSA(project model.Project) ([]string,
 			actions = append(actions, fmt.Sprintf("@%s there was a problem sending you a CNCF FOSSA invitation. A CNCF Staff member will contact you.", maintainer.GitHubAccount))
 		} else {
 			invitedMaintainers = append(invitedMaintainers, maintainer.GitHubAccount) // invited just now
+			s.logFossaAction(model.AuditLog{
+				ProjectID:    &project.ID,
+				MaintainerID: &maintainer.ID,
+				Action:       "FOSSA_INVITE_SENT",
+				Message:      fmt.Sprintf("Sent a FOSSA invitation to @%s", maintainer.GitHubAccount),
+			})
 		}
 	}
 
@@ -537,7 +567,7 @@ func (s *EventListener) addProjectMaintainersToFossaTeam(project model.Project,
 	if err != nil {
 		return nil, fmt.Errorf("GetMaintainersByProject: %w", err)
 	}
-	eligibleMaintainers, _ := filterEligibleMaintainers(maintainers)
+	eligibleMaintainers, _, _ := filterEligibleMaintainers(maintainers)
 	if len(eligibleMaintainers) == 0 {
 		actions = append(actions, "No registered maintainers found for this project")
 		return actions, nil
@@ -581,19 +611,12 @@ func (s *EventListener) addProjectMaintainersToFossaTeam(project model.Project,
 			continue
 		}
 		actions = append(actions, fmt.Sprintf("@%s: added to FOSSA team %s as Team Admin", handle, project.Name))
-		// Write audit log (best-effort)
-		// NOTE: ServiceID is optional; we omit or could set to FOSSA ID if available.
-		if s.Store != nil {
-			lg := zapNewNopSugar()
-			if err := s.Store.LogAuditEvent(lg, model.AuditLog{
-				ProjectID:    &project.ID,
-				MaintainerID: &m.ID,
-				Action:       "FOSSA_ADD_MEMBER",
-				Message:      fmt.Sprintf("Added @%s to FOSSA team %s", handle, project.Name),
-			}); err != nil {
-				log.Printf("addProjectMaintainersToFossaTeam: audit log error: %v", err)
-			}
-		}
+		s.logFossaAction(model.AuditLog{
+			ProjectID:    &project.ID,
+			MaintainerID: &m.ID,
+			Action:       "FOSSA_ADD_MEMBER",
+			Message:      fmt.Sprintf("Added @%s to FOSSA team %s", handle, project.Name),
+		})
 		// Update local cache of existing emails to avoid re-adding in this run
 		existingEmails = append(existingEmails, email)
 	}
@@ -610,9 +633,12 @@ func containsEmail(list []string, target string) bool {
 	return false
 }
 
-func filterEligibleMaintainers(maintainers []model.Maintainer) ([]model.Maintainer, []model.Maintainer) {
+// filterEligibleMaintainers splits maintainers into those we can invite to services, those missing an email or
+// GitHub handle, and those whose email address has not been verified yet.
+func filterEligibleMaintainers(maintainers []model.Maintainer) ([]model.Maintainer, []model.Maintainer, []model.Maintainer) {
 	var eligible []model.Maintainer
 	var skipped []model.Maintainer
+	var unverified []model.Maintainer
 	for _, m := range maintainers {
 		email := strings.TrimSpace(m.Email)
 		handle := strings.TrimSpace(m.GitHubAccount)
@@ -620,18 +646,74 @@ func filterEligibleMaintainers(maintainers []model.Maintainer) ([]model.Maintain
 			skipped = append(skipped, m)
 			continue
 		}
+		if m.EmailVerifiedAt == nil {
+			unverified = append(unverified, m)
+			continue
+		}
 		eligible = append(eligible, m)
 	}
-	return eligible, skipped
+	return eligible, skipped, unverified
+}
+
+// verificationResendInterval is how long onboarding waits before emailing a maintainer another verification link.
+const verificationResendInterval = 24 * time.Hour
+
+// reportUnverifiedMaintainers sends verification links (when configured) and returns the report line listing the
+// maintainers skipped because their email address is unverified. Only GitHub handles are reported. A maintainer
+// who was sent a link within verificationResendInterval is not emailed again.
+func (s *EventListener) reportUnverifiedMaintainers(unverified []model.Maintainer) string {
+	var handles []string
+	var notSent []string
+	sent := 0
+	for _, m := range unverified {
+		handle := strings.TrimSpace(m.GitHubAccount)
+		handles = append(handles, handle)
+		if s.EmailVerifier == nil {
+			continue
+		}
+		now := time.Now()
+		if m.EmailVerificationSentAt != nil && now.Sub(*m.EmailVerificationSentAt) < verificationResendInterval {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		err := s.EmailVerifier.Send(ctx, m)
+		cancel()
+		if err != nil {
+			log.Printf("reportUnverifiedMaintainers: WRN, failed to send verification email to @%s: %v", handle, err)
+			notSent = append(notSent, handle)
+			continue
+		}
+		sent++
+		if s.Store == nil {
+			continue
+		}
+		if err := s.Store.MarkEmailVerificationSent(m.ID, now); err != nil {
+			log.Printf("reportUnverifiedMaintainers: WRN, failed to record verification email to @%s: %v", handle, err)
+		}
+	}
+	line := fmt.Sprintf("📧 Maintainers skipped until they verify their email address: %s", formatHandles(handles))
+	switch {
+	case s.EmailVerifier == nil:
+		line += " (a CNCF Staff member will send verification links)"
+	case len(notSent) > 0:
+		line += fmt.Sprintf(" (verification links could not be emailed to %s; a CNCF Staff member will follow up)", formatHandles(notSent))
+	case sent == 0:
+		line += " (verification links were emailed recently)"
+	case !mailer.Delivers(s.EmailVerifier.Sender):
+		line += " (verification links were kept in the local mail drop; a CNCF Staff member will follow up)"
+	default:
+		line += " (verification links have been emailed)"
+	}
+	return line
 }
 
-// zapNewNopSugar returns a no-op SugaredLogger.
-func zapNewNopSugar() *zap.SugaredLogger {
-	log.Printf("zapNewNopSugar: called")
-	// Inline minimal no-op sugar to avoid adding zap imports here
-	// We cannot import zap in this file without adding the module; use a tiny shim via log.Printf only
-	// However, Store.LogAuditEvent requires *zap.SugaredLogger; to satisfy type, we implement a minimal shim.
-	// Fallback: use zap.NewNop().Sugar() via a thin wrapper declared in a small local package would be ideal.
-	// To keep changes minimal, we avoid heavy logging here and pass a nil-safe substitute.
-	return zap.NewNop().Sugar()
+// logFossaAction records an action taken on CNCF FOSSA, attributed to the foundation officer whose FOSSA identity is
+// configured to act for the service. Audit failures are logged and do not fail the onboarding step.
+func (s *EventListener) logFossaAction(event model.AuditLog) {
+	if s.Store == nil {
+		return
+	}
+	if err := s.Store.LogServiceAction("FOSSA", event); err != nil {
+		log.Printf("logFossaAction: WRN, failed to record %s: %v", event.Action, err)
+	}
 }
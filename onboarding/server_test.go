@@ -1,14 +1,20 @@
 package onboarding
 
 import (
+	"context"
 	"errors"
 	"net/http"
+	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-github/v55/github"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"maintainerd/emailverify"
+	"maintainerd/mailer"
 	"maintainerd/model"
 )
 
@@ -193,6 +199,77 @@ func TestFossaChosen_Basic(t *testing.T) {
 	})
 }
 
+func TestSignProjectUpForFOSSA_UnverifiedEmail(t *testing.T) {
+	db := setupTestDB(t)
+	project, _ := seedProjectData(t, db)
+
+	carol := model.Maintainer{
+		Name:             "Carol",
+		Email:            "carol@example.com",
+		GitHubAccount:    "carol",
+		MaintainerStatus: model.ActiveMaintainer,
+	}
+	require.NoError(t, db.Create(&carol).Error)
+	require.NoError(t, db.Model(&project).Association("Maintainers").Append(&carol))
+
+	mailDir := t.TempDir()
+	sender, err := mailer.NewFileSender(mailDir, "noreply@example.org")
+	require.NoError(t, err)
+	signer, err := emailverify.NewSigner([]byte("0123456789abcdef0123456789abcdef"), time.Hour)
+	require.NoError(t, err)
+
+	mockFossa := NewMockFossaClient()
+	mockGitHub := NewMockGitHubTransport()
+	server := createTestServer(t, db, mockFossa, mockGitHub)
+	server.EmailVerifier = &emailverify.Verifier{Signer: signer, Sender: sender, BaseURL: "https://bff.example.org"}
+
+	actions, err := server.signProjectUpForFOSSA(project)
+	require.NoError(t, err)
+
+	invitations := mockFossa.GetInvitationsSent()
+	assert.ElementsMatch(t, []string{"alice@example.com", "bob@example.com"}, invitations)
+
+	report := strings.Join(actions, "\n")
+	assert.Contains(t, report, "test-project has 2 maintainers")
+	assert.Contains(t, report, "Maintainers skipped until they verify their email address: @carol")
+	assert.Contains(t, report, "verification links were kept in the local mail drop")
+	assert.NotContains(t, report, "have been emailed", "a mail drop does not deliver")
+	assert.NotContains(t, report, "carol@example.com")
+
+	dropped, err := os.ReadDir(mailDir)
+	require.NoError(t, err)
+	require.Len(t, dropped, 1)
+	assert.Contains(t, dropped[0].Name(), "carol_example.com")
+
+	var stored model.Maintainer
+	require.NoError(t, db.First(&stored, carol.ID).Error)
+	require.NotNil(t, stored.EmailVerificationSentAt, "the send time is recorded")
+
+	_, err = server.signProjectUpForFOSSA(project)
+	require.NoError(t, err)
+	dropped, err = os.ReadDir(mailDir)
+	require.NoError(t, err)
+	assert.Len(t, dropped, 1, "a link sent within the resend interval is not sent again")
+
+	var delivered []string
+	server.EmailVerifier.Sender = senderFunc(func(_ context.Context, msg mailer.Message) error {
+		delivered = append(delivered, msg.To)
+		return nil
+	})
+	assert.Contains(t, server.reportUnverifiedMaintainers([]model.Maintainer{stored}), "verification links were emailed recently")
+	assert.Empty(t, delivered)
+
+	stale := time.Now().Add(-verificationResendInterval - time.Minute)
+	stored.EmailVerificationSentAt = &stale
+	assert.Contains(t, server.reportUnverifiedMaintainers([]model.Maintainer{stored}), "verification links have been emailed")
+	assert.Equal(t, []string{"carol@example.com"}, delivered)
+}
+
+// senderFunc is a mailer.Sender that delivers by calling itself.
+type senderFunc func(ctx context.Context, msg mailer.Message) error
+
+func (f senderFunc) Send(ctx context.Context, msg mailer.Message) error { return f(ctx, msg) }
+
 func TestSignProjectUpForFOSSA_CreateTeamFailure(t *testing.T) {
 	db := setupTestDB(t)
 	project, maintainers := seedProjectData(t, db)
@@ -438,3 +515,31 @@ func TestFossaInviteAcceptedAuthorization(t *testing.T) {
 		assert.False(t, ok)
 	})
 }
+
+func TestFossaActionsRecordActingOfficer(t *testing.T) {
+	database := setupTestDB(t)
+	project, _ := seedProjectData(t, database)
+
+	var fossa model.Service
+	require.NoError(t, database.Where("name = ?", "FOSSA").First(&fossa).Error)
+	officer := model.FoundationOfficer{Name: "Olga", GitHubAccount: "olga"}
+	require.NoError(t, database.Create(&officer).Error)
+	account := model.ServiceUser{ServiceID: fossa.ID, ServiceUserID: 9001, ServiceEmail: "fossa-admin@example.org"}
+	require.NoError(t, database.Create(&account).Error)
+	require.NoError(t, database.Model(&officer).Association("Services").Append(&account))
+	require.NoError(t, database.Model(&fossa).Update("acting_officer_id", officer.ID).Error)
+
+	server := createTestServer(t, database, NewMockFossaClient(), NewMockGitHubTransport())
+	_, err := server.signProjectUpForFOSSA(project)
+	require.NoError(t, err)
+
+	var entries []model.AuditLog
+	require.NoError(t, database.Where("action IN ?", []string{"FOSSA_CREATE_TEAM", "FOSSA_INVITE_SENT"}).Order("id").Find(&entries).Error)
+	require.Len(t, entries, 3, "one team created and two invitations sent")
+	for _, entry := range entries {
+		require.NotNil(t, entry.ServiceID)
+		assert.Equal(t, fossa.ID, *entry.ServiceID)
+		assert.Contains(t, entry.Metadata, `"officer":{"officerId":`)
+		assert.Contains(t, entry.Metadata, `"remoteUserId":9001`)
+	}
+}
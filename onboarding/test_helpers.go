@@ -3,6 +3,7 @@ package onboarding
 import (
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/google/go-github/v55/github"
 	"github.com/stretchr/testify/require"
@@ -25,11 +26,18 @@ func setupTestDB(t *testing.T) *gorm.DB {
 		&model.Company{},
 		&model.Foundation{},
 		&model.Project{},
+		&model.ProjectAlias{},
+		&model.ProjectMaturityEvent{},
 		&model.Maintainer{},
 		&model.MaintainerProject{},
+		&model.MembershipHistory{},
+		&model.CompanyDomain{},
+		&model.CompanyAlias{},
 		&model.StaffMember{},
 		&model.Service{},
 		&model.ServiceTeam{},
+		&model.ServiceUser{},
+		&model.FoundationOfficer{},
 		&model.AuditLog{},
 	)
 	require.NoError(t, err)
@@ -49,12 +57,15 @@ func seedProjectData(t *testing.T, database *gorm.DB) (model.Project, []model.Ma
 	project := model.Project{Name: "test-project", Maturity: model.Graduated}
 	require.NoError(t, database.Create(&project).Error)
 
+	verifiedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
 	maintainers := []model.Maintainer{
 		{
 			Name:             "Alice",
 			Email:            "alice@example.com",
 			GitHubAccount:    "alice",
 			MaintainerStatus: model.ActiveMaintainer,
+			EmailVerifiedAt:  &verifiedAt,
 			CompanyID:        &company.ID,
 		},
 		{
@@ -62,6 +73,7 @@ func seedProjectData(t *testing.T, database *gorm.DB) (model.Project, []model.Ma
 			Email:            "bob@example.com",
 			GitHubAccount:    "bob",
 			MaintainerStatus: model.ActiveMaintainer,
+			EmailVerifiedAt:  &verifiedAt,
 			CompanyID:        &company.ID,
 		},
 		{
@@ -345,6 +345,50 @@ func (c *Client) AddUserToTeamByEmail(teamID int, email string, roleID int) erro
 	return fmt.Errorf("AddUserToTeamByEmail failed: %s – %s", resp.Status, string(body))
 }
 
+// RemoveUserFromTeamByEmail removes a user, resolved by email, from a FOSSA team.
+func (c *Client) RemoveUserFromTeamByEmail(teamID int, email string) error {
+	uid, err := c.findUserIDByEmail(email)
+	if err != nil {
+		return fmt.Errorf("resolve user by email: %w", err)
+	}
+
+	jsonBody, err := json.Marshal(map[string]interface{}{
+		"users": []map[string]interface{}{
+			{
+				"id": uid,
+			},
+		},
+		"action": "remove",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode body: %w", err)
+	}
+	teamsUsersEndpoint := fmt.Sprintf("%s/teams/%d/users", c.APIBase, teamID)
+	req, err := http.NewRequest("PUT", teamsUsersEndpoint, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.APIKey)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	var fossaErr Error
+	if err := json.Unmarshal(body, &fossaErr); err == nil && fossaErr.Message != "" {
+		return fmt.Errorf("RemoveUserFromTeamByEmail failed (code %d): %s – %s", fossaErr.Code, resp.Status, fossaErr.Message)
+	}
+	return fmt.Errorf("RemoveUserFromTeamByEmail failed: %s – %s", resp.Status, string(body))
+}
+
 // findUserIDByEmail searches the user list for a matching email and returns the user ID.
 func (c *Client) findUserIDByEmail(email string) (int, error) {
 	log.Printf("findUserIDByEmail: email=%q", email)